@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/HueCodes/keel/internal/config"
+)
+
+func configCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the keel configuration",
+	}
+
+	cmd.AddCommand(configDumpCmd(), configValidateCmd())
+
+	return cmd
+}
+
+func configDumpCmd() *cobra.Command {
+	var (
+		output   string
+		severity string
+		ignore   []string
+		only     []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "dump [file]",
+		Short: "Print the effective configuration",
+		Long: `Print the configuration a lint run would use for a file (default
+./Dockerfile): the .keel.yaml files in its directory and the directories
+above it, nearest first, merged with the given flag overrides and
+resolved for every rule.
+
+Examples:
+  keel config dump                  # Effective config as YAML
+  keel config dump svc/Dockerfile   # Config for a file in a subdirectory
+  keel config dump -o json          # Effective config as JSON
+  keel config dump --ignore SEC001  # See the effect of lint flags
+  keel config dump --profile strict # Config with a built-in profile`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file := "Dockerfile"
+			if len(args) > 0 {
+				file = args[0]
+			}
+			resolver, err := configResolver(cmd)
+			if err != nil {
+				return err
+			}
+			cfg, err := resolver.ForFile(file)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			rules := allRules()
+			cfg.Apply(config.Overrides{
+				Severity: severity,
+				Only:     expandRulePatterns(only, rules, cmd.ErrOrStderr()),
+				Ignore:   expandRulePatterns(ignore, rules, cmd.ErrOrStderr()),
+			})
+
+			eff := cfg.Effective(rules)
+			switch output {
+			case "yaml":
+				return eff.WriteYAML(cmd.OutOrStdout())
+			case "json":
+				return eff.WriteJSON(cmd.OutOrStdout())
+			default:
+				return fmt.Errorf("unknown output format %q (expected yaml or json)", output)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "yaml", "Output format: yaml|json")
+	cmd.Flags().StringVar(&severity, "severity", "", "Minimum severity: error|warning|info|hint")
+	cmd.Flags().StringSliceVar(&ignore, "ignore", nil, "Rules to ignore, by ID or glob (e.g., --ignore SEC001,'PERF*')")
+	cmd.Flags().StringSliceVar(&only, "only", nil, "Only run these rules, by ID or glob (e.g., --only 'PERF00[13]')")
+	cmd.Flags().String("profile", "", "Built-in settings the config is applied over: "+strings.Join(config.ProfileNames(), "|"))
+
+	return cmd
+}
+
+func configValidateCmd() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "validate [file]",
+		Short: "Check a config file for mistakes",
+		Long: `Check a config file (default: the file given by --config, or .keel.yaml)
+for unknown keys, invalid severities, and rules that don't exist, so that
+a typo can't silently leave a rule enabled. Each problem is reported with
+the line and column of the YAML node at fault.
+
+Examples:
+  keel config validate                 # Check ./.keel.yaml
+  keel config validate ci/.keel.yaml   # Check another file
+  keel config validate -o json         # Problems as a JSON array`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("config")
+			if len(args) > 0 {
+				path = args[0]
+			}
+			if path == "" {
+				path = config.DefaultFile
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			var ids []string
+			for _, r := range allRules() {
+				ids = append(ids, r.ID())
+			}
+			problems, err := config.Validate(data, ids)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+
+			out := cmd.OutOrStdout()
+			switch output {
+			case "text":
+				for _, p := range problems {
+					fmt.Fprintf(out, "%s:%s\n", path, p)
+				}
+				if len(problems) == 0 {
+					fmt.Fprintf(out, "%s is valid\n", path)
+				}
+			case "json":
+				type problemJSON struct {
+					Line    int    `json:"line"`
+					Column  int    `json:"column"`
+					Message string `json:"message"`
+				}
+				list := make([]problemJSON, 0, len(problems))
+				for _, p := range problems {
+					list = append(list, problemJSON{Line: p.Line, Column: p.Column, Message: p.Message})
+				}
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(list); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("unknown output format %q (expected text or json)", output)
+			}
+
+			if len(problems) > 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%s: %d problem(s) found", path, len(problems))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text|json")
+
+	return cmd
+}
+
+// configResolver returns the resolver for the file given by --config,
+// or for discovering .keel.yaml files above each linted file, starting
+// from the profile given by --profile if the command has one
+func configResolver(cmd *cobra.Command) (*config.Resolver, error) {
+	path, _ := cmd.Flags().GetString("config")
+	resolver := config.NewResolver(path)
+	if profile, _ := cmd.Flags().GetString("profile"); profile != "" {
+		if err := resolver.UseProfile(profile, allRules()); err != nil {
+			return nil, err
+		}
+	}
+	return resolver, nil
+}
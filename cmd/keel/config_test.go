@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/HueCodes/keel/internal/config"
+)
+
+func TestConfigDump_IgnoreFlag(t *testing.T) {
+	cmd := configCmd()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"dump", "--ignore", "SEC001", "-o", "json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var eff config.Effective
+	if err := json.Unmarshal(buf.Bytes(), &eff); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	states := make(map[string]config.RuleState)
+	for _, r := range eff.Rules {
+		states[r.ID] = r
+	}
+	if s, ok := states["SEC001"]; !ok || s.Enabled {
+		t.Errorf("expected SEC001 to be disabled, got %+v", s)
+	}
+	if s, ok := states["SEC002"]; !ok || !s.Enabled {
+		t.Errorf("expected SEC002 to be enabled, got %+v", s)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".keel.yaml")
+	src := "severity: warning\nrules:\n  SEC001:\n    enabled: false\n  SEC999:\n    enabled: false\n  BP004:\n    severity: loud\n"
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := configCmd()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"validate", path})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "2 problem(s)") {
+		t.Errorf("expected 2 problems to be reported, got %v", err)
+	}
+
+	expected := path + ":5:3: unknown rule SEC999\n" +
+		path + ":8:15: rules.BP004.severity: invalid severity \"loud\" (expected error, warning, info, or hint)\n"
+	if buf.String() != expected {
+		t.Errorf("expected output:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestConfigValidate_Valid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".keel.yaml")
+	if err := os.WriteFile(path, []byte("rules:\n  PERF004:\n    max_consecutive: 3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := configCmd()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"validate", "-o", "json", path})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("expected an empty JSON array, got %q", buf.String())
+	}
+}
+
+func TestConfigDump_ProfileRelaxed(t *testing.T) {
+	cmd := configCmd()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"dump", "--profile", "relaxed", "-o", "json", filepath.Join(t.TempDir(), "Dockerfile")})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var eff config.Effective
+	if err := json.Unmarshal(buf.Bytes(), &eff); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if eff.Severity != "error" {
+		t.Errorf("expected severity error, got %q", eff.Severity)
+	}
+	for _, r := range eff.Rules {
+		if want := strings.HasPrefix(r.ID, "SEC"); r.Enabled != want {
+			t.Errorf("%s: expected enabled %t, got %t", r.ID, want, r.Enabled)
+		}
+	}
+}
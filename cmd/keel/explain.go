@@ -1,14 +1,19 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/optimizer"
+	"github.com/HueCodes/keel/internal/optimizer/transforms"
 	"github.com/HueCodes/keel/internal/rules/bestpractice"
 	"github.com/HueCodes/keel/internal/rules/performance"
 	"github.com/HueCodes/keel/internal/rules/security"
@@ -21,19 +26,89 @@ type ruleInfo struct {
 	Description string
 	Category    analyzer.Category
 	Severity    analyzer.Severity
+	Fixable     bool // a transform in keel fix handles the rule
+	Tags        []string
+	BadExample  string
+	GoodExample string
+}
+
+// newRuleInfo describes a rule. Name, Description, and Examples are
+// optional for rules registered through analyzer.RegisterRule.
+func newRuleInfo(r analyzer.Rule) ruleInfo {
+	info := ruleInfo{
+		ID:       r.ID(),
+		Category: r.Category(),
+		Severity: r.Severity(),
+		Tags:     analyzer.Tags(r),
+	}
+	if n, ok := r.(interface{ Name() string }); ok {
+		info.Name = n.Name()
+	}
+	if d, ok := r.(interface{ Description() string }); ok {
+		info.Description = d.Description()
+	}
+	if e, ok := r.(interface{ Examples() (bad, good string) }); ok {
+		info.BadExample, info.GoodExample = e.Examples()
+	}
+	return info
+}
+
+// ruleJSON is the JSON form of a rule in the catalog
+type ruleJSON struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Category    string   `json:"category"`
+	Severity    string   `json:"severity"`
+	Description string   `json:"description"`
+	Fixable     bool     `json:"fixable"`
+	Tags        []string `json:"tags,omitempty"`
 }
 
 func explainCmd() *cobra.Command {
+	var (
+		asJSON  bool
+		all     bool
+		docsDir string
+	)
+
 	cmd := &cobra.Command{
 		Use:   "explain [rule]",
 		Short: "Show detailed explanation of a rule",
-		Long:  "Show detailed explanation of a rule or list all available rules if no argument is given.",
-		Args:  cobra.MaximumNArgs(1),
+		Long: `Show detailed explanation of a rule or list all available rules if no argument is given.
+
+Examples:
+  keel explain SEC001          # Explain a single rule
+  keel explain --all           # Explain every rule
+  keel explain --all --json    # Export the rule catalog as JSON
+  keel explain --generate-docs docs/rules  # Write markdown docs for every rule`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Collect all rules
 			rules := collectAllRules()
 
+			if docsDir != "" {
+				if len(args) > 0 {
+					return fmt.Errorf("--generate-docs cannot be combined with a rule argument")
+				}
+				if err := generateRuleDocs(docsDir, rules); err != nil {
+					return err
+				}
+				fmt.Printf("Wrote docs for %d rules to %s\n", len(rules), docsDir)
+				return nil
+			}
+
 			if len(args) == 0 {
+				if asJSON {
+					return writeRulesJSON(os.Stdout, rules)
+				}
+				if all {
+					for _, r := range rules {
+						if err := explainRule(r); err != nil {
+							return err
+						}
+					}
+					return nil
+				}
 				// List all rules
 				return listRules(rules)
 			}
@@ -42,6 +117,9 @@ func explainCmd() *cobra.Command {
 			ruleID := strings.ToUpper(args[0])
 			for _, r := range rules {
 				if r.ID == ruleID {
+					if asJSON {
+						return writeRulesJSON(os.Stdout, []ruleInfo{r})
+					}
 					return explainRule(r)
 				}
 			}
@@ -50,47 +128,153 @@ func explainCmd() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output rules as a JSON array")
+	cmd.Flags().BoolVar(&all, "all", false, "Explain all rules")
+	cmd.Flags().StringVar(&docsDir, "generate-docs", "", "Write a markdown file per rule and an index to `dir`")
+
 	return cmd
 }
 
+// writeRulesJSON writes rules as a JSON array
+func writeRulesJSON(w io.Writer, rules []ruleInfo) error {
+	out := make([]ruleJSON, 0, len(rules))
+	for _, r := range rules {
+		out = append(out, ruleJSON{
+			ID:          r.ID,
+			Name:        r.Name,
+			Category:    string(r.Category),
+			Severity:    r.Severity.String(),
+			Description: r.Description,
+			Fixable:     r.Fixable,
+			Tags:        r.Tags,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// generateRuleDocs writes <ID>.md for each rule and an index README.md
+// grouped by category to dir, creating it if needed
+func generateRuleDocs(dir string, rules []ruleInfo) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	for _, r := range rules {
+		path := filepath.Join(dir, r.ID+".md")
+		if err := os.WriteFile(path, []byte(ruleMarkdown(r)), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	path := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(path, []byte(rulesIndexMarkdown(rules)), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func ruleMarkdown(r ruleInfo) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# %s: %s\n\n", r.ID, r.Name)
+	sb.WriteString("| | |\n|---|---|\n")
+	fmt.Fprintf(&sb, "| Category | %s |\n", r.Category)
+	fmt.Fprintf(&sb, "| Severity | %s |\n", r.Severity)
+	if r.Fixable {
+		sb.WriteString("| Fixable | yes (`keel fix`) |\n")
+	} else {
+		sb.WriteString("| Fixable | no |\n")
+	}
+	if len(r.Tags) > 0 {
+		fmt.Fprintf(&sb, "| Tags | %s |\n", strings.Join(r.Tags, ", "))
+	}
+
+	if r.Description != "" {
+		fmt.Fprintf(&sb, "\n%s\n", r.Description)
+	}
+	if r.BadExample != "" {
+		fmt.Fprintf(&sb, "\n## Bad\n\n```dockerfile\n%s\n```\n", strings.TrimRight(r.BadExample, "\n"))
+	}
+	if r.GoodExample != "" {
+		fmt.Fprintf(&sb, "\n## Good\n\n```dockerfile\n%s\n```\n", strings.TrimRight(r.GoodExample, "\n"))
+	}
+
+	return sb.String()
+}
+
+func rulesIndexMarkdown(rules []ruleInfo) string {
+	var sb strings.Builder
+
+	sb.WriteString("# Rules\n")
+
+	categories := map[analyzer.Category][]ruleInfo{}
+	var order []analyzer.Category
+	for _, r := range rules {
+		if _, ok := categories[r.Category]; !ok {
+			order = append(order, r.Category)
+		}
+		categories[r.Category] = append(categories[r.Category], r)
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return categoryRank(order[i]) < categoryRank(order[j])
+	})
+
+	for _, cat := range order {
+		fmt.Fprintf(&sb, "\n## %s\n\n", strings.Title(string(cat)))
+		sb.WriteString("| Rule | Name | Severity | Fixable |\n|---|---|---|---|\n")
+		for _, r := range categories[cat] {
+			fixable := ""
+			if r.Fixable {
+				fixable = "yes"
+			}
+			fmt.Fprintf(&sb, "| [%s](%s.md) | %s | %s | %s |\n", r.ID, r.ID, r.Name, r.Severity, fixable)
+		}
+	}
+
+	return sb.String()
+}
+
+// categoryRank orders the built-in categories as listRules does, with any
+// custom categories after them
+func categoryRank(c analyzer.Category) int {
+	switch c {
+	case analyzer.CategorySecurity:
+		return 0
+	case analyzer.CategoryPerformance:
+		return 1
+	case analyzer.CategoryBestPractice:
+		return 2
+	case analyzer.CategoryStyle:
+		return 3
+	}
+	return 4
+}
+
 func collectAllRules() []ruleInfo {
 	var rules []ruleInfo
 
 	for _, r := range security.All() {
-		rules = append(rules, ruleInfo{
-			ID:          r.ID(),
-			Name:        r.Name(),
-			Description: r.Description(),
-			Category:    r.Category(),
-			Severity:    r.Severity(),
-		})
+		rules = append(rules, newRuleInfo(r))
 	}
 	for _, r := range performance.All() {
-		rules = append(rules, ruleInfo{
-			ID:          r.ID(),
-			Name:        r.Name(),
-			Description: r.Description(),
-			Category:    r.Category(),
-			Severity:    r.Severity(),
-		})
+		rules = append(rules, newRuleInfo(r))
 	}
 	for _, r := range bestpractice.All() {
-		rules = append(rules, ruleInfo{
-			ID:          r.ID(),
-			Name:        r.Name(),
-			Description: r.Description(),
-			Category:    r.Category(),
-			Severity:    r.Severity(),
-		})
+		rules = append(rules, newRuleInfo(r))
 	}
 	for _, r := range style.All() {
-		rules = append(rules, ruleInfo{
-			ID:          r.ID(),
-			Name:        r.Name(),
-			Description: r.Description(),
-			Category:    r.Category(),
-			Severity:    r.Severity(),
-		})
+		rules = append(rules, newRuleInfo(r))
+	}
+	for _, r := range analyzer.RegisteredRules() {
+		rules = append(rules, newRuleInfo(r))
+	}
+
+	fixable := fixableRules()
+	for i := range rules {
+		rules[i].Fixable = fixable[rules[i].ID]
 	}
 
 	sort.Slice(rules, func(i, j int) bool {
@@ -100,6 +284,30 @@ func collectAllRules() []ruleInfo {
 	return rules
 }
 
+// fixableRules returns the IDs of the rules handled by a transform, which
+// keel fix can correct automatically. A rule counts only if keel fix
+// analyzes at its severity, since transforms act on its diagnostics.
+func fixableRules() map[string]bool {
+	handled := make(map[string]bool)
+	fixTransforms := append(optimizer.AllTransforms(),
+		&transforms.PinImageTagTransform{},           // keel fix --pin-images
+		&transforms.DockerignoreSensitiveTransform{}, // keel fix --write
+	)
+	for _, t := range fixTransforms {
+		for _, id := range t.Rules() {
+			handled[id] = true
+		}
+	}
+
+	fixable := make(map[string]bool)
+	for _, r := range allRules() {
+		if handled[r.ID()] && r.Severity() >= fixMinSeverity {
+			fixable[r.ID()] = true
+		}
+	}
+	return fixable
+}
+
 func listRules(rules []ruleInfo) error {
 	fmt.Println("Available rules:")
 	fmt.Println()
@@ -140,6 +348,12 @@ func explainRule(r ruleInfo) error {
 	fmt.Fprintf(os.Stdout, "Rule: %s (%s)\n", r.ID, r.Name)
 	fmt.Fprintf(os.Stdout, "Category: %s\n", r.Category)
 	fmt.Fprintf(os.Stdout, "Severity: %s %s\n", severityIcon(r.Severity), r.Severity)
+	if r.Fixable {
+		fmt.Fprintln(os.Stdout, "Fixable: yes (keel fix)")
+	}
+	if len(r.Tags) > 0 {
+		fmt.Fprintf(os.Stdout, "Tags: %s\n", strings.Join(r.Tags, ", "))
+	}
 	fmt.Println()
 	fmt.Println("Description:")
 	fmt.Printf("  %s\n", r.Description)
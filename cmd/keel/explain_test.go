@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+func TestWriteRulesJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeRulesJSON(&buf, collectAllRules()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var rules []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &rules); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	var found map[string]interface{}
+	for _, r := range rules {
+		if r["id"] == "BP004" {
+			found = r
+		}
+	}
+	if found == nil {
+		t.Fatal("expected BP004 in catalog")
+	}
+
+	expected := map[string]interface{}{
+		"name":     "deprecated-maintainer",
+		"category": "bestpractice",
+		"severity": "info",
+		"fixable":  true,
+	}
+	for key, want := range expected {
+		if found[key] != want {
+			t.Errorf("expected %s=%v, got %v", key, want, found[key])
+		}
+	}
+	if found["description"] == "" {
+		t.Error("expected a description")
+	}
+	if _, ok := found["tags"]; ok {
+		t.Errorf("expected untagged rule to omit tags, got %v", found["tags"])
+	}
+
+	for _, r := range rules {
+		if r["id"] == "SEC003" {
+			if tags, _ := r["tags"].([]interface{}); len(tags) == 0 || tags[0] != "supply-chain" {
+				t.Errorf("expected SEC003 to be tagged supply-chain, got %v", r["tags"])
+			}
+		}
+	}
+}
+
+func TestGenerateRuleDocs(t *testing.T) {
+	dir := t.TempDir()
+	rules := collectAllRules()
+	if err := generateRuleDocs(dir, rules); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	index, err := os.ReadFile(filepath.Join(dir, "README.md"))
+	if err != nil {
+		t.Fatalf("expected an index: %v", err)
+	}
+
+	for _, r := range rules {
+		data, err := os.ReadFile(filepath.Join(dir, r.ID+".md"))
+		if err != nil {
+			t.Errorf("expected a doc for %s: %v", r.ID, err)
+			continue
+		}
+		if !strings.HasPrefix(string(data), "# "+r.ID+": "+r.Name+"\n") {
+			t.Errorf("%s.md has unexpected heading: %q", r.ID, strings.SplitN(string(data), "\n", 2)[0])
+		}
+		if !strings.Contains(string(index), "("+r.ID+".md)") {
+			t.Errorf("expected index to link %s", r.ID)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(rules)+1 {
+		t.Errorf("expected %d files, got %d", len(rules)+1, len(entries))
+	}
+}
+
+func TestRuleMarkdown(t *testing.T) {
+	md := ruleMarkdown(ruleInfo{
+		ID:          "ORG001",
+		Name:        "no-latest",
+		Description: "Pin base images.",
+		Category:    analyzer.CategorySecurity,
+		Severity:    analyzer.SeverityWarning,
+		Fixable:     true,
+		BadExample:  "FROM alpine:latest\n",
+		GoodExample: "FROM alpine:3.19",
+	})
+
+	for _, want := range []string{
+		"# ORG001: no-latest\n",
+		"| Severity | warning |\n",
+		"| Fixable | yes (`keel fix`) |\n",
+		"\nPin base images.\n",
+		"## Bad\n\n```dockerfile\nFROM alpine:latest\n```\n",
+		"## Good\n\n```dockerfile\nFROM alpine:3.19\n```\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected markdown to contain %q, got:\n%s", want, md)
+		}
+	}
+}
+
+func TestFixableRules_BelowWarningSeverity(t *testing.T) {
+	// keel fix sees info and hint diagnostics, so these rules are fixable
+	fixable := fixableRules()
+	for _, id := range []string{"BP004", "STY003", "STY004", "STY006"} {
+		if !fixable[id] {
+			t.Errorf("expected %s to be fixable", id)
+		}
+	}
+}
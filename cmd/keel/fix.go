@@ -2,32 +2,59 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/formatter"
 	"github.com/HueCodes/keel/internal/optimizer"
+	"github.com/HueCodes/keel/internal/optimizer/transforms"
 	"github.com/HueCodes/keel/internal/parser"
-	"github.com/HueCodes/keel/internal/rules/bestpractice"
-	"github.com/HueCodes/keel/internal/rules/performance"
-	"github.com/HueCodes/keel/internal/rules/security"
-	"github.com/HueCodes/keel/internal/rules/style"
 )
 
+// newRegistryClient creates the client keel fix --pin-images fetches
+// digests with. Tests replace it to avoid the network.
+var newRegistryClient = func(timeout time.Duration) transforms.RegistryClient {
+	client := transforms.NewDockerHubClient()
+	client.HTTPClient.Timeout = timeout
+	return client
+}
+
+// fixMinSeverity is the severity keel fix analyzes at. Fixes apply
+// whatever the reporting threshold, so it is the lowest there is.
+const fixMinSeverity = analyzer.SeverityHint
+
 func fixCmd() *cobra.Command {
 	var (
 		file    string
 		diff    bool
+		context int
 		dryRun  bool
 		write   bool
+
+		pinImages       bool
+		registryTimeout time.Duration
+
+		only   []string
+		ignore []string
 	)
 
 	cmd := &cobra.Command{
 		Use:   "fix [file]",
 		Short: "Auto-fix issues and write corrected Dockerfile",
-		Long:  "Analyze a Dockerfile, apply automatic fixes, and write the corrected version.",
-		Args:  cobra.MaximumNArgs(1),
+		Long: `Analyze a Dockerfile, apply automatic fixes, and write the corrected version.
+
+--only and --ignore select the fixes to apply by transform name or by
+the rule ID they fix, with glob patterns; --dry-run shows the names:
+  keel fix --only add-to-copy,merge-run   # Apply just these two fixes
+  keel fix --ignore 'PERF*'               # Skip fixes for performance rules`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 {
 				file = args[0]
@@ -35,13 +62,21 @@ func fixCmd() *cobra.Command {
 			if file == "" {
 				file = "Dockerfile"
 			}
+			if cmd.Flags().Changed("registry-timeout") && !pinImages {
+				return fmt.Errorf("--registry-timeout requires --pin-images")
+			}
+			if cmd.Flags().Changed("context") && !diff {
+				return fmt.Errorf("--context requires --diff")
+			}
+			if context < 0 {
+				return fmt.Errorf("--context must not be negative")
+			}
 
 			// Read file
-			content, err := os.ReadFile(file)
+			source, err := parser.ReadFile(file)
 			if err != nil {
 				return fmt.Errorf("failed to read %s: %w", file, err)
 			}
-			source := string(content)
 
 			// Parse
 			df, parseErrors := parser.Parse(source)
@@ -52,33 +87,44 @@ func fixCmd() *cobra.Command {
 			}
 
 			// Collect all rules
-			var rules []analyzer.Rule
-			for _, r := range security.All() {
-				rules = append(rules, r)
-			}
-			for _, r := range performance.All() {
-				rules = append(rules, r)
-			}
-			for _, r := range bestpractice.All() {
-				rules = append(rules, r)
-			}
-			for _, r := range style.All() {
-				rules = append(rules, r)
-			}
+			rules := allRules()
 
 			// Analyze to find issues
-			a := analyzer.New(analyzer.WithRules(rules...))
+			a := analyzer.New(analyzer.WithRules(rules...), analyzer.WithMinSeverity(fixMinSeverity))
 			result := a.Analyze(df, file, source)
 
-			// Create optimizer with all transforms
+			// Create optimizer with all transforms, pinning digests only on request
+			fixTransforms := optimizer.AllTransforms()
+			var pin *transforms.PinImageTagTransform
+			if pinImages {
+				pin = transforms.NewPinImageTagTransform(newRegistryClient(registryTimeout), registryTimeout)
+				fixTransforms = append(fixTransforms, pin)
+			}
+			// Editing .dockerignore is a side effect, so only when writing;
+			// --diff shows the Dockerfile changes and writes nothing
+			var dockerignore *transforms.DockerignoreSensitiveTransform
+			if write && !diff {
+				dockerignore = &transforms.DockerignoreSensitiveTransform{Dockerfile: file}
+				fixTransforms = append(fixTransforms, dockerignore)
+			}
+			fixTransforms = selectTransforms(fixTransforms, only, ignore, cmd.ErrOrStderr())
 			opt := optimizer.New(
-				optimizer.WithTransforms(optimizer.AllTransforms()...),
+				optimizer.WithTransforms(fixTransforms...),
 				optimizer.WithDryRun(dryRun),
 			)
 
 			// Optimize
 			optResult := opt.Optimize(df, result.Diagnostics)
 
+			if pin != nil {
+				for _, err := range pin.Errors {
+					fmt.Fprintf(os.Stderr, "Warning: could not pin %s\n", err)
+				}
+			}
+			if dockerignore != nil && dockerignore.Err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not update .dockerignore: %s\n", dockerignore.Err)
+			}
+
 			if !optResult.HasChanges() && !dryRun {
 				fmt.Println("No fixable issues found.")
 				return nil
@@ -97,10 +143,7 @@ func fixCmd() *cobra.Command {
 			}
 
 			if diff {
-				// Show diff
-				fmt.Println("--- " + file + " (original)")
-				fmt.Println("+++ " + file + " (fixed)")
-				showDiff(source, fixed)
+				fmt.Fprint(cmd.OutOrStdout(), formatter.DiffWithOptions(file, source, fixed, formatter.DiffOptions{Context: context}))
 				return nil
 			}
 
@@ -115,6 +158,9 @@ func fixCmd() *cobra.Command {
 						fmt.Printf("  - %s: %s\n", c.Transform, c.Description)
 					}
 				}
+				if dockerignore != nil && len(dockerignore.Added) > 0 {
+					fmt.Printf("Added %s to %s\n", strings.Join(dockerignore.Added, ", "), dockerignore.Path)
+				}
 			} else {
 				// Print to stdout
 				fmt.Print(fixed)
@@ -126,57 +172,56 @@ func fixCmd() *cobra.Command {
 
 	cmd.Flags().StringVarP(&file, "file", "f", "", "Dockerfile path (default \"Dockerfile\")")
 	cmd.Flags().BoolVar(&diff, "diff", false, "Show diff instead of writing")
+	cmd.Flags().IntVar(&context, "context", formatter.DefaultDiffOptions().Context, "Unchanged lines shown around each change with --diff")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be changed without making changes")
 	cmd.Flags().BoolVarP(&write, "write", "w", false, "Write changes back to file")
+	cmd.Flags().BoolVar(&pinImages, "pin-images", false, "Pin base images to digests fetched from the registry")
+	cmd.Flags().DurationVar(&registryTimeout, "registry-timeout", 30*time.Second, "Timeout for registry requests made by --pin-images")
+	cmd.Flags().StringSliceVar(&only, "only", nil, "Only apply these fixes, by transform name or rule ID (e.g., --only add-to-copy,PERF004)")
+	cmd.Flags().StringSliceVar(&ignore, "ignore", nil, "Fixes to skip, by transform name or rule ID (e.g., --ignore 'SEC*')")
 
 	return cmd
 }
 
-func showDiff(original, fixed string) {
-	// Simple line-by-line diff
-	origLines := splitLines(original)
-	fixedLines := splitLines(fixed)
-
-	// Very simple diff - just show all lines with +/-
-	// A real implementation would use a proper diff algorithm
-	maxLines := len(origLines)
-	if len(fixedLines) > maxLines {
-		maxLines = len(fixedLines)
-	}
-
-	for i := 0; i < maxLines; i++ {
-		var origLine, fixedLine string
-		if i < len(origLines) {
-			origLine = origLines[i]
-		}
-		if i < len(fixedLines) {
-			fixedLine = fixedLines[i]
-		}
-
-		if origLine != fixedLine {
-			if origLine != "" {
-				fmt.Printf("\033[31m- %s\033[0m\n", origLine)
+// selectTransforms keeps the transforms matched by only, or all of them
+// when only is empty, and drops those matched by ignore. A pattern is a
+// transform name or the ID of a rule it fixes, with glob syntax, matched
+// case-insensitively. Patterns that match no transform are reported to
+// warn.
+func selectTransforms(ts []optimizer.Transform, only, ignore []string, warn io.Writer) []optimizer.Transform {
+	matches := func(pattern string, t optimizer.Transform) bool {
+		pattern = strings.ToLower(pattern)
+		for _, name := range append([]string{t.Name()}, t.Rules()...) {
+			if ok, _ := path.Match(pattern, strings.ToLower(name)); ok {
+				return true
 			}
-			if fixedLine != "" {
-				fmt.Printf("\033[32m+ %s\033[0m\n", fixedLine)
+		}
+		return false
+	}
+	anyMatch := func(patterns []string, t optimizer.Transform) bool {
+		for _, p := range patterns {
+			if matches(p, t) {
+				return true
 			}
-		} else if origLine != "" {
-			fmt.Printf("  %s\n", origLine)
 		}
+		return false
 	}
-}
 
-func splitLines(s string) []string {
-	var lines []string
-	start := 0
-	for i := 0; i < len(s); i++ {
-		if s[i] == '\n' {
-			lines = append(lines, s[start:i])
-			start = i + 1
+	for _, p := range append(slices.Clone(only), ignore...) {
+		if !slices.ContainsFunc(ts, func(t optimizer.Transform) bool { return matches(p, t) }) {
+			fmt.Fprintf(warn, "Warning: %s does not match any fix\n", p)
 		}
 	}
-	if start < len(s) {
-		lines = append(lines, s[start:])
+
+	var selected []optimizer.Transform
+	for _, t := range ts {
+		if len(only) > 0 && !anyMatch(only, t) {
+			continue
+		}
+		if anyMatch(ignore, t) {
+			continue
+		}
+		selected = append(selected, t)
 	}
-	return lines
+	return selected
 }
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HueCodes/keel/internal/optimizer"
+	"github.com/HueCodes/keel/internal/optimizer/transforms"
+)
+
+// mockRegistryClient returns digests from a map of image:tag to digest
+type mockRegistryClient struct {
+	digests map[string]string
+}
+
+func (m *mockRegistryClient) GetDigest(ctx context.Context, image, tag string) (string, error) {
+	if digest, ok := m.digests[image+":"+tag]; ok {
+		return digest, nil
+	}
+	return "", errors.New("manifest unknown")
+}
+
+func TestFix_PinImages(t *testing.T) {
+	var gotTimeout time.Duration
+	orig := newRegistryClient
+	newRegistryClient = func(timeout time.Duration) transforms.RegistryClient {
+		gotTimeout = timeout
+		return &mockRegistryClient{digests: map[string]string{"ubuntu:latest": "sha256:abc123"}}
+	}
+	defer func() { newRegistryClient = orig }()
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "without pin-images",
+			args:     nil,
+			expected: "FROM ubuntu:latest\n",
+		},
+		{
+			name:     "pin-images",
+			args:     []string{"--pin-images", "--registry-timeout", "5s"},
+			expected: "FROM ubuntu:latest@sha256:abc123\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "Dockerfile")
+			if err := os.WriteFile(path, []byte("FROM ubuntu:latest\nUSER 1000\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			cmd := fixCmd()
+			cmd.SetArgs(append(tt.args, "-w", path))
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(string(data), tt.expected) {
+				t.Errorf("expected output to start with %q, got:\n%s", tt.expected, data)
+			}
+		})
+	}
+
+	if gotTimeout != 5*time.Second {
+		t.Errorf("expected registry timeout 5s, got %v", gotTimeout)
+	}
+}
+
+func TestFix_RegistryTimeoutRequiresPinImages(t *testing.T) {
+	cmd := fixCmd()
+	cmd.SetArgs([]string{"--registry-timeout", "5s", filepath.Join(t.TempDir(), "Dockerfile")})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--pin-images") {
+		t.Errorf("expected an error mentioning --pin-images, got %v", err)
+	}
+}
+
+func TestFix_DiffContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Dockerfile")
+	source := "FROM alpine:3.19\nUSER 1000\nLABEL a=b\nWORKDIR app\nLABEL c=d\nCMD [\"sh\"]\n"
+	if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		context  string
+		expected string
+	}{
+		{"0", "@@ -4,1 +4,1 @@\n-WORKDIR app\n+WORKDIR /app\n"},
+		{"1", "@@ -3,3 +3,3 @@\n LABEL a=b\n-WORKDIR app\n+WORKDIR /app\n LABEL c=d\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run("context="+tt.context, func(t *testing.T) {
+			cmd := fixCmd()
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+			cmd.SetArgs([]string{"--diff", "--context", tt.context, path})
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expected := "--- " + path + "\n+++ " + path + "\n" + tt.expected
+			if buf.String() != expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestFix_DiffDoesNotWriteDockerignore(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Dockerfile")
+	if err := os.WriteFile(path, []byte("FROM alpine:3.19\nWORKDIR app\nCOPY . .\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("TOKEN=secret\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args   []string
+		exists bool
+	}{
+		{[]string{"--diff", "-w", path}, false},
+		{[]string{"-w", path}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args[:len(tt.args)-1], " "), func(t *testing.T) {
+			cmd := fixCmd()
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetArgs(tt.args)
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_, err := os.Stat(filepath.Join(dir, ".dockerignore"))
+			if exists := err == nil; exists != tt.exists {
+				t.Errorf("expected .dockerignore to exist: %v, got %v", tt.exists, exists)
+			}
+		})
+	}
+}
+
+func TestFix_ContextRequiresDiff(t *testing.T) {
+	cmd := fixCmd()
+	cmd.SetArgs([]string{"--context", "1", filepath.Join(t.TempDir(), "Dockerfile")})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--diff") {
+		t.Errorf("expected an error mentioning --diff, got %v", err)
+	}
+}
+
+func TestFix_OnlyIgnore(t *testing.T) {
+	// BP005 is fixed by workdir-absolute, BP002 by add-to-copy
+	source := "FROM alpine:3.19\nUSER 1000\nWORKDIR app\nADD main.go /app/\nCMD [\"sh\"]\n"
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "all fixes",
+			expected: "FROM alpine:3.19\nUSER 1000\nWORKDIR /app\nCOPY main.go /app/\n",
+		},
+		{
+			name:     "ignore by transform name",
+			args:     []string{"--ignore", "add-to-copy"},
+			expected: "FROM alpine:3.19\nUSER 1000\nWORKDIR /app\nADD main.go /app/\n",
+		},
+		{
+			name:     "ignore by rule ID",
+			args:     []string{"--ignore", "bp005"},
+			expected: "FROM alpine:3.19\nUSER 1000\nWORKDIR app\nCOPY main.go /app/\n",
+		},
+		{
+			name:     "only by glob",
+			args:     []string{"--only", "add-*"},
+			expected: "FROM alpine:3.19\nUSER 1000\nWORKDIR app\nCOPY main.go /app/\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "Dockerfile")
+			if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			cmd := fixCmd()
+			cmd.SetArgs(append(tt.args, "-w", path))
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(string(data), tt.expected) {
+				t.Errorf("expected output to start with %q, got:\n%s", tt.expected, data)
+			}
+		})
+	}
+}
+
+func TestFix_BelowWarningSeverity(t *testing.T) {
+	// Fixes for info and hint rules apply, though lint hides them by default
+	tests := []struct {
+		name   string
+		source string
+		before string
+		after  string
+	}{
+		{
+			name:   "BP004 maintainer",
+			source: "FROM alpine:3.19\nMAINTAINER bob@example.com\nUSER 1000\nCMD [\"sh\"]\n",
+			before: "MAINTAINER bob@example.com",
+			after:  "LABEL maintainer=bob@example.com",
+		},
+		{
+			name:   "STY003 legacy ENV",
+			source: "FROM alpine:3.19\nENV FOO bar\nUSER 1000\nCMD [\"sh\"]\n",
+			before: "ENV FOO bar",
+			after:  "ENV FOO=bar",
+		},
+		{
+			name:   "STY004 duplicate EXPOSE",
+			source: "FROM alpine:3.19\nEXPOSE 80 80/tcp\nUSER 1000\nCMD [\"sh\"]\n",
+			before: "EXPOSE 80 80/tcp",
+			after:  "EXPOSE 80\n",
+		},
+		{
+			name:   "STY006 redundant platform",
+			source: "FROM --platform=$TARGETPLATFORM alpine:3.19\nUSER 1000\nCMD [\"sh\"]\n",
+			before: "--platform",
+			after:  "FROM alpine:3.19\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "Dockerfile")
+			if err := os.WriteFile(path, []byte(tt.source), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			cmd := fixCmd()
+			cmd.SetArgs([]string{"-w", path})
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(data), tt.before) || !strings.Contains(string(data), tt.after) {
+				t.Errorf("expected %q to become %q, got:\n%s", tt.before, tt.after, data)
+			}
+		})
+	}
+}
+
+func TestSelectTransforms_UnmatchedPattern(t *testing.T) {
+	var warn bytes.Buffer
+	selected := selectTransforms(optimizer.AllTransforms(), []string{"merge-run", "no-such-fix"}, nil, &warn)
+	if len(selected) != 1 || selected[0].Name() != "merge-run" {
+		t.Errorf("expected only merge-run to be selected, got %v", selected)
+	}
+	if warn.String() != "Warning: no-such-fix does not match any fix\n" {
+		t.Errorf("unexpected warning %q", warn.String())
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/HueCodes/keel/internal/formatter"
+	"github.com/HueCodes/keel/internal/parser"
 )
 
 func fmtCmd() *cobra.Command {
@@ -45,14 +46,20 @@ Examples:
 			}
 
 			// Read file
-			content, err := os.ReadFile(file)
+			source, err := parser.ReadFile(file)
 			if err != nil {
 				return fmt.Errorf("failed to read %s: %w", file, err)
 			}
-			source := string(content)
 
-			// Create formatter with default options
-			f := formatter.New(formatter.DefaultOptions())
+			resolver, err := configResolver(cmd)
+			if err != nil {
+				return err
+			}
+			cfg, err := resolver.ForFile(file)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			f := formatter.New(cfg.FormatterOptions())
 
 			result, err := f.FormatSource(source)
 			if err != nil {
@@ -36,6 +36,18 @@ rules:
     enabled: true
     # allowed_tags:
     #   - "latest"  # Allow latest for specific images
+  SEC008:
+    enabled: true
+    # services_only: true  # Only images that EXPOSE a port and set CMD or ENTRYPOINT
+  SEC016:
+    enabled: true
+    # extra_images:  # End-of-life images to flag, with a suggested upgrade
+    #   golang:1.20: golang:1.25
+  SEC017:
+    enabled: true
+    # allowed_registries:  # Only allow base images from these registries
+    #   - ghcr.io
+    #   - docker.io/library
 
   # Performance rules
   PERF001:
@@ -49,12 +61,20 @@ rules:
     enabled: true
   BP002:
     enabled: true
+  BP033:
+    enabled: true  # Set to false when authoring base images that use ONBUILD
 
   # Style rules
   STY001:
     enabled: true
+  STY005:
+    enabled: true
+    max_commands: 20  # Hint when a RUN chains more than 20 commands
+  STY007:
+    enabled: true
+    allowed_keys: [maintainer]  # Bare LABEL keys that need no namespace
 
-# Ignore patterns (glob syntax)
+# Files lint skips when walking directories, as globs relative to this file
 ignore_paths:
   - "test/**"
   - "examples/**"
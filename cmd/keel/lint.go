@@ -2,31 +2,55 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/cache"
+	"github.com/HueCodes/keel/internal/compose"
+	"github.com/HueCodes/keel/internal/config"
+	"github.com/HueCodes/keel/internal/formatter"
+	"github.com/HueCodes/keel/internal/optimizer"
 	"github.com/HueCodes/keel/internal/parallel"
+	"github.com/HueCodes/keel/internal/parser"
 	"github.com/HueCodes/keel/internal/reporter"
-	"github.com/HueCodes/keel/internal/rules/bestpractice"
-	"github.com/HueCodes/keel/internal/rules/performance"
-	"github.com/HueCodes/keel/internal/rules/security"
-	"github.com/HueCodes/keel/internal/rules/style"
 )
 
 func lintCmd() *cobra.Command {
 	var (
 		file          string
 		output        string
+		outputFile    string
+		groupBy       string
 		severity      string
 		ignore        []string
 		only          []string
+		tags          []string
 		runParallel   bool
 		workers       int
+		timeout       time.Duration
 		parallelRules bool
+		useCache      bool
+		showFixes     bool
+		explain       bool
+		preCommit     bool
+		fixableOnly   bool
+		fromCompose   string
+
+		exitZeroUnmatched bool
+		batchJSON         bool
+
+		recursive bool
+		walkOpts  walkOptions
 	)
 
 	cmd := &cobra.Command{
@@ -38,79 +62,250 @@ Supports glob patterns for multiple files:
   keel lint                           # Lint ./Dockerfile
   keel lint Dockerfile.prod           # Lint specific file
   keel lint Dockerfile*               # Lint all matching files
-  keel lint --parallel **/Dockerfile  # Lint in parallel`,
+  keel lint --parallel **/Dockerfile  # Lint in parallel
+  keel lint ./...                     # Lint every Dockerfile under the current directory
+  keel lint --recursive services --exclude 'legacy/**'
+  keel lint --show-fixes              # Preview auto-fixes as a diff
+  keel lint --explain                 # Describe each rule that fired
+  keel lint --group-by rule           # Group issues by rule for triage
+  keel lint --fixable-only            # Only issues keel fix can correct
+  keel lint --ignore 'SEC*'           # Skip all security rules
+  keel lint --tag supply-chain        # Only run rules tagged supply-chain
+  keel lint --profile relaxed         # Only security errors; config and flags still apply
+  keel lint --from-compose compose.yml  # Lint Dockerfiles built by compose services
+  keel lint --pre-commit Dockerfile api/Dockerfile  # Run as a pre-commit hook
+  keel lint -o sarif --output-file results.sarif  # Write the report to a file
+
+With --recursive, or an argument ending in /..., directories are walked
+for files named Dockerfile, *.Dockerfile, or Dockerfile.* (see --pattern),
+skipping .git and node_modules. --include and --exclude filter the files
+found by path relative to the directory, where ** matches any number of
+directories.
+
+A glob that matches no files is reported and fails the run, unless
+--exit-zero-on-unmatched-glob is given.
+
+With --pre-commit, files without issues print nothing, the output ends
+with a one-line summary, and any issue at or above the configured
+severity fails the run, as pre-commit hooks expect.
+
+With --batch-json, files are read from stdin as a JSON array of
+{"filename": ..., "content": ...} objects, and the results are written
+to stdout as a JSON array with one object per file, in input order:
+  echo '[{"filename": "Dockerfile", "content": "FROM alpine\n"}]' | keel lint --batch-json`,
 		Args: cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if batchJSON && (len(args) > 0 || file != "" || fromCompose != "" || recursive) {
+				return fmt.Errorf("--batch-json reads files from stdin and takes no file arguments")
+			}
+
+			grouping, err := reporter.ParseGroupBy(groupBy)
+			if err != nil {
+				return err
+			}
+
+			roots, args := splitRecursiveArgs(args, recursive)
+			if len(roots) == 0 && (len(walkOpts.Include) > 0 || len(walkOpts.Exclude) > 0) {
+				return fmt.Errorf("--include and --exclude require --recursive or a ./... argument")
+			}
+
 			// Determine files to lint
-			var files []string
+			var files, unmatched []string
 			if len(args) > 0 {
-				for _, pattern := range args {
-					matches, err := filepath.Glob(pattern)
-					if err != nil {
-						return fmt.Errorf("invalid pattern %s: %w", pattern, err)
-					}
-					if len(matches) == 0 {
-						// Treat as literal file path
-						files = append(files, pattern)
-					} else {
-						files = append(files, matches...)
-					}
+				var err error
+				files, unmatched, err = expandFileArgs(args, cmd.ErrOrStderr())
+				if err != nil {
+					return err
 				}
 			} else if file != "" {
 				files = append(files, file)
-			} else {
+			} else if fromCompose == "" && !batchJSON && len(roots) == 0 {
 				files = append(files, "Dockerfile")
 			}
 
-			// Collect all rules
-			var rules []analyzer.Rule
-			for _, r := range security.All() {
-				rules = append(rules, r)
-			}
-			for _, r := range performance.All() {
-				rules = append(rules, r)
+			// Each file gets the config files above it, merged with flag overrides
+			resolver, err := configResolver(cmd)
+			if err != nil {
+				return err
 			}
-			for _, r := range bestpractice.All() {
-				rules = append(rules, r)
+
+			for _, root := range roots {
+				found, err := findDockerfiles(root, walkOpts)
+				if err != nil {
+					return fmt.Errorf("failed to walk %s: %w", root, err)
+				}
+				if found, err = withoutIgnored(found, resolver); err != nil {
+					return err
+				}
+				if len(found) == 0 {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Warning: no Dockerfiles found in %s\n", root)
+					unmatched = append(unmatched, root)
+				}
+				files = append(files, found...)
 			}
-			for _, r := range style.All() {
-				rules = append(rules, r)
+
+			if fromCompose != "" {
+				paths, err := compose.Load(fromCompose)
+				if err != nil {
+					return fmt.Errorf("failed to read compose file: %w", err)
+				}
+				if len(paths) == 0 {
+					return fmt.Errorf("no services with a build section in %s", fromCompose)
+				}
+				files = append(files, paths...)
 			}
 
-			// Parse severity
-			minSeverity := parseSeverity(severity)
+			if len(files) == 0 && !batchJSON {
+				if exitZeroUnmatched {
+					fmt.Fprintln(cmd.ErrOrStderr(), "No files to lint")
+					return nil
+				}
+				return fmt.Errorf("no files match %s", strings.Join(unmatched, ", "))
+			}
 
-			// Create analyzer options
-			opts := []analyzer.Option{
-				analyzer.WithRules(rules...),
-				analyzer.WithMinSeverity(minSeverity),
+			rules := allRules()
+			overrides := config.Overrides{
+				Only:   expandRulePatterns(only, rules, cmd.ErrOrStderr()),
+				Ignore: expandRulePatterns(ignore, rules, cmd.ErrOrStderr()),
+			}
+			tags = normalizeTags(tags, rules, cmd.ErrOrStderr())
+			if cmd.Flags().Changed("severity") {
+				overrides.Severity = severity
 			}
 
-			if len(only) > 0 {
-				opts = append(opts, analyzer.WithEnabled(only...))
+			var fixable map[string]bool
+			if fixableOnly {
+				fixable = fixableRules()
 			}
-			if len(ignore) > 0 {
-				opts = append(opts, analyzer.WithDisabled(ignore...))
+
+			optsFor := func(file string) ([]analyzer.Option, error) {
+				cfg, err := resolver.ForFile(file)
+				if err != nil {
+					return nil, err
+				}
+				cfg.Apply(overrides)
+
+				opts := append([]analyzer.Option{analyzer.WithRules(rules...)}, cfg.AnalyzerOptions()...)
+				if len(tags) > 0 {
+					opts = append(opts, analyzer.WithTags(tags...))
+				}
+				if parallelRules {
+					opts = append(opts, analyzer.WithParallelRules(true))
+				}
+				if workers > 0 {
+					opts = append(opts, analyzer.WithMaxWorkers(workers))
+				}
+				if timeout > 0 {
+					opts = append(opts, analyzer.WithTimeout(timeout))
+				}
+				if fixable != nil {
+					opts = append(opts, analyzer.WithFilter(func(d analyzer.Diagnostic) bool {
+						return fixable[d.Rule]
+					}))
+				}
+				return opts, nil
 			}
-			if parallelRules {
-				opts = append(opts, analyzer.WithParallelRules(true))
+
+			// With --output-file the report goes to the file, leaving
+			// stdout for a short summary
+			out := cmd.OutOrStdout()
+			var reportFile *os.File
+			if outputFile != "" {
+				f, err := os.Create(outputFile)
+				if err != nil {
+					return fmt.Errorf("failed to create output file: %w", err)
+				}
+				reportFile, out = f, f
 			}
-			if workers > 0 {
-				opts = append(opts, analyzer.WithMaxWorkers(workers))
+
+			// Every path from here closes the report with closeReport,
+			// which surfaces write errors that a deferred Close would drop
+			if batchJSON {
+				hasErrors, err := lintBatchJSON(cmd.InOrStdin(), out, optsFor, workers)
+				if cerr := closeReport(reportFile); err == nil {
+					err = cerr
+				}
+				if err != nil {
+					return err
+				}
+				if hasErrors {
+					os.Exit(1)
+				}
+				return nil
 			}
 
-			// Determine output format
-			noColor, _ := cmd.Flags().GetBool("no-color")
+			// Determine output format. Colors are on by default only when
+			// stdout is a terminal.
+			var repOpts []reporter.Option
+			if noColor, _ := cmd.Flags().GetBool("no-color"); noColor {
+				repOpts = append(repOpts, reporter.WithColors(false))
+			}
+			if explain {
+				repOpts = append(repOpts, reporter.WithDescriptions(ruleDescriptions(allRules())))
+			}
+			if preCommit {
+				repOpts = append(repOpts, reporter.WithQuiet(true))
+			}
+			repOpts = append(repOpts, reporter.WithSummary(true), reporter.WithGroupBy(grouping))
 			format := reporter.Format(output)
-			rep := reporter.New(format, os.Stdout, reporter.WithColors(!noColor))
+			rep := reporter.New(format, out, repOpts...)
+
+			// Parse through the AST cache when enabled
+			var astCache *cache.ASTCache
+			var cp *cache.CachedParser
+			if useCache {
+				astCache = cache.NewASTCache()
+				cp = cache.NewCachedParser(astCache)
+			}
+
+			// Fix previews go to stderr when stdout carries machine-readable output
+			var fixOut io.Writer
+			if showFixes {
+				fixOut = cmd.OutOrStdout()
+				if format != reporter.FormatTerminal {
+					fixOut = os.Stderr
+				}
+			}
 
-			var hasErrors bool
+			// Unmatched globs fail the run unless explicitly allowed
+			hasErrors := len(unmatched) > 0 && !exitZeroUnmatched
 
-			// Process files
-			if runParallel && len(files) > 1 {
-				hasErrors = lintFilesParallel(files, opts, rep, workers)
+			// Process files. Walked directories go through the parallel
+			// processor, which reports in input order. Files in the same
+			// directory share a config, and so an analyzer.
+			analyzerFor := analyzerCache(optsFor)
+			var stats lintStats
+			if (runParallel || len(roots) > 0) && len(files) > 1 {
+				stats = lintFilesParallel(files, analyzerFor, rep, workers, cp, fixOut)
 			} else {
-				hasErrors = lintFilesSequential(files, opts, rep)
+				stats = lintFilesSequential(files, analyzerFor, rep, cp, fixOut)
+			}
+			hasErrors = stats.errors || hasErrors
+			if f, ok := rep.(reporter.Finisher); ok {
+				if err := f.Finish(); err != nil {
+					fmt.Fprintf(os.Stderr, "Error reporting summary: %v\n", err)
+				}
+			}
+
+			if err := closeReport(reportFile); err != nil {
+				return err
+			}
+
+			if preCommit {
+				// Everything reported passed the severity filter
+				hasErrors = hasErrors || stats.issues > 0
+			}
+			if reportFile != nil {
+				stats.printReportSummary(cmd.OutOrStdout(), len(files), format, outputFile)
+			} else if preCommit {
+				stats.printSummary(cmd.OutOrStdout(), len(files))
+			}
+
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			if verbose && astCache != nil {
+				stats := astCache.Stats()
+				fmt.Fprintf(os.Stderr, "Cache: %d hits, %d misses, %d/%d entries\n",
+					stats.Hits, stats.Misses, stats.Entries, stats.MaxEntries)
 			}
 
 			if hasErrors {
@@ -122,114 +317,344 @@ Supports glob patterns for multiple files:
 	}
 
 	cmd.Flags().StringVarP(&file, "file", "f", "", "Dockerfile path (default \"Dockerfile\")")
-	cmd.Flags().StringVarP(&output, "output", "o", "terminal", "Output format: terminal|json|sarif|markdown|github")
+	cmd.Flags().StringVarP(&output, "output", "o", "terminal", "Output format: "+formatNames())
+	cmd.Flags().StringVar(&groupBy, "group-by", "position", "Group terminal and markdown output by: position|rule|severity")
+	cmd.Flags().StringVar(&outputFile, "output-file", "", "Write the report to this file and print a summary to stdout")
 	cmd.Flags().StringVar(&severity, "severity", "warning", "Minimum severity: error|warning|info|hint")
-	cmd.Flags().StringSliceVar(&ignore, "ignore", nil, "Rules to ignore (e.g., --ignore SEC001,PERF004)")
-	cmd.Flags().StringSliceVar(&only, "only", nil, "Only run these rules")
+	cmd.Flags().StringSliceVar(&ignore, "ignore", nil, "Rules to ignore, by ID or glob (e.g., --ignore SEC001,'PERF*')")
+	cmd.Flags().StringSliceVar(&only, "only", nil, "Only run these rules, by ID or glob (e.g., --only 'PERF00[13]')")
+	cmd.Flags().String("profile", "", "Built-in settings the config is applied over: "+strings.Join(config.ProfileNames(), "|"))
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "Only run rules with one of these tags (e.g., --tag supply-chain,layers)")
 	cmd.Flags().BoolVar(&runParallel, "parallel", false, "Process multiple files in parallel")
 	cmd.Flags().IntVar(&workers, "workers", 0, "Number of parallel workers (default: number of CPUs)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Time budget for analyzing each file (e.g., 5s); rules still running are reported as TIMEOUT")
 	cmd.Flags().BoolVar(&parallelRules, "parallel-rules", false, "Run rules in parallel for each file")
+	cmd.Flags().BoolVar(&useCache, "cache", false, "Cache parsed ASTs by file content (stats shown with --verbose)")
+	cmd.Flags().BoolVar(&showFixes, "show-fixes", false, "Show a diff of the auto-fixes without modifying files")
+	cmd.Flags().BoolVar(&preCommit, "pre-commit", false, "Print only issues and a summary, and fail on any issue at or above --severity")
+	cmd.Flags().BoolVar(&fixableOnly, "fixable-only", false, "Only report issues from rules that keel fix can correct")
+	cmd.Flags().BoolVar(&explain, "explain", false, "Show each rule's description after its diagnostics (terminal output)")
+	cmd.Flags().StringVar(&fromCompose, "from-compose", "", "Lint the Dockerfiles referenced by a Docker Compose file")
+	cmd.Flags().BoolVar(&exitZeroUnmatched, "exit-zero-on-unmatched-glob", false, "Don't fail when a glob pattern matches no files")
+	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Treat arguments as directories and lint the Dockerfiles under them (default \".\")")
+	cmd.Flags().StringSliceVar(&walkOpts.Names, "pattern", defaultDockerfilePatterns, "File names treated as Dockerfiles when walking directories")
+	cmd.Flags().StringSliceVar(&walkOpts.Include, "include", nil, "Only lint walked files whose path matches one of these globs (e.g., 'services/**')")
+	cmd.Flags().StringSliceVar(&walkOpts.Exclude, "exclude", nil, "Skip walked files and directories whose path matches one of these globs (e.g., 'test/**')")
+	cmd.Flags().BoolVar(&batchJSON, "batch-json", false, "Read a JSON array of {filename, content} from stdin and write a JSON array of results")
 
 	return cmd
 }
 
+// expandFileArgs expands glob patterns in args. Arguments without glob
+// characters are kept as literal paths so that a missing file is reported
+// when it is read. Globs matching nothing are returned as unmatched, with
+// a warning written to warn.
+func expandFileArgs(args []string, warn io.Writer) (files, unmatched []string, err error) {
+	for _, pattern := range args {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+		}
+		switch {
+		case len(matches) > 0:
+			files = append(files, matches...)
+		case strings.ContainsAny(pattern, "*?["):
+			fmt.Fprintf(warn, "Warning: no files match %s\n", pattern)
+			unmatched = append(unmatched, pattern)
+		default:
+			files = append(files, pattern)
+		}
+	}
+	return files, unmatched, nil
+}
+
+// optionsFunc returns the analyzer options for a file
+type optionsFunc func(file string) ([]analyzer.Option, error)
+
+// analyzerFunc returns the analyzer for a file
+type analyzerFunc func(file string) (*analyzer.Analyzer, error)
+
+// analyzerCache returns an analyzerFunc that builds one analyzer per
+// directory, since config files apply to whole directories. It is safe
+// for concurrent use.
+func analyzerCache(optsFor optionsFunc) analyzerFunc {
+	var mu sync.Mutex
+	analyzers := make(map[string]*analyzer.Analyzer)
+
+	return func(file string) (*analyzer.Analyzer, error) {
+		dir, err := filepath.Abs(filepath.Dir(file))
+		if err != nil {
+			return nil, err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if a, ok := analyzers[dir]; ok {
+			return a, nil
+		}
+		opts, err := optsFor(file)
+		if err != nil {
+			return nil, err
+		}
+		a := analyzer.New(opts...)
+		analyzers[dir] = a
+		return a, nil
+	}
+}
+
+// lintStats tallies the results of linting files
+type lintStats struct {
+	errors bool // a file had errors or could not be linted
+	issues int  // diagnostics reported
+	files  int  // files with at least one diagnostic
+}
+
+func (s *lintStats) add(result *analyzer.Result) {
+	if result.HasErrors() {
+		s.errors = true
+	}
+	if n := len(result.Diagnostics); n > 0 {
+		s.issues += n
+		s.files++
+	}
+}
+
+// printSummary writes a one-line summary for --pre-commit, or nothing
+// when no issues were found
+func (s lintStats) printSummary(w io.Writer, total int) {
+	if s.issues == 0 {
+		return
+	}
+	fmt.Fprintf(w, "keel: %d issue(s) in %d of %d file(s)\n", s.issues, s.files, total)
+}
+
+// printReportSummary writes the line shown on stdout when the report
+// went to a file with --output-file
+func (s lintStats) printReportSummary(w io.Writer, total int, format reporter.Format, path string) {
+	if s.issues == 0 {
+		fmt.Fprintf(w, "keel: no issues in %d file(s), %s report written to %s\n", total, format, path)
+		return
+	}
+	fmt.Fprintf(w, "keel: %d issue(s) in %d of %d file(s), %s report written to %s\n", s.issues, s.files, total, format, path)
+}
+
+// closeReport closes the --output-file, if any, reporting a failed write
+func closeReport(f *os.File) error {
+	if f == nil {
+		return nil
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", f.Name(), err)
+	}
+	return nil
+}
+
 // lintFilesSequential processes files one at a time
-func lintFilesSequential(files []string, opts []analyzer.Option, rep reporter.Reporter) bool {
-	var hasErrors bool
+func lintFilesSequential(files []string, analyzerFor analyzerFunc, rep reporter.Reporter, cp *cache.CachedParser, fixOut io.Writer) lintStats {
+	var stats lintStats
 
 	for _, file := range files {
-		content, err := os.ReadFile(file)
+		content, err := parser.ReadFile(file)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", file, err)
-			hasErrors = true
+			stats.errors = true
 			continue
 		}
 
-		a := analyzer.New(opts...)
-		result, parseErrors := a.AnalyzeSource(string(content), file)
-
-		for _, pe := range parseErrors {
-			fmt.Fprintf(os.Stderr, "Parse error in %s: %s\n", file, pe)
+		a, err := analyzerFor(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config for %s: %v\n", file, err)
+			stats.errors = true
+			continue
 		}
 
-		if err := rep.Report(result, string(content)); err != nil {
+		result := analyzeSource(a, cp, content, file)
+
+		if err := rep.Report(result, content); err != nil {
 			fmt.Fprintf(os.Stderr, "Error reporting %s: %v\n", file, err)
 		}
 
-		if result.HasErrors() {
-			hasErrors = true
+		if fixOut != nil {
+			fmt.Fprint(fixOut, fixesDiff(file, content, result.Diagnostics))
 		}
+
+		stats.add(result)
 	}
 
-	return hasErrors
+	return stats
 }
 
 // lintFilesParallel processes files concurrently
-func lintFilesParallel(files []string, opts []analyzer.Option, rep reporter.Reporter, workers int) bool {
+func lintFilesParallel(files []string, analyzerFor analyzerFunc, rep reporter.Reporter, workers int, cp *cache.CachedParser, fixOut io.Writer) lintStats {
 	type lintResult struct {
-		result      *analyzer.Result
-		content     string
-		parseErrors []string
+		result  *analyzer.Result
+		content string
+		fixes   string
 	}
 
 	p := parallel.New(parallel.WithWorkers(workers))
 	results := p.Process(context.Background(), files, func(ctx context.Context, file string) (interface{}, error) {
-		content, err := os.ReadFile(file)
+		content, err := parser.ReadFile(file)
 		if err != nil {
 			return nil, err
 		}
 
-		a := analyzer.New(opts...)
-		result, parseErrors := a.AnalyzeSource(string(content), file)
+		a, err := analyzerFor(file)
+		if err != nil {
+			return nil, fmt.Errorf("loading config: %w", err)
+		}
 
-		var errStrs []string
-		for _, pe := range parseErrors {
-			errStrs = append(errStrs, pe.Error())
+		result := analyzeSource(a, cp, content, file)
+
+		var fixes string
+		if fixOut != nil {
+			fixes = fixesDiff(file, content, result.Diagnostics)
 		}
 
 		return &lintResult{
-			result:      result,
-			content:     string(content),
-			parseErrors: errStrs,
+			result:  result,
+			content: content,
+			fixes:   fixes,
 		}, nil
 	})
 
-	var hasErrors bool
+	var stats lintStats
 	for _, r := range results {
 		if r.Error != nil {
 			fmt.Fprintf(os.Stderr, "Error processing %s: %v\n", r.Filename, r.Error)
-			hasErrors = true
+			stats.errors = true
 			continue
 		}
 
 		lr := r.Result.(*lintResult)
-		for _, pe := range lr.parseErrors {
-			fmt.Fprintf(os.Stderr, "Parse error in %s: %s\n", r.Filename, pe)
-		}
-
 		if err := rep.Report(lr.result, lr.content); err != nil {
 			fmt.Fprintf(os.Stderr, "Error reporting %s: %v\n", r.Filename, err)
 		}
 
-		if lr.result.HasErrors() {
+		if fixOut != nil {
+			fmt.Fprint(fixOut, lr.fixes)
+		}
+
+		stats.add(lr.result)
+	}
+
+	return stats
+}
+
+// batchFile is a file in a --batch-json payload
+type batchFile struct {
+	Filename string `json:"filename"`
+	Content  string `json:"content"`
+}
+
+// batchResult is the result for one file of a --batch-json payload
+type batchResult struct {
+	reporter.JSONOutput
+	ParseErrors []string `json:"parse_errors,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// lintBatchJSON lints the files in a JSON payload read from in and writes
+// their results to out as a JSON array, in input order. It reports whether
+// any file has errors or could not be linted.
+func lintBatchJSON(in io.Reader, out io.Writer, optsFor optionsFunc, workers int) (bool, error) {
+	var batch []batchFile
+	if err := json.NewDecoder(in).Decode(&batch); err != nil {
+		return false, fmt.Errorf("reading batch: %w", err)
+	}
+	for i, f := range batch {
+		if f.Filename == "" {
+			return false, fmt.Errorf("reading batch: file %d has no filename", i)
+		}
+	}
+
+	// Files are keyed by index, since filenames in a batch need not be unique
+	keys := make([]string, len(batch))
+	for i := range batch {
+		keys[i] = strconv.Itoa(i)
+	}
+
+	p := parallel.New(parallel.WithWorkers(workers))
+	results := p.Process(context.Background(), keys, func(ctx context.Context, key string) (interface{}, error) {
+		i, _ := strconv.Atoi(key)
+		f := batch[i]
+
+		opts, err := optsFor(f.Filename)
+		if err != nil {
+			return nil, fmt.Errorf("loading config: %w", err)
+		}
+
+		result, parseErrors := analyzer.New(opts...).AnalyzeSource(f.Content, f.Filename)
+
+		br := &batchResult{JSONOutput: reporter.NewJSONOutput(result)}
+		for _, pe := range parseErrors {
+			br.ParseErrors = append(br.ParseErrors, pe.Error())
+		}
+		return br, nil
+	})
+
+	var hasErrors bool
+	output := make([]*batchResult, len(results))
+	for i, r := range results {
+		if r.Error != nil {
+			output[i] = &batchResult{
+				JSONOutput: reporter.NewJSONOutput(&analyzer.Result{Filename: batch[i].Filename}),
+				Error:      r.Error.Error(),
+			}
 			hasErrors = true
+			continue
 		}
+
+		output[i] = r.Result.(*batchResult)
+		if output[i].Summary.Errors > 0 {
+			hasErrors = true
+		}
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return hasErrors, encoder.Encode(output)
+}
+
+// analyzeSource analyzes content, parsing through the AST cache when one
+// is given. Parse errors are reported as PARSE diagnostics.
+func analyzeSource(a *analyzer.Analyzer, cp *cache.CachedParser, content, file string) *analyzer.Result {
+	if cp == nil {
+		result, _ := a.AnalyzeSource(content, file)
+		return result
+	}
+	df, parseErrors := cp.Parse(file, content)
+	return a.AnalyzeParsed(df, file, content, parseErrors)
+}
+
+// fixesDiff returns a unified diff of the fixes the optimizer would apply
+// for diags, or "" when nothing would change. The source is parsed afresh
+// because transforms modify the AST, which may be shared by the cache.
+func fixesDiff(file, content string, diags []analyzer.Diagnostic) string {
+	df, parseErrors := parser.Parse(content)
+	if len(parseErrors) > 0 {
+		return ""
 	}
 
-	return hasErrors
+	opt := optimizer.New(optimizer.WithTransforms(optimizer.AllTransforms()...))
+	if !opt.Optimize(df, diags).HasChanges() {
+		return ""
+	}
+
+	fixed := optimizer.NewRewriter().Rewrite(df)
+	return formatter.Diff(file, content, fixed)
 }
 
 func parseSeverity(s string) analyzer.Severity {
-	switch s {
-	case "error":
-		return analyzer.SeverityError
-	case "warning":
-		return analyzer.SeverityWarning
-	case "info":
-		return analyzer.SeverityInfo
-	case "hint":
-		return analyzer.SeverityHint
-	default:
-		return analyzer.SeverityWarning
+	sev, _ := analyzer.ParseSeverity(s)
+	return sev
+}
+
+// formatNames lists the registered output formats for flag help
+func formatNames() string {
+	var names []string
+	for _, f := range reporter.Formats() {
+		names = append(names, string(f))
 	}
+	return strings.Join(names, "|")
 }
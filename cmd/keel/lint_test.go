@@ -0,0 +1,422 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/cache"
+	"github.com/HueCodes/keel/internal/reporter"
+)
+
+func TestFixesDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		changed bool
+	}{
+		{
+			name:    "reorderable copy",
+			source:  "FROM node:20.11-alpine\nWORKDIR /app\nCOPY . .\nRUN npm ci\n",
+			changed: true,
+		},
+		{
+			name:    "clean file",
+			source:  "FROM alpine:3.19\nCMD [\"echo\", \"hi\"]\n",
+			changed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := analyzer.New(analyzer.WithRules(allRules()...))
+			result, _ := a.AnalyzeSource(tt.source, "Dockerfile")
+
+			diff := fixesDiff("Dockerfile", tt.source, result.Diagnostics)
+			if tt.changed && !strings.Contains(diff, "@@") {
+				t.Errorf("expected a diff, got %q", diff)
+			}
+			if !tt.changed && diff != "" {
+				t.Errorf("expected no diff, got %q", diff)
+			}
+		})
+	}
+}
+
+func TestExpandFileArgs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"Dockerfile", "Dockerfile.prod"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("FROM alpine:3.19\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var warn bytes.Buffer
+	files, unmatched, err := expandFileArgs([]string{
+		filepath.Join(dir, "Dockerfile*"),
+		filepath.Join(dir, "services", "*", "Dockerfile"),
+		filepath.Join(dir, "Dockerfile.missing"),
+	}, &warn)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(files) != 3 || files[2] != filepath.Join(dir, "Dockerfile.missing") {
+		t.Errorf("expected both matches and the literal path, got %v", files)
+	}
+	if len(unmatched) != 1 || unmatched[0] != filepath.Join(dir, "services", "*", "Dockerfile") {
+		t.Errorf("expected the services glob to be unmatched, got %v", unmatched)
+	}
+	if !strings.Contains(warn.String(), "no files match") {
+		t.Errorf("expected a warning for the unmatched glob, got %q", warn.String())
+	}
+}
+
+func TestLint_UnmatchedGlob(t *testing.T) {
+	pattern := filepath.Join(t.TempDir(), "*", "Dockerfile")
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{
+			name:    "without flag",
+			args:    []string{pattern},
+			wantErr: true,
+		},
+		{
+			name:    "with flag",
+			args:    []string{"--exit-zero-on-unmatched-glob", pattern},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stderr bytes.Buffer
+			cmd := lintCmd()
+			cmd.SetErr(&stderr)
+			cmd.SetArgs(tt.args)
+			cmd.SilenceUsage = true
+
+			err := cmd.Execute()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !strings.Contains(stderr.String(), "Warning: no files match "+pattern) {
+				t.Errorf("expected an unmatched glob warning, got %q", stderr.String())
+			}
+		})
+	}
+}
+
+func TestLintBatchJSON(t *testing.T) {
+	payload := `[
+  {"filename": "api/Dockerfile", "content": "FROM alpine:3.19\nMAINTAINER someone\nUSER app\n"},
+  {"filename": "web/Dockerfile", "content": "FROM node\nUSER node\n"}
+]`
+	optsFor := func(file string) ([]analyzer.Option, error) {
+		return []analyzer.Option{analyzer.WithRules(allRules()...), analyzer.WithMinSeverity(analyzer.SeverityInfo)}, nil
+	}
+
+	var out bytes.Buffer
+	hasErrors, err := lintBatchJSON(strings.NewReader(payload), &out, optsFor, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hasErrors {
+		t.Error("expected the unpinned node image to be reported as an error")
+	}
+
+	var results []struct {
+		Filename    string `json:"filename"`
+		Diagnostics []struct {
+			Rule string `json:"rule"`
+		} `json:"diagnostics"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &results); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out.String())
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	has := func(i int, rule string) bool {
+		for _, d := range results[i].Diagnostics {
+			if d.Rule == rule {
+				return true
+			}
+		}
+		return false
+	}
+	if results[0].Filename != "api/Dockerfile" || !has(0, "BP004") {
+		t.Errorf("unexpected first result %+v", results[0])
+	}
+	if results[1].Filename != "web/Dockerfile" || !has(1, "SEC003") {
+		t.Errorf("unexpected second result %+v", results[1])
+	}
+}
+
+func TestLintBatchJSON_Invalid(t *testing.T) {
+	optsFor := func(file string) ([]analyzer.Option, error) { return nil, nil }
+
+	for _, payload := range []string{`{"filename": "Dockerfile"}`, `[{"content": "FROM alpine\n"}]`} {
+		if _, err := lintBatchJSON(strings.NewReader(payload), io.Discard, optsFor, 1); err == nil {
+			t.Errorf("expected an error for %s", payload)
+		}
+	}
+}
+
+func TestLint_PreCommitQuietSuccess(t *testing.T) {
+	dir := t.TempDir()
+	var files []string
+	for _, name := range []string{"Dockerfile", "Dockerfile.api"} {
+		path := filepath.Join(dir, name)
+		source := "FROM alpine:3.20\nRUN adduser -D app\nUSER app\nHEALTHCHECK CMD true\nCMD [\"sh\"]\n"
+		if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, path)
+	}
+
+	var stdout bytes.Buffer
+	cmd := lintCmd()
+	cmd.SetOut(&stdout)
+	cmd.SetArgs(append([]string{"--pre-commit"}, files...))
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("expected no output for clean files, got:\n%s", stdout.String())
+	}
+}
+
+func TestLint_PatternWarningsToCommandStderr(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Dockerfile")
+	if err := os.WriteFile(path, []byte("FROM alpine:3.20\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stderr bytes.Buffer
+	cmd := lintCmd()
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{"--only", "NOPE*", "--tag", "no-such-tag", path})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"NOPE* does not match any rule", "no rule has tag no-such-tag"} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("expected %q on the command's stderr, got:\n%s", want, stderr.String())
+		}
+	}
+}
+
+func TestLint_ParseErrorDiagnostic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Dockerfile")
+	if err := os.WriteFile(path, []byte("RUN true\nFROM alpine:3.20\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	optsFor := func(file string) ([]analyzer.Option, error) {
+		return []analyzer.Option{analyzer.WithRules(allRules()...)}, nil
+	}
+
+	tests := []struct {
+		format string
+		cached bool
+		want   string
+	}{
+		{"json", false, `"rule": "PARSE"`},
+		{"json", true, `"rule": "PARSE"`},
+		{"sarif", false, `"ruleId": "PARSE"`},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		var cp *cache.CachedParser
+		if tt.cached {
+			cp = cache.NewCachedParser(cache.NewASTCache())
+		}
+		rep := reporter.New(reporter.Format(tt.format), &out)
+
+		stats := lintFilesSequential([]string{path}, analyzerCache(optsFor), rep, cp, nil)
+		if !stats.errors {
+			t.Errorf("%s: expected the parse error to fail the lint", tt.format)
+		}
+		if !strings.Contains(out.String(), tt.want) || !strings.Contains(out.String(), "instruction outside of build stage") {
+			t.Errorf("%s: expected a PARSE diagnostic in output, got:\n%s", tt.format, out.String())
+		}
+	}
+}
+
+func TestLint_FixableOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Dockerfile")
+	// BP005 has a transform, BP030 doesn't
+	source := "FROM alpine:3.20\nRUN adduser -D app\nUSER app\nWORKDIR app\nENV A=x # note\nHEALTHCHECK CMD true\nCMD [\"sh\"]\n"
+	if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rulesReported := func(args ...string) []string {
+		var stdout bytes.Buffer
+		cmd := lintCmd()
+		cmd.SetOut(&stdout)
+		cmd.SetArgs(append(args, "-o", "json", path))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var out struct {
+			Diagnostics []struct {
+				Rule string `json:"rule"`
+			} `json:"diagnostics"`
+		}
+		if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+			t.Fatalf("invalid JSON output: %v\n%s", err, stdout.String())
+		}
+		var ids []string
+		for _, d := range out.Diagnostics {
+			ids = append(ids, d.Rule)
+		}
+		return ids
+	}
+
+	if got := rulesReported(); strings.Join(got, ",") != "BP005,BP030" {
+		t.Fatalf("expected BP005 and BP030 without the flag, got %v", got)
+	}
+	if got := rulesReported("--fixable-only"); strings.Join(got, ",") != "BP005" {
+		t.Errorf("expected only BP005 with --fixable-only, got %v", got)
+	}
+}
+
+func TestLint_OutputFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Dockerfile")
+	source := "FROM alpine:3.20\nRUN adduser -D app\nUSER app\nWORKDIR app\nHEALTHCHECK CMD true\nCMD [\"sh\"]\n"
+	if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	report := filepath.Join(dir, "results.sarif")
+
+	var stdout bytes.Buffer
+	cmd := lintCmd()
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{"-o", "sarif", "--output-file", report, path})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sarif struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID string `json:"ruleId"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &sarif); err != nil {
+		t.Fatalf("invalid SARIF in output file: %v\n%s", err, data)
+	}
+	if sarif.Version != "2.1.0" || len(sarif.Runs) != 1 || len(sarif.Runs[0].Results) != 1 || sarif.Runs[0].Results[0].RuleID != "BP005" {
+		t.Errorf("expected one BP005 result, got %+v", sarif)
+	}
+
+	expected := "keel: 1 issue(s) in 1 of 1 file(s), sarif report written to " + report + "\n"
+	if stdout.String() != expected {
+		t.Errorf("expected summary %q on stdout, got %q", expected, stdout.String())
+	}
+}
+
+func TestLint_OutputFileError(t *testing.T) {
+	cmd := lintCmd()
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--output-file", filepath.Join(t.TempDir(), "missing", "out.json"), "Dockerfile"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "failed to create output file") {
+		t.Errorf("expected an error creating the output file, got %v", err)
+	}
+}
+
+func TestLintStats_Summary(t *testing.T) {
+	var stats lintStats
+	stats.add(&analyzer.Result{Filename: "Dockerfile"})
+	stats.add(&analyzer.Result{Filename: "api/Dockerfile", Diagnostics: []analyzer.Diagnostic{
+		{Rule: "SEC003", Severity: analyzer.SeverityError},
+		{Rule: "PERF003", Severity: analyzer.SeverityWarning},
+	}})
+
+	var buf bytes.Buffer
+	stats.printSummary(&buf, 2)
+	if buf.String() != "keel: 2 issue(s) in 1 of 2 file(s)\n" {
+		t.Errorf("unexpected summary %q", buf.String())
+	}
+	if !stats.errors {
+		t.Error("expected errors to be recorded")
+	}
+}
+
+func TestAnalyzerCache(t *testing.T) {
+	calls := 0
+	analyzerFor := analyzerCache(func(file string) ([]analyzer.Option, error) {
+		calls++
+		return nil, nil
+	})
+
+	dir := t.TempDir()
+	a1, _ := analyzerFor(filepath.Join(dir, "Dockerfile"))
+	a2, _ := analyzerFor(filepath.Join(dir, "Dockerfile.prod"))
+	a3, _ := analyzerFor(filepath.Join(dir, "api", "Dockerfile"))
+	if a1 != a2 || a1 == a3 || calls != 2 {
+		t.Errorf("expected one analyzer per directory, got %d option lookups", calls)
+	}
+}
+
+func TestLint_ProfileRelaxed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Dockerfile")
+	source := "FROM alpine:3.20\nRUN adduser -D app\nUSER app\nWORKDIR app\nENV A=x # note\nHEALTHCHECK CMD true\nCMD [\"sh\"]\n"
+	if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout bytes.Buffer
+	cmd := lintCmd()
+	cmd.SetOut(&stdout)
+	// An explicit --severity beats the profile's, so only the disabled
+	// rules keep BP005 and BP030 from being reported
+	cmd.SetArgs([]string{"--profile", "relaxed", "--severity", "hint", "-o", "json", path})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out struct {
+		Diagnostics []struct {
+			Rule string `json:"rule"`
+		} `json:"diagnostics"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout.String())
+	}
+	for _, d := range out.Diagnostics {
+		if !strings.HasPrefix(d.Rule, "SEC") {
+			t.Errorf("expected only security rules with --profile relaxed, got %s", d.Rule)
+		}
+	}
+}
+
+func TestLint_UnknownProfile(t *testing.T) {
+	cmd := lintCmd()
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--profile", "lenient", filepath.Join(t.TempDir(), "Dockerfile")})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), `unknown profile "lenient"`) {
+		t.Errorf("expected an unknown profile error, got %v", err)
+	}
+}
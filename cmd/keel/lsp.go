@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/lsp"
+)
+
+func lspCmd() *cobra.Command {
+	var severity string
+
+	cmd := &cobra.Command{
+		Use:   "lsp",
+		Short: "Run the Language Server Protocol server over stdio",
+		Long: `Run a Language Server Protocol server over stdin/stdout.
+
+Diagnostics are published when documents are opened or changed, and
+textDocument/formatting is answered using the formatter.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			server := lsp.NewServer(os.Stdin, os.Stdout,
+				lsp.WithVersion(version),
+				lsp.WithAnalyzerOptions(
+					analyzer.WithRules(allRules()...),
+					analyzer.WithMinSeverity(parseSeverity(severity)),
+				),
+			)
+			return server.Serve()
+		},
+	}
+
+	cmd.Flags().StringVar(&severity, "severity", "hint", "Minimum severity: error|warning|info|hint")
+
+	return cmd
+}
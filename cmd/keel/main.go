@@ -27,11 +27,13 @@ automatically fix many issues and rewrite Dockerfiles.`,
 		fmtCmd(),
 		explainCmd(),
 		initCmd(),
+		configCmd(),
+		lspCmd(),
 	)
 
 	// Global flags
 	rootCmd.PersistentFlags().StringP("config", "c", "", "Config file path (default .keel.yaml)")
-	rootCmd.PersistentFlags().Bool("no-color", false, "Disable colored output")
+	rootCmd.PersistentFlags().Bool("no-color", false, "Disable colored output (default when stdout is not a terminal)")
 	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Only output errors")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Show additional context")
 
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/rules/bestpractice"
+	"github.com/HueCodes/keel/internal/rules/performance"
+	"github.com/HueCodes/keel/internal/rules/security"
+	"github.com/HueCodes/keel/internal/rules/style"
+)
+
+// allRules collects the rules from every rule package, followed by any
+// custom rules added with analyzer.RegisterRule
+func allRules() []analyzer.Rule {
+	var rules []analyzer.Rule
+	for _, r := range security.All() {
+		rules = append(rules, r)
+	}
+	for _, r := range performance.All() {
+		rules = append(rules, r)
+	}
+	for _, r := range bestpractice.All() {
+		rules = append(rules, r)
+	}
+	for _, r := range style.All() {
+		rules = append(rules, r)
+	}
+	return append(rules, analyzer.RegisteredRules()...)
+}
+
+// expandRulePatterns expands glob patterns such as SEC* or PERF00[13]
+// into the IDs of the matching rules. Matching is case-insensitive. A
+// pattern that matches nothing is kept as is, so it selects no rules,
+// and a warning is written to warn.
+func expandRulePatterns(patterns []string, rules []analyzer.Rule, warn io.Writer) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	add := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	for _, pattern := range patterns {
+		pattern = strings.ToUpper(pattern)
+		if pattern == analyzer.ParseRuleID || pattern == analyzer.TimeoutRuleID {
+			add(pattern)
+			continue
+		}
+		matched := false
+		for _, r := range rules {
+			if ok, _ := path.Match(pattern, r.ID()); ok {
+				add(r.ID())
+				matched = true
+			}
+		}
+		if !matched {
+			fmt.Fprintf(warn, "Warning: %s does not match any rule\n", pattern)
+			add(pattern)
+		}
+	}
+	return ids
+}
+
+// normalizeTags lowercases tags and writes a warning to warn for each tag
+// no rule carries
+func normalizeTags(tags []string, rules []analyzer.Rule, warn io.Writer) []string {
+	known := make(map[string]bool)
+	for _, r := range rules {
+		for _, tag := range analyzer.Tags(r) {
+			known[tag] = true
+		}
+	}
+
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(tag)
+		if !known[tag] {
+			fmt.Fprintf(warn, "Warning: no rule has tag %s\n", tag)
+		}
+		out = append(out, tag)
+	}
+	return out
+}
+
+// ruleDescriptions maps each rule's ID to its description, for rules
+// that have one
+func ruleDescriptions(rules []analyzer.Rule) map[string]string {
+	descriptions := make(map[string]string)
+	for _, r := range rules {
+		if d, ok := r.(interface{ Description() string }); ok && d.Description() != "" {
+			descriptions[r.ID()] = d.Description()
+		}
+	}
+	return descriptions
+}
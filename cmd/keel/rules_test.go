@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/reporter"
+	"github.com/HueCodes/keel/internal/rules/security"
+)
+
+func TestExpandRulePatterns(t *testing.T) {
+	rules := allRules()
+
+	var security []string
+	for _, r := range rules {
+		if strings.HasPrefix(r.ID(), "SEC") {
+			security = append(security, r.ID())
+		}
+	}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		expected []string
+		warns    bool
+	}{
+		{
+			name:     "prefix glob",
+			patterns: []string{"SEC*"},
+			expected: security,
+		},
+		{
+			name:     "character class",
+			patterns: []string{"PERF00[13]"},
+			expected: []string{"PERF001", "PERF003"},
+		},
+		{
+			name:     "exact IDs",
+			patterns: []string{"BP004", "sec001"},
+			expected: []string{"BP004", "SEC001"},
+		},
+		{
+			name:     "overlapping patterns",
+			patterns: []string{"SEC001", "SEC00[12]"},
+			expected: []string{"SEC001", "SEC002"},
+		},
+		{
+			name:     "matches nothing",
+			patterns: []string{"NOPE*"},
+			expected: []string{"NOPE*"},
+			warns:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var warn bytes.Buffer
+			got := expandRulePatterns(tt.patterns, rules, &warn)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+			if tt.warns != (warn.Len() > 0) {
+				t.Errorf("unexpected warning output %q", warn.String())
+			}
+		})
+	}
+}
+
+func TestConfigDump_IgnoreGlob(t *testing.T) {
+	cmd := configCmd()
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs([]string{"dump", "--ignore", "SEC*,NOPE*"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("a pattern matching nothing should not be an error: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "  SEC001:\n    enabled: false\n") {
+		t.Errorf("expected SEC001 to be disabled:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "  BP001:\n    enabled: true\n") {
+		t.Errorf("expected BP001 to stay enabled:\n%s", out.String())
+	}
+	if !strings.Contains(errOut.String(), "NOPE*") {
+		t.Errorf("expected a warning about NOPE*, got %q", errOut.String())
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	var warn bytes.Buffer
+	got := normalizeTags([]string{"Supply-Chain", "nope"}, allRules(), &warn)
+	if !reflect.DeepEqual(got, []string{"supply-chain", "nope"}) {
+		t.Errorf("unexpected tags %v", got)
+	}
+	if warn.String() != "Warning: no rule has tag nope\n" {
+		t.Errorf("unexpected warning output %q", warn.String())
+	}
+}
+
+func TestLint_TagFilter(t *testing.T) {
+	source := "FROM node\nMAINTAINER someone\nRUN curl -s https://example.com/install.sh | sh\n"
+
+	tests := []struct {
+		tags     []string
+		expected []string
+	}{
+		{[]string{"supply-chain"}, []string{"SEC003", "SEC004"}},
+		{[]string{"reproducibility"}, []string{"SEC003"}},
+		{[]string{"cis"}, []string{"SEC001", "SEC008"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.tags, ","), func(t *testing.T) {
+			a := analyzer.New(
+				analyzer.WithRules(allRules()...),
+				analyzer.WithMinSeverity(analyzer.SeverityHint),
+				analyzer.WithTags(tt.tags...),
+			)
+			result, _ := a.AnalyzeSource(source, "Dockerfile")
+
+			seen := make(map[string]bool)
+			var got []string
+			for _, d := range result.Diagnostics {
+				if !seen[d.Rule] {
+					seen[d.Rule] = true
+					got = append(got, d.Rule)
+				}
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected rules %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLint_Explain(t *testing.T) {
+	source := "FROM alpine:3.19\nRUN apk add curl\n"
+	a := analyzer.New(
+		analyzer.WithRules(allRules()...),
+		analyzer.WithEnabled("SEC001"),
+	)
+	result, _ := a.AnalyzeSource(source, "Dockerfile")
+
+	var buf bytes.Buffer
+	rep := reporter.New(reporter.FormatTerminal, &buf,
+		reporter.WithColors(false),
+		reporter.WithWidth(0),
+		reporter.WithDescriptions(ruleDescriptions(allRules())),
+	)
+	if err := rep.Report(result, source); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "= explain: " + (&security.SEC001RootUser{}).Description()
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("expected %q in output, got:\n%s", want, buf.String())
+	}
+}
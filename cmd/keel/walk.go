@@ -0,0 +1,185 @@
+package main
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/config"
+)
+
+// defaultDockerfilePatterns are the file names lint --recursive looks for
+var defaultDockerfilePatterns = []string{"Dockerfile", "*.Dockerfile", "Dockerfile.*"}
+
+// skipDirs are directories lint --recursive never descends into
+var skipDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+}
+
+// walkOptions selects the files findDockerfiles returns
+type walkOptions struct {
+	// Names are globs matched against file names
+	Names []string
+
+	// Include and Exclude are globs matched against paths relative to the
+	// root, where ** matches any number of directories. A glob without a
+	// slash is also matched against the file name alone.
+	Include []string
+	Exclude []string
+}
+
+// findDockerfiles walks root and returns the files whose names match
+// opts.Names and whose paths pass the include and exclude globs, in
+// lexical order
+func findDockerfiles(root string, opts walkOptions) ([]string, error) {
+	var files []string
+
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if p != root && (skipDirs[d.Name()] || matchAnyGlob(opts.Exclude, rel)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !matchAnyName(opts.Names, d.Name()) {
+			return nil
+		}
+		if len(opts.Include) > 0 && !matchAnyGlob(opts.Include, rel) {
+			return nil
+		}
+		if matchAnyGlob(opts.Exclude, rel) {
+			return nil
+		}
+
+		files = append(files, p)
+		return nil
+	})
+
+	return files, err
+}
+
+// withoutIgnored drops the files matching the ignore_paths of their config
+func withoutIgnored(files []string, resolver *config.Resolver) ([]string, error) {
+	kept := files[:0]
+	for _, file := range files {
+		cfg, err := resolver.ForFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if !ignoredByConfig(cfg, file) {
+			kept = append(kept, file)
+		}
+	}
+	return kept, nil
+}
+
+// ignoredByConfig reports whether file matches one of cfg.IgnorePaths,
+// which are relative to cfg.IgnoreDir
+func ignoredByConfig(cfg *config.Config, file string) bool {
+	if len(cfg.IgnorePaths) == 0 {
+		return false
+	}
+	dir, err := filepath.Abs(cfg.IgnoreDir)
+	if err != nil {
+		return false
+	}
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(dir, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	return matchAnyGlob(cfg.IgnorePaths, filepath.ToSlash(rel))
+}
+
+func matchAnyName(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAnyGlob(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob reports whether the slash-separated path rel matches pattern,
+// where a ** segment matches zero or more directories
+func matchGlob(pattern, rel string) bool {
+	pattern = strings.TrimPrefix(pattern, "./")
+	if !strings.Contains(pattern, "/") {
+		if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+			return true
+		}
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(rel, "/"))
+}
+
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Try every number of directories for the **
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(pattern[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
+// splitRecursiveArgs separates the directories to walk from the other file
+// arguments. With recursive every argument is a directory, defaulting to
+// the current one; otherwise only arguments such as ./... or dir/... are.
+func splitRecursiveArgs(args []string, recursive bool) (roots, rest []string) {
+	if recursive {
+		if len(args) == 0 {
+			return []string{"."}, nil
+		}
+		return args, nil
+	}
+
+	for _, arg := range args {
+		dir, ok := strings.CutSuffix(arg, "...")
+		switch {
+		case ok && dir == "":
+			roots = append(roots, ".")
+		case ok && strings.HasSuffix(dir, "/"):
+			roots = append(roots, filepath.Clean(dir))
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return roots, rest
+}
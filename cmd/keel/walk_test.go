@@ -0,0 +1,182 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/HueCodes/keel/internal/config"
+)
+
+func TestFindDockerfiles(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{
+		"Dockerfile",
+		"README.md",
+		"api/Dockerfile",
+		"api/api.Dockerfile",
+		"web/Dockerfile.prod",
+		"web/src/Dockerfile",
+		"test/Dockerfile",
+		".git/Dockerfile",
+		"node_modules/pkg/Dockerfile",
+		"docs/Dockerfile.md/notes",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("FROM alpine:3.19\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		opts     walkOptions
+		expected []string
+	}{
+		{
+			name: "default patterns",
+			expected: []string{
+				"Dockerfile", "api/Dockerfile", "api/api.Dockerfile",
+				"test/Dockerfile", "web/Dockerfile.prod", "web/src/Dockerfile",
+			},
+		},
+		{
+			name:     "custom pattern",
+			opts:     walkOptions{Names: []string{"*.Dockerfile"}},
+			expected: []string{"api/api.Dockerfile"},
+		},
+		{
+			name:     "include",
+			opts:     walkOptions{Include: []string{"web/**"}},
+			expected: []string{"web/Dockerfile.prod", "web/src/Dockerfile"},
+		},
+		{
+			name:     "include by file name",
+			opts:     walkOptions{Include: []string{"*.prod"}},
+			expected: []string{"web/Dockerfile.prod"},
+		},
+		{
+			name:     "exclude directory",
+			opts:     walkOptions{Exclude: []string{"test/**", "web/src"}},
+			expected: []string{"Dockerfile", "api/Dockerfile", "api/api.Dockerfile", "web/Dockerfile.prod"},
+		},
+		{
+			name:     "include and exclude",
+			opts:     walkOptions{Include: []string{"**/Dockerfile"}, Exclude: []string{"test"}},
+			expected: []string{"Dockerfile", "api/Dockerfile", "web/src/Dockerfile"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.opts.Names == nil {
+				tt.opts.Names = defaultDockerfilePatterns
+			}
+			files, err := findDockerfiles(root, tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got []string
+			for _, f := range files {
+				rel, _ := filepath.Rel(root, f)
+				got = append(got, filepath.ToSlash(rel))
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestWithoutIgnored(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		".keel.yaml":            "root: true\nignore_paths:\n  - \"test/**\"\n",
+		"Dockerfile":            "FROM alpine:3.19\n",
+		"test/Dockerfile":       "FROM alpine:3.19\n",
+		"api/Dockerfile":        "FROM alpine:3.19\n",
+		"api/.keel.yaml":        "ignore_paths: [legacy/**]\n",
+		"api/legacy/Dockerfile": "FROM alpine:3.19\n",
+		"api/test/Dockerfile":   "FROM alpine:3.19\n",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := findDockerfiles(root, walkOptions{Names: defaultDockerfilePatterns})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files, err = withoutIgnored(files, config.NewResolver(""))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The nearer config's ignore_paths replace the root's and are
+	// relative to api/
+	var got []string
+	for _, f := range files {
+		rel, _ := filepath.Rel(root, f)
+		got = append(got, filepath.ToSlash(rel))
+	}
+	expected := []string{"Dockerfile", "api/Dockerfile", "api/test/Dockerfile"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{"**/Dockerfile", "Dockerfile", true},
+		{"**/Dockerfile", "a/b/Dockerfile", true},
+		{"a/**/Dockerfile", "a/Dockerfile", true},
+		{"a/**/Dockerfile", "b/a/Dockerfile", false},
+		{"test/**", "test", true},
+		{"test/**", "tests/Dockerfile", false},
+		{"./web/*", "web/Dockerfile", true},
+		{"*.prod", "web/Dockerfile.prod", true},
+		{"web/*", "web/src/Dockerfile", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.path); got != tt.expected {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestSplitRecursiveArgs(t *testing.T) {
+	tests := []struct {
+		args      []string
+		recursive bool
+		roots     []string
+		rest      []string
+	}{
+		{nil, true, []string{"."}, nil},
+		{[]string{"services"}, true, []string{"services"}, nil},
+		{[]string{"./...", "Dockerfile"}, false, []string{"."}, []string{"Dockerfile"}},
+		{[]string{"services/..."}, false, []string{"services"}, nil},
+		{[]string{"Dockerfile..."}, false, nil, []string{"Dockerfile..."}},
+	}
+
+	for _, tt := range tests {
+		roots, rest := splitRecursiveArgs(tt.args, tt.recursive)
+		if !reflect.DeepEqual(roots, tt.roots) || !reflect.DeepEqual(rest, tt.rest) {
+			t.Errorf("splitRecursiveArgs(%q, %v) = %q, %q, want %q, %q", tt.args, tt.recursive, roots, rest, tt.roots, tt.rest)
+		}
+	}
+}
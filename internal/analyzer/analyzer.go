@@ -1,10 +1,13 @@
 package analyzer
 
 import (
+	"context"
 	"runtime"
 	"sort"
 	"sync"
+	"time"
 
+	"github.com/HueCodes/keel/internal/lexer"
 	"github.com/HueCodes/keel/internal/parser"
 )
 
@@ -17,6 +20,15 @@ type Rule interface {
 	Check(df *parser.Dockerfile, ctx *RuleContext) []Diagnostic
 }
 
+// Tags returns the tags of a rule that has a Tags() []string method, such
+// as supply-chain or layers, and nil otherwise
+func Tags(rule Rule) []string {
+	if t, ok := rule.(interface{ Tags() []string }); ok {
+		return t.Tags()
+	}
+	return nil
+}
+
 // RuleContext provides context for rule checking
 type RuleContext struct {
 	Filename    string
@@ -30,10 +42,14 @@ type Analyzer struct {
 	rules         []Rule
 	enabled       map[string]bool
 	disabled      map[string]bool
+	tags          map[string]bool
 	minSeverity   Severity
 	config        map[string]map[string]interface{}
+	overrides     map[string]Severity
 	parallelRules bool
 	maxWorkers    int
+	keep          func(Diagnostic) bool
+	timeout       time.Duration
 }
 
 // Option is a function that configures an Analyzer
@@ -44,8 +60,10 @@ func New(opts ...Option) *Analyzer {
 	a := &Analyzer{
 		enabled:     make(map[string]bool),
 		disabled:    make(map[string]bool),
+		tags:        make(map[string]bool),
 		minSeverity: SeverityWarning,
 		config:      make(map[string]map[string]interface{}),
+		overrides:   make(map[string]Severity),
 	}
 	for _, opt := range opts {
 		opt(a)
@@ -78,6 +96,15 @@ func WithDisabled(ids ...string) Option {
 	}
 }
 
+// WithTags limits the analyzer to rules carrying at least one of the tags
+func WithTags(tags ...string) Option {
+	return func(a *Analyzer) {
+		for _, tag := range tags {
+			a.tags[tag] = true
+		}
+	}
+}
+
 // WithMinSeverity sets the minimum severity to report
 func WithMinSeverity(s Severity) Option {
 	return func(a *Analyzer) {
@@ -92,6 +119,25 @@ func WithRuleConfig(ruleID string, config map[string]interface{}) Option {
 	}
 }
 
+// WithSeverityOverride overrides the severity reported by specific rules.
+// Overrides are applied before the minimum severity filter.
+func WithSeverityOverride(overrides map[string]Severity) Option {
+	return func(a *Analyzer) {
+		for id, sev := range overrides {
+			a.overrides[id] = sev
+		}
+	}
+}
+
+// WithFilter drops the rule diagnostics for which keep returns false,
+// after severity overrides and the minimum severity are applied. PARSE
+// diagnostics are always kept.
+func WithFilter(keep func(Diagnostic) bool) Option {
+	return func(a *Analyzer) {
+		a.keep = keep
+	}
+}
+
 // WithParallelRules enables parallel rule execution
 func WithParallelRules(enabled bool) Option {
 	return func(a *Analyzer) {
@@ -106,8 +152,35 @@ func WithMaxWorkers(n int) Option {
 	}
 }
 
+// WithTimeout sets a time budget for analyzing each file, 0 for none. A
+// rule still running when the budget runs out, and any rule that hadn't
+// started, is reported with a TIMEOUT diagnostic instead of its results.
+// Go can't stop a running function, so an abandoned rule's Check keeps
+// running in the background until it returns, but the analysis doesn't
+// wait for it.
+func WithTimeout(d time.Duration) Option {
+	return func(a *Analyzer) {
+		a.timeout = d
+	}
+}
+
+// ParseRuleID is the rule ID reserved for diagnostics that report parse
+// errors, so that syntax errors reach every reporter
+const ParseRuleID = "PARSE"
+
+// TimeoutRuleID is the rule ID reserved for diagnostics that report a
+// rule that didn't finish within the WithTimeout budget
+const TimeoutRuleID = "TIMEOUT"
+
 // Analyze runs all enabled rules against the Dockerfile
 func (a *Analyzer) Analyze(df *parser.Dockerfile, filename, source string) *Result {
+	return a.AnalyzeParsed(df, filename, source, nil)
+}
+
+// AnalyzeParsed is Analyze for a Dockerfile parsed with errors. Each parse
+// error becomes an error-severity PARSE diagnostic among the rules'
+// diagnostics, unless PARSE is disabled.
+func (a *Analyzer) AnalyzeParsed(df *parser.Dockerfile, filename, source string, parseErrors []parser.ParseError) *Result {
 	sourceLines := splitLines(source)
 
 	// Filter rules that should run
@@ -118,20 +191,39 @@ func (a *Analyzer) Analyze(df *parser.Dockerfile, filename, source string) *Resu
 		}
 	}
 
+	budget := context.Background()
+	if a.timeout > 0 {
+		var cancel context.CancelFunc
+		budget, cancel = context.WithTimeout(budget, a.timeout)
+		defer cancel()
+	}
+
 	var diagnostics []Diagnostic
 
 	if a.parallelRules && len(rulesToRun) > 1 {
-		diagnostics = a.analyzeParallel(df, filename, source, sourceLines, rulesToRun)
+		diagnostics = a.analyzeParallel(budget, df, filename, source, sourceLines, rulesToRun)
 	} else {
-		diagnostics = a.analyzeSequential(df, filename, source, sourceLines, rulesToRun)
+		diagnostics = a.analyzeSequential(budget, df, filename, source, sourceLines, rulesToRun)
 	}
 
-	// Sort diagnostics by position
-	sort.Slice(diagnostics, func(i, j int) bool {
-		if diagnostics[i].Pos.Line != diagnostics[j].Pos.Line {
-			return diagnostics[i].Pos.Line < diagnostics[j].Pos.Line
+	if !a.disabled[ParseRuleID] {
+		diagnostics = append(diagnostics, parseDiagnostics(parseErrors, sourceLines)...)
+	}
+
+	// Sort diagnostics by position, then rule and message so the order
+	// doesn't depend on which rules finished first in parallel mode
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		di, dj := diagnostics[i], diagnostics[j]
+		if di.Pos.Line != dj.Pos.Line {
+			return di.Pos.Line < dj.Pos.Line
+		}
+		if di.Pos.Column != dj.Pos.Column {
+			return di.Pos.Column < dj.Pos.Column
+		}
+		if di.Rule != dj.Rule {
+			return di.Rule < dj.Rule
 		}
-		return diagnostics[i].Pos.Column < diagnostics[j].Pos.Column
+		return di.Message < dj.Message
 	})
 
 	return &Result{
@@ -141,7 +233,7 @@ func (a *Analyzer) Analyze(df *parser.Dockerfile, filename, source string) *Resu
 }
 
 // analyzeSequential runs rules sequentially
-func (a *Analyzer) analyzeSequential(df *parser.Dockerfile, filename, source string, sourceLines []string, rules []Rule) []Diagnostic {
+func (a *Analyzer) analyzeSequential(budget context.Context, df *parser.Dockerfile, filename, source string, sourceLines []string, rules []Rule) []Diagnostic {
 	ctx := &RuleContext{
 		Filename:    filename,
 		Source:      source,
@@ -160,21 +252,20 @@ func (a *Analyzer) analyzeSequential(df *parser.Dockerfile, filename, source str
 		}
 
 		// Run rule
-		diags := rule.Check(df, ctx)
-
-		// Filter by severity
-		for _, d := range diags {
-			if d.Severity >= a.minSeverity {
-				diagnostics = append(diagnostics, d)
-			}
+		diags, ok := a.check(budget, rule, df, ctx)
+		if !ok {
+			diagnostics = append(diagnostics, a.timedOut(rule)...)
+			continue
 		}
+
+		diagnostics = append(diagnostics, a.filter(diags)...)
 	}
 
 	return diagnostics
 }
 
 // analyzeParallel runs rules in parallel using a worker pool
-func (a *Analyzer) analyzeParallel(df *parser.Dockerfile, filename, source string, sourceLines []string, rules []Rule) []Diagnostic {
+func (a *Analyzer) analyzeParallel(budget context.Context, df *parser.Dockerfile, filename, source string, sourceLines []string, rules []Rule) []Diagnostic {
 	numWorkers := a.maxWorkers
 	if numWorkers <= 0 {
 		numWorkers = runtime.GOMAXPROCS(0)
@@ -217,14 +308,14 @@ func (a *Analyzer) analyzeParallel(df *parser.Dockerfile, filename, source strin
 				}
 
 				// Run rule
-				diags := rule.Check(df, ctx)
+				diags, ok := a.check(budget, rule, df, ctx)
 
 				// Collect results
 				var filtered []Diagnostic
-				for _, d := range diags {
-					if d.Severity >= a.minSeverity {
-						filtered = append(filtered, d)
-					}
+				if ok {
+					filtered = a.filter(diags)
+				} else {
+					filtered = a.timedOut(rule)
 				}
 
 				if len(filtered) > 0 {
@@ -240,6 +331,79 @@ func (a *Analyzer) analyzeParallel(df *parser.Dockerfile, filename, source strin
 	return diagnostics
 }
 
+// check runs rule, giving up when the budget runs out first. Without a
+// timeout the rule runs on the calling goroutine.
+func (a *Analyzer) check(budget context.Context, rule Rule, df *parser.Dockerfile, ctx *RuleContext) ([]Diagnostic, bool) {
+	if a.timeout <= 0 {
+		return rule.Check(df, ctx), true
+	}
+	if budget.Err() != nil {
+		return nil, false
+	}
+
+	// The rule gets its own copy of the context, since the caller reuses
+	// ctx for the next rule while an abandoned rule may still be reading it
+	rc := *ctx
+	done := make(chan []Diagnostic, 1)
+	go func() {
+		done <- rule.Check(df, &rc)
+	}()
+
+	select {
+	case diags := <-done:
+		return diags, true
+	case <-budget.Done():
+		return nil, false
+	}
+}
+
+// timedOut returns the TIMEOUT diagnostic for a rule that didn't finish,
+// unless TIMEOUT is disabled. Like PARSE diagnostics, it isn't subject to
+// the minimum severity.
+func (a *Analyzer) timedOut(rule Rule) []Diagnostic {
+	if a.disabled[TimeoutRuleID] {
+		return nil
+	}
+	return []Diagnostic{NewDiagnostic(TimeoutRuleID, rule.Category()).
+		WithSeverity(SeverityError).
+		WithMessagef("Rule %s did not finish within the %s analysis budget and was skipped", rule.ID(), a.timeout).
+		WithPos(lexer.Position{Line: 1, Column: 1}).
+		WithHelp("Raise the time budget, or disable the rule if it is consistently slow").
+		Build()}
+}
+
+// parseDiagnostics converts parse errors into PARSE diagnostics
+func parseDiagnostics(parseErrors []parser.ParseError, sourceLines []string) []Diagnostic {
+	var diags []Diagnostic
+	for _, pe := range parseErrors {
+		var context string
+		if pe.Pos.Line >= 1 && pe.Pos.Line <= len(sourceLines) {
+			context = sourceLines[pe.Pos.Line-1]
+		}
+		diags = append(diags, NewDiagnostic(ParseRuleID, CategorySyntax).
+			WithSeverity(SeverityError).
+			WithMessage(pe.Message).
+			WithPos(pe.Pos).
+			WithContext(context).
+			Build())
+	}
+	return diags
+}
+
+// filter applies severity overrides and drops diagnostics below the minimum severity
+func (a *Analyzer) filter(diags []Diagnostic) []Diagnostic {
+	var filtered []Diagnostic
+	for _, d := range diags {
+		if sev, ok := a.overrides[d.Rule]; ok {
+			d.Severity = sev
+		}
+		if d.Severity >= a.minSeverity && (a.keep == nil || a.keep(d)) {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered
+}
+
 // shouldRun checks if a rule should be run
 func (a *Analyzer) shouldRun(rule Rule) bool {
 	// If disabled, don't run
@@ -247,6 +411,11 @@ func (a *Analyzer) shouldRun(rule Rule) bool {
 		return false
 	}
 
+	// If tags are specified, the rule needs one of them
+	if len(a.tags) > 0 && !a.hasTag(rule) {
+		return false
+	}
+
 	// If enabled set is specified, only run those
 	if len(a.enabled) > 0 {
 		return a.enabled[rule.ID()]
@@ -255,6 +424,15 @@ func (a *Analyzer) shouldRun(rule Rule) bool {
 	return true
 }
 
+func (a *Analyzer) hasTag(rule Rule) bool {
+	for _, tag := range Tags(rule) {
+		if a.tags[tag] {
+			return true
+		}
+	}
+	return false
+}
+
 // GetLine returns the source line at the given line number (1-based)
 func (c *RuleContext) GetLine(lineNum int) string {
 	if lineNum < 1 || lineNum > len(c.SourceLines) {
@@ -281,10 +459,6 @@ func splitLines(s string) []string {
 // AnalyzeSource parses and analyzes source code
 func (a *Analyzer) AnalyzeSource(source, filename string) (*Result, []parser.ParseError) {
 	df, parseErrors := parser.Parse(source)
-	if len(parseErrors) > 0 {
-		// Still try to analyze what we can
-		result := a.Analyze(df, filename, source)
-		return result, parseErrors
-	}
-	return a.Analyze(df, filename, source), nil
+	// Still analyze what we can; the errors are reported as PARSE diagnostics
+	return a.AnalyzeParsed(df, filename, source, parseErrors), parseErrors
 }
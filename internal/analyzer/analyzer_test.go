@@ -0,0 +1,307 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+func TestAnalyzer_SeverityOverride(t *testing.T) {
+	source := "FROM alpine:3.18\nRUN echo hi\n"
+
+	tests := []struct {
+		name        string
+		overrides   map[string]Severity
+		minSeverity Severity
+		parallel    bool
+		expected    int
+		severity    Severity
+	}{
+		{
+			name:        "no override",
+			minSeverity: SeverityWarning,
+			expected:    1,
+			severity:    SeverityWarning,
+		},
+		{
+			name:        "raised to error",
+			overrides:   map[string]Severity{"MOCK001": SeverityError},
+			minSeverity: SeverityWarning,
+			expected:    1,
+			severity:    SeverityError,
+		},
+		{
+			name:        "raised above min severity",
+			overrides:   map[string]Severity{"MOCK001": SeverityError},
+			minSeverity: SeverityError,
+			expected:    1,
+			severity:    SeverityError,
+		},
+		{
+			name:        "lowered below min severity",
+			overrides:   map[string]Severity{"MOCK001": SeverityInfo},
+			minSeverity: SeverityWarning,
+			expected:    0,
+		},
+		{
+			name:        "other rule unaffected",
+			overrides:   map[string]Severity{"OTHER": SeverityHint},
+			minSeverity: SeverityWarning,
+			expected:    1,
+			severity:    SeverityWarning,
+		},
+		{
+			name:        "parallel rules",
+			overrides:   map[string]Severity{"MOCK001": SeverityError},
+			minSeverity: SeverityError,
+			parallel:    true,
+			expected:    1,
+			severity:    SeverityError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := New(
+				WithRules(&mockRuleWithDiags{id: "MOCK001"}, &mockRule{id: "MOCK002"}),
+				WithMinSeverity(tt.minSeverity),
+				WithSeverityOverride(tt.overrides),
+				WithParallelRules(tt.parallel),
+			)
+
+			result, _ := a.AnalyzeSource(source, "Dockerfile")
+			if len(result.Diagnostics) != tt.expected {
+				t.Fatalf("expected %d diagnostics, got %d", tt.expected, len(result.Diagnostics))
+			}
+			for _, d := range result.Diagnostics {
+				if d.Severity != tt.severity {
+					t.Errorf("expected severity %s, got %s", tt.severity, d.Severity)
+				}
+			}
+		})
+	}
+}
+
+// taggedRule is a mockRuleWithDiags with tags
+type taggedRule struct {
+	mockRuleWithDiags
+	tags []string
+}
+
+func (r *taggedRule) Tags() []string { return r.tags }
+
+func TestAnalyzer_WithTags(t *testing.T) {
+	source := "FROM alpine:3.18\nRUN echo hi\n"
+	rules := []Rule{
+		&taggedRule{mockRuleWithDiags{id: "MOCK001"}, []string{"supply-chain", "reproducibility"}},
+		&taggedRule{mockRuleWithDiags{id: "MOCK002"}, []string{"layers"}},
+		&mockRuleWithDiags{id: "MOCK003"},
+	}
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected []string
+	}{
+		{
+			name:     "no tags",
+			expected: []string{"MOCK001", "MOCK002", "MOCK003"},
+		},
+		{
+			name:     "one tag",
+			opts:     []Option{WithTags("supply-chain")},
+			expected: []string{"MOCK001"},
+		},
+		{
+			name:     "any of several tags",
+			opts:     []Option{WithTags("reproducibility", "layers")},
+			expected: []string{"MOCK001", "MOCK002"},
+		},
+		{
+			name:     "unknown tag",
+			opts:     []Option{WithTags("cis")},
+			expected: nil,
+		},
+		{
+			name:     "tags and enabled rules",
+			opts:     []Option{WithTags("supply-chain", "layers"), WithEnabled("MOCK002", "MOCK003")},
+			expected: []string{"MOCK002"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := New(append([]Option{WithRules(rules...)}, tt.opts...)...)
+			result, _ := a.AnalyzeSource(source, "Dockerfile")
+
+			var got []string
+			for _, d := range result.Diagnostics {
+				got = append(got, d.Rule)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected rules %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestAnalyzer_DeterministicOrder(t *testing.T) {
+	source := "FROM alpine:3.18\nRUN echo hi\nCOPY . /app\n"
+
+	rules := []Rule{
+		&mockRuleWithDiags{id: "MOCK003"},
+		&mockRuleWithDiags{id: "MOCK001"},
+		&mockRuleWithDiags{id: "MOCK002"},
+	}
+
+	sequential, _ := New(WithRules(rules...)).AnalyzeSource(source, "Dockerfile")
+
+	var order []string
+	for _, d := range sequential.Diagnostics {
+		order = append(order, d.Pos.String()+" "+d.Rule)
+	}
+	expected := []string{
+		"2:1 MOCK001", "2:1 MOCK002", "2:1 MOCK003",
+		"3:1 MOCK001", "3:1 MOCK002", "3:1 MOCK003",
+	}
+	if len(order) != len(expected) {
+		t.Fatalf("expected %d diagnostics, got %v", len(expected), order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("expected order %v, got %v", expected, order)
+		}
+	}
+
+	for i := 0; i < 20; i++ {
+		parallel, _ := New(WithRules(rules...), WithParallelRules(true), WithMaxWorkers(3)).AnalyzeSource(source, "Dockerfile")
+		for j, d := range parallel.Diagnostics {
+			if d.Rule != sequential.Diagnostics[j].Rule || d.Pos != sequential.Diagnostics[j].Pos {
+				t.Fatalf("run %d: parallel order differs at %d: %v vs %v", i, j, d, sequential.Diagnostics[j])
+			}
+		}
+	}
+}
+
+func TestAnalyzer_ParseErrors(t *testing.T) {
+	source := "RUN echo hi\nFROM alpine:3.18\n"
+
+	result, parseErrors := New().AnalyzeSource(source, "Dockerfile")
+	if len(parseErrors) != 1 || len(result.Diagnostics) != 1 {
+		t.Fatalf("expected 1 parse error reported as 1 diagnostic, got %v and %v", parseErrors, result.Diagnostics)
+	}
+	d := result.Diagnostics[0]
+	if d.Rule != ParseRuleID || d.Severity != SeverityError || d.Category != CategorySyntax {
+		t.Errorf("unexpected diagnostic %v", d)
+	}
+	if d.Pos.Line != 1 || d.Context != "RUN echo hi" || d.Message != parseErrors[0].Message {
+		t.Errorf("unexpected position, context, or message in %+v", d)
+	}
+
+	result, _ = New(WithDisabled(ParseRuleID)).AnalyzeSource(source, "Dockerfile")
+	if len(result.Diagnostics) != 0 {
+		t.Errorf("expected no diagnostics with PARSE disabled, got %v", result.Diagnostics)
+	}
+}
+
+func TestAnalyzer_WithFilter(t *testing.T) {
+	source := "RUN echo hi\nFROM alpine:3.18\nRUN echo hi\n"
+	keep := func(d Diagnostic) bool { return d.Rule == "MOCK002" }
+
+	result, _ := New(
+		WithRules(&mockRuleWithDiags{id: "MOCK001"}, &mockRuleWithDiags{id: "MOCK002"}),
+		WithFilter(keep),
+	).AnalyzeSource(source, "Dockerfile")
+
+	var rules []string
+	for _, d := range result.Diagnostics {
+		rules = append(rules, d.Rule)
+	}
+	if !reflect.DeepEqual(rules, []string{ParseRuleID, "MOCK002"}) {
+		t.Errorf("expected the PARSE diagnostic and MOCK002, got %v", rules)
+	}
+}
+
+// slowRule blocks in Check until release is closed
+type slowRule struct {
+	release chan struct{}
+}
+
+func (r *slowRule) ID() string         { return "SLOW001" }
+func (r *slowRule) Category() Category { return CategoryPerformance }
+func (r *slowRule) Severity() Severity { return SeverityWarning }
+
+func (r *slowRule) Check(df *parser.Dockerfile, ctx *RuleContext) []Diagnostic {
+	<-r.release
+	return []Diagnostic{{Rule: r.ID(), Severity: SeverityWarning}}
+}
+
+func TestAnalyzer_WithTimeout(t *testing.T) {
+	source := "FROM alpine:3.18\nRUN echo hi\n"
+
+	for _, parallel := range []bool{false, true} {
+		slow := &slowRule{release: make(chan struct{})}
+		t.Cleanup(func() { close(slow.release) })
+
+		start := time.Now()
+		result, _ := New(
+			WithRules(&mockRuleWithDiags{id: "MOCK001"}, slow),
+			WithTimeout(20*time.Millisecond),
+			WithParallelRules(parallel),
+		).AnalyzeSource(source, "Dockerfile")
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Fatalf("parallel=%t: expected the analysis to give up on the slow rule, took %s", parallel, elapsed)
+		}
+
+		var rules []string
+		for _, d := range result.Diagnostics {
+			rules = append(rules, d.Rule)
+		}
+		// The TIMEOUT diagnostic is placed on line 1, ahead of MOCK001's
+		if !reflect.DeepEqual(rules, []string{TimeoutRuleID, "MOCK001"}) {
+			t.Fatalf("parallel=%t: expected a TIMEOUT diagnostic and MOCK001, got %v", parallel, rules)
+		}
+		d := result.Diagnostics[0]
+		if d.Severity != SeverityError || !strings.Contains(d.Message, "SLOW001") || !strings.Contains(d.Message, "20ms") {
+			t.Errorf("parallel=%t: unexpected timeout diagnostic %+v", parallel, d)
+		}
+	}
+}
+
+func TestAnalyzer_WithTimeoutDisabled(t *testing.T) {
+	slow := &slowRule{release: make(chan struct{})}
+	t.Cleanup(func() { close(slow.release) })
+
+	result, _ := New(
+		WithRules(slow),
+		WithTimeout(time.Millisecond),
+		WithDisabled(TimeoutRuleID),
+	).AnalyzeSource("FROM alpine:3.18\n", "Dockerfile")
+	if len(result.Diagnostics) != 0 {
+		t.Errorf("expected no diagnostics with TIMEOUT disabled, got %v", result.Diagnostics)
+	}
+}
+
+func TestRegisterRule_Duplicate(t *testing.T) {
+	RegisterRule(&mockRule{id: "MOCK900"})
+
+	found := false
+	for _, r := range RegisteredRules() {
+		if r.ID() == "MOCK900" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("expected MOCK900 to be registered")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected registering MOCK900 twice to panic")
+		}
+	}()
+	RegisterRule(&mockRule{id: "MOCK900"})
+}
@@ -31,6 +31,22 @@ func (s Severity) String() string {
 	}
 }
 
+// ParseSeverity returns the severity named s (error, warning, info, or hint)
+func ParseSeverity(s string) (Severity, error) {
+	switch s {
+	case "error":
+		return SeverityError, nil
+	case "warning":
+		return SeverityWarning, nil
+	case "info":
+		return SeverityInfo, nil
+	case "hint":
+		return SeverityHint, nil
+	default:
+		return SeverityWarning, fmt.Errorf("unknown severity %q", s)
+	}
+}
+
 // Category represents the category of a rule
 type Category string
 
@@ -39,6 +55,7 @@ const (
 	CategoryPerformance Category = "performance"
 	CategoryBestPractice Category = "bestpractice"
 	CategoryStyle       Category = "style"
+	CategorySyntax      Category = "syntax" // parse errors
 )
 
 // Diagnostic represents a linting issue
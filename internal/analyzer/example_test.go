@@ -0,0 +1,47 @@
+package analyzer_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// noLatestRule is an organization-specific rule that rejects the
+// latest tag, which the built-in rules only warn about
+type noLatestRule struct{}
+
+func (r *noLatestRule) ID() string                  { return "ORG001" }
+func (r *noLatestRule) Name() string                { return "no-latest" }
+func (r *noLatestRule) Description() string         { return "Base images must not use the latest tag." }
+func (r *noLatestRule) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *noLatestRule) Severity() analyzer.Severity { return analyzer.SeverityError }
+
+func (r *noLatestRule) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+	for _, stage := range df.Stages {
+		if stage.From == nil || !strings.EqualFold(stage.From.Tag, "latest") {
+			continue
+		}
+		diags = append(diags, analyzer.NewDiagnostic(r.ID(), r.Category()).
+			WithSeverity(r.Severity()).
+			WithMessagef("%s uses the latest tag", stage.From.Image).
+			WithPos(stage.From.Pos()).
+			WithHelp("Pin the image to a release tag").
+			Build())
+	}
+	return diags
+}
+
+func ExampleRegisterRule() {
+	// Usually called from an init function
+	analyzer.RegisterRule(&noLatestRule{})
+
+	a := analyzer.New(analyzer.WithRules(analyzer.RegisteredRules()...))
+	result, _ := a.AnalyzeSource("FROM alpine:latest\nRUN apk add curl\n", "Dockerfile")
+	for _, d := range result.Diagnostics {
+		fmt.Println(d)
+	}
+	// Output: [ORG001] error: alpine uses the latest tag at 1:1
+}
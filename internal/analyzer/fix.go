@@ -0,0 +1,131 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/HueCodes/keel/internal/lexer"
+)
+
+// TextEdit replaces the source text between Pos (inclusive) and EndPos (exclusive)
+type TextEdit struct {
+	Pos     lexer.Position
+	EndPos  lexer.Position
+	NewText string
+}
+
+// FixEdit returns the text edit described by a diagnostic's fix suggestion.
+// Only single-line spans are supported; ok is false if the diagnostic has no
+// suggestion or its range does not describe a single-line span. An empty
+// suggestion over a non-empty span deletes the span.
+func FixEdit(d Diagnostic) (TextEdit, bool) {
+	if !d.Fixable {
+		return TextEdit{}, false
+	}
+	if d.Pos.Line < 1 || d.Pos.Column < 1 {
+		return TextEdit{}, false
+	}
+	if d.EndPos.Line != d.Pos.Line || d.EndPos.Column < d.Pos.Column {
+		return TextEdit{}, false
+	}
+	if d.FixSuggestion == "" && d.EndPos.Column == d.Pos.Column {
+		return TextEdit{}, false
+	}
+	return TextEdit{Pos: d.Pos, EndPos: d.EndPos, NewText: d.FixSuggestion}, true
+}
+
+// ApplyFix applies a single diagnostic's fix suggestion to the source
+func ApplyFix(source string, d Diagnostic) (string, error) {
+	edit, ok := FixEdit(d)
+	if !ok {
+		return source, fmt.Errorf("%s at %s has no applicable single-line fix", d.Rule, d.Pos)
+	}
+	return ApplyEdits(source, []TextEdit{edit})
+}
+
+// ApplyEdits applies non-overlapping text edits to the source.
+// Positions are resolved by line and column; byte offsets are ignored
+// since many rules only populate line/column.
+func ApplyEdits(source string, edits []TextEdit) (string, error) {
+	if len(edits) == 0 {
+		return source, nil
+	}
+
+	lineStarts := computeLineStarts(source)
+
+	type span struct {
+		start, end int
+		text       string
+	}
+	spans := make([]span, 0, len(edits))
+	for _, e := range edits {
+		start, err := resolveOffset(source, lineStarts, e.Pos)
+		if err != nil {
+			return source, err
+		}
+		end, err := resolveOffset(source, lineStarts, e.EndPos)
+		if err != nil {
+			return source, err
+		}
+		if end < start {
+			return source, fmt.Errorf("edit end %s is before start %s", e.EndPos, e.Pos)
+		}
+		spans = append(spans, span{start: start, end: end, text: e.NewText})
+	}
+
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].start < spans[j].start
+	})
+	for i := 1; i < len(spans); i++ {
+		if spans[i].start < spans[i-1].end {
+			return source, fmt.Errorf("overlapping edits at offset %d", spans[i].start)
+		}
+	}
+
+	// Apply from the end so earlier offsets stay valid
+	result := source
+	for i := len(spans) - 1; i >= 0; i-- {
+		s := spans[i]
+		result = result[:s.start] + s.text + result[s.end:]
+	}
+	return result, nil
+}
+
+// computeLineStarts returns the byte offset of the start of each line
+func computeLineStarts(source string) []int {
+	starts := []int{0}
+	for i := 0; i < len(source); i++ {
+		if source[i] == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// resolveOffset converts a 1-based line/column position into a byte offset.
+// A column one past the last character addresses the end of the line.
+func resolveOffset(source string, lineStarts []int, pos lexer.Position) (int, error) {
+	if pos.Line < 1 || pos.Line > len(lineStarts) {
+		return 0, fmt.Errorf("line %d out of range", pos.Line)
+	}
+	if pos.Column < 1 {
+		return 0, fmt.Errorf("column %d out of range", pos.Column)
+	}
+
+	lineStart := lineStarts[pos.Line-1]
+	lineEnd := len(source)
+	if pos.Line < len(lineStarts) {
+		lineEnd = lineStarts[pos.Line] - 1 // exclude the newline
+	}
+
+	offset := lineStart
+	for col := 1; col < pos.Column; col++ {
+		if offset >= lineEnd {
+			return 0, fmt.Errorf("column %d out of range on line %d", pos.Column, pos.Line)
+		}
+		_, size := utf8.DecodeRuneInString(source[offset:lineEnd])
+		offset += size
+	}
+	return offset, nil
+}
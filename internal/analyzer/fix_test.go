@@ -0,0 +1,158 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/HueCodes/keel/internal/lexer"
+)
+
+func TestApplyFix_SingleLineSpan(t *testing.T) {
+	source := "from alpine:3.18\nRUN echo hi\n"
+	diag := NewDiagnostic("STY001", CategoryStyle).
+		WithRange(lexer.Position{Line: 1, Column: 1}, lexer.Position{Line: 1, Column: 5}).
+		WithFix("FROM").
+		Build()
+
+	got, err := ApplyFix(source, diag)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "FROM alpine:3.18\nRUN echo hi\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestApplyFix_EndOfLine(t *testing.T) {
+	source := "FROM alpine\nWORKDIR app\n"
+	diag := NewDiagnostic("BP005", CategoryBestPractice).
+		WithRange(lexer.Position{Line: 2, Column: 1}, lexer.Position{Line: 2, Column: 12}).
+		WithFix("WORKDIR /app").
+		Build()
+
+	got, err := ApplyFix(source, diag)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "FROM alpine\nWORKDIR /app\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestApplyFix_MultiByteColumns(t *testing.T) {
+	source := "LABEL name=\"héllo\" x=y\n"
+	// Replace x=y (columns 20-22, counted in runes)
+	diag := NewDiagnostic("TEST", CategoryStyle).
+		WithRange(lexer.Position{Line: 1, Column: 20}, lexer.Position{Line: 1, Column: 23}).
+		WithFix("x=z").
+		Build()
+
+	got, err := ApplyFix(source, diag)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "LABEL name=\"héllo\" x=z\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestApplyFix_Deletion(t *testing.T) {
+	source := "FROM alpine   \nRUN echo hi\n"
+	diag := NewDiagnostic("STY002", CategoryStyle).
+		WithRange(lexer.Position{Line: 1, Column: 12}, lexer.Position{Line: 1, Column: 15}).
+		WithFix("").
+		Build()
+
+	got, err := ApplyFix(source, diag)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "FROM alpine\nRUN echo hi\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestApplyFix_NotApplicable(t *testing.T) {
+	source := "FROM alpine\nRUN a\nRUN b\n"
+
+	tests := []struct {
+		name string
+		diag Diagnostic
+	}{
+		{
+			name: "no suggestion",
+			diag: NewDiagnostic("X", CategoryStyle).
+				WithRange(lexer.Position{Line: 1, Column: 1}, lexer.Position{Line: 1, Column: 5}).
+				Build(),
+		},
+		{
+			name: "no end position",
+			diag: NewDiagnostic("X", CategoryStyle).
+				WithPos(lexer.Position{Line: 1, Column: 1}).
+				WithFix("FROM").
+				Build(),
+		},
+		{
+			name: "multi-line span",
+			diag: NewDiagnostic("X", CategoryStyle).
+				WithRange(lexer.Position{Line: 2, Column: 1}, lexer.Position{Line: 3, Column: 6}).
+				WithFix("RUN a && b").
+				Build(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyFix(source, tt.diag)
+			if err == nil {
+				t.Error("expected error for non-applicable fix")
+			}
+			if got != source {
+				t.Errorf("expected source unchanged, got %q", got)
+			}
+		})
+	}
+}
+
+func TestApplyEdits_Multiple(t *testing.T) {
+	source := "from alpine\nrun echo hi\n"
+	edits := []TextEdit{
+		{Pos: lexer.Position{Line: 2, Column: 1}, EndPos: lexer.Position{Line: 2, Column: 4}, NewText: "RUN"},
+		{Pos: lexer.Position{Line: 1, Column: 1}, EndPos: lexer.Position{Line: 1, Column: 5}, NewText: "FROM"},
+	}
+
+	got, err := ApplyEdits(source, edits)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "FROM alpine\nRUN echo hi\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestApplyEdits_Overlapping(t *testing.T) {
+	source := "FROM alpine\n"
+	edits := []TextEdit{
+		{Pos: lexer.Position{Line: 1, Column: 1}, EndPos: lexer.Position{Line: 1, Column: 8}, NewText: "X"},
+		{Pos: lexer.Position{Line: 1, Column: 6}, EndPos: lexer.Position{Line: 1, Column: 12}, NewText: "Y"},
+	}
+
+	if _, err := ApplyEdits(source, edits); err == nil {
+		t.Error("expected error for overlapping edits")
+	}
+}
+
+func TestApplyEdits_OutOfRange(t *testing.T) {
+	source := "FROM alpine\n"
+	edits := []TextEdit{
+		{Pos: lexer.Position{Line: 1, Column: 30}, EndPos: lexer.Position{Line: 1, Column: 31}, NewText: "X"},
+	}
+
+	if _, err := ApplyEdits(source, edits); err == nil {
+		t.Error("expected error for out-of-range column")
+	}
+}
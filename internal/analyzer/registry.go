@@ -0,0 +1,50 @@
+package analyzer
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Rules added with RegisterRule, in registration order
+var (
+	registryMu sync.RWMutex
+	registry   []Rule
+)
+
+// RegisterRule adds a custom rule that keel runs alongside its built-in
+// rules. Programs that embed keel call it from an init function, before
+// any analysis starts:
+//
+//	func init() {
+//		analyzer.RegisterRule(&NoLatestOnProdRule{})
+//	}
+//
+// Custom rules should use an ID prefix of their own, such as ORG001, so
+// they don't collide with built-in rules and can be selected with
+// --only and --ignore. A rule that also has Name() and Description()
+// methods is listed by keel explain.
+//
+// RegisterRule panics if rule is nil or its ID is already registered.
+func RegisterRule(rule Rule) {
+	if rule == nil {
+		panic("analyzer: RegisterRule called with a nil rule")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, r := range registry {
+		if r.ID() == rule.ID() {
+			panic(fmt.Sprintf("analyzer: rule %s registered twice", rule.ID()))
+		}
+	}
+	registry = append(registry, rule)
+}
+
+// RegisteredRules returns the rules added with RegisterRule
+func RegisteredRules() []Rule {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return append([]Rule(nil), registry...)
+}
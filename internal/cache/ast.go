@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/HueCodes/keel/internal/parser"
@@ -25,6 +26,9 @@ type ASTCache struct {
 	lru        *list.List
 	maxEntries int
 	maxAge     time.Duration
+
+	hits   atomic.Int64
+	misses atomic.Int64
 }
 
 // entry stores the key and value in the LRU list
@@ -77,6 +81,7 @@ func (c *ASTCache) Get(filename, content string) (*ASTEntry, bool) {
 	c.mu.RUnlock()
 
 	if !ok {
+		c.misses.Add(1)
 		return nil, false
 	}
 
@@ -88,6 +93,7 @@ func (c *ASTCache) Get(filename, content string) (*ASTEntry, bool) {
 		c.mu.Lock()
 		c.removeElement(elem)
 		c.mu.Unlock()
+		c.misses.Add(1)
 		return nil, false
 	}
 
@@ -96,6 +102,7 @@ func (c *ASTCache) Get(filename, content string) (*ASTEntry, bool) {
 		c.mu.Lock()
 		c.removeElement(elem)
 		c.mu.Unlock()
+		c.misses.Add(1)
 		return nil, false
 	}
 
@@ -105,6 +112,7 @@ func (c *ASTCache) Get(filename, content string) (*ASTEntry, bool) {
 	ent.value.LastAccessed = time.Now()
 	c.mu.Unlock()
 
+	c.hits.Add(1)
 	return ent.value, true
 }
 
@@ -180,6 +188,8 @@ func (c *ASTCache) Stats() CacheStats {
 	return CacheStats{
 		Entries:    len(c.cache),
 		MaxEntries: c.maxEntries,
+		Hits:       c.hits.Load(),
+		Misses:     c.misses.Load(),
 	}
 }
 
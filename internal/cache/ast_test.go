@@ -167,3 +167,20 @@ func TestHashContent(t *testing.T) {
 		t.Error("expected different hash for different content")
 	}
 }
+
+func TestASTCache_StatsHitsMisses(t *testing.T) {
+	cache := NewASTCache()
+	cp := NewCachedParser(cache)
+
+	content := "FROM alpine\n"
+	cp.Parse("Dockerfile", content) // miss
+	cp.Parse("Dockerfile", content) // hit
+
+	stats := cache.Stats()
+	if stats.Hits != 1 {
+		t.Errorf("expected 1 hit, got %d", stats.Hits)
+	}
+	if stats.Misses != 1 {
+		t.Errorf("expected 1 miss, got %d", stats.Misses)
+	}
+}
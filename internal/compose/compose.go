@@ -0,0 +1,121 @@
+// Package compose reads the build configuration of services defined in a
+// Docker Compose file, so the Dockerfiles they reference can be linted.
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Service is a compose service that builds an image from a Dockerfile
+type Service struct {
+	Name       string
+	Context    string // build context, relative to the compose file's directory
+	Dockerfile string // Dockerfile path, relative to the context
+}
+
+// DockerfilePath returns the path of the service's Dockerfile, resolved
+// against dir, the directory containing the compose file
+func (s Service) DockerfilePath(dir string) string {
+	if filepath.IsAbs(s.Dockerfile) {
+		return s.Dockerfile
+	}
+	context := s.Context
+	if !filepath.IsAbs(context) {
+		context = filepath.Join(dir, context)
+	}
+	return filepath.Join(context, s.Dockerfile)
+}
+
+// Load reads a compose file and returns the paths of the Dockerfiles its
+// services build, in order and without duplicates
+func Load(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	services, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	seen := make(map[string]bool)
+	var paths []string
+	for _, svc := range services {
+		p := svc.DockerfilePath(dir)
+		if !seen[p] {
+			seen[p] = true
+			paths = append(paths, p)
+		}
+	}
+	return paths, nil
+}
+
+// Parse returns the services in a compose file that have a build section
+func Parse(data []byte) ([]Service, error) {
+	var f file
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	if f.Services.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	// Walk the mapping node rather than decoding into a map, to keep the
+	// services in document order
+	var services []Service
+	for i := 0; i+1 < len(f.Services.Content); i += 2 {
+		name := f.Services.Content[i].Value
+		var svc service
+		if err := f.Services.Content[i+1].Decode(&svc); err != nil {
+			return nil, fmt.Errorf("services.%s: %w", name, err)
+		}
+		if svc.Build == nil {
+			continue
+		}
+
+		s := Service{Name: name, Context: ".", Dockerfile: "Dockerfile"}
+		if svc.Build.Context != "" {
+			s.Context = svc.Build.Context
+		}
+		if svc.Build.Dockerfile != "" {
+			s.Dockerfile = svc.Build.Dockerfile
+		}
+
+		// Remote contexts can't be linted locally
+		if strings.Contains(s.Context, "://") || strings.HasPrefix(s.Context, "git@") {
+			continue
+		}
+		services = append(services, s)
+	}
+	return services, nil
+}
+
+// file is the part of a compose file Parse reads
+type file struct {
+	Services yaml.Node `yaml:"services"`
+}
+
+type service struct {
+	Build *build `yaml:"build"`
+}
+
+type build struct {
+	Context    string `yaml:"context"`
+	Dockerfile string `yaml:"dockerfile"`
+}
+
+// UnmarshalYAML accepts the short form, build: ./dir, as the context
+func (b *build) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		return n.Decode(&b.Context)
+	}
+	type plain build
+	return n.Decode((*plain)(b))
+}
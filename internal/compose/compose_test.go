@@ -0,0 +1,98 @@
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testCompose = `# Example stack
+services:
+  web:
+    build: ./web
+    ports:
+      - "8080:80"
+  api:
+    image: example/api
+    build:
+      context: ./api   # service source
+      dockerfile: "Dockerfile.prod"
+      args:
+        - VERSION=1
+  db:
+    image: postgres:16
+  worker:
+    build:
+      context: https://github.com/example/worker.git
+`
+
+func TestParse(t *testing.T) {
+	services, err := Parse([]byte(testCompose))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Service{
+		{Name: "web", Context: "./web", Dockerfile: "Dockerfile"},
+		{Name: "api", Context: "./api", Dockerfile: "Dockerfile.prod"},
+	}
+	if !reflect.DeepEqual(services, expected) {
+		t.Errorf("expected %+v, got %+v", expected, services)
+	}
+}
+
+func TestParse_FlowAndAnchors(t *testing.T) {
+	src := `x-build: &build
+  context: ./shared
+  dockerfile: Dockerfile.dev
+services:
+  a:
+    build: *build
+  b:
+    build: {context: ./b}
+`
+	services, err := Parse([]byte(src))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Service{
+		{Name: "a", Context: "./shared", Dockerfile: "Dockerfile.dev"},
+		{Name: "b", Context: "./b", Dockerfile: "Dockerfile"},
+	}
+	if !reflect.DeepEqual(services, expected) {
+		t.Errorf("expected %+v, got %+v", expected, services)
+	}
+}
+
+func TestParse_NoServices(t *testing.T) {
+	services, err := Parse([]byte("version: '3'\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(services) != 0 {
+		t.Errorf("expected no services, got %+v", services)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "docker-compose.yml")
+	if err := os.WriteFile(path, []byte(testCompose), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		filepath.Join(dir, "web", "Dockerfile"),
+		filepath.Join(dir, "api", "Dockerfile.prod"),
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected %v, got %v", expected, paths)
+	}
+}
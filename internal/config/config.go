@@ -0,0 +1,435 @@
+// Package config loads keel's configuration file (.keel.yaml) and merges
+// it with command-line overrides into the settings used for a run.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/formatter"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile is the name of the config file looked up in the directories
+// above each linted file
+const DefaultFile = ".keel.yaml"
+
+// Config holds the settings from a config file
+type Config struct {
+	// Severity is the minimum severity to report
+	Severity string `yaml:"severity"`
+
+	// Root stops the search for config files in the directories above
+	Root bool `yaml:"root"`
+
+	// Rules holds per-rule settings, keyed by rule ID
+	Rules map[string]RuleConfig `yaml:"rules"`
+
+	// Only, when non-empty, restricts the run to these rules
+	Only []string `yaml:"-"`
+
+	// IgnorePaths are glob patterns of files not to lint, relative to
+	// IgnoreDir
+	IgnorePaths []string `yaml:"ignore_paths"`
+
+	// IgnoreDir is the directory of the config file that set IgnorePaths,
+	// empty for the current directory
+	IgnoreDir string `yaml:"-"`
+
+	Format FormatConfig `yaml:"format"`
+}
+
+// RuleConfig holds the settings for a single rule
+type RuleConfig struct {
+	// Enabled is nil when the config doesn't mention it
+	Enabled *bool `yaml:"enabled"`
+
+	// Severity overrides the rule's default severity when set
+	Severity string `yaml:"severity"`
+
+	// Options holds rule-specific settings, such as PERF004's max_consecutive
+	Options map[string]interface{} `yaml:",inline"`
+}
+
+// FormatConfig holds formatter settings
+type FormatConfig struct {
+	MaxLineLength int `json:"max_line_length" yaml:"max_line_length"`
+	Indent        int `json:"indent" yaml:"indent"`
+}
+
+// Overrides are settings given on the command line. They take precedence
+// over the config file.
+type Overrides struct {
+	Severity string
+	Only     []string
+	Ignore   []string
+}
+
+// Default returns the configuration used when there is no config file
+func Default() *Config {
+	opts := formatter.DefaultOptions()
+	return &Config{
+		Severity: "warning",
+		Rules:    make(map[string]RuleConfig),
+		Format: FormatConfig{
+			MaxLineLength: opts.MaxLineLength,
+			Indent:        len(opts.IndentString),
+		},
+	}
+}
+
+// Load reads the config file at path
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	cfg.IgnoreDir = filepath.Dir(path)
+	return cfg, nil
+}
+
+// Parse decodes a config file. Settings it doesn't mention keep their defaults.
+func Parse(data []byte) (*Config, error) {
+	doc, err := parseYAML(string(data))
+	if err != nil {
+		return nil, err
+	}
+	return decode(doc)
+}
+
+// decode builds a Config from a parsed config document
+func decode(doc map[string]interface{}) (*Config, error) {
+	cfg := Default()
+
+	if v, ok := doc["severity"]; ok && v != nil {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("severity: expected a string")
+		}
+		if _, err := analyzer.ParseSeverity(s); err != nil {
+			return nil, fmt.Errorf("severity: %w", err)
+		}
+		cfg.Severity = s
+	}
+
+	if v, ok := doc["root"]; ok && v != nil {
+		root, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("root: expected true or false")
+		}
+		cfg.Root = root
+	}
+
+	if v, ok := doc["rules"]; ok && v != nil {
+		rules, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("rules: expected a mapping")
+		}
+		for id, rv := range rules {
+			rc, err := parseRule(rv)
+			if err != nil {
+				return nil, fmt.Errorf("rules.%s: %w", id, err)
+			}
+			cfg.Rules[id] = rc
+		}
+	}
+
+	if v, ok := doc["ignore_paths"]; ok && v != nil {
+		paths, err := stringList(v)
+		if err != nil {
+			return nil, fmt.Errorf("ignore_paths: %w", err)
+		}
+		cfg.IgnorePaths = paths
+	}
+
+	if v, ok := doc["format"]; ok && v != nil {
+		format, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("format: expected a mapping")
+		}
+		for key, dst := range map[string]*int{
+			"max_line_length": &cfg.Format.MaxLineLength,
+			"indent":          &cfg.Format.Indent,
+		} {
+			fv, ok := format[key]
+			if !ok {
+				continue
+			}
+			n, ok := fv.(int)
+			if !ok || n < 0 {
+				return nil, fmt.Errorf("format.%s: expected a non-negative integer", key)
+			}
+			*dst = n
+		}
+	}
+
+	return cfg, nil
+}
+
+func parseRule(v interface{}) (RuleConfig, error) {
+	var rc RuleConfig
+	if v == nil {
+		return rc, nil
+	}
+
+	settings, ok := v.(map[string]interface{})
+	if !ok {
+		return rc, fmt.Errorf("expected a mapping")
+	}
+
+	for key, value := range settings {
+		switch key {
+		case "enabled":
+			b, ok := value.(bool)
+			if !ok {
+				return rc, fmt.Errorf("enabled: expected true or false")
+			}
+			rc.Enabled = &b
+		case "severity":
+			s, ok := value.(string)
+			if !ok {
+				return rc, fmt.Errorf("severity: expected a string")
+			}
+			if _, err := analyzer.ParseSeverity(s); err != nil {
+				return rc, fmt.Errorf("severity: %w", err)
+			}
+			rc.Severity = s
+		default:
+			if rc.Options == nil {
+				rc.Options = make(map[string]interface{})
+			}
+			rc.Options[key] = value
+		}
+	}
+	return rc, nil
+}
+
+func stringList(v interface{}) ([]string, error) {
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a list")
+	}
+	var list []string
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a list of strings")
+		}
+		list = append(list, s)
+	}
+	return list, nil
+}
+
+// Apply merges command-line overrides into the config
+func (c *Config) Apply(o Overrides) {
+	if o.Severity != "" {
+		c.Severity = o.Severity
+	}
+	if len(o.Only) > 0 {
+		c.Only = o.Only
+	}
+	for _, id := range o.Ignore {
+		rc := c.Rules[id]
+		disabled := false
+		rc.Enabled = &disabled
+		c.Rules[id] = rc
+	}
+}
+
+// Enabled reports whether the rule with the given ID runs
+func (c *Config) Enabled(id string) bool {
+	if rc, ok := c.Rules[id]; ok && rc.Enabled != nil && !*rc.Enabled {
+		return false
+	}
+	if len(c.Only) > 0 {
+		for _, only := range c.Only {
+			if only == id {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// AnalyzerOptions returns the analyzer options for the config. Rules
+// must be added separately with analyzer.WithRules.
+func (c *Config) AnalyzerOptions() []analyzer.Option {
+	minSeverity, _ := analyzer.ParseSeverity(c.Severity)
+	opts := []analyzer.Option{analyzer.WithMinSeverity(minSeverity)}
+
+	if len(c.Only) > 0 {
+		opts = append(opts, analyzer.WithEnabled(c.Only...))
+	}
+
+	overrides := make(map[string]analyzer.Severity)
+	for id, rc := range c.Rules {
+		if rc.Enabled != nil && !*rc.Enabled {
+			opts = append(opts, analyzer.WithDisabled(id))
+		}
+		if rc.Severity != "" {
+			overrides[id], _ = analyzer.ParseSeverity(rc.Severity)
+		}
+		if len(rc.Options) > 0 {
+			opts = append(opts, analyzer.WithRuleConfig(id, rc.Options))
+		}
+	}
+	if len(overrides) > 0 {
+		opts = append(opts, analyzer.WithSeverityOverride(overrides))
+	}
+
+	return opts
+}
+
+// FormatterOptions returns the formatter options for the config
+func (c *Config) FormatterOptions() formatter.Options {
+	opts := formatter.DefaultOptions()
+	if c.Format.MaxLineLength > 0 {
+		opts.MaxLineLength = c.Format.MaxLineLength
+	}
+	if c.Format.Indent > 0 {
+		opts.IndentString = strings.Repeat(" ", c.Format.Indent)
+	}
+	return opts
+}
+
+// Effective is the resolved configuration for a set of rules, as printed
+// by "keel config dump"
+type Effective struct {
+	Severity    string       `json:"severity"`
+	Rules       []RuleState  `json:"rules"`
+	IgnorePaths []string     `json:"ignore_paths"`
+	Format      FormatConfig `json:"format"`
+}
+
+// RuleState is the resolved configuration of a single rule
+type RuleState struct {
+	ID       string                 `json:"id"`
+	Enabled  bool                   `json:"enabled"`
+	Severity string                 `json:"severity"`
+	Options  map[string]interface{} `json:"options,omitempty"`
+}
+
+// Effective resolves the config against rules, sorted by ID
+func (c *Config) Effective(rules []analyzer.Rule) Effective {
+	eff := Effective{
+		Severity:    c.Severity,
+		Rules:       []RuleState{},
+		IgnorePaths: c.IgnorePaths,
+		Format:      c.Format,
+	}
+	if eff.IgnorePaths == nil {
+		eff.IgnorePaths = []string{}
+	}
+
+	for _, r := range rules {
+		rc := c.Rules[r.ID()]
+		state := RuleState{
+			ID:       r.ID(),
+			Enabled:  c.Enabled(r.ID()),
+			Severity: r.Severity().String(),
+			Options:  rc.Options,
+		}
+		if rc.Severity != "" {
+			state.Severity = rc.Severity
+		}
+		eff.Rules = append(eff.Rules, state)
+	}
+	sort.Slice(eff.Rules, func(i, j int) bool { return eff.Rules[i].ID < eff.Rules[j].ID })
+
+	return eff
+}
+
+// WriteJSON writes the effective configuration as indented JSON
+func (e Effective) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(e)
+}
+
+// WriteYAML writes the effective configuration in the config file's format
+func (e Effective) WriteYAML(w io.Writer) error {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "severity: %s\n", e.Severity)
+
+	sb.WriteString("rules:\n")
+	for _, r := range e.Rules {
+		fmt.Fprintf(&sb, "  %s:\n", r.ID)
+		fmt.Fprintf(&sb, "    enabled: %t\n", r.Enabled)
+		fmt.Fprintf(&sb, "    severity: %s\n", r.Severity)
+
+		keys := make([]string, 0, len(r.Options))
+		for k := range r.Options {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&sb, "    %s: %s\n", k, yamlValue(r.Options[k]))
+		}
+	}
+
+	if len(e.IgnorePaths) == 0 {
+		sb.WriteString("ignore_paths: []\n")
+	} else {
+		sb.WriteString("ignore_paths:\n")
+		for _, p := range e.IgnorePaths {
+			fmt.Fprintf(&sb, "  - %s\n", yamlValue(p))
+		}
+	}
+
+	sb.WriteString("format:\n")
+	fmt.Fprintf(&sb, "  max_line_length: %d\n", e.Format.MaxLineLength)
+	fmt.Fprintf(&sb, "  indent: %d\n", e.Format.Indent)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// yamlValue renders an option value as a YAML scalar or flow collection
+func yamlValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		if v == "" || strings.ContainsAny(v, ":#*&!|>'\"%@`[]{},") || v != strings.TrimSpace(v) {
+			return fmt.Sprintf("%q", v)
+		}
+		var parsed interface{}
+		if err := yaml.Unmarshal([]byte(v), &parsed); err != nil || parsed != v {
+			// Quote strings that would otherwise read back as bool, int, or null
+			return fmt.Sprintf("%q", v)
+		}
+		return v
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = yamlValue(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		items := make([]string, len(keys))
+		for i, k := range keys {
+			items[i] = k + ": " + yamlValue(v[k])
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+const sample = `# Keel configuration file
+severity: info
+
+rules:
+  SEC001:
+    enabled: false
+  SEC003:
+    enabled: true
+    allowed_tags:
+      - "latest"  # Allow latest for specific images
+  PERF004:
+    max_consecutive: 3
+    severity: error
+
+ignore_paths:
+  - "test/**"
+  - examples/**
+
+format:
+  max_line_length: 120
+  indent: 2
+`
+
+func TestParse(t *testing.T) {
+	cfg, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Severity != "info" {
+		t.Errorf("expected severity info, got %q", cfg.Severity)
+	}
+	if cfg.Enabled("SEC001") {
+		t.Error("expected SEC001 to be disabled")
+	}
+	if !cfg.Enabled("SEC003") || !cfg.Enabled("BP001") {
+		t.Error("expected SEC003 and BP001 to be enabled")
+	}
+	if got := cfg.Rules["SEC003"].Options["allowed_tags"]; !reflect.DeepEqual(got, []interface{}{"latest"}) {
+		t.Errorf("expected allowed_tags [latest], got %v", got)
+	}
+	perf := cfg.Rules["PERF004"]
+	if perf.Options["max_consecutive"] != 3 || perf.Severity != "error" {
+		t.Errorf("unexpected PERF004 config: %+v", perf)
+	}
+	if !reflect.DeepEqual(cfg.IgnorePaths, []string{"test/**", "examples/**"}) {
+		t.Errorf("unexpected ignore_paths: %v", cfg.IgnorePaths)
+	}
+	if cfg.Format != (FormatConfig{MaxLineLength: 120, Indent: 2}) {
+		t.Errorf("unexpected format: %+v", cfg.Format)
+	}
+	if got := cfg.FormatterOptions().IndentString; got != "  " {
+		t.Errorf("expected two-space indent, got %q", got)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		errMsg string
+	}{
+		{"bad severity", "severity: loud\n", "unknown severity"},
+		{"enabled not bool", "rules:\n  SEC001:\n    enabled: maybe\n", "rules.SEC001: enabled"},
+		{"bad indentation", "rules:\n  SEC001:\n      enabled: true\n    severity: error\n", "did not find expected key"},
+		{"tab indentation", "rules:\n\tSEC001:\n", "line 2"},
+		{"not a mapping", "severity warning\n", "line 1"},
+		{"format not int", "format:\n  indent: wide\n", "format.indent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.source))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error containing %q, got %q", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	cfg, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.Apply(Overrides{Severity: "error", Ignore: []string{"SEC003"}})
+	if cfg.Severity != "error" {
+		t.Errorf("expected flag severity to win, got %q", cfg.Severity)
+	}
+	if cfg.Enabled("SEC003") {
+		t.Error("expected --ignore to disable SEC003")
+	}
+	if cfg.Rules["SEC003"].Options["allowed_tags"] == nil {
+		t.Error("expected --ignore to keep rule options")
+	}
+
+	cfg.Apply(Overrides{Only: []string{"BP001"}})
+	if !cfg.Enabled("BP001") || cfg.Enabled("BP002") {
+		t.Error("expected --only to restrict the enabled rules")
+	}
+}
+
+type fakeRule struct {
+	id  string
+	cat analyzer.Category
+	sev analyzer.Severity
+}
+
+func (r fakeRule) ID() string                  { return r.id }
+func (r fakeRule) Category() analyzer.Category { return r.cat }
+func (r fakeRule) Severity() analyzer.Severity { return r.sev }
+func (r fakeRule) Check(*parser.Dockerfile, *analyzer.RuleContext) []analyzer.Diagnostic {
+	return nil
+}
+
+func TestEffective_WriteYAMLRoundTrip(t *testing.T) {
+	cfg, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rules := []analyzer.Rule{
+		fakeRule{"SEC003", analyzer.CategorySecurity, analyzer.SeverityWarning},
+		fakeRule{"PERF004", analyzer.CategoryPerformance, analyzer.SeverityInfo},
+		fakeRule{"SEC001", analyzer.CategorySecurity, analyzer.SeverityError},
+	}
+
+	eff := cfg.Effective(rules)
+	var ids []string
+	for _, r := range eff.Rules {
+		ids = append(ids, r.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"PERF004", "SEC001", "SEC003"}) {
+		t.Errorf("expected rules sorted by ID, got %v", ids)
+	}
+
+	var buf bytes.Buffer
+	if err := eff.WriteYAML(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The dump is itself a valid config file
+	reloaded, err := Parse(buf.Bytes())
+	if err != nil {
+		t.Fatalf("dump is not a valid config: %v\n%s", err, buf.String())
+	}
+	if !reflect.DeepEqual(reloaded.Effective(rules), eff) {
+		t.Errorf("round trip changed the config:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+// Resolver finds the configuration for each linted file. Like
+// EditorConfig, every DefaultFile from the file's directory up to the
+// filesystem root applies, with nearer files taking precedence. A config
+// file containing "root: true" stops the search.
+type Resolver struct {
+	file string                 // when set, used for every file instead of discovery
+	base map[string]interface{} // profile settings the config files are merged over
+
+	mu   sync.Mutex
+	docs map[string]map[string]interface{} // parsed config files by path, nil if missing
+}
+
+// NewResolver creates a Resolver. When file is non-empty it is used for
+// every linted file and no discovery takes place.
+func NewResolver(file string) *Resolver {
+	return &Resolver{
+		file: file,
+		docs: make(map[string]map[string]interface{}),
+	}
+}
+
+// UseProfile makes the named built-in profile the base that config files
+// are merged over
+func (r *Resolver) UseProfile(name string, rules []analyzer.Rule) error {
+	doc, err := profileDoc(name, rules)
+	if err != nil {
+		return err
+	}
+	r.base = doc
+	return nil
+}
+
+// ForFile returns the configuration for the file at path. Each call
+// returns a new Config, which the caller may modify.
+func (r *Resolver) ForFile(path string) (*Config, error) {
+	if r.file != "" {
+		doc, err := r.load(r.file, true)
+		if err != nil {
+			return nil, err
+		}
+		cfg, err := decode(mergeDocs(r.base, doc))
+		if err != nil {
+			return nil, err
+		}
+		cfg.IgnoreDir = filepath.Dir(r.file)
+		return cfg, nil
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect config files from the nearest outwards. ignore_paths is
+	// relative to the nearest file that sets it.
+	var docs []map[string]interface{}
+	var ignoreDir string
+	for dir := filepath.Dir(abs); ; dir = filepath.Dir(dir) {
+		doc, err := r.load(filepath.Join(dir, DefaultFile), false)
+		if err != nil {
+			return nil, err
+		}
+		if doc != nil {
+			docs = append(docs, doc)
+			if _, ok := doc["ignore_paths"]; ok && ignoreDir == "" {
+				ignoreDir = dir
+			}
+			if root, _ := doc["root"].(bool); root {
+				break
+			}
+		}
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+
+	merged := r.base
+	for i := len(docs) - 1; i >= 0; i-- {
+		merged = mergeDocs(merged, docs[i])
+	}
+	cfg, err := decode(merged)
+	if err != nil {
+		return nil, err
+	}
+	cfg.IgnoreDir = ignoreDir
+	return cfg, nil
+}
+
+// load reads and parses the config file at path, caching the result. A
+// missing file yields a nil document, or an error when required is set.
+func (r *Resolver) load(path string, required bool) (map[string]interface{}, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if doc, ok := r.docs[path]; ok {
+		return doc, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && !required {
+			r.docs[path] = nil
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// Decoding validates each file on its own, so errors name the file
+	// at fault rather than the merged result
+	doc, err := parseYAML(string(data))
+	if err == nil {
+		_, err = decode(doc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	r.docs[path] = doc
+	return doc, nil
+}
+
+// mergeDocs merges override into base, recursing into nested mappings so
+// that a nearer config can change one setting of a rule and keep the rest
+func mergeDocs(base, override map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		if bm, ok := merged[k].(map[string]interface{}); ok {
+			if om, ok := v.(map[string]interface{}); ok {
+				merged[k] = mergeDocs(bm, om)
+				continue
+			}
+		}
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolver_Cascading(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, DefaultFile), `root: true
+severity: info
+rules:
+  PERF004:
+    max_consecutive: 3
+  SEC001:
+    enabled: false
+`)
+	writeFile(t, filepath.Join(dir, "services", "api", DefaultFile), `rules:
+  PERF004:
+    severity: error
+`)
+
+	r := NewResolver("")
+
+	root, err := r.ForFile(filepath.Join(dir, "Dockerfile"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if root.Rules["PERF004"].Severity != "" {
+		t.Errorf("expected no PERF004 severity override at the root, got %q", root.Rules["PERF004"].Severity)
+	}
+
+	api, err := r.ForFile(filepath.Join(dir, "services", "api", "Dockerfile"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	perf := api.Rules["PERF004"]
+	if perf.Severity != "error" {
+		t.Errorf("expected the nearer config to set PERF004 severity, got %q", perf.Severity)
+	}
+	if perf.Options["max_consecutive"] != 3 {
+		t.Errorf("expected PERF004 options from the root config, got %v", perf.Options)
+	}
+	if api.Severity != "info" || api.Enabled("SEC001") {
+		t.Errorf("expected root settings to be inherited, got severity %q, SEC001 enabled %v", api.Severity, api.Enabled("SEC001"))
+	}
+
+	// A sibling directory without its own config gets the root's
+	web, err := r.ForFile(filepath.Join(dir, "services", "web", "Dockerfile"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if web.Rules["PERF004"].Severity != "" {
+		t.Errorf("expected the api override not to apply to web, got %q", web.Rules["PERF004"].Severity)
+	}
+}
+
+func TestResolver_ExplicitFile(t *testing.T) {
+	dir := t.TempDir()
+	explicit := filepath.Join(dir, "ci.yaml")
+	writeFile(t, explicit, "severity: error\n")
+	writeFile(t, filepath.Join(dir, "app", DefaultFile), "root: true\nseverity: hint\n")
+
+	cfg, err := NewResolver(explicit).ForFile(filepath.Join(dir, "app", "Dockerfile"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Severity != "error" {
+		t.Errorf("expected the explicit config to replace discovery, got severity %q", cfg.Severity)
+	}
+
+	if _, err := NewResolver(filepath.Join(dir, "missing.yaml")).ForFile("Dockerfile"); err == nil {
+		t.Error("expected an error for a missing explicit config")
+	}
+}
+
+func TestResolver_InvalidFileNamed(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, DefaultFile), "root: true\n")
+	bad := filepath.Join(dir, "svc", DefaultFile)
+	writeFile(t, bad, "severity: loud\n")
+
+	_, err := NewResolver("").ForFile(filepath.Join(dir, "svc", "Dockerfile"))
+	if err == nil || !strings.Contains(err.Error(), bad) {
+		t.Errorf("expected an error naming %s, got %v", bad, err)
+	}
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+// profiles are the built-in bundles of settings selected with --profile.
+// Each builds a config document for the given rules, which config files
+// are then merged over, so any setting a file makes takes precedence.
+var profiles = map[string]func(rules []analyzer.Rule) map[string]interface{}{
+	// strict runs every rule and reports hints and info at warning level
+	"strict": func(rules []analyzer.Rule) map[string]interface{} {
+		settings := make(map[string]interface{})
+		for _, r := range rules {
+			rc := map[string]interface{}{"enabled": true}
+			if r.Severity() < analyzer.SeverityWarning {
+				rc["severity"] = analyzer.SeverityWarning.String()
+			}
+			settings[r.ID()] = rc
+		}
+		return map[string]interface{}{"severity": "warning", "rules": settings}
+	},
+
+	// relaxed reports only security errors
+	"relaxed": func(rules []analyzer.Rule) map[string]interface{} {
+		settings := make(map[string]interface{})
+		for _, r := range rules {
+			if r.Category() != analyzer.CategorySecurity {
+				settings[r.ID()] = map[string]interface{}{"enabled": false}
+			}
+		}
+		return map[string]interface{}{"severity": "error", "rules": settings}
+	},
+}
+
+// ProfileNames returns the names of the built-in profiles, sorted
+func ProfileNames() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Profile returns the configuration of the named built-in profile for
+// rules, before any config file is applied
+func Profile(name string, rules []analyzer.Rule) (*Config, error) {
+	doc, err := profileDoc(name, rules)
+	if err != nil {
+		return nil, err
+	}
+	return decode(doc)
+}
+
+func profileDoc(name string, rules []analyzer.Rule) (map[string]interface{}, error) {
+	build, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile %q (expected %s)", name, strings.Join(ProfileNames(), " or "))
+	}
+	return build(rules), nil
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+var profileRules = []analyzer.Rule{
+	fakeRule{"SEC001", analyzer.CategorySecurity, analyzer.SeverityError},
+	fakeRule{"SEC003", analyzer.CategorySecurity, analyzer.SeverityWarning},
+	fakeRule{"PERF004", analyzer.CategoryPerformance, analyzer.SeverityInfo},
+	fakeRule{"STY001", analyzer.CategoryStyle, analyzer.SeverityHint},
+}
+
+func TestProfile_Relaxed(t *testing.T) {
+	cfg, err := Profile("relaxed", profileRules)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Severity != "error" {
+		t.Errorf("expected severity error, got %q", cfg.Severity)
+	}
+	for id, want := range map[string]bool{"SEC001": true, "SEC003": true, "PERF004": false, "STY001": false} {
+		if got := cfg.Enabled(id); got != want {
+			t.Errorf("%s: expected enabled %t, got %t", id, want, got)
+		}
+	}
+}
+
+func TestProfile_Strict(t *testing.T) {
+	cfg, err := Profile("strict", profileRules)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	eff := cfg.Effective(profileRules)
+	for _, r := range eff.Rules {
+		if !r.Enabled {
+			t.Errorf("%s: expected to be enabled", r.ID)
+		}
+		want := "warning"
+		if r.ID == "SEC001" {
+			want = "error"
+		}
+		if r.Severity != want {
+			t.Errorf("%s: expected severity %s, got %s", r.ID, want, r.Severity)
+		}
+	}
+}
+
+func TestProfile_Unknown(t *testing.T) {
+	_, err := Profile("lenient", profileRules)
+	if err == nil || !strings.Contains(err.Error(), "expected relaxed or strict") {
+		t.Errorf("expected an unknown profile error, got %v", err)
+	}
+}
+
+func TestResolver_ProfileUnderConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, DefaultFile), `root: true
+severity: warning
+rules:
+  PERF004:
+    enabled: true
+  SEC003:
+    enabled: false
+`)
+
+	r := NewResolver("")
+	if err := r.UseProfile("relaxed", profileRules); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg, err := r.ForFile(filepath.Join(dir, "Dockerfile"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Severity != "warning" {
+		t.Errorf("expected the config file's severity to win, got %q", cfg.Severity)
+	}
+	for id, want := range map[string]bool{"SEC001": true, "SEC003": false, "PERF004": true, "STY001": false} {
+		if got := cfg.Enabled(id); got != want {
+			t.Errorf("%s: expected enabled %t, got %t", id, want, got)
+		}
+	}
+
+	cfg.Apply(Overrides{Severity: "hint"})
+	if cfg.Severity != "hint" {
+		t.Errorf("expected flag severity to win, got %q", cfg.Severity)
+	}
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"gopkg.in/yaml.v3"
+)
+
+// Problem is an issue found by Validate, at the YAML node it concerns
+type Problem struct {
+	Pos
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%d:%d: %s", p.Line, p.Column, p.Message)
+}
+
+// Validate checks a config file for unknown keys, invalid values, and
+// rules that aren't in ruleIDs. Unlike Parse it reports every problem,
+// sorted by position, instead of stopping at the first. Rule-specific
+// options can't be checked and are accepted as is. The error is set only
+// if the file isn't valid YAML.
+func Validate(data []byte, ruleIDs []string) ([]Problem, error) {
+	_, nodes, err := parseYAMLNodes(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []Problem
+	report := func(pos Pos, format string, args ...interface{}) {
+		problems = append(problems, Problem{Pos: pos, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Strict decoding reports unknown keys and values of the wrong type,
+	// and fills in everything else
+	var cfg Config
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && err != io.EOF {
+		terr, ok := err.(*yaml.TypeError)
+		if !ok {
+			return nil, err
+		}
+		for _, msg := range terr.Errors {
+			pos, message := decodeProblem(msg, nodes)
+			report(pos, "%s", message)
+		}
+	}
+
+	known := make(map[string]bool, len(ruleIDs))
+	for _, id := range ruleIDs {
+		known[id] = true
+	}
+
+	checkSeverity := func(s, path string) {
+		if _, err := analyzer.ParseSeverity(s); s != "" && err != nil {
+			report(nodes[path].Value, "%s: invalid severity %q (expected error, warning, info, or hint)", path, s)
+		}
+	}
+
+	checkSeverity(cfg.Severity, "severity")
+	for id, rc := range cfg.Rules {
+		path := "rules." + id
+		if !known[id] {
+			report(nodes[path].Key, "unknown rule %s", id)
+			continue
+		}
+		checkSeverity(rc.Severity, path+".severity")
+	}
+	for path, n := range map[string]int{
+		"format.max_line_length": cfg.Format.MaxLineLength,
+		"format.indent":          cfg.Format.Indent,
+	} {
+		if n < 0 {
+			report(nodes[path].Value, "%s: expected a non-negative integer", path)
+		}
+	}
+
+	sort.Slice(problems, func(i, j int) bool {
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+		return problems[i].Column < problems[j].Column
+	})
+	return problems, nil
+}
+
+var (
+	unknownFieldRE = regexp.MustCompile(`^line \d+: field (.+) not found in type `)
+	wrongTypeRE    = regexp.MustCompile(`^line \d+: cannot unmarshal .* into (.+)$`)
+)
+
+// expectedTypes describes the Go types config values decode into
+var expectedTypes = map[string]string{
+	"bool":     "true or false",
+	"int":      "an integer",
+	"string":   "a string",
+	"[]string": "a list",
+}
+
+// decodeProblem turns one of yaml.v3's decoding errors into the position
+// and message of a Problem
+func decodeProblem(msg string, nodes map[string]node) (Pos, string) {
+	num, _, _ := strings.Cut(strings.TrimPrefix(msg, "line "), ":")
+	line, _ := strconv.Atoi(num)
+	at := func(pos Pos) Pos {
+		if pos.Line != line {
+			return Pos{Line: line, Column: 1}
+		}
+		return pos
+	}
+
+	if m := unknownFieldRE.FindStringSubmatch(msg); m != nil {
+		path := pathAt(nodes, func(path string, n node) bool {
+			return n.Key.Line == line && (path == m[1] || strings.HasSuffix(path, "."+m[1]))
+		})
+		if i := strings.LastIndex(path, "."); i >= 0 {
+			return at(nodes[path].Key), fmt.Sprintf("unknown key %q in %s", m[1], path[:i])
+		}
+		return at(nodes[path].Key), fmt.Sprintf("unknown key %q", m[1])
+	}
+
+	if m := wrongTypeRE.FindStringSubmatch(msg); m != nil {
+		path := pathAt(nodes, func(_ string, n node) bool { return n.Key.Line == line && n.Value.Line == line })
+		expected, ok := expectedTypes[m[1]]
+		if !ok {
+			expected = "a mapping"
+		}
+		return at(nodes[path].Value), fmt.Sprintf("%s: expected %s", path, expected)
+	}
+
+	return Pos{Line: line, Column: 1}, msg
+}
+
+// pathAt returns the shortest path of the nodes matching match, the
+// outermost key when several share a line
+func pathAt(nodes map[string]node, match func(string, node) bool) string {
+	var found string
+	for path, n := range nodes {
+		if !match(path, n) {
+			continue
+		}
+		if found == "" || len(path) < len(found) || (len(path) == len(found) && path < found) {
+			found = path
+		}
+	}
+	return found
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	ruleIDs := []string{"SEC001", "PERF004"}
+
+	tests := []struct {
+		name     string
+		src      string
+		expected []string
+	}{
+		{
+			name: "valid",
+			src: `severity: info
+rules:
+  SEC001:
+    enabled: false
+  PERF004:
+    severity: error
+    max_consecutive: 3
+ignore_paths:
+  - "test/**"
+format:
+  indent: 2
+`,
+			expected: nil,
+		},
+		{
+			name: "unknown rule",
+			src: `rules:
+  SEC001:
+    enabled: true
+  SEC0001:
+    enabled: false
+`,
+			expected: []string{"4:3: unknown rule SEC0001"},
+		},
+		{
+			name: "bad severity",
+			src: `severity: warn
+rules:
+  PERF004:
+    severity: critical
+`,
+			expected: []string{
+				`1:11: severity: invalid severity "warn" (expected error, warning, info, or hint)`,
+				`4:15: rules.PERF004.severity: invalid severity "critical" (expected error, warning, info, or hint)`,
+			},
+		},
+		{
+			name: "unknown keys",
+			src: `severity: error
+ignore_path:
+  - test/**
+format:
+  indent: 2
+  width: 80
+`,
+			expected: []string{`2:1: unknown key "ignore_path"`, `6:3: unknown key "width" in format`},
+		},
+		{
+			name: "wrong types",
+			src: `root: 1
+rules:
+  SEC001:
+    enabled: maybe
+  PERF004: 3
+ignore_paths: test/**
+format:
+  indent: -1
+`,
+			expected: []string{
+				"1:7: root: expected true or false",
+				"4:14: rules.SEC001.enabled: expected true or false",
+				"5:12: rules.PERF004: expected a mapping",
+				"6:15: ignore_paths: expected a list",
+				"8:11: format.indent: expected a non-negative integer",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems, err := Validate([]byte(tt.src), ruleIDs)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got []string
+			for _, p := range problems {
+				got = append(got, p.String())
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestValidate_InvalidYAML(t *testing.T) {
+	if _, err := Validate([]byte("rules:\n\tSEC001: {}\n"), nil); err == nil {
+		t.Error("expected an error for invalid YAML")
+	}
+}
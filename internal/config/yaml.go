@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Pos is a 1-based line and column in a YAML document
+type Pos struct {
+	Line   int
+	Column int
+}
+
+// node records where a mapping key and its value appear
+type node struct {
+	Key   Pos
+	Value Pos
+}
+
+// parseYAML decodes a config document. Mappings decode to
+// map[string]interface{}, sequences to []interface{}, and scalars to
+// bool, int, float64, string, or nil.
+func parseYAML(src string) (map[string]interface{}, error) {
+	doc, _, err := parseYAMLNodes(src)
+	return doc, err
+}
+
+// parseYAMLNodes is parseYAML that also returns the position of every
+// mapping key, keyed by its dotted path such as rules.SEC001.severity.
+// Sequence items are addressed as ignore_paths[0].
+func parseYAMLNodes(src string) (map[string]interface{}, map[string]node, error) {
+	doc := make(map[string]interface{})
+	nodes := make(map[string]node)
+
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(src), &root); err != nil {
+		return nil, nil, err
+	}
+	if len(root.Content) == 0 {
+		return doc, nodes, nil
+	}
+
+	top := root.Content[0]
+	if top.Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("line %d: expected a mapping", top.Line)
+	}
+	if err := top.Decode(&doc); err != nil {
+		return nil, nil, err
+	}
+	recordNodes(top, "", nodes)
+	return doc, nodes, nil
+}
+
+// recordNodes adds the positions of the keys below n to nodes
+func recordNodes(n *yaml.Node, path string, nodes map[string]node) {
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			keyPath := key.Value
+			if path != "" {
+				keyPath = path + "." + key.Value
+			}
+			nodes[keyPath] = node{Key: posOf(key), Value: posOf(value)}
+			recordNodes(value, keyPath, nodes)
+		}
+	case yaml.SequenceNode:
+		for i, item := range n.Content {
+			recordNodes(item, fmt.Sprintf("%s[%d]", path, i), nodes)
+		}
+	}
+}
+
+func posOf(n *yaml.Node) Pos {
+	return Pos{Line: n.Line, Column: n.Column}
+}
@@ -2,24 +2,40 @@ package formatter
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
+// DiffOptions configures a unified diff
+type DiffOptions struct {
+	Context int // Unchanged lines shown around each change (default 3)
+}
+
+// DefaultDiffOptions returns the default diff options
+func DefaultDiffOptions() DiffOptions {
+	return DiffOptions{Context: 3}
+}
+
 // Diff generates a unified diff between original and formatted content
 func Diff(filename, original, formatted string) string {
+	return DiffWithOptions(filename, original, formatted, DefaultDiffOptions())
+}
+
+// DiffWithOptions is Diff with the given options
+func DiffWithOptions(filename, original, formatted string, opts DiffOptions) string {
 	if original == formatted {
 		return ""
 	}
 
-	origLines := strings.Split(original, "\n")
-	fmtLines := strings.Split(formatted, "\n")
+	origLines := diffSplit(original)
+	fmtLines := diffSplit(formatted)
 
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("--- %s\n", filename))
 	sb.WriteString(fmt.Sprintf("+++ %s\n", filename))
 
-	// Generate hunks using a simple diff algorithm
-	hunks := generateHunks(origLines, fmtLines)
+	// Generate hunks from a minimal line diff
+	hunks := generateHunks(origLines, fmtLines, max(0, opts.Context))
 
 	for _, hunk := range hunks {
 		sb.WriteString(hunk.String())
@@ -28,10 +44,21 @@ func Diff(filename, original, formatted string) string {
 	return sb.String()
 }
 
+// diffSplit splits s into lines for diffing. Each line keeps its
+// newline, so that a last line without one differs from the same line
+// with one, as in diff(1). An empty string has no lines.
+func diffSplit(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
 // DiffLine represents a line in a diff
 type DiffLine struct {
 	Type byte   // ' ', '+', '-'
-	Text string
+	Text string // including the newline, unless it ends a file without one
 }
 
 // Hunk represents a diff hunk
@@ -49,153 +76,264 @@ func (h *Hunk) String() string {
 	for _, line := range h.Lines {
 		sb.WriteByte(line.Type)
 		sb.WriteString(line.Text)
-		sb.WriteByte('\n')
+		if !strings.HasSuffix(line.Text, "\n") {
+			sb.WriteString("\n\\ No newline at end of file\n")
+		}
 	}
 	return sb.String()
 }
 
-// generateHunks generates diff hunks between two sets of lines
-func generateHunks(orig, new []string) []*Hunk {
-	// Compute LCS (Longest Common Subsequence) for diffing
-	lcs := computeLCS(orig, new)
+// generateHunks generates diff hunks between two sets of lines, with
+// contextLines unchanged lines around each change. Changes separated by
+// no more than 2*contextLines unchanged lines share a hunk.
+func generateHunks(orig, new []string, contextLines int) []*Hunk {
+	ops := diffLines(orig, new)
 
 	var hunks []*Hunk
-	var currentHunk *Hunk
-
-	origIdx, newIdx, lcsIdx := 0, 0, 0
-	contextLines := 3 // Lines of context around changes
-
-	for origIdx < len(orig) || newIdx < len(new) {
-		// Check if we're on a matching line
-		if lcsIdx < len(lcs) && origIdx < len(orig) && newIdx < len(new) &&
-			orig[origIdx] == lcs[lcsIdx] && new[newIdx] == lcs[lcsIdx] {
-			// Matching line
-			if currentHunk != nil {
-				// Add context line to current hunk
-				currentHunk.Lines = append(currentHunk.Lines, DiffLine{Type: ' ', Text: orig[origIdx]})
-				currentHunk.OrigCount++
-				currentHunk.NewCount++
-
-				// Check if we should close the hunk
-				if shouldCloseHunk(orig, new, lcs, origIdx, newIdx, lcsIdx, contextLines) {
-					hunks = append(hunks, currentHunk)
-					currentHunk = nil
+	var hunk *Hunk
+	origLine, newLine := 0, 0 // 0-based line numbers of ops[i]
+
+	for i := 0; i < len(ops); {
+		if ops[i].Type != ' ' {
+			if hunk == nil {
+				// Open a hunk with leading context
+				start := max(0, i-contextLines)
+				lead := i - start
+				hunk = &Hunk{OrigStart: origLine - lead + 1, NewStart: newLine - lead + 1}
+				for _, op := range ops[start:i] {
+					hunk.add(op)
 				}
 			}
-			origIdx++
-			newIdx++
-			lcsIdx++
-		} else {
-			// Difference found
-			if currentHunk == nil {
-				// Start new hunk with context
-				start := max(0, origIdx-contextLines)
-				currentHunk = &Hunk{
-					OrigStart: start + 1, // 1-based
-					NewStart:  max(0, newIdx-contextLines) + 1,
-				}
-				// Add leading context
-				for i := start; i < origIdx; i++ {
-					currentHunk.Lines = append(currentHunk.Lines, DiffLine{Type: ' ', Text: orig[i]})
-					currentHunk.OrigCount++
-					currentHunk.NewCount++
-				}
+			hunk.add(ops[i])
+			if ops[i].Type == '-' {
+				origLine++
+			} else {
+				newLine++
 			}
+			i++
+			continue
+		}
 
-			// Add removed lines
-			for origIdx < len(orig) && (lcsIdx >= len(lcs) || orig[origIdx] != lcs[lcsIdx]) {
-				currentHunk.Lines = append(currentHunk.Lines, DiffLine{Type: '-', Text: orig[origIdx]})
-				currentHunk.OrigCount++
-				origIdx++
-			}
+		// A run of unchanged lines
+		end := i
+		for end < len(ops) && ops[end].Type == ' ' {
+			end++
+		}
+		run := end - i
 
-			// Add added lines
-			for newIdx < len(new) && (lcsIdx >= len(lcs) || new[newIdx] != lcs[lcsIdx]) {
-				currentHunk.Lines = append(currentHunk.Lines, DiffLine{Type: '+', Text: new[newIdx]})
-				currentHunk.NewCount++
-				newIdx++
+		if hunk != nil {
+			if end < len(ops) && run <= 2*contextLines {
+				// The next change is close enough to share the hunk
+				for _, op := range ops[i:end] {
+					hunk.add(op)
+				}
+			} else {
+				for _, op := range ops[i:min(end, i+contextLines)] {
+					hunk.add(op)
+				}
+				hunks = append(hunks, hunk.finish())
+				hunk = nil
 			}
 		}
+		origLine += run
+		newLine += run
+		i = end
 	}
 
-	if currentHunk != nil {
-		hunks = append(hunks, currentHunk)
+	if hunk != nil {
+		hunks = append(hunks, hunk.finish())
 	}
-
 	return hunks
 }
 
-// computeLCS computes the Longest Common Subsequence
-func computeLCS(a, b []string) []string {
-	m, n := len(a), len(b)
+func (h *Hunk) add(line DiffLine) {
+	h.Lines = append(h.Lines, line)
+	if line.Type != '+' {
+		h.OrigCount++
+	}
+	if line.Type != '-' {
+		h.NewCount++
+	}
+}
 
-	// Create DP table
-	dp := make([][]int, m+1)
-	for i := range dp {
-		dp[i] = make([]int, n+1)
+// finish adjusts the start of an empty side to the line before the
+// change, as unified diffs expect
+func (h *Hunk) finish() *Hunk {
+	if h.OrigCount == 0 {
+		h.OrigStart--
 	}
+	if h.NewCount == 0 {
+		h.NewStart--
+	}
+	return h
+}
 
-	// Fill DP table
-	for i := 1; i <= m; i++ {
-		for j := 1; j <= n; j++ {
-			if a[i-1] == b[j-1] {
-				dp[i][j] = dp[i-1][j-1] + 1
-			} else {
-				dp[i][j] = max(dp[i-1][j], dp[i][j-1])
-			}
+// diffLines returns a minimal edit script turning a into b, using Myers'
+// O(ND) algorithm in linear space: each step finds the middle snake of
+// the shortest edit path and recurses on the halves either side of it.
+func diffLines(a, b []string) []DiffLine {
+	m := &myers{a: a, b: b}
+	m.diff(0, len(a), 0, len(b))
+	groupChanges(m.ops)
+	return m.ops
+}
+
+// groupChanges moves the deletions in each run of changed lines ahead of
+// its insertions, which the recursion can leave interleaved
+func groupChanges(ops []DiffLine) {
+	for i := 0; i < len(ops); {
+		if ops[i].Type == ' ' {
+			i++
+			continue
+		}
+		end := i
+		for end < len(ops) && ops[end].Type != ' ' {
+			end++
 		}
+		run := ops[i:end]
+		sort.SliceStable(run, func(x, y int) bool {
+			return run[x].Type == '-' && run[y].Type == '+'
+		})
+		i = end
+	}
+}
+
+type myers struct {
+	a, b []string
+	ops  []DiffLine
+}
+
+func (m *myers) emit(typ byte, lines []string) {
+	for _, line := range lines {
+		m.ops = append(m.ops, DiffLine{Type: typ, Text: line})
 	}
+}
 
-	// Backtrack to find LCS
-	lcs := make([]string, dp[m][n])
-	i, j, k := m, n, dp[m][n]-1
-	for i > 0 && j > 0 {
-		if a[i-1] == b[j-1] {
-			lcs[k] = a[i-1]
-			i--
-			j--
-			k--
-		} else if dp[i-1][j] > dp[i][j-1] {
-			i--
+// diff appends the edit script for a[aLo:aHi] -> b[bLo:bHi]
+func (m *myers) diff(aLo, aHi, bLo, bHi int) {
+	// Common prefix and suffix need no search
+	prefix := 0
+	for aLo+prefix < aHi && bLo+prefix < bHi && m.a[aLo+prefix] == m.b[bLo+prefix] {
+		prefix++
+	}
+	m.emit(' ', m.a[aLo:aLo+prefix])
+	aLo += prefix
+	bLo += prefix
+
+	suffix := 0
+	for aHi-suffix > aLo && bHi-suffix > bLo && m.a[aHi-suffix-1] == m.b[bHi-suffix-1] {
+		suffix++
+	}
+	aHi -= suffix
+	bHi -= suffix
+
+	switch {
+	case aLo == aHi:
+		m.emit('+', m.b[bLo:bHi])
+	case bLo == bHi:
+		m.emit('-', m.a[aLo:aHi])
+	default:
+		if x, y, ok := m.middleSnake(aLo, aHi, bLo, bHi); ok {
+			m.diff(aLo, x, bLo, y)
+			m.diff(x, aHi, y, bHi)
 		} else {
-			j--
+			m.emit('-', m.a[aLo:aHi])
+			m.emit('+', m.b[bLo:bHi])
 		}
 	}
 
-	return lcs
+	m.emit(' ', m.a[aHi:aHi+suffix])
 }
 
-// shouldCloseHunk checks if we should close the current hunk
-func shouldCloseHunk(orig, new, lcs []string, origIdx, newIdx, lcsIdx, contextLines int) bool {
-	// Look ahead to see if there are more changes coming
-	lookAhead := contextLines * 2
+// middleSnake searches forward from the start and backward from the end
+// of a[aLo:aHi] and b[bLo:bHi] at once, and returns the point where the
+// two furthest-reaching paths overlap. Both ranges must be non-empty and
+// differ in their first and last lines.
+func (m *myers) middleSnake(aLo, aHi, bLo, bHi int) (x, y int, ok bool) {
+	n, mm := aHi-aLo, bHi-bLo
+	maxD := (n + mm + 1) / 2
+	offset := maxD
+	size := 2*maxD + 2
 
-	for i := 1; i <= lookAhead; i++ {
-		o := origIdx + i
-		n := newIdx + i
-		l := lcsIdx + i
+	// vf[k] and vb[k] hold the furthest x reached on diagonal k by the
+	// forward and backward searches; the backward search counts from the end
+	vf := make([]int, size)
+	vb := make([]int, size)
+	for i := range vf {
+		vf[i] = -1
+		vb[i] = -1
+	}
+	vf[offset+1] = 0
+	vb[offset+1] = 0
 
-		if o >= len(orig) && n >= len(new) {
-			return true // End of both
-		}
+	delta := n - mm
+	// With an odd delta the paths meet during a forward step
+	front := delta%2 != 0
 
-		if l >= len(lcs) {
-			return false // More changes coming
-		}
+	// Diagonals that run off the edit graph are trimmed from the search
+	var kfStart, kfEnd, kbStart, kbEnd int
 
-		if o < len(orig) && n < len(new) {
-			if orig[o] != lcs[l] || new[n] != lcs[l] {
-				return false // More changes coming
+	for d := 0; d <= maxD; d++ {
+		for k := -d + kfStart; k <= d-kfEnd; k += 2 {
+			ki := offset + k
+			var x1 int
+			if k == -d || (k != d && vf[ki-1] < vf[ki+1]) {
+				x1 = vf[ki+1]
+			} else {
+				x1 = vf[ki-1] + 1
+			}
+			y1 := x1 - k
+			for x1 < n && y1 < mm && m.a[aLo+x1] == m.b[bLo+y1] {
+				x1++
+				y1++
+			}
+			vf[ki] = x1
+
+			switch {
+			case x1 > n:
+				kfEnd += 2
+			case y1 > mm:
+				kfStart += 2
+			case front:
+				bi := offset + delta - k
+				if bi >= 0 && bi < size && vb[bi] != -1 && x1 >= n-vb[bi] {
+					return aLo + x1, bLo + y1, true
+				}
 			}
 		}
-	}
 
-	return true
-}
+		for k := -d + kbStart; k <= d-kbEnd; k += 2 {
+			ki := offset + k
+			var x2 int
+			if k == -d || (k != d && vb[ki-1] < vb[ki+1]) {
+				x2 = vb[ki+1]
+			} else {
+				x2 = vb[ki-1] + 1
+			}
+			y2 := x2 - k
+			for x2 < n && y2 < mm && m.a[aHi-x2-1] == m.b[bHi-y2-1] {
+				x2++
+				y2++
+			}
+			vb[ki] = x2
 
-func max(a, b int) int {
-	if a > b {
-		return a
+			switch {
+			case x2 > n:
+				kbEnd += 2
+			case y2 > mm:
+				kbStart += 2
+			case !front:
+				fi := offset + delta - k
+				if fi >= 0 && fi < size && vf[fi] != -1 {
+					x1 := vf[fi]
+					y1 := x1 - (fi - offset)
+					if x1 >= n-x2 {
+						return aLo + x1, bLo + y1, true
+					}
+				}
+			}
+		}
 	}
-	return b
+
+	return 0, 0, false
 }
@@ -0,0 +1,308 @@
+package formatter
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func numberedLines(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	return lines
+}
+
+func TestDiff_Fixtures(t *testing.T) {
+	base := numberedLines(20)
+	edit := func(f func(lines []string) []string) string {
+		lines := append([]string(nil), base...)
+		return strings.Join(f(lines), "\n") + "\n"
+	}
+	original := strings.Join(base, "\n") + "\n"
+
+	tests := []struct {
+		name     string
+		original string
+		modified string
+		expected string
+	}{
+		{
+			name:     "single change",
+			original: original,
+			modified: edit(func(l []string) []string { l[9] = "changed"; return l }),
+			expected: `@@ -7,7 +7,7 @@
+ line 7
+ line 8
+ line 9
+-line 10
++changed
+ line 11
+ line 12
+ line 13
+`,
+		},
+		{
+			name:     "distant changes make separate hunks",
+			original: original,
+			modified: edit(func(l []string) []string { l[1] = "two"; l[18] = "nineteen"; return l }),
+			expected: `@@ -1,5 +1,5 @@
+ line 1
+-line 2
++two
+ line 3
+ line 4
+ line 5
+@@ -16,5 +16,5 @@
+ line 16
+ line 17
+ line 18
+-line 19
++nineteen
+ line 20
+`,
+		},
+		{
+			name:     "nearby changes share a hunk",
+			original: original,
+			modified: edit(func(l []string) []string { l[4] = "five"; l[11] = "twelve"; return l }),
+			expected: `@@ -2,14 +2,14 @@
+ line 2
+ line 3
+ line 4
+-line 5
++five
+ line 6
+ line 7
+ line 8
+ line 9
+ line 10
+ line 11
+-line 12
++twelve
+ line 13
+ line 14
+ line 15
+`,
+		},
+		{
+			name:     "insertion and deletion",
+			original: "FROM alpine\nRUN apk add curl\nUSER app\n",
+			modified: "FROM alpine\nWORKDIR /app\nUSER app\n",
+			expected: `@@ -1,3 +1,3 @@
+ FROM alpine
+-RUN apk add curl
++WORKDIR /app
+ USER app
+`,
+		},
+		{
+			name:     "pure insertion",
+			original: "FROM alpine\nUSER app\n",
+			modified: "FROM alpine\nRUN apk add curl\nRUN apk add git\nUSER app\n",
+			expected: `@@ -1,2 +1,4 @@
+ FROM alpine
++RUN apk add curl
++RUN apk add git
+ USER app
+`,
+		},
+		{
+			name:     "into empty file",
+			original: "",
+			modified: "FROM alpine\n",
+			expected: `@@ -0,0 +1,1 @@
++FROM alpine
+`,
+		},
+		{
+			name:     "change at the end",
+			original: "FROM debian:12\nWORKDIR /app\nRUN apt-get update\nRUN apt-get install -y curl\n",
+			modified: "FROM debian:12\nWORKDIR /app\nRUN apt-get update && apt-get install -y curl\n",
+			expected: `@@ -1,4 +1,3 @@
+ FROM debian:12
+ WORKDIR /app
+-RUN apt-get update
+-RUN apt-get install -y curl
++RUN apt-get update && apt-get install -y curl
+`,
+		},
+		{
+			name:     "newline added at end of file",
+			original: "FROM alpine\nUSER app",
+			modified: "FROM alpine\nUSER app\n",
+			expected: `@@ -1,2 +1,2 @@
+ FROM alpine
+-USER app
+\ No newline at end of file
++USER app
+`,
+		},
+		{
+			name:     "change in a file without a final newline",
+			original: "FROM alpine\nRUN apk add curl\nUSER app",
+			modified: "FROM alpine\nRUN apk add git\nUSER app",
+			expected: `@@ -1,3 +1,3 @@
+ FROM alpine
+-RUN apk add curl
++RUN apk add git
+ USER app
+\ No newline at end of file
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := Diff("Dockerfile", tt.original, tt.modified)
+			expected := "--- Dockerfile\n+++ Dockerfile\n" + tt.expected
+			if diff != expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", expected, diff)
+			}
+		})
+	}
+}
+
+// lcsLength is the textbook O(n*m) LCS length, used to check minimality
+func lcsLength(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				cur[j] = prev[j-1] + 1
+			} else {
+				cur[j] = max(prev[j], cur[j-1])
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func TestDiffLines_MinimalEditScript(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	words := []string{"a", "b", "c", "d"}
+	randomLines := func() []string {
+		lines := make([]string, rng.Intn(15))
+		for i := range lines {
+			lines[i] = words[rng.Intn(len(words))]
+		}
+		return lines
+	}
+
+	for i := 0; i < 500; i++ {
+		a, b := randomLines(), randomLines()
+		ops := diffLines(a, b)
+
+		// Applying the script to a gives b
+		var gotA, gotB []string
+		kept := 0
+		for _, op := range ops {
+			if op.Type != '+' {
+				gotA = append(gotA, op.Text)
+			}
+			if op.Type != '-' {
+				gotB = append(gotB, op.Text)
+			}
+			if op.Type == ' ' {
+				kept++
+			}
+		}
+		if strings.Join(gotA, ",") != strings.Join(a, ",") || strings.Join(gotB, ",") != strings.Join(b, ",") {
+			t.Fatalf("edit script for %v -> %v is wrong: %v", a, b, ops)
+		}
+		if want := lcsLength(a, b); kept != want {
+			t.Fatalf("edit script for %v -> %v keeps %d lines, want %d", a, b, kept, want)
+		}
+	}
+}
+
+func TestDiff_LargeInput(t *testing.T) {
+	lines := numberedLines(50000)
+	original := strings.Join(lines, "\n")
+	lines[9] = "changed start"
+	lines[49989] = "changed end"
+	modified := strings.Join(lines, "\n")
+
+	diff := Diff("Dockerfile", original, modified)
+
+	if strings.Count(diff, "@@ -") != 2 {
+		t.Fatalf("expected two hunks, got:\n%s", diff)
+	}
+	for _, want := range []string{"@@ -7,7 +7,7 @@\n", "-line 10\n+changed start\n", "@@ -49987,7 +49987,7 @@\n", "-line 49990\n+changed end\n"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("expected diff to contain %q, got:\n%s", want, diff)
+		}
+	}
+}
+
+func TestDiffWithOptions_Context(t *testing.T) {
+	lines := numberedLines(20)
+	original := strings.Join(lines, "\n")
+	lines[9] = "changed"
+	lines[12] = "changed too"
+	modified := strings.Join(lines, "\n")
+
+	tests := []struct {
+		context  int
+		expected string
+	}{
+		{
+			context: 0,
+			expected: `@@ -10,1 +10,1 @@
+-line 10
++changed
+@@ -13,1 +13,1 @@
+-line 13
++changed too
+`,
+		},
+		{
+			context: 1,
+			expected: `@@ -9,6 +9,6 @@
+ line 9
+-line 10
++changed
+ line 11
+ line 12
+-line 13
++changed too
+ line 14
+`,
+		},
+		{
+			context: 5,
+			expected: `@@ -5,14 +5,14 @@
+ line 5
+ line 6
+ line 7
+ line 8
+ line 9
+-line 10
++changed
+ line 11
+ line 12
+-line 13
++changed too
+ line 14
+ line 15
+ line 16
+ line 17
+ line 18
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("context=%d", tt.context), func(t *testing.T) {
+			diff := DiffWithOptions("Dockerfile", original, modified, DiffOptions{Context: tt.context})
+			expected := "--- Dockerfile\n+++ Dockerfile\n" + tt.expected
+			if diff != expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", expected, diff)
+			}
+		})
+	}
+}
@@ -60,6 +60,15 @@ func (f *Formatter) Format(df *parser.Dockerfile) string {
 		f.writeComment(&sb, comment)
 	}
 
+	// Format global ARGs
+	for _, arg := range df.Args {
+		f.writeComments(&sb, arg.LeadingComments())
+		f.writeArg(&sb, arg)
+	}
+	if len(df.Args) > 0 && len(df.Stages) > 0 {
+		sb.WriteString("\n")
+	}
+
 	// Format stages
 	for i, stage := range df.Stages {
 		if i > 0 {
@@ -96,20 +105,20 @@ func (f *Formatter) FormatSource(source string) (*Result, error) {
 
 // formatStage formats a single build stage
 func (f *Formatter) formatStage(sb *strings.Builder, stage *parser.Stage) {
-	// Write stage comments
-	for _, comment := range stage.Comments {
-		f.writeComment(sb, comment)
-	}
-
 	// Write FROM instruction
 	if stage.From != nil {
+		f.writeComments(sb, stage.From.LeadingComments())
 		f.writeFrom(sb, stage.From)
 	}
 
-	// Write other instructions
+	// Write other instructions, each after its comments
 	for _, inst := range stage.Instructions {
+		f.writeComments(sb, inst.LeadingComments())
 		f.writeInstruction(sb, inst)
 	}
+
+	// Write comments from the end of the stage
+	f.writeComments(sb, stage.Comments)
 }
 
 // writeComment writes a comment
@@ -118,6 +127,13 @@ func (f *Formatter) writeComment(sb *strings.Builder, comment *parser.Comment) {
 	sb.WriteString("\n")
 }
 
+// writeComments writes comments in order
+func (f *Formatter) writeComments(sb *strings.Builder, comments []*parser.Comment) {
+	for _, comment := range comments {
+		f.writeComment(sb, comment)
+	}
+}
+
 // writeInstruction writes any instruction
 func (f *Formatter) writeInstruction(sb *strings.Builder, inst parser.Instruction) {
 	switch v := inst.(type) {
@@ -196,9 +212,9 @@ func (f *Formatter) writeRun(sb *strings.Builder, run *parser.RunInstruction) {
 	sb.WriteString("RUN ")
 
 	// Write flags
-	if run.Mount != "" {
+	for _, mount := range run.Mounts {
 		sb.WriteString("--mount=")
-		sb.WriteString(run.Mount)
+		sb.WriteString(mount)
 		sb.WriteString(" ")
 	}
 	if run.Network != "" {
@@ -213,7 +229,14 @@ func (f *Formatter) writeRun(sb *strings.Builder, run *parser.RunInstruction) {
 	}
 
 	if run.Heredoc != nil {
+		sb.WriteString(run.Heredoc.Marker())
+		if run.Command != "" {
+			sb.WriteString(" ")
+			sb.WriteString(run.Command)
+		}
+		sb.WriteString("\n")
 		sb.WriteString(run.Heredoc.Content)
+		sb.WriteString(run.Heredoc.Delimiter)
 	} else if run.IsExec {
 		f.writeExecForm(sb, run.Arguments)
 	} else {
@@ -298,7 +321,7 @@ func (f *Formatter) writeEnv(sb *strings.Builder, env *parser.EnvInstruction) {
 			}
 			sb.WriteString(kv.Key)
 			sb.WriteString("=")
-			sb.WriteString(f.quoteIfNeeded(kv.Value))
+			sb.WriteString(parser.QuoteValue(kv.Value, kv.Quote))
 		}
 		sb.WriteString("\n")
 		return
@@ -320,7 +343,7 @@ func (f *Formatter) writeEnv(sb *strings.Builder, env *parser.EnvInstruction) {
 		sb.WriteString(kv.Key)
 		sb.WriteString(strings.Repeat(" ", maxKeyLen-len(kv.Key)))
 		sb.WriteString("=")
-		sb.WriteString(f.quoteIfNeeded(kv.Value))
+		sb.WriteString(parser.QuoteValue(kv.Value, kv.Quote))
 	}
 	sb.WriteString("\n")
 }
@@ -331,7 +354,7 @@ func (f *Formatter) writeArg(sb *strings.Builder, arg *parser.ArgInstruction) {
 	sb.WriteString(arg.Name)
 	if arg.HasDefault {
 		sb.WriteString("=")
-		sb.WriteString(f.quoteIfNeeded(arg.DefaultValue))
+		sb.WriteString(parser.QuoteValue(arg.DefaultValue, arg.DefaultQuote))
 	}
 	sb.WriteString("\n")
 }
@@ -348,7 +371,7 @@ func (f *Formatter) writeLabel(sb *strings.Builder, label *parser.LabelInstructi
 			}
 			sb.WriteString(f.quoteIfNeeded(kv.Key))
 			sb.WriteString("=")
-			sb.WriteString(f.quoteIfNeeded(kv.Value))
+			sb.WriteString(parser.QuoteValue(kv.Value, kv.Quote))
 		}
 		sb.WriteString("\n")
 		return
@@ -372,7 +395,7 @@ func (f *Formatter) writeLabel(sb *strings.Builder, label *parser.LabelInstructi
 		sb.WriteString(quoted)
 		sb.WriteString(strings.Repeat(" ", maxKeyLen-len(quoted)))
 		sb.WriteString("=")
-		sb.WriteString(f.quoteIfNeeded(kv.Value))
+		sb.WriteString(parser.QuoteValue(kv.Value, kv.Quote))
 	}
 	sb.WriteString("\n")
 }
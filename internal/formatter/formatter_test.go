@@ -297,3 +297,69 @@ func TestQuoteIfNeeded(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatter_StripsTrailingWhitespace(t *testing.T) {
+	input := "FROM alpine  \nRUN echo a \\\n\t&& echo b\t\n"
+	expected := "FROM alpine\nRUN echo a \\\n    && echo b\n"
+
+	f := New(DefaultOptions())
+	result, err := f.FormatSource(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Formatted != expected {
+		t.Errorf("got:\n%q\nwant:\n%q", result.Formatted, expected)
+	}
+}
+
+func TestFormatter_PreservesQuoteStyle(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "single-quoted env with variable",
+			input:    "FROM alpine\nENV PROMPT='$USER@$HOST'\n",
+			expected: "FROM alpine\nENV PROMPT='$USER@$HOST'\n",
+		},
+		{
+			name:     "single-quoted label",
+			input:    "FROM alpine\nLABEL description='costs $5 a month'\n",
+			expected: "FROM alpine\nLABEL description='costs $5 a month'\n",
+		},
+		{
+			name:     "single-quoted arg default",
+			input:    "FROM alpine\nARG PATTERN='*.$EXT'\n",
+			expected: "FROM alpine\nARG PATTERN='*.$EXT'\n",
+		},
+		{
+			name:     "double-quoted env",
+			input:    "FROM alpine\nENV HOME_DIR=\"$HOME\"\n",
+			expected: "FROM alpine\nENV HOME_DIR=\"$HOME\"\n",
+		},
+		{
+			name:     "unquoted value quoted only when needed",
+			input:    "FROM alpine\nENV NAME=app\n",
+			expected: "FROM alpine\nENV NAME=app\n",
+		},
+		{
+			name:     "unquoted variable left bare",
+			input:    "FROM alpine\nENV OPTS=--data=$HOME/data\n",
+			expected: "FROM alpine\nENV OPTS=--data=$HOME/data\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := New(DefaultOptions())
+			result, err := f.FormatSource(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Formatted != tt.expected {
+				t.Errorf("got:\n%s\nwant:\n%s", result.Formatted, tt.expected)
+			}
+		})
+	}
+}
@@ -37,18 +37,18 @@ func New(input string) *Lexer {
 
 // readChar reads the next character
 func (l *Lexer) readChar() {
+	// A newline belongs to the line it ends; the next line starts after it
+	if l.ch == '\n' {
+		l.line++
+		l.column = 0
+	}
 	if l.readPos >= len(l.input) {
 		l.ch = 0 // EOF
 	} else {
 		l.ch, _ = utf8.DecodeRuneInString(l.input[l.readPos:])
 	}
 	l.pos = l.readPos
-	if l.ch == '\n' {
-		l.line++
-		l.column = 0
-	} else {
-		l.column++
-	}
+	l.column++
 	l.readPos += utf8.RuneLen(l.ch)
 }
 
@@ -270,7 +270,8 @@ func (l *Lexer) readWord() Token {
 	if l.atLineStart {
 		upper := strings.ToUpper(literal)
 		if tokType := LookupKeyword(upper); tokType != TokenWord {
-			l.atLineStart = false
+			// ONBUILD is followed by the instruction it triggers
+			l.atLineStart = tokType == TokenOnbuild
 			l.inInstruction = true
 			return l.makeToken(tokType, literal)
 		}
@@ -417,9 +418,7 @@ func (l *Lexer) readHeredocStart() Token {
 	}
 
 	// Read until we find delimiter on its own line
-	contentStart := l.pos
 	for l.ch != 0 {
-		lineStart := l.pos
 		// Skip leading whitespace if stripTabs
 		if stripTabs {
 			for l.ch == '\t' {
@@ -440,13 +439,8 @@ func (l *Lexer) readHeredocStart() Token {
 		}
 
 		if word == delimiter && (l.ch == '\n' || l.ch == 0) {
-			// Found the end delimiter
-			// Content is everything before this line
-			content := l.input[contentStart:lineStart]
-			if l.ch == '\n' {
-				l.readChar()
-			}
-			_ = content // heredoc content captured
+			// Found the end delimiter. The newline after it ends the
+			// instruction, so it's left for the next token.
 			break
 		}
 
@@ -464,7 +458,9 @@ func (l *Lexer) readHeredocStart() Token {
 
 // Tokenize returns all tokens from the input
 func (l *Lexer) Tokenize() []Token {
-	var tokens []Token
+	// Dockerfiles average a token every six or so bytes; sizing the slice
+	// up front avoids most of the regrowth on large files
+	tokens := make([]Token, 0, len(l.input)/6+1)
 	for {
 		tok := l.NextToken()
 		tokens = append(tokens, tok)
@@ -477,7 +473,7 @@ func (l *Lexer) Tokenize() []Token {
 
 // isWordChar returns true if r can be part of a word
 func isWordChar(r rune) bool {
-	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.' || r == '/'
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.' || r == '/' || r == '*' || r == '?'
 }
 
 // isVarChar returns true if r can be part of a variable name
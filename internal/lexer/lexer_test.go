@@ -127,6 +127,22 @@ func TestLexerVariable(t *testing.T) {
 	}
 }
 
+func TestLexerGlobWord(t *testing.T) {
+	input := `COPY config/id_* *.pe? /keys/`
+	l := New(input)
+	tokens := l.Tokenize()
+
+	var words []string
+	for _, tok := range tokens {
+		if tok.Type == TokenWord {
+			words = append(words, tok.Literal)
+		}
+	}
+	if len(words) != 3 || words[0] != "config/id_*" || words[1] != "*.pe?" {
+		t.Errorf("expected glob patterns as single words, got %q", words)
+	}
+}
+
 func TestLexerFlag(t *testing.T) {
 	input := `COPY --from=builder --chmod=755 /app /app`
 	l := New(input)
@@ -281,3 +297,14 @@ SHELL ["/bin/sh"]
 		}
 	}
 }
+
+func TestLexerOnbuildTrigger(t *testing.T) {
+	tokens := New("ONBUILD copy --chown=app:app . /app\n").Tokenize()
+
+	expected := []TokenType{TokenOnbuild, TokenCopy, TokenFlag}
+	for i, exp := range expected {
+		if tokens[i].Type != exp {
+			t.Errorf("token %d: expected %s, got %s (%q)", i, exp, tokens[i].Type, tokens[i].Literal)
+		}
+	}
+}
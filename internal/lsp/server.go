@@ -0,0 +1,336 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf16"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/cache"
+	"github.com/HueCodes/keel/internal/formatter"
+	"github.com/HueCodes/keel/internal/lexer"
+)
+
+// Server is a Language Server Protocol server for Dockerfiles.
+// It speaks JSON-RPC over the given reader/writer, publishes diagnostics
+// on didOpen/didChange, and answers textDocument/formatting requests.
+type Server struct {
+	in  *bufio.Reader
+	out io.Writer
+
+	writeMu sync.Mutex
+
+	mu   sync.Mutex
+	docs map[string]string
+
+	parser       *cache.CachedParser
+	analyzerOpts []analyzer.Option
+	formatOpts   formatter.Options
+	version      string
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithAnalyzerOptions sets the options used to build the analyzer
+func WithAnalyzerOptions(opts ...analyzer.Option) Option {
+	return func(s *Server) {
+		s.analyzerOpts = append(s.analyzerOpts, opts...)
+	}
+}
+
+// WithFormatterOptions sets the options used for textDocument/formatting
+func WithFormatterOptions(opts formatter.Options) Option {
+	return func(s *Server) {
+		s.formatOpts = opts
+	}
+}
+
+// WithVersion sets the server version reported on initialize
+func WithVersion(version string) Option {
+	return func(s *Server) {
+		s.version = version
+	}
+}
+
+// NewServer creates a new LSP server reading from r and writing to w
+func NewServer(r io.Reader, w io.Writer, opts ...Option) *Server {
+	s := &Server{
+		in:         bufio.NewReader(r),
+		out:        w,
+		docs:       make(map[string]string),
+		parser:     cache.NewCachedParser(cache.NewASTCache()),
+		formatOpts: formatter.DefaultOptions(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// errExit is returned by handle when the client sends exit
+var errExit = errors.New("exit")
+
+// Serve processes messages until the client exits or the input is closed
+func (s *Server) Serve() error {
+	for {
+		body, err := readMessage(s.in)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			if err := s.replyError(nil, codeParseError, err.Error()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := s.handle(&req); err != nil {
+			if errors.Is(err, errExit) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// handle dispatches a single request or notification
+func (s *Server) handle(req *request) error {
+	switch req.Method {
+	case "initialize":
+		return s.reply(req.ID, InitializeResult{
+			Capabilities: ServerCapabilities{
+				TextDocumentSync:           syncFull,
+				DocumentFormattingProvider: true,
+			},
+			ServerInfo: ServerInfo{Name: "keel", Version: s.version},
+		})
+	case "initialized":
+		return nil
+	case "shutdown":
+		return s.reply(req.ID, nil)
+	case "exit":
+		return errExit
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil // notifications cannot be answered
+		}
+		s.setDocument(params.TextDocument.URI, params.TextDocument.Text)
+		return s.publishDiagnostics(params.TextDocument.URI, params.TextDocument.Version)
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil || len(params.ContentChanges) == 0 {
+			return nil
+		}
+		// Full sync: the last change carries the complete text
+		text := params.ContentChanges[len(params.ContentChanges)-1].Text
+		s.setDocument(params.TextDocument.URI, text)
+		return s.publishDiagnostics(params.TextDocument.URI, params.TextDocument.Version)
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil
+		}
+		s.closeDocument(params.TextDocument.URI)
+		// Clear diagnostics for the closed document
+		return s.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+			URI:         params.TextDocument.URI,
+			Diagnostics: []Diagnostic{},
+		})
+	case "textDocument/formatting":
+		var params DocumentFormattingParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.replyError(req.ID, codeInvalidParams, err.Error())
+		}
+		return s.reply(req.ID, s.format(params.TextDocument.URI))
+	default:
+		// Unknown notifications are ignored; unknown requests get an error
+		if req.ID == nil {
+			return nil
+		}
+		return s.replyError(req.ID, codeMethodNotFound, fmt.Sprintf("method not found: %s", req.Method))
+	}
+}
+
+func (s *Server) setDocument(uri, text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.docs[uri] = text
+}
+
+func (s *Server) closeDocument(uri string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.docs, uri)
+	s.parser.Invalidate(uri)
+}
+
+func (s *Server) document(uri string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	text, ok := s.docs[uri]
+	return text, ok
+}
+
+// publishDiagnostics analyzes a document and sends its diagnostics
+func (s *Server) publishDiagnostics(uri string, version int) error {
+	text, ok := s.document(uri)
+	if !ok {
+		return nil
+	}
+
+	// Rules look for files such as .dockerignore next to the Dockerfile,
+	// so they get its path rather than the URI
+	df, parseErrors := s.parser.Parse(uri, text)
+	a := analyzer.New(s.analyzerOpts...)
+	result := a.AnalyzeParsed(df, uriToPath(uri), text, parseErrors)
+
+	lines := strings.Split(text, "\n")
+	diags := make([]Diagnostic, 0, len(result.Diagnostics))
+	for _, d := range result.Diagnostics {
+		diags = append(diags, convertDiagnostic(d, lines))
+	}
+
+	return s.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         uri,
+		Version:     version,
+		Diagnostics: diags,
+	})
+}
+
+// format returns the edits that format a document, or nil if none apply
+func (s *Server) format(uri string) []TextEdit {
+	text, ok := s.document(uri)
+	if !ok {
+		return nil
+	}
+
+	result, err := formatter.New(s.formatOpts).FormatSource(text)
+	if err != nil || !result.HasChanges {
+		return []TextEdit{}
+	}
+
+	// Replace the whole document
+	lines := strings.Split(text, "\n")
+	end := Position{Line: len(lines) - 1, Character: utf16Len(lines[len(lines)-1])}
+	return []TextEdit{{
+		Range:   Range{Start: Position{}, End: end},
+		NewText: result.Formatted,
+	}}
+}
+
+// convertDiagnostic maps an analyzer diagnostic to an LSP diagnostic
+func convertDiagnostic(d analyzer.Diagnostic, lines []string) Diagnostic {
+	start := toPosition(d.Pos, lines)
+	end := toPosition(d.EndPos, lines)
+
+	// Without a usable end, highlight to the end of the start line
+	if d.EndPos.Line == 0 || end.Line < start.Line || (end.Line == start.Line && end.Character <= start.Character) {
+		end = Position{Line: start.Line}
+		if start.Line < len(lines) {
+			end.Character = utf16Len(strings.TrimRight(lines[start.Line], "\r"))
+		}
+	}
+
+	return Diagnostic{
+		Range:    Range{Start: start, End: end},
+		Severity: toSeverity(d.Severity),
+		Code:     d.Rule,
+		Source:   "keel",
+		Message:  d.Message,
+	}
+}
+
+// toPosition converts a 1-based lexer position, whose columns count
+// runes, to a 0-based LSP position, whose characters count UTF-16 code
+// units
+func toPosition(pos lexer.Position, lines []string) Position {
+	p := Position{Line: pos.Line - 1, Character: pos.Column - 1}
+	if p.Line < 0 {
+		p.Line = 0
+	}
+	if p.Character < 0 {
+		p.Character = 0
+	}
+	if p.Line < len(lines) {
+		line := []rune(lines[p.Line])
+		if p.Character <= len(line) {
+			p.Character = utf16Len(string(line[:p.Character]))
+		}
+	}
+	return p
+}
+
+// utf16Len returns the length of s in UTF-16 code units, the LSP's
+// default position encoding
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// uriToPath returns the filesystem path of a file:// URI, or the URI
+// itself for other schemes
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	p := u.Path
+	// file:///C:/dir has the path /C:/dir on Windows
+	if len(p) >= 3 && p[0] == '/' && p[2] == ':' {
+		p = p[1:]
+	}
+	return filepath.FromSlash(p)
+}
+
+// toSeverity maps analyzer severities to LSP severities
+func toSeverity(s analyzer.Severity) DiagnosticSeverity {
+	switch s {
+	case analyzer.SeverityError:
+		return SeverityError
+	case analyzer.SeverityWarning:
+		return SeverityWarning
+	case analyzer.SeverityInfo:
+		return SeverityInformation
+	default:
+		return SeverityHint
+	}
+}
+
+func (s *Server) reply(id *json.RawMessage, result interface{}) error {
+	return s.write(response{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+func (s *Server) replyError(id *json.RawMessage, code int, msg string) error {
+	return s.write(errorResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   &responseError{Code: code, Message: msg},
+	})
+}
+
+func (s *Server) notify(method string, params interface{}) error {
+	return s.write(notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (s *Server) write(msg interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return writeMessage(s.out, msg)
+}
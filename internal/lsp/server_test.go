@@ -0,0 +1,364 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/lexer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/rules/security"
+)
+
+// latestTagRule flags FROM instructions using the latest tag
+type latestTagRule struct{}
+
+func (r *latestTagRule) ID() string                  { return "TEST001" }
+func (r *latestTagRule) Name() string                { return "latest-tag" }
+func (r *latestTagRule) Description() string         { return "test rule" }
+func (r *latestTagRule) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *latestTagRule) Severity() analyzer.Severity { return analyzer.SeverityError }
+
+func (r *latestTagRule) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+	for _, stage := range df.Stages {
+		if stage.From != nil && stage.From.Tag == "latest" {
+			diags = append(diags, analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithMessage("avoid latest").
+				WithPos(stage.From.Pos()).
+				Build())
+		}
+	}
+	return diags
+}
+
+// testClient drives a Server over an in-memory transport
+type testClient struct {
+	t      *testing.T
+	in     *io.PipeWriter
+	out    *bufio.Reader
+	done   chan error
+	nextID int
+}
+
+func newTestClient(t *testing.T, rules ...analyzer.Rule) *testClient {
+	t.Helper()
+
+	if len(rules) == 0 {
+		rules = []analyzer.Rule{&latestTagRule{}}
+	}
+
+	clientToServer, serverIn := io.Pipe()
+	serverOut, clientFromServer := io.Pipe()
+
+	server := NewServer(clientToServer, clientFromServer,
+		WithAnalyzerOptions(
+			analyzer.WithRules(rules...),
+			analyzer.WithMinSeverity(analyzer.SeverityHint),
+		),
+	)
+
+	c := &testClient{
+		t:    t,
+		in:   serverIn,
+		out:  bufio.NewReader(serverOut),
+		done: make(chan error, 1),
+	}
+	go func() {
+		c.done <- server.Serve()
+		clientFromServer.Close()
+	}()
+
+	t.Cleanup(func() {
+		serverIn.Close()
+		select {
+		case <-c.done:
+		case <-time.After(2 * time.Second):
+			t.Error("server did not stop")
+		}
+	})
+	return c
+}
+
+func (c *testClient) send(msg interface{}) {
+	c.t.Helper()
+	if err := writeMessage(c.in, msg); err != nil {
+		c.t.Fatalf("write failed: %v", err)
+	}
+}
+
+func (c *testClient) notify(method string, params interface{}) {
+	c.t.Helper()
+	c.send(map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+func (c *testClient) call(method string, params interface{}) json.RawMessage {
+	c.t.Helper()
+	c.nextID++
+	c.send(map[string]interface{}{"jsonrpc": "2.0", "id": c.nextID, "method": method, "params": params})
+
+	var resp struct {
+		ID     int             `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  *responseError  `json:"error"`
+	}
+	c.receive(&resp)
+	if resp.ID != c.nextID {
+		c.t.Fatalf("expected response id %d, got %d", c.nextID, resp.ID)
+	}
+	if resp.Error != nil {
+		c.t.Fatalf("unexpected error response: %+v", resp.Error)
+	}
+	return resp.Result
+}
+
+func (c *testClient) receive(v interface{}) {
+	c.t.Helper()
+	body, err := readMessage(c.out)
+	if err != nil {
+		c.t.Fatalf("read failed: %v", err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		c.t.Fatalf("invalid message %s: %v", body, err)
+	}
+}
+
+func (c *testClient) receiveDiagnostics() PublishDiagnosticsParams {
+	c.t.Helper()
+	var msg struct {
+		Method string                   `json:"method"`
+		Params PublishDiagnosticsParams `json:"params"`
+	}
+	c.receive(&msg)
+	if msg.Method != "textDocument/publishDiagnostics" {
+		c.t.Fatalf("expected publishDiagnostics, got %q", msg.Method)
+	}
+	return msg.Params
+}
+
+func TestServer_Initialize(t *testing.T) {
+	c := newTestClient(t)
+
+	var result InitializeResult
+	if err := json.Unmarshal(c.call("initialize", map[string]interface{}{}), &result); err != nil {
+		t.Fatalf("invalid initialize result: %v", err)
+	}
+	if result.Capabilities.TextDocumentSync != syncFull {
+		t.Errorf("expected full sync, got %d", result.Capabilities.TextDocumentSync)
+	}
+	if !result.Capabilities.DocumentFormattingProvider {
+		t.Error("expected formatting provider")
+	}
+	if result.ServerInfo.Name != "keel" {
+		t.Errorf("expected server name keel, got %q", result.ServerInfo.Name)
+	}
+}
+
+func TestServer_DidChangeDiagnosticsRoundTrip(t *testing.T) {
+	c := newTestClient(t)
+	c.call("initialize", map[string]interface{}{})
+	c.notify("initialized", map[string]interface{}{})
+
+	uri := "file:///work/Dockerfile"
+	c.notify("textDocument/didOpen", DidOpenTextDocumentParams{
+		TextDocument: TextDocumentItem{URI: uri, LanguageID: "dockerfile", Version: 1, Text: "FROM alpine:3.18\n"},
+	})
+
+	params := c.receiveDiagnostics()
+	if params.URI != uri {
+		t.Errorf("expected uri %q, got %q", uri, params.URI)
+	}
+	if len(params.Diagnostics) != 0 {
+		t.Fatalf("expected no diagnostics, got %d", len(params.Diagnostics))
+	}
+
+	c.notify("textDocument/didChange", DidChangeTextDocumentParams{
+		TextDocument:   VersionedTextDocumentIdentifier{URI: uri, Version: 2},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: "RUN true\nFROM alpine:latest\n"}},
+	})
+
+	params = c.receiveDiagnostics()
+	if params.Version != 2 {
+		t.Errorf("expected version 2, got %d", params.Version)
+	}
+	if len(params.Diagnostics) != 2 {
+		t.Fatalf("expected 2 diagnostics, got %d", len(params.Diagnostics))
+	}
+
+	// RUN before FROM is a parse error
+	if pd := params.Diagnostics[0]; pd.Code != "PARSE" || pd.Range.Start.Line != 0 {
+		t.Errorf("expected a PARSE diagnostic on line 0, got %q on line %d", pd.Code, pd.Range.Start.Line)
+	}
+
+	d := params.Diagnostics[1]
+	if d.Code != "TEST001" {
+		t.Errorf("expected code TEST001, got %q", d.Code)
+	}
+	if d.Severity != SeverityError {
+		t.Errorf("expected severity %d, got %d", SeverityError, d.Severity)
+	}
+	if d.Source != "keel" {
+		t.Errorf("expected source keel, got %q", d.Source)
+	}
+	if d.Range.Start.Line != 1 || d.Range.Start.Character != 0 {
+		t.Errorf("expected start 1:0, got %d:%d", d.Range.Start.Line, d.Range.Start.Character)
+	}
+	if d.Range.End.Line != 1 || d.Range.End.Character != len("FROM alpine:latest") {
+		t.Errorf("expected end 1:%d, got %d:%d", len("FROM alpine:latest"), d.Range.End.Line, d.Range.End.Character)
+	}
+
+	// Fixing the document clears the diagnostic
+	c.notify("textDocument/didChange", DidChangeTextDocumentParams{
+		TextDocument:   VersionedTextDocumentIdentifier{URI: uri, Version: 3},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: "FROM alpine:3.18\n"}},
+	})
+	if params = c.receiveDiagnostics(); len(params.Diagnostics) != 0 {
+		t.Errorf("expected no diagnostics after fix, got %d", len(params.Diagnostics))
+	}
+
+	c.notify("textDocument/didClose", DidCloseTextDocumentParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+	})
+	if params = c.receiveDiagnostics(); len(params.Diagnostics) != 0 {
+		t.Errorf("expected diagnostics cleared on close, got %d", len(params.Diagnostics))
+	}
+}
+
+func TestServer_Formatting(t *testing.T) {
+	c := newTestClient(t)
+	c.call("initialize", map[string]interface{}{})
+
+	uri := "file:///work/Dockerfile"
+	c.notify("textDocument/didOpen", DidOpenTextDocumentParams{
+		TextDocument: TextDocumentItem{URI: uri, Version: 1, Text: "from alpine:3.18\nrun echo hi\n"},
+	})
+	c.receiveDiagnostics()
+
+	var edits []TextEdit
+	result := c.call("textDocument/formatting", DocumentFormattingParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+	})
+	if err := json.Unmarshal(result, &edits); err != nil {
+		t.Fatalf("invalid formatting result: %v", err)
+	}
+	if len(edits) != 1 {
+		t.Fatalf("expected 1 edit, got %d", len(edits))
+	}
+	if !strings.HasPrefix(edits[0].NewText, "FROM alpine:3.18\nRUN echo hi") {
+		t.Errorf("unexpected formatted text %q", edits[0].NewText)
+	}
+	if edits[0].Range.End.Line != 2 {
+		t.Errorf("expected edit to cover the whole document, got end line %d", edits[0].Range.End.Line)
+	}
+}
+
+func TestServer_ShutdownExit(t *testing.T) {
+	c := newTestClient(t)
+	c.call("initialize", map[string]interface{}{})
+
+	if result := c.call("shutdown", nil); string(result) != "null" {
+		t.Errorf("expected null shutdown result, got %s", result)
+	}
+	c.notify("exit", nil)
+
+	select {
+	case err := <-c.done:
+		if err != nil {
+			t.Errorf("expected clean exit, got %v", err)
+		}
+		c.done <- err
+	case <-time.After(2 * time.Second):
+		t.Fatal("server did not exit")
+	}
+}
+
+func TestServer_UnknownMethod(t *testing.T) {
+	c := newTestClient(t)
+
+	c.send(map[string]interface{}{"jsonrpc": "2.0", "id": 7, "method": "textDocument/hover"})
+
+	var resp struct {
+		ID    int            `json:"id"`
+		Error *responseError `json:"error"`
+	}
+	c.receive(&resp)
+	if resp.Error == nil || resp.Error.Code != codeMethodNotFound {
+		t.Errorf("expected method-not-found error, got %+v", resp.Error)
+	}
+}
+
+func TestConvertDiagnostic_UTF16(t *testing.T) {
+	// "é" is one UTF-16 unit and "😀" two, though each is a single rune
+	lines := []string{"LABEL é=😀 x=1", "RUN 😀"}
+	d := analyzer.Diagnostic{
+		Pos:    lexer.Position{Line: 1, Column: 12},
+		EndPos: lexer.Position{Line: 1, Column: 14},
+	}
+	got := convertDiagnostic(d, lines).Range
+	want := Range{Start: Position{Line: 0, Character: 12}, End: Position{Line: 0, Character: 14}}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	// Without an end, the range runs to the end of the line
+	d = analyzer.Diagnostic{Pos: lexer.Position{Line: 2, Column: 1}}
+	got = convertDiagnostic(d, lines).Range
+	want = Range{Start: Position{Line: 1, Character: 0}, End: Position{Line: 1, Character: 6}}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestServer_AnalyzesFilePath(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".dockerignore"), []byte(".env\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := newTestClient(t, &security.SEC012DockerignoreSecrets{})
+	c.call("initialize", map[string]interface{}{})
+	c.notify("initialized", map[string]interface{}{})
+
+	uri := "file://" + filepath.ToSlash(filepath.Join(dir, "Dockerfile"))
+	c.notify("textDocument/didOpen", DidOpenTextDocumentParams{
+		TextDocument: TextDocumentItem{URI: uri, LanguageID: "dockerfile", Version: 1, Text: "FROM alpine:3.18\nCOPY . /app\n"},
+	})
+
+	params := c.receiveDiagnostics()
+	if params.URI != uri {
+		t.Errorf("expected uri %q, got %q", uri, params.URI)
+	}
+	if len(params.Diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(params.Diagnostics))
+	}
+	// The .dockerignore next to the Dockerfile is found, so only the
+	// patterns it's missing are reported
+	if msg := params.Diagnostics[0].Message; !strings.Contains(msg, "not excluded by .dockerignore") {
+		t.Errorf("expected the .dockerignore to be read, got %q", msg)
+	}
+}
+
+func TestURIToPath(t *testing.T) {
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{"file:///work/Dockerfile", filepath.FromSlash("/work/Dockerfile")},
+		{"file:///work/my%20app/Dockerfile", filepath.FromSlash("/work/my app/Dockerfile")},
+		{"file:///C:/work/Dockerfile", filepath.FromSlash("C:/work/Dockerfile")},
+		{"untitled:Untitled-1", "untitled:Untitled-1"},
+	}
+
+	for _, tt := range tests {
+		if got := uriToPath(tt.uri); got != tt.want {
+			t.Errorf("uriToPath(%q) = %q, want %q", tt.uri, got, tt.want)
+		}
+	}
+}
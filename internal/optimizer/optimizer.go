@@ -6,6 +6,7 @@ import (
 	"github.com/HueCodes/keel/internal/analyzer"
 	"github.com/HueCodes/keel/internal/optimizer/transforms"
 	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
 )
 
 // Transform is the interface for AST transformations
@@ -131,7 +132,9 @@ type Change struct {
 	Applied     bool
 }
 
-// AllTransforms returns all available transforms
+// AllTransforms returns all available transforms. Pinning image digests
+// needs the network, so transforms.PinImageTagTransform is only added by
+// keel fix --pin-images.
 func AllTransforms() []Transform {
 	return []Transform{
 		// Existing transforms
@@ -139,12 +142,16 @@ func AllTransforms() []Transform {
 		&AddCacheCleanup{},
 		&AddNoInstallRecommends{},
 		// New transforms
-		&transforms.RemoveSudoTransform{},         // SEC005
-		&transforms.AddToCopyTransform{},          // BP002
-		&transforms.MaintainerToLabelTransform{},  // BP004
-		&transforms.WorkdirAbsoluteTransform{},    // BP005
-		&transforms.PinImageTagTransform{},        // SEC003 (requires Client to be set)
-		&transforms.ReorderCopyTransform{},        // PERF001
+		&transforms.RemoveSudoTransform{},             // SEC005
+		&transforms.AddToCopyTransform{},              // BP002
+		&transforms.MaintainerToLabelTransform{},      // BP004
+		&transforms.WorkdirAbsoluteTransform{},        // BP005
+		&transforms.ReorderCopyTransform{},            // PERF001
+		&transforms.HoistFromArgTransform{},           // BP009
+		&transforms.EnvEqualsFormTransform{},          // STY003
+		&transforms.DedupeExposeTransform{},           // STY004
+		&transforms.MergeAptUpdateTransform{},         // PERF008
+		&transforms.StripRedundantPlatformTransform{}, // STY006
 	}
 }
 
@@ -187,9 +194,16 @@ func mergeConsecutiveRuns(instructions []parser.Instruction, changed *bool) []pa
 		runGroup = nil
 	}
 
+	// Commands can only be joined with && under a POSIX shell
+	posix := true
+
 	for _, inst := range instructions {
+		if sh, ok := inst.(*parser.ShellInstruction); ok {
+			posix = shell.IsPOSIX(sh.Shell)
+		}
+
 		run, isRun := inst.(*parser.RunInstruction)
-		if isRun && canMergeRun(run) {
+		if isRun && posix && canMergeRun(run) {
 			runGroup = append(runGroup, run)
 		} else {
 			flushRunGroup()
@@ -202,7 +216,7 @@ func mergeConsecutiveRuns(instructions []parser.Instruction, changed *bool) []pa
 }
 
 func canMergeRun(run *parser.RunInstruction) bool {
-	if run.Heredoc != nil || run.IsExec || run.Mount != "" {
+	if run.Heredoc != nil || run.IsExec || len(run.Mounts) > 0 {
 		return false
 	}
 	return true
@@ -217,17 +231,20 @@ func mergeRuns(runs []*parser.RunInstruction) *parser.RunInstruction {
 	}
 
 	var commands []string
+	var comments []*parser.Comment
 	for _, run := range runs {
 		cmd := strings.TrimSpace(run.Command)
 		if cmd != "" {
-			commands = append(commands, cmd)
+			commands = append(commands, shell.Group(cmd))
 		}
+		comments = append(comments, run.Comments...)
 	}
 
 	return &parser.RunInstruction{
 		BaseInstruction: parser.BaseInstruction{
 			StartPos: runs[0].Pos(),
 			EndPos:   runs[len(runs)-1].End(),
+			Comments: comments,
 		},
 		Command: strings.Join(commands, " && "),
 	}
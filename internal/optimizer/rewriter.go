@@ -57,6 +57,15 @@ func (r *Rewriter) Rewrite(df *parser.Dockerfile) string {
 		sb.WriteString("\n")
 	}
 
+	// Write global ARGs
+	for _, arg := range df.Args {
+		writeComments(&sb, arg.LeadingComments())
+		r.writeArg(&sb, arg)
+	}
+	if len(df.Args) > 0 && len(df.Stages) > 0 {
+		sb.WriteString("\n")
+	}
+
 	// Write stages
 	for i, stage := range df.Stages {
 		if i > 0 {
@@ -69,21 +78,28 @@ func (r *Rewriter) Rewrite(df *parser.Dockerfile) string {
 }
 
 func (r *Rewriter) writeStage(sb *strings.Builder, stage *parser.Stage) {
-	// Write stage comments
-	for _, comment := range stage.Comments {
-		sb.WriteString(comment.Text)
-		sb.WriteString("\n")
-	}
-
 	// Write FROM instruction
 	if stage.From != nil {
+		writeComments(sb, stage.From.LeadingComments())
 		r.writeFrom(sb, stage.From)
 	}
 
-	// Write other instructions
+	// Write other instructions, each after its comments, so that comments
+	// move with reordered instructions
 	for _, inst := range stage.Instructions {
+		writeComments(sb, inst.LeadingComments())
 		r.writeInstruction(sb, inst)
 	}
+
+	// Write comments from the end of the stage
+	writeComments(sb, stage.Comments)
+}
+
+func writeComments(sb *strings.Builder, comments []*parser.Comment) {
+	for _, comment := range comments {
+		sb.WriteString(comment.Text)
+		sb.WriteString("\n")
+	}
 }
 
 func (r *Rewriter) writeFrom(sb *strings.Builder, from *parser.FromInstruction) {
@@ -157,9 +173,9 @@ func (r *Rewriter) writeInstruction(sb *strings.Builder, inst parser.Instruction
 func (r *Rewriter) writeRun(sb *strings.Builder, run *parser.RunInstruction) {
 	sb.WriteString("RUN ")
 
-	if run.Mount != "" {
+	for _, mount := range run.Mounts {
 		sb.WriteString("--mount=")
-		sb.WriteString(run.Mount)
+		sb.WriteString(mount)
 		sb.WriteString(" ")
 	}
 
@@ -169,8 +185,21 @@ func (r *Rewriter) writeRun(sb *strings.Builder, run *parser.RunInstruction) {
 		sb.WriteString(" ")
 	}
 
+	if run.Security != "" {
+		sb.WriteString("--security=")
+		sb.WriteString(run.Security)
+		sb.WriteString(" ")
+	}
+
 	if run.Heredoc != nil {
+		sb.WriteString(run.Heredoc.Marker())
+		if run.Command != "" {
+			sb.WriteString(" ")
+			sb.WriteString(run.Command)
+		}
+		sb.WriteString("\n")
 		sb.WriteString(run.Heredoc.Content)
+		sb.WriteString(run.Heredoc.Delimiter)
 	} else if run.IsExec {
 		r.writeExecForm(sb, run.Arguments)
 	} else {
@@ -290,19 +319,21 @@ func (r *Rewriter) writeAdd(sb *strings.Builder, add *parser.AddInstruction) {
 
 func (r *Rewriter) writeEnv(sb *strings.Builder, env *parser.EnvInstruction) {
 	sb.WriteString("ENV ")
+	if env.Legacy && len(env.Variables) == 1 {
+		// Keep the original form; the legacy value runs to the end of the line
+		sb.WriteString(env.Variables[0].Key)
+		sb.WriteString(" ")
+		sb.WriteString(env.Variables[0].Value)
+		sb.WriteString("\n")
+		return
+	}
 	for i, kv := range env.Variables {
 		if i > 0 {
 			sb.WriteString(" ")
 		}
 		sb.WriteString(kv.Key)
 		sb.WriteString("=")
-		if strings.Contains(kv.Value, " ") {
-			sb.WriteString("\"")
-			sb.WriteString(kv.Value)
-			sb.WriteString("\"")
-		} else {
-			sb.WriteString(kv.Value)
-		}
+		sb.WriteString(parser.QuoteValue(kv.Value, kv.Quote))
 	}
 	sb.WriteString("\n")
 }
@@ -312,7 +343,7 @@ func (r *Rewriter) writeArg(sb *strings.Builder, arg *parser.ArgInstruction) {
 	sb.WriteString(arg.Name)
 	if arg.HasDefault {
 		sb.WriteString("=")
-		sb.WriteString(arg.DefaultValue)
+		sb.WriteString(parser.QuoteValue(arg.DefaultValue, arg.DefaultQuote))
 	}
 	sb.WriteString("\n")
 }
@@ -325,13 +356,7 @@ func (r *Rewriter) writeLabel(sb *strings.Builder, label *parser.LabelInstructio
 		}
 		sb.WriteString(kv.Key)
 		sb.WriteString("=")
-		if strings.Contains(kv.Value, " ") {
-			sb.WriteString("\"")
-			sb.WriteString(kv.Value)
-			sb.WriteString("\"")
-		} else {
-			sb.WriteString(kv.Value)
-		}
+		sb.WriteString(parser.QuoteValue(kv.Value, kv.Quote))
 	}
 	sb.WriteString("\n")
 }
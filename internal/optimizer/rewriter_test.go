@@ -0,0 +1,115 @@
+package optimizer
+
+import (
+	"testing"
+
+	"github.com/HueCodes/keel/internal/optimizer/transforms"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+func TestRewriter_EnvForms(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		transform bool
+		expected  string
+	}{
+		{
+			name:     "legacy form preserved",
+			input:    "FROM alpine\nENV GREETING hello world\n",
+			expected: "FROM alpine\nENV GREETING hello world\n",
+		},
+		{
+			name:      "legacy form rewritten",
+			input:     "FROM alpine\nENV GREETING hello world\n",
+			transform: true,
+			expected:  "FROM alpine\nENV GREETING=\"hello world\"\n",
+		},
+		{
+			name:      "simple value unquoted",
+			input:     "FROM alpine\nENV APP_HOME /app\n",
+			transform: true,
+			expected:  "FROM alpine\nENV APP_HOME=/app\n",
+		},
+		{
+			name:      "variable unquoted",
+			input:     "FROM alpine\nENV DATA $HOME/data\n",
+			transform: true,
+			expected:  "FROM alpine\nENV DATA=$HOME/data\n",
+		},
+		{
+			name:     "single quotes preserved",
+			input:    "FROM alpine\nARG PATTERN='*.$EXT'\nENV PROMPT='$USER> ' HOME_DIR=\"$HOME\"\nLABEL price='$5'\n",
+			expected: "FROM alpine\nARG PATTERN='*.$EXT'\nENV PROMPT='$USER> ' HOME_DIR=\"$HOME\"\nLABEL price='$5'\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			df, errs := parser.Parse(tt.input)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected parse errors: %v", errs)
+			}
+			if tt.transform {
+				(&transforms.EnvEqualsFormTransform{}).Transform(df, nil)
+			}
+			if got := NewRewriter().Rewrite(df); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRewriter_Onbuild(t *testing.T) {
+	input := "FROM alpine\nONBUILD COPY --chown=app:app . /app\nONBUILD RUN [\"echo\", \"x\"]\n"
+
+	df, errs := parser.Parse(input)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected parse errors: %v", errs)
+	}
+	if got := NewRewriter().Rewrite(df); got != input {
+		t.Errorf("got %q, want %q", got, input)
+	}
+}
+
+func TestRewriter_CommentsMoveWithInstructions(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		transform Transform
+		expected  string
+	}{
+		{
+			name:     "comments kept in place",
+			input:    "# header\nFROM node:20\n# deps\nRUN npm ci\n# end of stage\n\nFROM alpine\n# run\nRUN true\n",
+			expected: "# header\nFROM node:20\n# deps\nRUN npm ci\n# end of stage\n\nFROM alpine\n# run\nRUN true\n",
+		},
+		{
+			name:      "reordered COPY keeps its comment",
+			input:     "FROM node:20\n# copy sources\nCOPY . /app\n# install\nRUN npm install\n",
+			transform: &transforms.ReorderCopyTransform{},
+			expected:  "FROM node:20\n# install\nRUN npm install\n# copy sources\nCOPY . /app\n",
+		},
+		{
+			name:      "merged RUNs keep both comments",
+			input:     "FROM alpine\n# first\nRUN echo a\n# second\nRUN echo b\n",
+			transform: &MergeRun{},
+			expected:  "FROM alpine\n# first\n# second\nRUN echo a \\\n    && echo b\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			df, errs := parser.Parse(tt.input)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected parse errors: %v", errs)
+			}
+			if tt.transform != nil {
+				tt.transform.Transform(df, nil)
+			}
+			if got := NewRewriter().Rewrite(df); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
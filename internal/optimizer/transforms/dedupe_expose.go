@@ -0,0 +1,40 @@
+package transforms
+
+import (
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// DedupeExposeTransform removes ports listed more than once in an EXPOSE
+type DedupeExposeTransform struct{}
+
+func (t *DedupeExposeTransform) Name() string {
+	return "dedupe-expose"
+}
+
+func (t *DedupeExposeTransform) Description() string {
+	return "Remove duplicate ports from EXPOSE"
+}
+
+func (t *DedupeExposeTransform) Rules() []string {
+	return []string{"STY004"}
+}
+
+func (t *DedupeExposeTransform) Transform(df *parser.Dockerfile, diags []analyzer.Diagnostic) bool {
+	changed := false
+
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			expose, ok := inst.(*parser.ExposeInstruction)
+			if !ok {
+				continue
+			}
+			if unique := expose.UniquePorts(); len(unique) < len(expose.Ports) {
+				expose.Ports = unique
+				changed = true
+			}
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,30 @@
+package transforms
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+func TestDedupeExposeTransform(t *testing.T) {
+	df, errs := parser.Parse("FROM alpine\nEXPOSE 80 80/tcp 53/udp 53\n")
+	if len(errs) > 0 {
+		t.Fatalf("unexpected parse errors: %v", errs)
+	}
+
+	tr := &DedupeExposeTransform{}
+	if !tr.Transform(df, nil) {
+		t.Fatal("expected transform to report changes")
+	}
+
+	expose := df.Stages[0].Instructions[0].(*parser.ExposeInstruction)
+	expected := []parser.PortSpec{{Port: "80"}, {Port: "53", Protocol: "udp"}, {Port: "53"}}
+	if !reflect.DeepEqual(expose.Ports, expected) {
+		t.Errorf("expected %v, got %v", expected, expose.Ports)
+	}
+
+	if tr.Transform(df, nil) {
+		t.Error("expected no changes on second run")
+	}
+}
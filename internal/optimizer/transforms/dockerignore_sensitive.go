@@ -0,0 +1,230 @@
+package transforms
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/rules/security"
+)
+
+// DockerignoreSensitiveTransform adds the sensitive files that a COPY of
+// the whole build context would sweep into the image to the .dockerignore
+// next to the Dockerfile, creating it if needed. Files that a COPY or ADD
+// names explicitly are left out, since ignoring them would break the
+// build. Unlike the other transforms it leaves the AST alone and edits
+// another file, so keel fix only runs it with --write.
+type DockerignoreSensitiveTransform struct {
+	// Dockerfile is the path of the Dockerfile being fixed. Its directory
+	// is taken to be the build context.
+	Dockerfile string
+
+	// ReadFile, WriteFile, and WalkDir access the build context; they
+	// default to os.ReadFile, os.WriteFile, and filepath.WalkDir
+	ReadFile  func(name string) ([]byte, error)
+	WriteFile func(name string, data []byte, perm os.FileMode) error
+	WalkDir   func(root string, fn fs.WalkDirFunc) error
+
+	// Path and Added record the file and patterns the last Transform
+	// call wrote; Err holds the error if writing it failed
+	Path  string
+	Added []string
+	Err   error
+}
+
+func (t *DockerignoreSensitiveTransform) Name() string {
+	return "dockerignore-sensitive"
+}
+
+func (t *DockerignoreSensitiveTransform) Description() string {
+	return "Add sensitive files in the build context to .dockerignore"
+}
+
+func (t *DockerignoreSensitiveTransform) Rules() []string {
+	return []string{"SEC012"}
+}
+
+func (t *DockerignoreSensitiveTransform) Transform(df *parser.Dockerfile, diags []analyzer.Diagnostic) bool {
+	t.Path, t.Added, t.Err = "", nil, nil
+
+	triggered := false
+	for _, d := range diags {
+		if d.Rule == "SEC012" {
+			triggered = true
+		}
+	}
+	if !triggered {
+		return false
+	}
+
+	broad, explicit := copySources(df)
+	if !broad {
+		return false
+	}
+
+	readFile, writeFile, walkDir := t.ReadFile, t.WriteFile, t.WalkDir
+	if readFile == nil {
+		readFile = os.ReadFile
+	}
+	if writeFile == nil {
+		writeFile = os.WriteFile
+	}
+	if walkDir == nil {
+		walkDir = filepath.WalkDir
+	}
+
+	// A <Dockerfile>.dockerignore takes precedence, as in BuildKit
+	name := t.Dockerfile + ".dockerignore"
+	data, err := readFile(name)
+	if err != nil {
+		name = filepath.Join(filepath.Dir(t.Dockerfile), ".dockerignore")
+		data, _ = readFile(name)
+	}
+
+	var existing []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			existing = append(existing, line)
+		}
+	}
+
+	var patterns []string
+	for _, p := range sensitiveContextFiles(filepath.Dir(t.Dockerfile), walkDir) {
+		if !namedBy(p, explicit) && !excludedBy(p, existing) {
+			patterns = append(patterns, p)
+		}
+	}
+	if len(patterns) == 0 {
+		return false
+	}
+
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	for _, p := range patterns {
+		content += p + "\n"
+	}
+
+	if err := writeFile(name, []byte(content), 0644); err != nil {
+		t.Err = err
+		return false
+	}
+	t.Path, t.Added = name, patterns
+	return true
+}
+
+// copySources reports whether the Dockerfile copies the whole build
+// context, and returns the context sources its COPY and ADD instructions
+// name explicitly, as .dockerignore patterns
+func copySources(df *parser.Dockerfile) (broad bool, explicit []string) {
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			var sources []string
+			switch v := inst.(type) {
+			case *parser.CopyInstruction:
+				if v.From != "" {
+					continue
+				}
+				sources = v.Sources
+			case *parser.AddInstruction:
+				sources = v.Sources
+			}
+			for _, src := range sources {
+				switch {
+				case isBroadSource(src):
+					broad = true
+				case !strings.Contains(src, "://"):
+					explicit = append(explicit, ignorePattern(src))
+				}
+			}
+		}
+	}
+	return broad, explicit
+}
+
+// Directories that are sensitive as a whole, and not worth walking
+var sensitiveDirs = map[string]bool{
+	".git": true, ".ssh": true, ".aws": true, ".kube": true,
+}
+
+// sensitiveContextFiles walks the build context and returns the
+// sensitive files and directories in it, relative to the context with
+// forward slashes
+func sensitiveContextFiles(dir string, walkDir func(string, fs.WalkDirFunc) error) []string {
+	var found []string
+	walkDir(dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, relErr := filepath.Rel(dir, name)
+		if relErr != nil || rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if sensitiveDirs[d.Name()] {
+				found = append(found, rel)
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if sensitive, _ := security.IsSensitiveFile(rel); sensitive {
+			found = append(found, rel)
+		}
+		return nil
+	})
+	return found
+}
+
+// namedBy reports whether excluding pattern p would remove a file that
+// one of the explicit sources copies: the source itself, a file under
+// it, or a file a glob source matches
+func namedBy(p string, sources []string) bool {
+	for _, src := range sources {
+		if src == p || strings.HasPrefix(src, p+"/") {
+			return true
+		}
+		if matched, _ := path.Match(src, p); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// excludedBy reports whether the .dockerignore patterns already exclude
+// file, directly, by glob, or by excluding a directory containing it.
+// Later patterns win, and ! re-includes.
+func excludedBy(file string, patterns []string) bool {
+	excluded := false
+	for _, p := range patterns {
+		negate := strings.HasPrefix(p, "!")
+		p = strings.TrimPrefix(p, "!")
+		anyDepth := strings.HasPrefix(p, "**/")
+		p = ignorePattern(strings.TrimPrefix(p, "**/"))
+
+		matched := p == file || strings.HasPrefix(file, p+"/")
+		if !matched {
+			matched, _ = path.Match(p, file)
+		}
+		if !matched && anyDepth {
+			matched, _ = path.Match(p, path.Base(file))
+		}
+		if matched {
+			excluded = !negate
+		}
+	}
+	return excluded
+}
+
+// ignorePattern normalizes a COPY source into a .dockerignore pattern
+func ignorePattern(src string) string {
+	src = strings.TrimPrefix(src, "./")
+	src = strings.TrimPrefix(src, "/")
+	return strings.TrimSuffix(src, "/")
+}
@@ -0,0 +1,172 @@
+package transforms
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/lexer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// sec012At returns a SEC012 diagnostic on the given line
+func sec012At(line int) []analyzer.Diagnostic {
+	return []analyzer.Diagnostic{{Rule: "SEC012", Pos: lexer.Position{Line: line, Column: 1}}}
+}
+
+// writeContext creates the files of a build context under dir
+func writeContext(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDockerignoreSensitiveTransform(t *testing.T) {
+	context := map[string]string{
+		".env":          "TOKEN=x\n",
+		"config/id_rsa": "key\n",
+		"app.js":        "\n",
+	}
+
+	tests := []struct {
+		name       string
+		dockerfile string
+		existing   map[string]string // extra files, such as ignore files
+		expected   string
+		file       string
+	}{
+		{
+			name:       "creates .dockerignore",
+			dockerfile: "FROM node:20\nCOPY . /app/\n",
+			expected:   ".env\nconfig/id_rsa\n",
+			file:       ".dockerignore",
+		},
+		{
+			name:       "appends without duplicating",
+			dockerfile: "FROM node:20\nCOPY . /app/\n",
+			existing:   map[string]string{".dockerignore": "node_modules\n./.env"},
+			expected:   "node_modules\n./.env\nconfig/id_rsa\n",
+			file:       ".dockerignore",
+		},
+		{
+			name:       "prefers the Dockerfile's own ignore file",
+			dockerfile: "FROM node:20\nCOPY . /app/\n",
+			existing:   map[string]string{".dockerignore": "", "Dockerfile.dockerignore": "*.md\n"},
+			expected:   "*.md\n.env\nconfig/id_rsa\n",
+			file:       "Dockerfile.dockerignore",
+		},
+		{
+			name:       "leaves out explicitly copied files",
+			dockerfile: "FROM node:20\nCOPY .env /app/.env\nCOPY . /app/\n",
+			expected:   "config/id_rsa\n",
+			file:       ".dockerignore",
+		},
+		{
+			name:       "leaves out files matched by a glob source",
+			dockerfile: "FROM node:20\nCOPY config/id_* /keys/\nCOPY ./ /app/\n",
+			expected:   ".env\n",
+			file:       ".dockerignore",
+		},
+		{
+			name:       "sensitive directory",
+			dockerfile: "FROM node:20\nCOPY . /app/\n",
+			existing:   map[string]string{".git/HEAD": "ref: refs/heads/main\n", ".dockerignore": ".env\nconfig/\n"},
+			expected:   ".env\nconfig/\n.git\n",
+			file:       ".dockerignore",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeContext(t, dir, context)
+			writeContext(t, dir, tt.existing)
+
+			df, errs := parser.Parse(tt.dockerfile)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected parse errors: %v", errs)
+			}
+
+			tr := &DockerignoreSensitiveTransform{Dockerfile: filepath.Join(dir, "Dockerfile")}
+			if !tr.Transform(df, sec012At(2)) {
+				t.Fatalf("expected transform to report changes (err: %v)", tr.Err)
+			}
+			if tr.Path != filepath.Join(dir, tt.file) {
+				t.Errorf("expected %s to be written, got %s", tt.file, tr.Path)
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("expected contents:\n%q\ngot:\n%q", tt.expected, string(data))
+			}
+
+			if tr.Transform(df, sec012At(2)) {
+				t.Error("expected no changes on second run")
+			}
+		})
+	}
+}
+
+func TestDockerignoreSensitiveTransform_ExplicitCopyOnly(t *testing.T) {
+	dir := t.TempDir()
+	writeContext(t, dir, map[string]string{".env": "TOKEN=x\n"})
+
+	// Ignoring .env would make this COPY fail, and there is no broad COPY
+	// that could sweep it in by accident
+	df, _ := parser.Parse("FROM node:20\nCOPY .env /app/.env\n")
+	tr := &DockerignoreSensitiveTransform{
+		Dockerfile: filepath.Join(dir, "Dockerfile"),
+		WriteFile: func(string, []byte, os.FileMode) error {
+			t.Error("expected no write")
+			return nil
+		},
+	}
+	if tr.Transform(df, sec012At(2)) {
+		t.Error("expected no changes for an explicit COPY")
+	}
+}
+
+func TestDockerignoreSensitiveTransform_NoDiagnostics(t *testing.T) {
+	df, _ := parser.Parse("FROM node:20\nCOPY . /app/\n")
+	tr := &DockerignoreSensitiveTransform{
+		Dockerfile: "Dockerfile",
+		WriteFile: func(string, []byte, os.FileMode) error {
+			t.Error("expected no write")
+			return nil
+		},
+	}
+	if tr.Transform(df, nil) {
+		t.Error("expected no changes without SEC012 diagnostics")
+	}
+}
+
+func TestDockerignoreSensitiveTransform_WriteError(t *testing.T) {
+	dir := t.TempDir()
+	writeContext(t, dir, map[string]string{".env": "TOKEN=x\n"})
+
+	df, _ := parser.Parse("FROM node:20\nCOPY . /app/\n")
+	failed := errors.New("read-only file system")
+	tr := &DockerignoreSensitiveTransform{
+		Dockerfile: filepath.Join(dir, "Dockerfile"),
+		ReadFile:   func(string) ([]byte, error) { return nil, os.ErrNotExist },
+		WriteFile:  func(string, []byte, os.FileMode) error { return failed },
+	}
+	if tr.Transform(df, sec012At(2)) {
+		t.Error("expected no changes when the write fails")
+	}
+	if !errors.Is(tr.Err, failed) {
+		t.Errorf("expected the write error to be recorded, got %v", tr.Err)
+	}
+}
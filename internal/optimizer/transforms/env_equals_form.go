@@ -0,0 +1,39 @@
+package transforms
+
+import (
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// EnvEqualsFormTransform rewrites legacy ENV key value to ENV key=value
+type EnvEqualsFormTransform struct{}
+
+func (t *EnvEqualsFormTransform) Name() string {
+	return "env-equals-form"
+}
+
+func (t *EnvEqualsFormTransform) Description() string {
+	return "Rewrite legacy ENV key value to ENV key=value"
+}
+
+func (t *EnvEqualsFormTransform) Rules() []string {
+	return []string{"STY003"}
+}
+
+func (t *EnvEqualsFormTransform) Transform(df *parser.Dockerfile, diags []analyzer.Diagnostic) bool {
+	changed := false
+
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			env, ok := inst.(*parser.EnvInstruction)
+			if !ok || !env.Legacy {
+				continue
+			}
+			// The rewriter writes key=value unless the legacy form is marked
+			env.Legacy = false
+			changed = true
+		}
+	}
+
+	return changed
+}
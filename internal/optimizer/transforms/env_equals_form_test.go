@@ -0,0 +1,31 @@
+package transforms
+
+import (
+	"testing"
+
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+func TestEnvEqualsFormTransform(t *testing.T) {
+	df, errs := parser.Parse("FROM alpine\nENV GREETING hello world\nENV A=1\n")
+	if len(errs) > 0 {
+		t.Fatalf("unexpected parse errors: %v", errs)
+	}
+
+	tr := &EnvEqualsFormTransform{}
+	if !tr.Transform(df, nil) {
+		t.Fatal("expected transform to report changes")
+	}
+
+	env := df.Stages[0].Instructions[0].(*parser.EnvInstruction)
+	if env.Legacy {
+		t.Error("expected legacy marker to be cleared")
+	}
+	if env.Variables[0].Value != "hello world" {
+		t.Errorf("expected value to be preserved, got %q", env.Variables[0].Value)
+	}
+
+	if tr.Transform(df, nil) {
+		t.Error("expected no changes on second run")
+	}
+}
@@ -0,0 +1,147 @@
+package transforms
+
+import (
+	"slices"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// HoistFromArgTransform moves an ARG declared inside a stage, but only
+// used by that stage's FROM, to the global scope before the first FROM
+type HoistFromArgTransform struct{}
+
+func (t *HoistFromArgTransform) Name() string {
+	return "hoist-from-arg"
+}
+
+func (t *HoistFromArgTransform) Description() string {
+	return "Move ARGs used only by FROM before the first FROM"
+}
+
+func (t *HoistFromArgTransform) Rules() []string {
+	return []string{"BP009"}
+}
+
+func (t *HoistFromArgTransform) Transform(df *parser.Dockerfile, diags []analyzer.Diagnostic) bool {
+	changed := false
+
+	global := make(map[string]bool)
+	for _, arg := range df.Args {
+		global[arg.Name] = true
+	}
+
+	for _, stage := range df.Stages {
+		if stage.From == nil {
+			continue
+		}
+
+		refs := make(map[string]bool)
+		for _, field := range []string{stage.From.Platform, stage.From.Image, stage.From.Tag, stage.From.Digest} {
+			for _, name := range shell.Vars(field) {
+				refs[name] = true
+			}
+		}
+
+		for i := 0; i < len(stage.Instructions); i++ {
+			arg, ok := stage.Instructions[i].(*parser.ArgInstruction)
+			if !ok || !refs[arg.Name] || global[arg.Name] {
+				continue
+			}
+
+			rest := make([]parser.Instruction, 0, len(stage.Instructions)-1)
+			rest = append(rest, stage.Instructions[:i]...)
+			rest = append(rest, stage.Instructions[i+1:]...)
+
+			// Moving the ARG out of the stage makes it unset there, so
+			// only hoist it when nothing else in the stage needs it
+			if referencesVar(rest, arg.Name) {
+				continue
+			}
+
+			stage.Instructions = rest
+			df.Args = append(df.Args, arg)
+			global[arg.Name] = true
+			changed = true
+			i--
+		}
+	}
+
+	return changed
+}
+
+// referencesVar reports whether any of the instructions expands $name or ${name}
+func referencesVar(insts []parser.Instruction, name string) bool {
+	for _, inst := range insts {
+		for _, field := range instructionFields(inst) {
+			if slices.Contains(shell.Vars(field), name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// instructionFields returns the values of an instruction that can
+// reference variables
+func instructionFields(inst parser.Instruction) []string {
+	switch v := inst.(type) {
+	case *parser.FromInstruction:
+		return []string{v.Platform, v.Image, v.Tag, v.Digest}
+	case *parser.RunInstruction:
+		fields := append([]string{v.Command, v.Network, v.Security}, v.Arguments...)
+		fields = append(fields, v.Mounts...)
+		if v.Heredoc != nil {
+			fields = append(fields, v.Heredoc.Content)
+		}
+		return fields
+	case *parser.CmdInstruction:
+		return append([]string{v.Command}, v.Arguments...)
+	case *parser.EntrypointInstruction:
+		return append([]string{v.Command}, v.Arguments...)
+	case *parser.CopyInstruction:
+		return append([]string{v.Destination, v.From, v.Chown, v.Chmod}, v.Sources...)
+	case *parser.AddInstruction:
+		return append([]string{v.Destination, v.Chown, v.Chmod, v.Checksum}, v.Sources...)
+	case *parser.EnvInstruction:
+		return keyValueFields(v.Variables)
+	case *parser.ArgInstruction:
+		return []string{v.DefaultValue}
+	case *parser.LabelInstruction:
+		return keyValueFields(v.Labels)
+	case *parser.ExposeInstruction:
+		var fields []string
+		for _, p := range v.Ports {
+			fields = append(fields, p.Port, p.Protocol)
+		}
+		return fields
+	case *parser.VolumeInstruction:
+		return v.Paths
+	case *parser.UserInstruction:
+		return []string{v.User, v.Group}
+	case *parser.WorkdirInstruction:
+		return []string{v.Path}
+	case *parser.ShellInstruction:
+		return v.Shell
+	case *parser.HealthcheckInstruction:
+		return append([]string{v.Interval, v.Timeout, v.StartPeriod, v.Retries, v.Command}, v.Arguments...)
+	case *parser.StopsignalInstruction:
+		return []string{v.Signal}
+	case *parser.OnbuildInstruction:
+		if v.Instruction != nil {
+			return instructionFields(v.Instruction)
+		}
+	case *parser.MaintainerInstruction:
+		return []string{v.Maintainer}
+	}
+	return nil
+}
+
+func keyValueFields(kvs []parser.KeyValue) []string {
+	var fields []string
+	for _, kv := range kvs {
+		fields = append(fields, kv.Key, kv.Value)
+	}
+	return fields
+}
@@ -0,0 +1,81 @@
+package transforms
+
+import (
+	"testing"
+
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+func TestHoistFromArgTransform_Hoists(t *testing.T) {
+	df, errs := parser.Parse("FROM node:${VERSION}-alpine\nARG VERSION=20\nRUN npm ci\n")
+	if len(errs) > 0 {
+		t.Fatalf("unexpected parse errors: %v", errs)
+	}
+
+	tr := &HoistFromArgTransform{}
+	if !tr.Transform(df, nil) {
+		t.Fatal("expected transform to report changes")
+	}
+
+	if len(df.Args) != 1 || df.Args[0].Name != "VERSION" || df.Args[0].DefaultValue != "20" {
+		t.Fatalf("expected global ARG VERSION=20, got %+v", df.Args)
+	}
+	if len(df.Stages[0].Instructions) != 1 {
+		t.Errorf("expected ARG to be removed from the stage, got %d instructions", len(df.Stages[0].Instructions))
+	}
+}
+
+func TestHoistFromArgTransform_UsedElsewhere(t *testing.T) {
+	tests := []struct {
+		name string
+		use  string
+	}{
+		{"shell form RUN", "RUN echo \"node $VERSION\"\n"},
+		{"exec form CMD", "CMD [\"node\", \"${VERSION}\"]\n"},
+		{"COPY flag", "COPY --chown=${VERSION} . /app\n"},
+		{"ENV value", "ENV NODE_VERSION=$VERSION\n"},
+		{"LABEL value", "LABEL version=${VERSION:-20}\n"},
+		{"ONBUILD", "ONBUILD RUN echo $VERSION\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			df, errs := parser.Parse("FROM node:${VERSION}-alpine\nARG VERSION=20\n" + tt.use)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected parse errors: %v", errs)
+			}
+
+			tr := &HoistFromArgTransform{}
+			if tr.Transform(df, nil) {
+				t.Error("expected no changes when the ARG is used in the stage")
+			}
+			if len(df.Args) != 0 {
+				t.Errorf("expected no global ARGs, got %d", len(df.Args))
+			}
+		})
+	}
+}
+
+func TestHoistFromArgTransform_SimilarName(t *testing.T) {
+	df, errs := parser.Parse("FROM node:${VERSION}-alpine\nARG VERSION=20\nRUN echo $VERSION_SUFFIX \\$VERSION\n")
+	if len(errs) > 0 {
+		t.Fatalf("unexpected parse errors: %v", errs)
+	}
+
+	tr := &HoistFromArgTransform{}
+	if !tr.Transform(df, nil) {
+		t.Error("expected the ARG to be hoisted when only a longer name and an escaped $ mention it")
+	}
+}
+
+func TestHoistFromArgTransform_AlreadyGlobal(t *testing.T) {
+	df, errs := parser.Parse("ARG VERSION=18\nFROM node:${VERSION}\nARG VERSION\n")
+	if len(errs) > 0 {
+		t.Fatalf("unexpected parse errors: %v", errs)
+	}
+
+	tr := &HoistFromArgTransform{}
+	if tr.Transform(df, nil) {
+		t.Error("expected no changes when a global ARG already exists")
+	}
+}
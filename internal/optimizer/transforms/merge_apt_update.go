@@ -0,0 +1,106 @@
+package transforms
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/rules/performance"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// MergeAptUpdateTransform merges an apt-get update RUN into the apt-get
+// install RUN that immediately follows it
+type MergeAptUpdateTransform struct{}
+
+func (t *MergeAptUpdateTransform) Name() string {
+	return "merge-apt-update"
+}
+
+func (t *MergeAptUpdateTransform) Description() string {
+	return "Merge apt-get update into the following apt-get install RUN"
+}
+
+func (t *MergeAptUpdateTransform) Rules() []string {
+	return []string{"PERF008"}
+}
+
+func (t *MergeAptUpdateTransform) Transform(df *parser.Dockerfile, diags []analyzer.Diagnostic) bool {
+	changed := false
+
+	for _, stage := range df.Stages {
+		var result []parser.Instruction
+		posix := true
+
+		for i := 0; i < len(stage.Instructions); i++ {
+			inst := stage.Instructions[i]
+			if sh, ok := inst.(*parser.ShellInstruction); ok {
+				posix = shell.IsPOSIX(sh.Shell)
+			}
+
+			if posix && i+1 < len(stage.Instructions) {
+				update, ok1 := inst.(*parser.RunInstruction)
+				install, ok2 := stage.Instructions[i+1].(*parser.RunInstruction)
+				if ok1 && ok2 {
+					if merged := mergeAptUpdate(update, install); merged != nil {
+						result = append(result, merged)
+						changed = true
+						i++
+						continue
+					}
+				}
+			}
+
+			result = append(result, inst)
+		}
+
+		stage.Instructions = result
+	}
+
+	return changed
+}
+
+// mergeAptUpdate returns update and install merged into one RUN, or nil if
+// update is not a lone apt-get update, install does not use the package
+// index, or their flags conflict
+func mergeAptUpdate(update, install *parser.RunInstruction) *parser.RunInstruction {
+	for _, run := range []*parser.RunInstruction{update, install} {
+		if run.Heredoc != nil || run.IsExec {
+			return nil
+		}
+	}
+
+	if u, i := performance.AptUpdateInstall(update.Command); !u || i {
+		return nil
+	}
+	if _, i := performance.AptUpdateInstall(install.Command); !i {
+		return nil
+	}
+
+	// The network and security modes apply to the whole command, so they
+	// must agree. Mounts are kept if only one side has them.
+	if update.Network != install.Network || update.Security != install.Security {
+		return nil
+	}
+	mounts := install.Mounts
+	switch {
+	case len(update.Mounts) == 0:
+	case len(install.Mounts) == 0:
+		mounts = update.Mounts
+	case !slices.Equal(update.Mounts, install.Mounts):
+		return nil
+	}
+
+	return &parser.RunInstruction{
+		BaseInstruction: parser.BaseInstruction{
+			StartPos: update.Pos(),
+			EndPos:   install.End(),
+			Comments: append(slices.Clone(update.Comments), install.Comments...),
+		},
+		Mounts:   mounts,
+		Network:  install.Network,
+		Security: install.Security,
+		Command:  shell.Group(strings.TrimSpace(update.Command)) + " && " + shell.Group(strings.TrimSpace(install.Command)),
+	}
+}
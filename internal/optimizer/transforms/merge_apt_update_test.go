@@ -0,0 +1,103 @@
+package transforms
+
+import (
+	"testing"
+
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+func TestMergeAptUpdateTransform(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected []string // RUN commands after the transform
+		changed  bool
+	}{
+		{
+			name:     "adjacent update and install",
+			source:   "FROM debian:12\nRUN apt-get update\nRUN apt-get install -y curl\n",
+			expected: []string{"apt-get update && apt-get install -y curl"},
+			changed:  true,
+		},
+		{
+			name:     "compound commands are grouped",
+			source:   "FROM debian:12\nRUN apt-get update || true\nRUN apt-get install -y curl && rm -rf /var/lib/apt/lists/*\n",
+			expected: []string{"{ apt-get update || true; } && apt-get install -y curl && rm -rf /var/lib/apt/lists/*"},
+			changed:  true,
+		},
+		{
+			name:     "not adjacent",
+			source:   "FROM debian:12\nRUN apt-get update\nWORKDIR /app\nRUN apt-get install -y curl\n",
+			expected: []string{"apt-get update", "apt-get install -y curl"},
+		},
+		{
+			name:     "already combined",
+			source:   "FROM debian:12\nRUN apt-get update && apt-get install -y curl\nRUN apt-get install -y git\n",
+			expected: []string{"apt-get update && apt-get install -y curl", "apt-get install -y git"},
+		},
+		{
+			name:     "install mounts are kept",
+			source:   "FROM debian:12\nRUN apt-get update\nRUN --mount=type=cache,target=/var/cache/apt apt-get install -y curl\n",
+			expected: []string{"apt-get update && apt-get install -y curl"},
+			changed:  true,
+		},
+		{
+			name:     "different network modes",
+			source:   "FROM debian:12\nRUN apt-get update\nRUN --network=none apt-get install -y curl\n",
+			expected: []string{"apt-get update", "apt-get install -y curl"},
+		},
+		{
+			name:     "different mounts",
+			source:   "FROM debian:12\nRUN --mount=type=cache,target=/var/lib/apt apt-get update\nRUN --mount=type=cache,target=/var/cache/apt apt-get install -y curl\n",
+			expected: []string{"apt-get update", "apt-get install -y curl"},
+		},
+		{
+			name:     "non-POSIX shell",
+			source:   "FROM debian:12\nSHELL [\"cmd\", \"/S\", \"/C\"]\nRUN apt-get update\nRUN apt-get install -y curl\n",
+			expected: []string{"apt-get update", "apt-get install -y curl"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			df, errs := parser.Parse(tt.source)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected parse errors: %v", errs)
+			}
+
+			tr := &MergeAptUpdateTransform{}
+			if changed := tr.Transform(df, nil); changed != tt.changed {
+				t.Errorf("expected changed=%v, got %v", tt.changed, changed)
+			}
+
+			var commands []string
+			for _, inst := range df.Stages[0].Instructions {
+				if run, ok := inst.(*parser.RunInstruction); ok {
+					commands = append(commands, run.Command)
+				}
+			}
+			if len(commands) != len(tt.expected) {
+				t.Fatalf("expected %d RUNs, got %d: %q", len(tt.expected), len(commands), commands)
+			}
+			for i := range commands {
+				if commands[i] != tt.expected[i] {
+					t.Errorf("RUN %d: expected %q, got %q", i, tt.expected[i], commands[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMergeAptUpdateTransform_KeepsMounts(t *testing.T) {
+	df, errs := parser.Parse("FROM debian:12\nRUN apt-get update\nRUN --mount=type=cache,target=/var/cache/apt apt-get install -y curl\n")
+	if len(errs) > 0 {
+		t.Fatalf("unexpected parse errors: %v", errs)
+	}
+
+	(&MergeAptUpdateTransform{}).Transform(df, nil)
+
+	run := df.Stages[0].Instructions[0].(*parser.RunInstruction)
+	if len(run.Mounts) != 1 || run.Mounts[0] != "type=cache,target=/var/cache/apt" {
+		t.Errorf("expected install mount to be kept, got %v", run.Mounts)
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/HueCodes/keel/internal/analyzer"
 	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
 )
 
 // MergeRunTransform merges consecutive RUN instructions
@@ -55,9 +56,16 @@ func mergeConsecutiveRuns(instructions []parser.Instruction, changed *bool) []pa
 		runGroup = nil
 	}
 
+	// Commands can only be joined with && under a POSIX shell
+	posix := true
+
 	for _, inst := range instructions {
+		if sh, ok := inst.(*parser.ShellInstruction); ok {
+			posix = shell.IsPOSIX(sh.Shell)
+		}
+
 		run, isRun := inst.(*parser.RunInstruction)
-		if isRun && canMergeRun(run) {
+		if isRun && posix && canMergeRun(run) {
 			runGroup = append(runGroup, run)
 		} else {
 			flushRunGroup()
@@ -80,7 +88,7 @@ func canMergeRun(run *parser.RunInstruction) bool {
 		return false
 	}
 	// Don't merge if has special mounts
-	if run.Mount != "" {
+	if len(run.Mounts) > 0 {
 		return false
 	}
 	return true
@@ -95,13 +103,15 @@ func mergeRuns(runs []*parser.RunInstruction) *parser.RunInstruction {
 		return runs[0]
 	}
 
-	// Collect all commands
+	// Collect all commands, and the comments above them
 	var commands []string
+	var comments []*parser.Comment
 	for _, run := range runs {
 		cmd := strings.TrimSpace(run.Command)
 		if cmd != "" {
-			commands = append(commands, cmd)
+			commands = append(commands, shell.Group(cmd))
 		}
+		comments = append(comments, run.Comments...)
 	}
 
 	// Join with && and proper formatting
@@ -109,6 +119,7 @@ func mergeRuns(runs []*parser.RunInstruction) *parser.RunInstruction {
 		BaseInstruction: parser.BaseInstruction{
 			StartPos: runs[0].Pos(),
 			EndPos:   runs[len(runs)-1].End(),
+			Comments: comments,
 		},
 		Command: strings.Join(commands, " \\\n    && "),
 	}
@@ -0,0 +1,96 @@
+package transforms
+
+import (
+	"testing"
+
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+func TestMergeRunTransform_Merges(t *testing.T) {
+	df := &parser.Dockerfile{
+		Stages: []*parser.Stage{
+			{
+				Instructions: []parser.Instruction{
+					&parser.RunInstruction{Command: "apt-get update"},
+					&parser.RunInstruction{Command: "echo 'a && b'"},
+				},
+			},
+		},
+	}
+
+	tr := &MergeRunTransform{}
+	if !tr.Transform(df, nil) {
+		t.Fatal("expected transform to report changes")
+	}
+
+	insts := df.Stages[0].Instructions
+	if len(insts) != 1 {
+		t.Fatalf("expected 1 instruction, got %d", len(insts))
+	}
+	expected := "apt-get update \\\n    && echo 'a && b'"
+	if got := insts[0].(*parser.RunInstruction).Command; got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestMergeRunTransform_GroupsOtherOperators(t *testing.T) {
+	df := &parser.Dockerfile{
+		Stages: []*parser.Stage{
+			{
+				Instructions: []parser.Instruction{
+					&parser.RunInstruction{Command: "make"},
+					&parser.RunInstruction{Command: "make test || true"},
+				},
+			},
+		},
+	}
+
+	tr := &MergeRunTransform{}
+	tr.Transform(df, nil)
+
+	expected := "make \\\n    && { make test || true; }"
+	if got := df.Stages[0].Instructions[0].(*parser.RunInstruction).Command; got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestMergeRunTransform_NonPOSIXShell(t *testing.T) {
+	df := &parser.Dockerfile{
+		Stages: []*parser.Stage{
+			{
+				Instructions: []parser.Instruction{
+					&parser.ShellInstruction{Shell: []string{"powershell", "-Command"}},
+					&parser.RunInstruction{Command: "Write-Host a"},
+					&parser.RunInstruction{Command: "Write-Host b"},
+				},
+			},
+		},
+	}
+
+	tr := &MergeRunTransform{}
+	if tr.Transform(df, nil) {
+		t.Error("expected no merge under a non-POSIX SHELL")
+	}
+	if len(df.Stages[0].Instructions) != 3 {
+		t.Errorf("expected 3 instructions, got %d", len(df.Stages[0].Instructions))
+	}
+}
+
+func TestMergeRunTransform_KeepsComments(t *testing.T) {
+	df, errs := parser.Parse("FROM alpine\n# first\nRUN echo a\n# second\nRUN echo b\n")
+	if len(errs) > 0 {
+		t.Fatalf("unexpected parse errors: %v", errs)
+	}
+
+	if !(&MergeRunTransform{}).Transform(df, nil) {
+		t.Fatal("expected transform to report changes")
+	}
+
+	var texts []string
+	for _, c := range df.Stages[0].Instructions[0].LeadingComments() {
+		texts = append(texts, c.Text)
+	}
+	if len(texts) != 2 || texts[0] != "# first" || texts[1] != "# second" {
+		t.Errorf("expected the merged RUN to keep both comments, got %v", texts)
+	}
+}
@@ -26,6 +26,15 @@ type PinImageTagTransform struct {
 
 	// Timeout for registry requests
 	Timeout time.Duration
+
+	// Errors holds the images the last Transform call failed to pin
+	Errors []error
+}
+
+// NewPinImageTagTransform creates a transform that fetches digests with
+// client, giving up on the registry after timeout
+func NewPinImageTagTransform(client RegistryClient, timeout time.Duration) *PinImageTagTransform {
+	return &PinImageTagTransform{Client: client, Timeout: timeout}
 }
 
 func (t *PinImageTagTransform) Name() string {
@@ -41,6 +50,8 @@ func (t *PinImageTagTransform) Rules() []string {
 }
 
 func (t *PinImageTagTransform) Transform(df *parser.Dockerfile, diags []analyzer.Diagnostic) bool {
+	t.Errors = nil
+
 	// If no client configured, we can't fetch digests
 	if t.Client == nil {
 		return false
@@ -85,7 +96,8 @@ func (t *PinImageTagTransform) Transform(df *parser.Dockerfile, diags []analyzer
 		// Fetch the digest from the registry
 		digest, err := t.Client.GetDigest(ctx, from.Image, tag)
 		if err != nil {
-			// Failed to fetch digest, skip this image
+			// Skip this image, but record why it wasn't pinned
+			t.Errors = append(t.Errors, fmt.Errorf("%s:%s: %w", from.Image, tag, err))
 			continue
 		}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/HueCodes/keel/internal/parser"
 )
@@ -272,6 +273,34 @@ func TestPinImageTagTransform_NetworkError(t *testing.T) {
 	if from.Digest != "" {
 		t.Errorf("expected no digest on error, got '%s'", from.Digest)
 	}
+
+	if len(tr.Errors) != 1 || tr.Errors[0].Error() != "ubuntu:latest: network error" {
+		t.Errorf("expected the failure to be recorded, got %v", tr.Errors)
+	}
+}
+
+func TestNewPinImageTagTransform(t *testing.T) {
+	client := &mockRegistryClient{
+		digests: map[string]string{
+			"ubuntu:latest": "sha256:abc123",
+		},
+	}
+	tr := NewPinImageTagTransform(client, 5*time.Second)
+	if tr.Client != client || tr.Timeout != 5*time.Second {
+		t.Errorf("expected client and timeout to be set, got %+v", tr)
+	}
+
+	df := &parser.Dockerfile{
+		Stages: []*parser.Stage{
+			{From: &parser.FromInstruction{Image: "ubuntu", Tag: "latest"}},
+		},
+	}
+	if !tr.Transform(df, nil) || df.Stages[0].From.Digest != "sha256:abc123" {
+		t.Errorf("expected digest to be pinned, got %q", df.Stages[0].From.Digest)
+	}
+	if len(tr.Errors) != 0 {
+		t.Errorf("expected no errors, got %v", tr.Errors)
+	}
 }
 
 func TestPinImageTagTransform_SpecificTag(t *testing.T) {
@@ -0,0 +1,197 @@
+package transforms
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// PinPackagesTransform pins the packages installed with apt-get, apt, and
+// apk to the versions in a version map, rewriting "apt-get install curl"
+// to "apt-get install curl=<version>"
+type PinPackagesTransform struct {
+	// Versions maps package names to the version to pin them to.
+	// Packages missing from the map are left unpinned. If nil, nothing
+	// is changed.
+	Versions map[string]string
+}
+
+func (t *PinPackagesTransform) Name() string {
+	return "pin-packages"
+}
+
+func (t *PinPackagesTransform) Description() string {
+	return "Pin apt and apk packages to the versions in a lockfile"
+}
+
+// Rules returns nil: no rule reports unpinned packages, so the optimizer
+// never selects this transform and callers run it directly
+func (t *PinPackagesTransform) Rules() []string {
+	return nil
+}
+
+func (t *PinPackagesTransform) Transform(df *parser.Dockerfile, diags []analyzer.Diagnostic) bool {
+	if len(t.Versions) == 0 {
+		return false
+	}
+
+	changed := false
+
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			run, ok := inst.(*parser.RunInstruction)
+			if !ok || run.IsExec {
+				continue
+			}
+
+			if run.Heredoc != nil {
+				run.Heredoc.Content = pinPackages(run.Heredoc.Content, t.Versions, &changed)
+			} else {
+				run.Command = pinPackages(run.Command, t.Versions, &changed)
+			}
+		}
+	}
+
+	return changed
+}
+
+// LoadPackageVersions reads a package lockfile with one "name=version"
+// per line. Blank lines and lines starting with # are ignored.
+func LoadPackageVersions(file string) (map[string]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	versions, err := ParsePackageVersions(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return versions, nil
+}
+
+// ParsePackageVersions parses the lockfile format read by LoadPackageVersions
+func ParsePackageVersions(r io.Reader) (map[string]string, error) {
+	versions := make(map[string]string)
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, version, ok := strings.Cut(line, "=")
+		name, version = strings.TrimSpace(name), strings.TrimSpace(version)
+		if !ok || name == "" || version == "" {
+			return nil, fmt.Errorf("line %d: expected name=version", lineNum)
+		}
+		versions[name] = version
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// Package manager options that take a separate value, e.g. "-o Dpkg::Options=..."
+var (
+	aptValueFlags = map[string]bool{
+		"-o": true, "--option": true, "-t": true, "--target-release": true,
+		"-c": true, "--config-file": true,
+	}
+	apkValueFlags = map[string]bool{
+		"-X": true, "--repository": true, "-t": true, "--virtual": true,
+		"-p": true, "--root": true, "--arch": true, "--cache-dir": true,
+		"--keys-dir": true, "--repositories-file": true,
+	}
+)
+
+// pinPackages appends "=version" to each unpinned package installed by
+// the commands in cmd
+func pinPackages(cmd string, versions map[string]string, changed *bool) string {
+	type insert struct {
+		at      int
+		version string
+	}
+	var inserts []insert
+
+	for _, c := range shell.Split(cmd) {
+		for _, w := range installedPackages(c) {
+			if version, ok := versions[w.Value]; ok {
+				inserts = append(inserts, insert{w.End, version})
+			}
+		}
+	}
+
+	if len(inserts) == 0 {
+		return cmd
+	}
+
+	sort.Slice(inserts, func(i, j int) bool { return inserts[i].at < inserts[j].at })
+	for i := len(inserts) - 1; i >= 0; i-- {
+		cmd = cmd[:inserts[i].at] + "=" + inserts[i].version + cmd[inserts[i].at:]
+	}
+	*changed = true
+
+	return cmd
+}
+
+// installedPackages returns the unpinned package arguments of an apt-get
+// install, apt install, or apk add command. Flags, flag values, pinned
+// packages, and local files are skipped.
+func installedPackages(c shell.Command) []shell.Word {
+	args := c.Args()
+	if len(args) == 0 {
+		return nil
+	}
+
+	var subcommand string
+	var valueFlags map[string]bool
+	switch path.Base(args[0].Value) {
+	case "apt-get", "apt":
+		subcommand, valueFlags = "install", aptValueFlags
+	case "apk":
+		subcommand, valueFlags = "add", apkValueFlags
+	default:
+		return nil
+	}
+
+	var pkgs []shell.Word
+	found := false
+	for i := 1; i < len(args); i++ {
+		w := args[i].Value
+		if strings.HasPrefix(w, "-") {
+			if valueFlags[w] {
+				i++
+			}
+			continue
+		}
+		if !found {
+			if w != subcommand {
+				return nil
+			}
+			found = true
+			continue
+		}
+		if strings.ContainsAny(w, "=<>~/$*?[") {
+			// Already pinned, a release or local file, or not a literal name
+			continue
+		}
+		pkgs = append(pkgs, args[i])
+	}
+
+	return pkgs
+}
@@ -0,0 +1,147 @@
+package transforms
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+func TestPinPackagesTransform_Name(t *testing.T) {
+	tr := &PinPackagesTransform{}
+	if tr.Name() != "pin-packages" {
+		t.Errorf("expected name 'pin-packages', got %s", tr.Name())
+	}
+}
+
+func TestPinPackagesTransform(t *testing.T) {
+	versions := map[string]string{
+		"curl":            "7.88.1-10+deb12u5",
+		"ca-certificates": "20230311",
+		"git":             "2.43.0-r0",
+		"build-base":      "0.5-r3",
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "apt-get install",
+			input:    "apt-get install -y curl",
+			expected: "apt-get install -y curl=7.88.1-10+deb12u5",
+		},
+		{
+			name:     "apt install with several packages",
+			input:    "apt install -y --no-install-recommends curl ca-certificates",
+			expected: "apt install -y --no-install-recommends curl=7.88.1-10+deb12u5 ca-certificates=20230311",
+		},
+		{
+			name:     "apk add",
+			input:    "apk add --no-cache git build-base",
+			expected: "apk add --no-cache git=2.43.0-r0 build-base=0.5-r3",
+		},
+		{
+			name:     "already pinned",
+			input:    "apt-get install -y curl=7.74.0 ca-certificates",
+			expected: "apt-get install -y curl=7.74.0 ca-certificates=20230311",
+		},
+		{
+			name:     "apk version constraint",
+			input:    "apk add git~2.43 build-base",
+			expected: "apk add git~2.43 build-base=0.5-r3",
+		},
+		{
+			name:     "package not in map",
+			input:    "apt-get install -y curl wget",
+			expected: "apt-get install -y curl=7.88.1-10+deb12u5 wget",
+		},
+		{
+			name:     "flag values untouched",
+			input:    "apt-get -o Acquire::Retries=3 install -t curl curl",
+			expected: "apt-get -o Acquire::Retries=3 install -t curl curl=7.88.1-10+deb12u5",
+		},
+		{
+			name:     "apk virtual package name untouched",
+			input:    "apk add --virtual git build-base",
+			expected: "apk add --virtual git build-base=0.5-r3",
+		},
+		{
+			name:     "chained commands",
+			input:    "apt-get update && DEBIAN_FRONTEND=noninteractive apt-get install -y curl && rm -rf /var/lib/apt/lists/*",
+			expected: "apt-get update && DEBIAN_FRONTEND=noninteractive apt-get install -y curl=7.88.1-10+deb12u5 && rm -rf /var/lib/apt/lists/*",
+		},
+		{
+			name:     "other subcommands untouched",
+			input:    "apt-get remove -y curl && apk del git",
+			expected: "apt-get remove -y curl && apk del git",
+		},
+		{
+			name:     "other package managers untouched",
+			input:    "pip install curl",
+			expected: "pip install curl",
+		},
+		{
+			name:     "quoted package name",
+			input:    `apt-get install -y "curl"`,
+			expected: `apt-get install -y "curl"=7.88.1-10+deb12u5`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			df := &parser.Dockerfile{
+				Stages: []*parser.Stage{
+					{
+						Instructions: []parser.Instruction{
+							&parser.RunInstruction{Command: tt.input},
+						},
+					},
+				},
+			}
+
+			tr := &PinPackagesTransform{Versions: versions}
+			changed := tr.Transform(df, nil)
+
+			run := df.Stages[0].Instructions[0].(*parser.RunInstruction)
+			if run.Command != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, run.Command)
+			}
+			if changed != (tt.input != tt.expected) {
+				t.Errorf("expected changed=%v, got %v", tt.input != tt.expected, changed)
+			}
+		})
+	}
+}
+
+func TestPinPackagesTransform_NoVersions(t *testing.T) {
+	df := &parser.Dockerfile{
+		Stages: []*parser.Stage{
+			{
+				Instructions: []parser.Instruction{
+					&parser.RunInstruction{Command: "apt-get install -y curl"},
+				},
+			},
+		},
+	}
+
+	tr := &PinPackagesTransform{}
+	if tr.Transform(df, nil) {
+		t.Error("expected no changes without a version map")
+	}
+}
+
+func TestParsePackageVersions(t *testing.T) {
+	versions, err := ParsePackageVersions(strings.NewReader("# pinned for bookworm\ncurl=7.88.1\n\n ca-certificates = 20230311 \n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(versions) != 2 || versions["curl"] != "7.88.1" || versions["ca-certificates"] != "20230311" {
+		t.Errorf("unexpected versions: %v", versions)
+	}
+
+	if _, err := ParsePackageVersions(strings.NewReader("curl\n")); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("expected a line 1 error, got %v", err)
+	}
+}
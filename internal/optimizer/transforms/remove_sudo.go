@@ -1,11 +1,11 @@
 package transforms
 
 import (
-	"regexp"
 	"strings"
 
 	"github.com/HueCodes/keel/internal/analyzer"
 	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
 )
 
 // RemoveSudoTransform removes sudo from RUN commands
@@ -23,21 +23,8 @@ func (t *RemoveSudoTransform) Rules() []string {
 	return []string{"SEC005"}
 }
 
-// Regex patterns for sudo removal
-// These match sudo with various common flags
-var sudoPatterns = []*regexp.Regexp{
-	// sudo with common flags that don't change user
-	regexp.MustCompile(`\bsudo\s+(?:-[EHnPS]\s+)*`),
-	// sudo -E (preserve environment)
-	regexp.MustCompile(`\bsudo\s+-E\s+`),
-	// sudo -n (non-interactive)
-	regexp.MustCompile(`\bsudo\s+-n\s+`),
-	// sudo alone
-	regexp.MustCompile(`\bsudo\s+`),
-}
-
-// sudoUserPattern matches sudo -u which changes user and should NOT be auto-fixed
-var sudoUserPattern = regexp.MustCompile(`\bsudo\s+(-\w+\s+)*-u\s+`)
+// sudo flags that don't change the target user and take no value
+const sudoPlainFlags = "EHnPSiks"
 
 func (t *RemoveSudoTransform) Transform(df *parser.Dockerfile, diags []analyzer.Diagnostic) bool {
 	changed := false
@@ -70,27 +57,45 @@ func (t *RemoveSudoTransform) Transform(df *parser.Dockerfile, diags []analyzer.
 	return changed
 }
 
+// removeSudo strips a leading sudo (and its flags) from each command in
+// cmd. Commands using sudo -u/-g change user and are left alone since they
+// need a USER instruction instead.
 func removeSudo(cmd string, changed *bool) string {
-	// Skip if using sudo -u (changing user) - this needs USER instruction instead
-	if sudoUserPattern.MatchString(cmd) {
-		return cmd
-	}
+	type span struct{ start, end int }
+	var spans []span
 
-	original := cmd
+	for _, c := range shell.Split(cmd) {
+		args := c.Args()
+		if len(args) < 2 || args[0].Value != "sudo" {
+			continue
+		}
 
-	// Apply patterns in order from most specific to least specific
-	for _, pattern := range sudoPatterns {
-		if pattern.MatchString(cmd) {
-			cmd = pattern.ReplaceAllString(cmd, "")
+		i := 1
+		for ; i < len(args); i++ {
+			flag := args[i].Value
+			if !strings.HasPrefix(flag, "-") || len(flag) < 2 {
+				break
+			}
+			if strings.Trim(flag[1:], sudoPlainFlags) != "" {
+				// Unknown or user-changing flag (-u, -g, --user, ...)
+				i = -1
+				break
+			}
+		}
+		if i < 0 || i >= len(args) {
+			continue
 		}
+		spans = append(spans, span{args[0].Start, args[i].Start})
 	}
 
-	// Clean up any double spaces that may have been introduced
-	cmd = strings.Join(strings.Fields(cmd), " ")
+	if len(spans) == 0 {
+		return cmd
+	}
 
-	if cmd != original {
-		*changed = true
+	for i := len(spans) - 1; i >= 0; i-- {
+		cmd = cmd[:spans[i].start] + cmd[spans[i].end:]
 	}
+	*changed = true
 
 	return cmd
 }
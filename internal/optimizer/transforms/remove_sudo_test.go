@@ -174,7 +174,7 @@ func TestRemoveSudoTransform_Heredoc(t *testing.T) {
 	}
 
 	run := df.Stages[0].Instructions[0].(*parser.RunInstruction)
-	expected := "apt-get update apt-get install -y curl"
+	expected := "apt-get update\napt-get install -y curl"
 	if run.Heredoc.Content != expected {
 		t.Errorf("expected '%s', got '%s'", expected, run.Heredoc.Content)
 	}
@@ -314,3 +314,27 @@ func TestRemoveSudoTransform_WithDiagnostics(t *testing.T) {
 		t.Error("expected transform to report changes")
 	}
 }
+
+func TestRemoveSudoTransform_QuotedSudo(t *testing.T) {
+	// sudo inside quotes is an argument, not a command
+	df := &parser.Dockerfile{
+		Stages: []*parser.Stage{
+			{
+				Instructions: []parser.Instruction{
+					&parser.RunInstruction{
+						Command: "echo 'run sudo make' && sudo make install",
+					},
+				},
+			},
+		},
+	}
+
+	tr := &RemoveSudoTransform{}
+	tr.Transform(df, nil)
+
+	run := df.Stages[0].Instructions[0].(*parser.RunInstruction)
+	expected := "echo 'run sudo make' && make install"
+	if run.Command != expected {
+		t.Errorf("expected '%s', got '%s'", expected, run.Command)
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/HueCodes/keel/internal/analyzer"
 	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
 )
 
 // ReorderCopyTransform moves broad COPY instructions after dependency install RUNs
@@ -195,7 +196,7 @@ func isDependencyInstallCommand(cmd string) bool {
 
 	cmdLower := strings.ToLower(cmd)
 	for _, pattern := range installPatterns {
-		if strings.Contains(cmdLower, pattern) {
+		if shell.HasCommand(cmdLower, strings.Fields(pattern)...) {
 			return true
 		}
 	}
@@ -441,6 +441,8 @@ func TestIsDependencyInstallCommand(t *testing.T) {
 		{"go build", false},
 		{"python app.py", false},
 		{"chmod +x script.sh", false},
+		{"cd /app && npm ci", true},
+		{"echo 'run npm install first'", false},
 	}
 
 	for _, tt := range tests {
@@ -452,3 +454,28 @@ func TestIsDependencyInstallCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestReorderCopyTransform_KeepsComments(t *testing.T) {
+	input := "FROM node:20\n# copy sources\nCOPY . /app\n# install dependencies\nRUN npm install\nRUN npm run build\n"
+	df, errs := parser.Parse(input)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected parse errors: %v", errs)
+	}
+
+	if !(&ReorderCopyTransform{}).Transform(df, nil) {
+		t.Fatal("expected transform to report changes")
+	}
+
+	copyInst, ok := df.Stages[0].Instructions[1].(*parser.CopyInstruction)
+	if !ok {
+		t.Fatalf("expected COPY to move after the install, got %T", df.Stages[0].Instructions[1])
+	}
+	if comments := copyInst.LeadingComments(); len(comments) != 1 || comments[0].Text != "# copy sources" {
+		t.Errorf("expected COPY to keep its comment, got %v", comments)
+	}
+
+	expected := "FROM node:20\n# install dependencies\nRUN npm install\n# copy sources\nCOPY . /app\nRUN npm run build\n"
+	if got := df.String(); got != expected {
+		t.Errorf("got %q, want %q", got, expected)
+	}
+}
@@ -0,0 +1,35 @@
+package transforms
+
+import (
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// StripRedundantPlatformTransform removes FROM --platform=$TARGETPLATFORM,
+// which is the default. Explicit platforms and $BUILDPLATFORM are kept.
+type StripRedundantPlatformTransform struct{}
+
+func (t *StripRedundantPlatformTransform) Name() string {
+	return "strip-redundant-platform"
+}
+
+func (t *StripRedundantPlatformTransform) Description() string {
+	return "Remove --platform=$TARGETPLATFORM from FROM"
+}
+
+func (t *StripRedundantPlatformTransform) Rules() []string {
+	return []string{"STY006"}
+}
+
+func (t *StripRedundantPlatformTransform) Transform(df *parser.Dockerfile, diags []analyzer.Diagnostic) bool {
+	changed := false
+
+	for _, stage := range df.Stages {
+		if stage.From != nil && stage.From.RedundantPlatform() {
+			stage.From.Platform = ""
+			changed = true
+		}
+	}
+
+	return changed
+}
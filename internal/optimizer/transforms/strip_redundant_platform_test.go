@@ -0,0 +1,34 @@
+package transforms
+
+import (
+	"testing"
+
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+func TestStripRedundantPlatformTransform(t *testing.T) {
+	source := "FROM --platform=$TARGETPLATFORM golang:1.25 AS build\n" +
+		"FROM --platform=$BUILDPLATFORM golang:1.25 AS tools\n" +
+		"FROM --platform=linux/arm64 alpine:3.19 AS arm\n" +
+		"FROM --platform=${TARGETPLATFORM} alpine:3.19\n"
+	df, errs := parser.Parse(source)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected parse errors: %v", errs)
+	}
+
+	tr := &StripRedundantPlatformTransform{}
+	if !tr.Transform(df, nil) {
+		t.Fatal("expected transform to report changes")
+	}
+
+	expected := []string{"", "$BUILDPLATFORM", "linux/arm64", ""}
+	for i, stage := range df.Stages {
+		if stage.From.Platform != expected[i] {
+			t.Errorf("stage %d: expected platform %q, got %q", i, expected[i], stage.From.Platform)
+		}
+	}
+
+	if tr.Transform(df, nil) {
+		t.Error("expected no changes on second run")
+	}
+}
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"path"
 	"strings"
 
 	"github.com/HueCodes/keel/internal/lexer"
@@ -16,11 +17,16 @@ type Node interface {
 // Instruction is a Dockerfile instruction
 type Instruction interface {
 	Node
+	// LeadingComments returns the comments on the lines before the
+	// instruction, which stay with it when transforms move or merge it
+	LeadingComments() []*Comment
 	instructionName() string
+	attachComments(comments []*Comment)
 }
 
 // Dockerfile represents a complete Dockerfile
 type Dockerfile struct {
+	Args     []*ArgInstruction // global ARGs declared before the first FROM
 	Stages   []*Stage          // build stages
 	Comments []*Comment        // top-level comments
 	Escape   rune              // escape character (default \)
@@ -62,12 +68,20 @@ type BaseInstruction struct {
 	StartPos lexer.Position
 	EndPos   lexer.Position
 	RawText  string     // original text
-	Comments []*Comment // inline comments
+	Comments []*Comment // comments on the lines before the instruction
 }
 
-func (b *BaseInstruction) Pos() lexer.Position { return b.StartPos }
-func (b *BaseInstruction) End() lexer.Position { return b.EndPos }
-func (b *BaseInstruction) node()               {}
+func (b *BaseInstruction) Pos() lexer.Position         { return b.StartPos }
+func (b *BaseInstruction) End() lexer.Position         { return b.EndPos }
+func (b *BaseInstruction) node()                       {}
+func (b *BaseInstruction) LeadingComments() []*Comment { return b.Comments }
+func (b *BaseInstruction) attachComments(c []*Comment) {
+	if len(b.Comments) == 0 {
+		b.Comments = c
+		return
+	}
+	b.Comments = append(b.Comments, c...)
+}
 
 // FromInstruction represents FROM instruction
 type FromInstruction struct {
@@ -77,10 +91,20 @@ type FromInstruction struct {
 	Digest   string // digest (after @)
 	Platform string // --platform flag value
 	AsName   string // AS name
+
+	// BaseStage is the name of the earlier stage this FROM builds on,
+	// or "" when it refers to an image
+	BaseStage string
 }
 
 func (f *FromInstruction) instructionName() string { return "FROM" }
 
+// RedundantPlatform reports whether --platform names $TARGETPLATFORM,
+// the platform FROM uses without the flag
+func (f *FromInstruction) RedundantPlatform() bool {
+	return f.Platform == "$TARGETPLATFORM" || f.Platform == "${TARGETPLATFORM}"
+}
+
 // ImageRef returns the full image reference
 func (f *FromInstruction) ImageRef() string {
 	ref := f.Image
@@ -93,6 +117,79 @@ func (f *FromInstruction) ImageRef() string {
 	return ref
 }
 
+// Registry and namespace implied by unqualified image names
+const (
+	DefaultRegistry  = "docker.io"
+	DefaultNamespace = "library"
+)
+
+// CanonicalRef returns the fully qualified image reference, expanding the
+// implicit registry, namespace, and latest tag, e.g. alpine becomes
+// docker.io/library/alpine:latest. Stage references, scratch, and
+// references containing variables are returned unchanged.
+func (f *FromInstruction) CanonicalRef() string {
+	if f.BaseStage != "" {
+		return f.BaseStage
+	}
+	if strings.EqualFold(f.Image, "scratch") || strings.Contains(f.ImageRef(), "$") {
+		return f.ImageRef()
+	}
+
+	name := f.Image
+	registry := DefaultRegistry
+	if i := strings.Index(name, "/"); i >= 0 {
+		first := name[:i]
+		if strings.ContainsAny(first, ".:") || first == "localhost" {
+			registry = first
+			name = name[i+1:]
+		}
+	}
+	if registry == "index.docker.io" {
+		registry = DefaultRegistry
+	}
+	if registry == DefaultRegistry && !strings.Contains(name, "/") {
+		name = DefaultNamespace + "/" + name
+	}
+
+	ref := registry + "/" + name
+	if f.Tag != "" {
+		ref += ":" + f.Tag
+	} else if f.Digest == "" {
+		ref += ":latest"
+	}
+	if f.Digest != "" {
+		ref += "@" + f.Digest
+	}
+	return ref
+}
+
+// PlatformSpec is a --platform value split into its components
+type PlatformSpec struct {
+	OS      string
+	Arch    string
+	Variant string
+}
+
+// ParsePlatform splits a platform string such as linux/arm64/v8 into
+// os, architecture, and variant. Missing components are left empty.
+func ParsePlatform(value string) PlatformSpec {
+	var spec PlatformSpec
+	parts := strings.SplitN(value, "/", 3)
+	spec.OS = parts[0]
+	if len(parts) > 1 {
+		spec.Arch = parts[1]
+	}
+	if len(parts) > 2 {
+		spec.Variant = parts[2]
+	}
+	return spec
+}
+
+// PlatformSpec returns the parsed --platform value
+func (f *FromInstruction) PlatformSpec() PlatformSpec {
+	return ParsePlatform(f.Platform)
+}
+
 // RunInstruction represents RUN instruction
 type RunInstruction struct {
 	BaseInstruction
@@ -100,18 +197,111 @@ type RunInstruction struct {
 	Arguments []string // exec form arguments
 	IsExec    bool     // true if exec form ["cmd", "arg"]
 	Heredoc   *Heredoc // heredoc content if present
-	Mount     string   // --mount flag
+	Mounts    []string // --mount flags, in order
 	Network   string   // --network flag
 	Security  string   // --security flag
 }
 
 func (r *RunInstruction) instructionName() string { return "RUN" }
 
-// Heredoc represents heredoc content in RUN instructions
+// MountSpecs returns the parsed --mount values
+func (r *RunInstruction) MountSpecs() []MountSpec {
+	specs := make([]MountSpec, len(r.Mounts))
+	for i, m := range r.Mounts {
+		specs[i] = ParseMount(m)
+	}
+	return specs
+}
+
+// MountSpec is a parsed RUN --mount value such as
+// type=secret,id=npmrc,target=/root/.npmrc
+type MountSpec struct {
+	Type   string // bind, cache, tmpfs, secret, or ssh; bind when omitted
+	Target string // target, dst, or destination
+	Source string // source or src
+	ID     string
+	From   string
+	Env    string // environment variable a secret is exposed as
+
+	// Options holds every key as written, including those above. Flags
+	// without a value, such as readonly, map to "".
+	Options map[string]string
+}
+
+// Mount types
+const (
+	MountBind   = "bind"
+	MountCache  = "cache"
+	MountTmpfs  = "tmpfs"
+	MountSecret = "secret"
+	MountSSH    = "ssh"
+)
+
+// DefaultSecretDir is where secret mounts without a target are placed
+const DefaultSecretDir = "/run/secrets/"
+
+// ParseMount parses a --mount value. Secret mounts get BuildKit's
+// defaults: the id defaults to the target's base name, and the target
+// to /run/secrets/<id> unless the secret is only exposed through env.
+func ParseMount(value string) MountSpec {
+	spec := MountSpec{Type: MountBind, Options: make(map[string]string)}
+
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		key, val, _ := strings.Cut(field, "=")
+		key = strings.ToLower(key)
+		spec.Options[key] = val
+
+		switch key {
+		case "type":
+			spec.Type = strings.ToLower(val)
+		case "target", "dst", "destination":
+			spec.Target = val
+		case "source", "src":
+			spec.Source = val
+		case "id":
+			spec.ID = val
+		case "from":
+			spec.From = val
+		case "env":
+			spec.Env = val
+		}
+	}
+
+	if spec.Type == MountSecret {
+		if spec.ID == "" && spec.Target != "" {
+			spec.ID = path.Base(spec.Target)
+		}
+		if spec.Target == "" && spec.Env == "" && spec.ID != "" {
+			spec.Target = DefaultSecretDir + spec.ID
+		}
+	}
+
+	return spec
+}
+
+// Heredoc represents heredoc content in RUN instructions. Any command
+// following the marker on the first line is kept in RunInstruction.Command.
 type Heredoc struct {
 	Delimiter string
-	Content   string
-	StripTabs bool
+	Content   string // body lines, each ending in a newline
+	StripTabs bool   // <<- form
+	Quoted    bool   // quoted delimiter, which disables variable expansion
+}
+
+// Marker returns the heredoc's opening marker, e.g. <<EOF or <<-"EOF"
+func (h *Heredoc) Marker() string {
+	marker := "<<"
+	if h.StripTabs {
+		marker += "-"
+	}
+	if h.Quoted {
+		return marker + `"` + h.Delimiter + `"`
+	}
+	return marker + h.Delimiter
 }
 
 // CmdInstruction represents CMD instruction
@@ -163,6 +353,8 @@ func (a *AddInstruction) instructionName() string { return "ADD" }
 type EnvInstruction struct {
 	BaseInstruction
 	Variables []KeyValue
+	Legacy    bool   // written in the legacy ENV key value form, without =
+	Trailing  string // text from a # after the values, which is not a comment
 }
 
 func (e *EnvInstruction) instructionName() string { return "ENV" }
@@ -172,7 +364,9 @@ type ArgInstruction struct {
 	BaseInstruction
 	Name         string
 	DefaultValue string
+	DefaultQuote QuoteStyle // how DefaultValue was quoted in the source
 	HasDefault   bool
+	Trailing     string // text from a # after the default, which is not a comment
 }
 
 func (a *ArgInstruction) instructionName() string { return "ARG" }
@@ -189,8 +383,19 @@ func (l *LabelInstruction) instructionName() string { return "LABEL" }
 type KeyValue struct {
 	Key   string
 	Value string
+	Quote QuoteStyle // how Value was quoted in the source
 }
 
+// QuoteStyle records how a value was quoted. Single quotes matter for
+// values containing $, since they prevent variable expansion.
+type QuoteStyle int
+
+const (
+	Unquoted     QuoteStyle = iota // unquoted, partly quoted, or built in code
+	DoubleQuoted                   // a single "..." string
+	SingleQuoted                   // a single '...' string
+)
+
 // ExposeInstruction represents EXPOSE instruction
 type ExposeInstruction struct {
 	BaseInstruction
@@ -343,6 +548,38 @@ func HasInstruction[T Instruction](df *Dockerfile) bool {
 	return false
 }
 
+// EffectiveProtocol returns the port's protocol in lowercase, defaulting to tcp
+func (p PortSpec) EffectiveProtocol() string {
+	if p.Protocol == "" {
+		return "tcp"
+	}
+	return strings.ToLower(p.Protocol)
+}
+
+// String returns the port as written, e.g. 80 or 53/udp
+func (p PortSpec) String() string {
+	if p.Protocol == "" {
+		return p.Port
+	}
+	return p.Port + "/" + p.Protocol
+}
+
+// UniquePorts returns the ports without duplicates, keeping the first of
+// each. 80 and 80/tcp are the same port, since tcp is the default.
+func (e *ExposeInstruction) UniquePorts() []PortSpec {
+	seen := make(map[string]bool)
+	var ports []PortSpec
+	for _, p := range e.Ports {
+		key := p.Port + "/" + p.EffectiveProtocol()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		ports = append(ports, p)
+	}
+	return ports
+}
+
 // IsPrivilegedPort returns true if the port is below 1024
 func (p PortSpec) IsPrivilegedPort() bool {
 	port := strings.TrimSuffix(p.Port, "/tcp")
@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ParseDuration parses a HEALTHCHECK duration such as 30s or 1m30s, using
+// the Go duration format Docker accepts. Negative durations are rejected.
+func ParseDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("duration %q cannot be negative", s)
+	}
+	return d, nil
+}
+
+// ParseRetries parses a HEALTHCHECK --retries value, which must be a
+// positive integer
+func ParseRetries(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid retries %q", s)
+	}
+	if n < 1 {
+		return 0, fmt.Errorf("retries %q must be at least 1", s)
+	}
+	return n, nil
+}
+
+// IntervalDuration returns the parsed --interval, or zero if unset
+func (h *HealthcheckInstruction) IntervalDuration() (time.Duration, error) {
+	return optionalDuration(h.Interval)
+}
+
+// TimeoutDuration returns the parsed --timeout, or zero if unset
+func (h *HealthcheckInstruction) TimeoutDuration() (time.Duration, error) {
+	return optionalDuration(h.Timeout)
+}
+
+// StartPeriodDuration returns the parsed --start-period, or zero if unset
+func (h *HealthcheckInstruction) StartPeriodDuration() (time.Duration, error) {
+	return optionalDuration(h.StartPeriod)
+}
+
+func optionalDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return ParseDuration(s)
+}
@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"30s", 30 * time.Second, false},
+		{"1m30s", 90 * time.Second, false},
+		{"2h", 2 * time.Hour, false},
+		{"500ms", 500 * time.Millisecond, false},
+		{"0s", 0, false},
+		{"abc", 0, true},
+		{"30", 0, true},
+		{"", 0, true},
+		{"-5s", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDuration(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDuration(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseDuration(%q) = %s, want %s", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseRetries(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+		wantErr  bool
+	}{
+		{"3", 3, false},
+		{"1", 1, false},
+		{"0", 0, true},
+		{"-1", 0, true},
+		{"three", 0, true},
+		{"1.5", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseRetries(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRetries(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseRetries(%q) = %d, want %d", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHealthcheckDurations(t *testing.T) {
+	df, errs := Parse("FROM alpine\nHEALTHCHECK --interval=1m --timeout=bad CMD true\n")
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	hc := df.Stages[0].Instructions[0].(*HealthcheckInstruction)
+	if d, err := hc.IntervalDuration(); err != nil || d != time.Minute {
+		t.Errorf("expected interval 1m, got %s (%v)", d, err)
+	}
+	if _, err := hc.TimeoutDuration(); err == nil {
+		t.Error("expected error for invalid timeout")
+	}
+	if d, err := hc.StartPeriodDuration(); err != nil || d != 0 {
+		t.Errorf("expected unset start period to be zero, got %s (%v)", d, err)
+	}
+}
@@ -13,10 +13,30 @@ type Parser struct {
 	pos     int
 	current lexer.Token
 	errors  []ParseError
+	escape  byte
+}
+
+// ErrorCode identifies the kind of a parse error, so that callers can
+// react to it without matching on the message
+type ErrorCode string
+
+const (
+	ErrUnexpectedToken         ErrorCode = "unexpected-token"
+	ErrInstructionOutsideStage ErrorCode = "instruction-outside-stage"
+	ErrUnterminatedString      ErrorCode = "unterminated-string"
+	ErrMalformedHeredoc        ErrorCode = "malformed-heredoc"
+	ErrMalformedShell          ErrorCode = "malformed-shell"
+	ErrInvalidOnbuild          ErrorCode = "invalid-onbuild"
+)
+
+// Error makes a code usable as the target of errors.Is
+func (c ErrorCode) Error() string {
+	return string(c)
 }
 
 // ParseError represents a parsing error
 type ParseError struct {
+	Code    ErrorCode
 	Message string
 	Pos     lexer.Position
 }
@@ -25,11 +45,19 @@ func (e ParseError) Error() string {
 	return fmt.Sprintf("%s at %s", e.Message, e.Pos)
 }
 
+// Is reports whether target is the error's code, so that
+// errors.Is(err, parser.ErrUnterminatedString) works
+func (e ParseError) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+	return ok && code == e.Code
+}
+
 // New creates a new Parser
 func New(tokens []lexer.Token) *Parser {
 	p := &Parser{
 		tokens: tokens,
 		pos:    0,
+		escape: '\\',
 	}
 	if len(tokens) > 0 {
 		p.current = tokens[0]
@@ -56,6 +84,19 @@ func (p *Parser) advance() {
 	}
 }
 
+// lastEnd returns the end of the last consumed token other than a newline
+// or comment, which is where the instruction being parsed ends
+func (p *Parser) lastEnd() lexer.Position {
+	for i := min(p.pos, len(p.tokens)) - 1; i >= 0; i-- {
+		switch p.tokens[i].Type {
+		case lexer.TokenNewline, lexer.TokenComment:
+			continue
+		}
+		return p.tokens[i].EndPos
+	}
+	return p.current.Pos
+}
+
 // peek returns the next token without advancing
 func (p *Parser) peek() lexer.Token {
 	if p.pos+1 < len(p.tokens) {
@@ -71,25 +112,57 @@ func (p *Parser) skipNewlines() {
 	}
 }
 
-// skipComments advances past any comment tokens, collecting them
+// skipCommentsAndNewlines advances past any comment and newline tokens,
+// collecting the comments. They share one backing array.
 func (p *Parser) skipCommentsAndNewlines() []*Comment {
-	var comments []*Comment
+	n := 0
+	for i := p.pos; i < len(p.tokens); i++ {
+		if t := p.tokens[i].Type; t == lexer.TokenComment {
+			n++
+		} else if t != lexer.TokenNewline {
+			break
+		}
+	}
+	if n == 0 {
+		p.skipNewlines()
+		return nil
+	}
+
+	backing := make([]Comment, n)
+	comments := make([]*Comment, n)
+	i := 0
 	for p.current.Type == lexer.TokenNewline || p.current.Type == lexer.TokenComment {
 		if p.current.Type == lexer.TokenComment {
-			comments = append(comments, &Comment{
+			backing[i] = Comment{
 				Text:     p.current.Literal,
 				StartPos: p.current.Pos,
 				EndPos:   p.current.EndPos,
-			})
+			}
+			comments[i] = &backing[i]
+			i++
 		}
 		p.advance()
 	}
 	return comments
 }
 
+// lineLen returns the number of tokens from the current one to the end
+// of the line, for sizing slices before collecting them
+func (p *Parser) lineLen() int {
+	n := 0
+	for i := p.pos; i < len(p.tokens); i++ {
+		if t := p.tokens[i].Type; t == lexer.TokenNewline || t == lexer.TokenEOF {
+			break
+		}
+		n++
+	}
+	return n
+}
+
 // error records a parsing error
-func (p *Parser) error(msg string) {
+func (p *Parser) error(code ErrorCode, msg string) {
 	p.errors = append(p.errors, ParseError{
+		Code:    code,
 		Message: msg,
 		Pos:     p.current.Pos,
 	})
@@ -113,6 +186,7 @@ func (p *Parser) ParseDockerfile() *Dockerfile {
 			rest := strings.TrimSpace(text[idx+1:])
 			if len(rest) > 0 {
 				df.Escape = rune(rest[0])
+				p.escape = rest[0]
 			}
 		}
 		p.advance()
@@ -126,6 +200,11 @@ func (p *Parser) ParseDockerfile() *Dockerfile {
 		if p.current.Type == lexer.TokenFrom {
 			stage := p.parseStage()
 			if stage != nil {
+				for _, prev := range df.Stages {
+					if prev.Name != "" && strings.EqualFold(prev.Name, stage.From.Image) {
+						stage.From.BaseStage = prev.Name
+					}
+				}
 				df.Stages = append(df.Stages, stage)
 			}
 		} else if p.current.Type == lexer.TokenComment {
@@ -137,9 +216,12 @@ func (p *Parser) ParseDockerfile() *Dockerfile {
 			p.advance()
 		} else if p.current.Type == lexer.TokenNewline {
 			p.advance()
+		} else if p.current.Type == lexer.TokenArg && len(df.Stages) == 0 {
+			// Global ARG, usable in FROM
+			df.Args = append(df.Args, p.parseArg())
 		} else {
 			// Instruction outside of stage - error but try to recover
-			p.error("instruction outside of build stage")
+			p.error(ErrInstructionOutsideStage, "instruction outside of build stage")
 			p.skipToNextInstruction()
 		}
 	}
@@ -164,19 +246,27 @@ func (p *Parser) parseStage() *Stage {
 	}
 	stage.From = from
 	stage.Name = from.AsName
+	if n := p.stageLen(); n > 0 {
+		stage.Instructions = make([]Instruction, 0, n)
+	}
 
-	// Parse instructions until next FROM or EOF
+	// Parse instructions until next FROM or EOF. Comments are attached to
+	// the instruction that follows them; those at the end of the stage
+	// stay with the stage.
 	for p.current.Type != lexer.TokenEOF && p.current.Type != lexer.TokenFrom {
 		comments := p.skipCommentsAndNewlines()
-		stage.Comments = append(stage.Comments, comments...)
 
 		if p.current.Type == lexer.TokenEOF || p.current.Type == lexer.TokenFrom {
+			stage.Comments = append(stage.Comments, comments...)
 			break
 		}
 
 		inst := p.parseInstruction()
 		if inst != nil {
+			inst.attachComments(comments)
 			stage.Instructions = append(stage.Instructions, inst)
+		} else {
+			stage.Comments = append(stage.Comments, comments...)
 		}
 	}
 
@@ -189,6 +279,21 @@ func (p *Parser) parseStage() *Stage {
 	return stage
 }
 
+// stageLen returns the number of instructions before the next FROM
+func (p *Parser) stageLen() int {
+	n := 0
+	for i := p.pos; i < len(p.tokens); i++ {
+		t := p.tokens[i]
+		if t.Type == lexer.TokenFrom {
+			break
+		}
+		if t.IsInstruction() {
+			n++
+		}
+	}
+	return n
+}
+
 // parseInstruction parses a single instruction
 func (p *Parser) parseInstruction() Instruction {
 	switch p.current.Type {
@@ -229,7 +334,7 @@ func (p *Parser) parseInstruction() Instruction {
 	case lexer.TokenMaintainer:
 		return p.parseMaintainer()
 	default:
-		p.error(fmt.Sprintf("unexpected token: %s", p.current.Type))
+		p.error(ErrUnexpectedToken, fmt.Sprintf("unexpected token: %s", p.current.Type))
 		p.skipToNextInstruction()
 		return nil
 	}
@@ -251,7 +356,7 @@ func (p *Parser) skipToNextInstruction() {
 
 // collectLine collects all tokens until newline or EOF
 func (p *Parser) collectLine() []lexer.Token {
-	var tokens []lexer.Token
+	tokens := make([]lexer.Token, 0, p.lineLen())
 	for p.current.Type != lexer.TokenNewline && p.current.Type != lexer.TokenEOF {
 		tokens = append(tokens, p.current)
 		p.advance()
@@ -264,7 +369,7 @@ func (p *Parser) collectLine() []lexer.Token {
 
 // collectWords collects word and string tokens from line
 func (p *Parser) collectWords() []string {
-	var words []string
+	words := make([]string, 0, p.lineLen())
 	for p.current.Type != lexer.TokenNewline && p.current.Type != lexer.TokenEOF {
 		switch p.current.Type {
 		case lexer.TokenWord, lexer.TokenString, lexer.TokenVariable:
@@ -278,6 +383,85 @@ func (p *Parser) collectWords() []string {
 	return words
 }
 
+// joinAdjacent consumes the current word or variable token and any
+// touching word/variable tokens, e.g. ${REGISTRY}/node or ${VERSION}-alpine
+func (p *Parser) joinAdjacent() string {
+	var sb strings.Builder
+	sb.WriteString(p.current.Literal)
+	end := p.current.EndPos
+	p.advance()
+	for (p.current.Type == lexer.TokenWord || p.current.Type == lexer.TokenVariable) && p.current.Pos == end {
+		sb.WriteString(p.current.Literal)
+		end = p.current.EndPos
+		p.advance()
+	}
+	return sb.String()
+}
+
+// collectValue consumes the = of a key=value pair and the tokens that
+// touch it, so values such as http://localhost:8080 stay whole. Quotes
+// around each quoted part are removed; the style is recorded when the
+// value is a single quoted string.
+func (p *Parser) collectValue() (string, QuoteStyle) {
+	var sb strings.Builder
+	style := Unquoted
+	parts := 0
+	end := p.current.EndPos
+	p.advance() // consume =
+	for p.current.Type != lexer.TokenNewline && p.current.Type != lexer.TokenEOF && p.current.Pos.Offset == end.Offset {
+		part := p.current.Literal
+		style = Unquoted
+		if p.current.Type == lexer.TokenString && !p.terminated(part) {
+			p.error(ErrUnterminatedString, fmt.Sprintf("unterminated string %s", part))
+		} else if p.current.Type == lexer.TokenString {
+			style = DoubleQuoted
+			if part[0] == '\'' {
+				style = SingleQuoted
+			}
+			part = part[1 : len(part)-1]
+		}
+		sb.WriteString(part)
+		parts++
+		end = p.current.EndPos
+		p.advance()
+	}
+	if parts != 1 {
+		style = Unquoted
+	}
+	return sb.String(), style
+}
+
+// terminated reports whether a quoted string token ends with its
+// opening quote. The lexer stops a string at the end of the line when the
+// closing quote is missing.
+func (p *Parser) terminated(s string) bool {
+	if len(s) < 2 || s[len(s)-1] != s[0] {
+		return false
+	}
+	// An odd run of escape characters before the final quote escapes it
+	n := 0
+	for i := len(s) - 2; i > 0 && s[i] == p.escape; i-- {
+		n++
+	}
+	return n%2 == 0
+}
+
+// collectRawRest consumes the rest of the line and returns the token
+// literals, separated by a space wherever the source had whitespace
+func (p *Parser) collectRawRest() string {
+	var sb strings.Builder
+	var end lexer.Position
+	for p.current.Type != lexer.TokenNewline && p.current.Type != lexer.TokenEOF {
+		if sb.Len() > 0 && p.current.Pos != end {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(p.current.Literal)
+		end = p.current.EndPos
+		p.advance()
+	}
+	return sb.String()
+}
+
 // parseFrom parses FROM instruction
 func (p *Parser) parseFrom() *FromInstruction {
 	inst := &FromInstruction{
@@ -302,38 +486,47 @@ func (p *Parser) parseFrom() *FromInstruction {
 	for p.current.Type != lexer.TokenNewline && p.current.Type != lexer.TokenEOF {
 		switch p.current.Type {
 		case lexer.TokenWord:
-			word := p.current.Literal
-			upperWord := strings.ToUpper(word)
-			if upperWord == "AS" {
+			if strings.EqualFold(p.current.Literal, "AS") {
 				p.advance()
 				if p.current.Type == lexer.TokenWord {
 					inst.AsName = p.current.Literal
 					p.advance()
 				}
 			} else if inst.Image == "" {
-				inst.Image = word
-				p.advance()
+				inst.Image = p.joinAdjacent()
 			} else {
 				p.advance()
 			}
 		case lexer.TokenColon:
 			p.advance()
-			if p.current.Type == lexer.TokenWord {
-				inst.Tag = p.current.Literal
-				p.advance()
+			if p.current.Type == lexer.TokenWord || p.current.Type == lexer.TokenVariable {
+				word := p.joinAdjacent()
+				if strings.Contains(word, "/") && inst.Tag == "" {
+					// Registry port, e.g. localhost:5000/app
+					inst.Image += ":" + word
+				} else {
+					inst.Tag = word
+				}
 			}
 		case lexer.TokenAt:
 			p.advance()
 			if p.current.Type == lexer.TokenWord {
 				inst.Digest = p.current.Literal
 				p.advance()
+				// algorithm:hex
+				if p.current.Type == lexer.TokenColon && p.peek().Type == lexer.TokenWord {
+					p.advance()
+					inst.Digest += ":" + p.current.Literal
+					p.advance()
+				}
 			}
 		case lexer.TokenVariable:
 			// Image can be a variable
 			if inst.Image == "" {
-				inst.Image = p.current.Literal
+				inst.Image = p.joinAdjacent()
+			} else {
+				p.advance()
 			}
-			p.advance()
 		default:
 			p.advance()
 		}
@@ -342,16 +535,28 @@ func (p *Parser) parseFrom() *FromInstruction {
 	// Build raw text
 	endPos := p.pos
 	if endPos > startPos && endPos <= len(p.tokens) {
-		var parts []string
-		for i := startPos; i < endPos && i < len(p.tokens); i++ {
-			if p.tokens[i].Type != lexer.TokenNewline {
-				parts = append(parts, p.tokens[i].Literal)
+		toks := p.tokens[startPos:endPos]
+		size := 0
+		for _, t := range toks {
+			size += len(t.Literal) + 1
+		}
+		var sb strings.Builder
+		sb.Grow(size)
+		first := true
+		for _, t := range toks {
+			if t.Type == lexer.TokenNewline {
+				continue
+			}
+			if !first {
+				sb.WriteByte(' ')
 			}
+			sb.WriteString(t.Literal)
+			first = false
 		}
-		inst.RawText = strings.Join(parts, " ")
+		inst.RawText = sb.String()
 	}
 
-	inst.EndPos = p.current.Pos
+	inst.EndPos = p.lastEnd()
 	if p.current.Type == lexer.TokenNewline {
 		p.advance()
 	}
@@ -373,7 +578,7 @@ func (p *Parser) parseRun() *RunInstruction {
 	for p.current.Type == lexer.TokenFlag {
 		flag := p.current.Literal
 		if strings.HasPrefix(flag, "--mount=") {
-			inst.Mount = strings.TrimPrefix(flag, "--mount=")
+			inst.Mounts = append(inst.Mounts, strings.TrimPrefix(flag, "--mount="))
 		} else if strings.HasPrefix(flag, "--network=") {
 			inst.Network = strings.TrimPrefix(flag, "--network=")
 		} else if strings.HasPrefix(flag, "--security=") {
@@ -384,9 +589,12 @@ func (p *Parser) parseRun() *RunInstruction {
 
 	// Check for heredoc
 	if p.current.Type == lexer.TokenHeredoc {
-		inst.Heredoc = &Heredoc{
-			Content: p.current.Literal,
+		heredoc, command, ok := parseHeredoc(p.current.Literal)
+		if !ok {
+			p.error(ErrMalformedHeredoc, fmt.Sprintf("unterminated heredoc, expected %s", heredoc.Delimiter))
 		}
+		inst.Heredoc = heredoc
+		inst.Command = command
 		p.advance()
 	} else if p.current.Type == lexer.TokenLeftBracket {
 		// Exec form
@@ -397,7 +605,7 @@ func (p *Parser) parseRun() *RunInstruction {
 		inst.Command = p.collectRestOfLine()
 	}
 
-	inst.EndPos = p.current.Pos
+	inst.EndPos = p.lastEnd()
 	if p.current.Type == lexer.TokenNewline {
 		p.advance()
 	}
@@ -407,7 +615,23 @@ func (p *Parser) parseRun() *RunInstruction {
 
 // collectRestOfLine collects the rest of the line as a string, preserving proper spacing
 func (p *Parser) collectRestOfLine() string {
+	n := p.lineLen()
+	switch n {
+	case 0:
+		return ""
+	case 1:
+		// Fast path: a single token needs no building
+		s := p.current.Literal
+		p.advance()
+		return s
+	}
+
+	size := 0
+	for _, t := range p.tokens[p.pos : p.pos+n] {
+		size += len(t.Literal) + 1
+	}
 	var sb strings.Builder
+	sb.Grow(size)
 	var lastEnd lexer.Position
 	first := true
 
@@ -416,7 +640,7 @@ func (p *Parser) collectRestOfLine() string {
 			// Add space only if there was whitespace between tokens in the source
 			// If the current token starts right after the previous one ended, no space
 			if p.current.Pos.Offset > lastEnd.Offset {
-				sb.WriteString(" ")
+				sb.WriteByte(' ')
 			}
 		}
 		sb.WriteString(p.current.Literal)
@@ -429,24 +653,36 @@ func (p *Parser) collectRestOfLine() string {
 
 // collectRestOfLineRaw collects the rest of the line preserving original spacing
 func (p *Parser) collectRestOfLineRaw() string {
-	var parts []string
+	var sb strings.Builder
+	var lastEnd lexer.Position
+	first := true
+
 	for p.current.Type != lexer.TokenNewline && p.current.Type != lexer.TokenEOF {
-		parts = append(parts, p.current.Literal)
+		if !first && p.current.Pos.Offset > lastEnd.Offset {
+			// Tokens don't include whitespace, so restore the gap between them
+			sb.WriteString(strings.Repeat(" ", p.current.Pos.Offset-lastEnd.Offset))
+		}
+		sb.WriteString(p.current.Literal)
+		lastEnd = p.current.EndPos
+		first = false
 		p.advance()
 	}
-	return strings.Join(parts, "")
+	return sb.String()
 }
 
 // parseExecForm parses ["cmd", "arg", ...] form
 func (p *Parser) parseExecForm() []string {
-	var args []string
+	args := make([]string, 0, p.lineLen()/2)
 	p.advance() // consume [
 
 	for p.current.Type != lexer.TokenRightBracket && p.current.Type != lexer.TokenEOF {
 		if p.current.Type == lexer.TokenString {
 			// Remove quotes
 			s := p.current.Literal
-			if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') {
+			if !p.terminated(s) {
+				p.error(ErrUnterminatedString, fmt.Sprintf("unterminated string %s", s))
+				s = s[1:]
+			} else if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') {
 				s = s[1 : len(s)-1]
 			}
 			args = append(args, s)
@@ -476,7 +712,7 @@ func (p *Parser) parseCmd() *CmdInstruction {
 		inst.Command = p.collectRestOfLine()
 	}
 
-	inst.EndPos = p.current.Pos
+	inst.EndPos = p.lastEnd()
 	if p.current.Type == lexer.TokenNewline {
 		p.advance()
 	}
@@ -501,7 +737,7 @@ func (p *Parser) parseEntrypoint() *EntrypointInstruction {
 		inst.Command = p.collectRestOfLine()
 	}
 
-	inst.EndPos = p.current.Pos
+	inst.EndPos = p.lastEnd()
 	if p.current.Type == lexer.TokenNewline {
 		p.advance()
 	}
@@ -535,7 +771,7 @@ func (p *Parser) parseCopy() *CopyInstruction {
 	}
 
 	// Parse sources and destination
-	var paths []string
+	paths := make([]string, 0, p.lineLen())
 	for p.current.Type != lexer.TokenNewline && p.current.Type != lexer.TokenEOF {
 		if p.current.Type == lexer.TokenWord || p.current.Type == lexer.TokenString || p.current.Type == lexer.TokenVariable {
 			path := p.current.Literal
@@ -553,7 +789,7 @@ func (p *Parser) parseCopy() *CopyInstruction {
 		inst.Sources = paths[:len(paths)-1]
 	}
 
-	inst.EndPos = p.current.Pos
+	inst.EndPos = p.lastEnd()
 	if p.current.Type == lexer.TokenNewline {
 		p.advance()
 	}
@@ -585,7 +821,7 @@ func (p *Parser) parseAdd() *AddInstruction {
 	}
 
 	// Parse sources and destination
-	var paths []string
+	paths := make([]string, 0, p.lineLen())
 	for p.current.Type != lexer.TokenNewline && p.current.Type != lexer.TokenEOF {
 		if p.current.Type == lexer.TokenWord || p.current.Type == lexer.TokenString {
 			path := p.current.Literal
@@ -602,7 +838,7 @@ func (p *Parser) parseAdd() *AddInstruction {
 		inst.Sources = paths[:len(paths)-1]
 	}
 
-	inst.EndPos = p.current.Pos
+	inst.EndPos = p.lastEnd()
 	if p.current.Type == lexer.TokenNewline {
 		p.advance()
 	}
@@ -627,32 +863,29 @@ func (p *Parser) parseEnv() *EnvInstruction {
 			p.advance()
 
 			var value string
+			var quote QuoteStyle
 			if p.current.Type == lexer.TokenEquals {
-				p.advance()
-				if p.current.Type == lexer.TokenWord || p.current.Type == lexer.TokenString || p.current.Type == lexer.TokenVariable {
-					value = p.current.Literal
-					// Remove quotes
-					if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
-						value = value[1 : len(value)-1]
-					}
-					p.advance()
-				}
-			} else if p.current.Type == lexer.TokenWord || p.current.Type == lexer.TokenString {
-				// Old syntax: ENV key value
-				value = p.current.Literal
-				if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+				value, quote = p.collectValue()
+			} else if len(inst.Variables) == 0 && p.current.Type != lexer.TokenNewline && p.current.Type != lexer.TokenEOF {
+				// Old syntax: ENV key value, where the value is the rest of the line
+				inst.Legacy = true
+				value = p.collectRawRest()
+				if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
 					value = value[1 : len(value)-1]
 				}
-				p.advance()
 			}
 
-			inst.Variables = append(inst.Variables, KeyValue{Key: key, Value: value})
+			inst.Variables = append(inst.Variables, KeyValue{Key: key, Value: value, Quote: quote})
 		} else {
+			if p.current.Type == lexer.TokenComment {
+				// Docker only treats # as a comment at the start of a line
+				inst.Trailing = p.current.Literal
+			}
 			p.advance()
 		}
 	}
 
-	inst.EndPos = p.current.Pos
+	inst.EndPos = p.lastEnd()
 	if p.current.Type == lexer.TokenNewline {
 		p.advance()
 	}
@@ -675,24 +908,21 @@ func (p *Parser) parseArg() *ArgInstruction {
 		p.advance()
 
 		if p.current.Type == lexer.TokenEquals {
-			p.advance()
 			inst.HasDefault = true
-			if p.current.Type == lexer.TokenWord || p.current.Type == lexer.TokenString {
-				inst.DefaultValue = p.current.Literal
-				if len(inst.DefaultValue) >= 2 && (inst.DefaultValue[0] == '"' || inst.DefaultValue[0] == '\'') {
-					inst.DefaultValue = inst.DefaultValue[1 : len(inst.DefaultValue)-1]
-				}
-				p.advance()
-			}
+			inst.DefaultValue, inst.DefaultQuote = p.collectValue()
 		}
 	}
 
-	// Skip rest of line
+	// Skip rest of line. Docker only treats # as a comment at the start
+	// of a line.
 	for p.current.Type != lexer.TokenNewline && p.current.Type != lexer.TokenEOF {
+		if p.current.Type == lexer.TokenComment {
+			inst.Trailing = p.current.Literal
+		}
 		p.advance()
 	}
 
-	inst.EndPos = p.current.Pos
+	inst.EndPos = p.lastEnd()
 	if p.current.Type == lexer.TokenNewline {
 		p.advance()
 	}
@@ -720,24 +950,18 @@ func (p *Parser) parseLabel() *LabelInstruction {
 			p.advance()
 
 			var value string
+			var quote QuoteStyle
 			if p.current.Type == lexer.TokenEquals {
-				p.advance()
-				if p.current.Type == lexer.TokenWord || p.current.Type == lexer.TokenString {
-					value = p.current.Literal
-					if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
-						value = value[1 : len(value)-1]
-					}
-					p.advance()
-				}
+				value, quote = p.collectValue()
 			}
 
-			inst.Labels = append(inst.Labels, KeyValue{Key: key, Value: value})
+			inst.Labels = append(inst.Labels, KeyValue{Key: key, Value: value, Quote: quote})
 		} else {
 			p.advance()
 		}
 	}
 
-	inst.EndPos = p.current.Pos
+	inst.EndPos = p.lastEnd()
 	if p.current.Type == lexer.TokenNewline {
 		p.advance()
 	}
@@ -774,7 +998,7 @@ func (p *Parser) parseExpose() *ExposeInstruction {
 		p.advance()
 	}
 
-	inst.EndPos = p.current.Pos
+	inst.EndPos = p.lastEnd()
 	if p.current.Type == lexer.TokenNewline {
 		p.advance()
 	}
@@ -809,7 +1033,7 @@ func (p *Parser) parseVolume() *VolumeInstruction {
 		}
 	}
 
-	inst.EndPos = p.current.Pos
+	inst.EndPos = p.lastEnd()
 	if p.current.Type == lexer.TokenNewline {
 		p.advance()
 	}
@@ -853,7 +1077,7 @@ func (p *Parser) parseUser() *UserInstruction {
 		p.advance()
 	}
 
-	inst.EndPos = p.current.Pos
+	inst.EndPos = p.lastEnd()
 	if p.current.Type == lexer.TokenNewline {
 		p.advance()
 	}
@@ -880,7 +1104,7 @@ func (p *Parser) parseWorkdir() *WorkdirInstruction {
 	}
 	inst.Path = strings.Join(parts, "")
 
-	inst.EndPos = p.current.Pos
+	inst.EndPos = p.lastEnd()
 	if p.current.Type == lexer.TokenNewline {
 		p.advance()
 	}
@@ -898,8 +1122,14 @@ func (p *Parser) parseShell() *ShellInstruction {
 
 	p.advance() // consume SHELL
 
+	// SHELL only has an exec form
 	if p.current.Type == lexer.TokenLeftBracket {
 		inst.Shell = p.parseExecForm()
+		if len(inst.Shell) == 0 {
+			p.error(ErrMalformedShell, "SHELL requires a non-empty JSON array")
+		}
+	} else {
+		p.error(ErrMalformedShell, "SHELL requires a JSON array, e.g. SHELL [\"/bin/bash\", \"-c\"]")
 	}
 
 	// Skip rest of line
@@ -907,7 +1137,7 @@ func (p *Parser) parseShell() *ShellInstruction {
 		p.advance()
 	}
 
-	inst.EndPos = p.current.Pos
+	inst.EndPos = p.lastEnd()
 	if p.current.Type == lexer.TokenNewline {
 		p.advance()
 	}
@@ -957,7 +1187,7 @@ func (p *Parser) parseHealthcheck() *HealthcheckInstruction {
 		}
 	}
 
-	inst.EndPos = p.current.Pos
+	inst.EndPos = p.lastEnd()
 	if p.current.Type == lexer.TokenNewline {
 		p.advance()
 	}
@@ -985,7 +1215,7 @@ func (p *Parser) parseStopsignal() *StopsignalInstruction {
 		p.advance()
 	}
 
-	inst.EndPos = p.current.Pos
+	inst.EndPos = p.lastEnd()
 	if p.current.Type == lexer.TokenNewline {
 		p.advance()
 	}
@@ -1003,22 +1233,23 @@ func (p *Parser) parseOnbuild() *OnbuildInstruction {
 
 	p.advance() // consume ONBUILD
 
-	// Parse nested instruction - might be a word token since we're not at line start
-	if p.current.IsInstruction() {
+	// The lexer treats the word after ONBUILD as an instruction keyword
+	switch {
+	case p.current.Type == lexer.TokenOnbuild || p.current.Type == lexer.TokenFrom || p.current.Type == lexer.TokenMaintainer:
+		p.error(ErrInvalidOnbuild, fmt.Sprintf("%s is not allowed as an ONBUILD trigger", strings.ToUpper(p.current.Literal)))
+		p.collectLine()
+	case p.current.IsInstruction():
 		inst.Instruction = p.parseInstruction()
-	} else if p.current.Type == lexer.TokenWord {
-		// Check if the word is an instruction keyword
-		keyword := strings.ToUpper(p.current.Literal)
-		tokType := lexer.LookupKeyword(keyword)
-		if tokType != lexer.TokenWord {
-			// It's an instruction keyword, parse it
-			// Temporarily update current token type for parsing
-			p.current.Type = tokType
-			inst.Instruction = p.parseInstruction()
-		}
+	default:
+		p.error(ErrInvalidOnbuild, "ONBUILD requires an instruction")
+		p.collectLine()
 	}
 
-	inst.EndPos = p.current.Pos
+	if inst.Instruction != nil {
+		inst.EndPos = inst.Instruction.End()
+	} else {
+		inst.EndPos = p.lastEnd()
+	}
 	return inst
 }
 
@@ -1034,10 +1265,56 @@ func (p *Parser) parseMaintainer() *MaintainerInstruction {
 
 	inst.Maintainer = p.collectRestOfLineRaw()
 
-	inst.EndPos = p.current.Pos
+	inst.EndPos = p.lastEnd()
 	if p.current.Type == lexer.TokenNewline {
 		p.advance()
 	}
 
 	return inst
 }
+
+// parseHeredoc splits a heredoc token into the heredoc and the command
+// following its marker on the first line. ok is false when the closing
+// delimiter is missing.
+func parseHeredoc(literal string) (heredoc *Heredoc, command string, ok bool) {
+	header, body, _ := strings.Cut(literal, "\n")
+	heredoc = &Heredoc{}
+
+	marker := strings.TrimPrefix(header, "<<")
+	if strings.HasPrefix(marker, "-") {
+		heredoc.StripTabs = true
+		marker = marker[1:]
+	}
+	if marker != "" && (marker[0] == '"' || marker[0] == '\'') {
+		heredoc.Quoted = true
+		if end := strings.IndexByte(marker[1:], marker[0]); end >= 0 {
+			heredoc.Delimiter = marker[1 : end+1]
+			command = marker[end+2:]
+		} else {
+			heredoc.Delimiter = marker[1:]
+		}
+	} else {
+		end := strings.IndexAny(marker, " \t")
+		if end < 0 {
+			end = len(marker)
+		}
+		heredoc.Delimiter = marker[:end]
+		command = marker[end:]
+	}
+	command = strings.TrimSpace(command)
+
+	// The last line is the closing delimiter
+	if body != "" {
+		i := strings.LastIndexByte(body, '\n')
+		last := strings.TrimRight(body[i+1:], " \t")
+		if heredoc.StripTabs {
+			last = strings.TrimLeft(last, "\t")
+		}
+		if last == heredoc.Delimiter {
+			heredoc.Content = body[:i+1]
+			return heredoc, command, true
+		}
+	}
+	heredoc.Content = body
+	return heredoc, command, false
+}
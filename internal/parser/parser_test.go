@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -233,6 +235,26 @@ ARG NAME
 	}
 }
 
+func TestParseGlobalArg(t *testing.T) {
+	input := `ARG VERSION=20
+FROM node:${VERSION}-alpine
+`
+	df, errs := Parse(input)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	if len(df.Args) != 1 || df.Args[0].Name != "VERSION" {
+		t.Fatalf("expected global ARG VERSION, got %+v", df.Args)
+	}
+	if len(df.Stages) != 1 {
+		t.Fatalf("expected 1 stage, got %d", len(df.Stages))
+	}
+	if tag := df.Stages[0].From.Tag; tag != "${VERSION}-alpine" {
+		t.Errorf("expected tag '${VERSION}-alpine', got %q", tag)
+	}
+}
+
 func TestParseCopyFlags(t *testing.T) {
 	input := `FROM alpine
 COPY --chmod=755 --chown=root:root src/ /app/
@@ -299,6 +321,31 @@ SHELL ["/bin/bash", "-c"]
 	}
 }
 
+func TestParseShellMalformed(t *testing.T) {
+	inputs := []string{
+		"FROM alpine\nSHELL /bin/bash -c\nRUN x\n",
+		"FROM alpine\nSHELL []\nRUN x\n",
+		"FROM alpine\nSHELL\nRUN x\n",
+	}
+
+	for _, input := range inputs {
+		df, errs := Parse(input)
+		if len(errs) != 1 || !strings.Contains(errs[0].Message, "SHELL requires") {
+			t.Errorf("%q: expected 1 SHELL error, got %v", input, errs)
+		}
+		if errs[0].Pos.Line != 2 {
+			t.Errorf("%q: expected error on line 2, got %s", input, errs[0].Pos)
+		}
+		shell := df.Stages[0].Instructions[0].(*ShellInstruction)
+		if len(shell.Shell) != 0 {
+			t.Errorf("%q: expected no shell, got %q", input, shell.Shell)
+		}
+		if _, ok := df.Stages[0].Instructions[1].(*RunInstruction); !ok {
+			t.Errorf("%q: expected trailing RUN to be parsed", input)
+		}
+	}
+}
+
 func TestParseMaintainer(t *testing.T) {
 	input := `FROM alpine
 MAINTAINER test@example.com
@@ -314,6 +361,18 @@ MAINTAINER test@example.com
 	}
 }
 
+func TestParseMaintainerSpacing(t *testing.T) {
+	df, errs := Parse("FROM alpine\nMAINTAINER John Doe <john@example.com>\n")
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	maint := df.Stages[0].Instructions[0].(*MaintainerInstruction)
+	if maint.Maintainer != "John Doe <john@example.com>" {
+		t.Errorf("expected spacing to be preserved, got %q", maint.Maintainer)
+	}
+}
+
 func TestParseOnbuild(t *testing.T) {
 	input := `FROM alpine
 ONBUILD RUN echo "triggered"
@@ -333,6 +392,105 @@ ONBUILD RUN echo "triggered"
 	}
 }
 
+func TestParseOnbuildNested(t *testing.T) {
+	input := `FROM alpine
+ONBUILD COPY --chown=app:app . /app
+ONBUILD RUN ["echo","x"]
+`
+	df, errs := Parse(input)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	insts := df.Stages[0].Instructions
+	if len(insts) != 2 {
+		t.Fatalf("expected 2 instructions, got %d", len(insts))
+	}
+
+	cp, ok := insts[0].(*OnbuildInstruction).Instruction.(*CopyInstruction)
+	if !ok {
+		t.Fatalf("expected nested COPY, got %T", insts[0].(*OnbuildInstruction).Instruction)
+	}
+	if cp.Chown != "app:app" || cp.Destination != "/app" || len(cp.Sources) != 1 || cp.Sources[0] != "." {
+		t.Errorf("unexpected COPY: %+v", cp)
+	}
+
+	run, ok := insts[1].(*OnbuildInstruction).Instruction.(*RunInstruction)
+	if !ok {
+		t.Fatalf("expected nested RUN, got %T", insts[1].(*OnbuildInstruction).Instruction)
+	}
+	if !run.IsExec || len(run.Arguments) != 2 || run.Arguments[0] != "echo" {
+		t.Errorf("expected exec form [echo x], got %+v", run)
+	}
+	if insts[1].End().Line != run.End().Line {
+		t.Errorf("expected ONBUILD to end with its trigger, got %s", insts[1].End())
+	}
+}
+
+func TestParseOnbuildInvalidTrigger(t *testing.T) {
+	inputs := []string{
+		"FROM alpine\nONBUILD FROM debian\nRUN x\n",
+		"FROM alpine\nONBUILD ONBUILD RUN x\nRUN x\n",
+		"FROM alpine\nONBUILD nonsense\nRUN x\n",
+	}
+
+	for _, input := range inputs {
+		df, errs := Parse(input)
+		if len(errs) != 1 {
+			t.Errorf("%q: expected 1 error, got %v", input, errs)
+		}
+		// Parsing recovers at the next line
+		last := df.Stages[0].Instructions[len(df.Stages[0].Instructions)-1]
+		if _, ok := last.(*RunInstruction); !ok {
+			t.Errorf("%q: expected trailing RUN to be parsed, got %T", input, last)
+		}
+	}
+}
+
+func TestParseRunHeredoc(t *testing.T) {
+	input := `FROM alpine
+RUN <<EOF
+#!/bin/sh
+apk add curl
+EOF
+RUN <<-"EOT" bash
+	echo $HOME
+	EOT
+USER nobody
+`
+	df, errs := Parse(input)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	insts := df.Stages[0].Instructions
+	if len(insts) != 3 {
+		t.Fatalf("expected 3 instructions, got %d", len(insts))
+	}
+
+	run := insts[0].(*RunInstruction)
+	expected := Heredoc{Delimiter: "EOF", Content: "#!/bin/sh\napk add curl\n"}
+	if run.Heredoc == nil || *run.Heredoc != expected || run.Command != "" {
+		t.Errorf("expected heredoc %+v, got %+v (command %q)", expected, run.Heredoc, run.Command)
+	}
+
+	run = insts[1].(*RunInstruction)
+	expected = Heredoc{Delimiter: "EOT", Content: "\techo $HOME\n", StripTabs: true, Quoted: true}
+	if run.Heredoc == nil || *run.Heredoc != expected || run.Command != "bash" {
+		t.Errorf("expected heredoc %+v with command bash, got %+v (command %q)", expected, run.Heredoc, run.Command)
+	}
+	if run.Heredoc.Marker() != `<<-"EOT"` {
+		t.Errorf("expected marker <<-\"EOT\", got %s", run.Heredoc.Marker())
+	}
+}
+
+func TestParseRunHeredocUnterminated(t *testing.T) {
+	_, errs := Parse("FROM alpine\nRUN <<EOF\necho hi\n")
+	if len(errs) != 1 || !strings.Contains(errs[0].Message, "unterminated heredoc") {
+		t.Errorf("expected an unterminated heredoc error, got %v", errs)
+	}
+}
+
 func TestParseFromPlatform(t *testing.T) {
 	input := `FROM --platform=linux/amd64 alpine:3.18
 `
@@ -359,9 +517,12 @@ func TestParseFromDigest(t *testing.T) {
 	if from.Image != "alpine" {
 		t.Errorf("expected image 'alpine', got %q", from.Image)
 	}
-	if from.Digest != "sha256" {
+	if from.Digest != "sha256:abc123" {
 		t.Errorf("expected digest 'sha256:abc123', got %q", from.Digest)
 	}
+	if from.Tag != "" {
+		t.Errorf("expected no tag, got %q", from.Tag)
+	}
 }
 
 func TestGetInstructions(t *testing.T) {
@@ -413,6 +574,38 @@ RUN echo hello
 	}
 }
 
+func TestParseAttachesComments(t *testing.T) {
+	input := `# header
+FROM alpine
+# install curl
+# and certificates
+
+RUN apk add curl ca-certificates
+USER app
+# end of stage
+`
+	df, errs := Parse(input)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	if len(df.Comments) != 1 || df.Comments[0].Text != "# header" {
+		t.Errorf("expected the header comment at the top level, got %v", df.Comments)
+	}
+
+	stage := df.Stages[0]
+	run := stage.Instructions[0].LeadingComments()
+	if len(run) != 2 || run[0].Text != "# install curl" || run[1].Text != "# and certificates" {
+		t.Errorf("expected RUN to carry the two comments above it, got %v", run)
+	}
+	if c := stage.Instructions[1].LeadingComments(); len(c) != 0 {
+		t.Errorf("expected USER to have no comments, got %v", c)
+	}
+	if len(stage.Comments) != 1 || stage.Comments[0].Text != "# end of stage" {
+		t.Errorf("expected the trailing comment on the stage, got %v", stage.Comments)
+	}
+}
+
 func TestPortSpecPrivileged(t *testing.T) {
 	tests := []struct {
 		port       string
@@ -434,3 +627,353 @@ func TestPortSpecPrivileged(t *testing.T) {
 		}
 	}
 }
+
+func TestParsePlatform(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected PlatformSpec
+	}{
+		{"linux", PlatformSpec{OS: "linux"}},
+		{"linux/amd64", PlatformSpec{OS: "linux", Arch: "amd64"}},
+		{"linux/arm64/v8", PlatformSpec{OS: "linux", Arch: "arm64", Variant: "v8"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := ParsePlatform(tt.input); got != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseRunMounts(t *testing.T) {
+	input := `FROM alpine
+RUN --mount=type=cache,target=/root/.cache --mount=type=secret,id=npmrc,target=/root/.npmrc npm ci
+`
+	df, errs := Parse(input)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	run := df.Stages[0].Instructions[0].(*RunInstruction)
+	if len(run.Mounts) != 2 {
+		t.Fatalf("expected 2 mounts, got %v", run.Mounts)
+	}
+	specs := run.MountSpecs()
+	if specs[0].Type != MountCache || specs[0].Target != "/root/.cache" {
+		t.Errorf("unexpected first mount %+v", specs[0])
+	}
+	if specs[1].Type != MountSecret || specs[1].ID != "npmrc" || specs[1].Target != "/root/.npmrc" {
+		t.Errorf("unexpected second mount %+v", specs[1])
+	}
+	if run.Command != "npm ci" {
+		t.Errorf("expected command 'npm ci', got %q", run.Command)
+	}
+}
+
+func TestParseMount(t *testing.T) {
+	tests := []struct {
+		input  string
+		typ    string
+		target string
+		source string
+		id     string
+		env    string
+	}{
+		{"target=/src", MountBind, "/src", "", "", ""},
+		{"type=bind,from=build,src=/out,dst=/in", MountBind, "/in", "/out", "", ""},
+		{"type=cache,target=/var/cache/apt,sharing=locked", MountCache, "/var/cache/apt", "", "", ""},
+		{"type=secret,id=token", MountSecret, "/run/secrets/token", "", "token", ""},
+		{"type=secret,target=/root/.netrc", MountSecret, "/root/.netrc", "", ".netrc", ""},
+		{"type=secret,id=token,env=TOKEN", MountSecret, "", "", "token", "TOKEN"},
+		{"Type=SECRET,ID=aws", MountSecret, "/run/secrets/aws", "", "aws", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := ParseMount(tt.input)
+			if got.Type != tt.typ || got.Target != tt.target || got.Source != tt.source || got.ID != tt.id || got.Env != tt.env {
+				t.Errorf("unexpected spec %+v", got)
+			}
+		})
+	}
+
+	if spec := ParseMount("type=bind,target=/src,readonly"); spec.Options["readonly"] != "" {
+		t.Errorf("expected readonly flag in options, got %v", spec.Options)
+	} else if _, ok := spec.Options["readonly"]; !ok {
+		t.Errorf("expected readonly flag in options, got %v", spec.Options)
+	}
+}
+
+func TestFromCanonicalRef(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"FROM alpine", "docker.io/library/alpine:latest"},
+		{"FROM alpine:3.18", "docker.io/library/alpine:3.18"},
+		{"FROM myorg/app:1", "docker.io/myorg/app:1"},
+		{"FROM docker.io/library/alpine:3.18", "docker.io/library/alpine:3.18"},
+		{"FROM index.docker.io/nginx", "docker.io/library/nginx:latest"},
+		{"FROM ghcr.io/x/y@sha256:abc123", "ghcr.io/x/y@sha256:abc123"},
+		{"FROM localhost:5000/app:2", "localhost:5000/app:2"},
+		{"FROM scratch", "scratch"},
+		{"FROM node:${VERSION}", "node:${VERSION}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			df, errs := Parse(tt.input + "\n")
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if got := df.Stages[0].From.CanonicalRef(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFromCanonicalRef_StageReference(t *testing.T) {
+	input := `FROM golang:1.22 AS Build
+FROM build
+`
+	df, errs := Parse(input)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	from := df.Stages[1].From
+	if from.BaseStage != "Build" {
+		t.Errorf("expected base stage 'Build', got %q", from.BaseStage)
+	}
+	if got := from.CanonicalRef(); got != "Build" {
+		t.Errorf("expected stage reference to be unchanged, got %q", got)
+	}
+}
+
+func TestParseEnvLegacyForm(t *testing.T) {
+	tests := []struct {
+		input  string
+		value  string
+		legacy bool
+	}{
+		{"ENV GREETING hello world", "hello world", true},
+		{`ENV GREETING "hello world"`, "hello world", true},
+		{"ENV ONE TWO= THREE=world", "TWO= THREE=world", true},
+		{"ENV GREETING=hello", "hello", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			df, errs := Parse("FROM alpine\n" + tt.input + "\n")
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			env := df.Stages[0].Instructions[0].(*EnvInstruction)
+			if len(env.Variables) != 1 || env.Variables[0].Value != tt.value {
+				t.Errorf("expected value %q, got %+v", tt.value, env.Variables)
+			}
+			if env.Legacy != tt.legacy {
+				t.Errorf("expected Legacy=%v, got %v", tt.legacy, env.Legacy)
+			}
+		})
+	}
+}
+
+func TestParseTrailingHash(t *testing.T) {
+	df, errs := Parse("FROM alpine\nENV FOO=bar # the foo\nARG VERSION=1.0 # pinned\nENV PLAIN=value\n")
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	insts := df.Stages[0].Instructions
+
+	env := insts[0].(*EnvInstruction)
+	if env.Variables[0].Value != "bar" || env.Trailing != "# the foo" {
+		t.Errorf("expected value bar and trailing \"# the foo\", got %+v", env)
+	}
+	if arg := insts[1].(*ArgInstruction); arg.DefaultValue != "1.0" || arg.Trailing != "# pinned" {
+		t.Errorf("expected default 1.0 and trailing \"# pinned\", got %+v", arg)
+	}
+	if plain := insts[2].(*EnvInstruction); plain.Trailing != "" {
+		t.Errorf("expected no trailing text, got %q", plain.Trailing)
+	}
+}
+
+func TestParseKeyValueSeparators(t *testing.T) {
+	tests := []struct {
+		input string
+		value string
+	}{
+		{"ENV API=http://localhost:8080", "http://localhost:8080"},
+		{"ENV IMAGE=alpine@sha256:abc", "alpine@sha256:abc"},
+		{`ENV PATH=/app/bin:$PATH`, "/app/bin:$PATH"},
+		{`ENV GREETING=hello" world"`, "hello world"},
+		{"ENV LIST=a,b,c", "a,b,c"},
+		{"ENV EMPTY=", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			df, errs := Parse("FROM alpine\n" + tt.input + "\n")
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			env := df.Stages[0].Instructions[0].(*EnvInstruction)
+			if len(env.Variables) != 1 || env.Variables[0].Value != tt.value {
+				t.Errorf("expected value %q, got %+v", tt.value, env.Variables)
+			}
+		})
+	}
+
+	df, errs := Parse("ARG MIRROR=https://mirror.example.com:8443/debian\nFROM alpine\nLABEL org.opencontainers.image.source=https://github.com/example/app a=b\n")
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if got := df.Args[0].DefaultValue; got != "https://mirror.example.com:8443/debian" {
+		t.Errorf("unexpected ARG default %q", got)
+	}
+	label := df.Stages[0].Instructions[0].(*LabelInstruction)
+	if len(label.Labels) != 2 || label.Labels[0].Value != "https://github.com/example/app" {
+		t.Errorf("unexpected labels %+v", label.Labels)
+	}
+}
+
+func TestInstructionEndPos(t *testing.T) {
+	input := "FROM alpine:3.19 AS base\n" +
+		"RUN apk add curl   \n" +
+		"ENV A=1 B=\"two words\"\n" +
+		"COPY --chown=app src/ /app/ # trailing comment\n" +
+		"CMD [\"sh\", \"-c\", \"echo hi\"]\n" +
+		"RUN apk add \\\n    git\n" +
+		"RUN <<EOF\necho one\nEOF\n" +
+		"USER app"
+
+	df, errs := Parse(input)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	stage := df.Stages[0]
+	if end := stage.From.End(); end.Line != 1 || end.Column != 25 {
+		t.Errorf("FROM: expected end 1:25, got %s", end)
+	}
+
+	tests := []struct {
+		line   int
+		column int
+	}{
+		{2, 17}, // RUN, trailing whitespace excluded
+		{3, 22}, // ENV
+		{4, 28}, // COPY, trailing comment excluded
+		{5, 28}, // CMD
+		{7, 8},  // continued RUN
+		{10, 4}, // heredoc RUN ends after the delimiter
+		{11, 9}, // USER at EOF without a newline
+	}
+
+	if len(stage.Instructions) != len(tests) {
+		t.Fatalf("expected %d instructions, got %d", len(tests), len(stage.Instructions))
+	}
+	for i, tt := range tests {
+		inst := stage.Instructions[i]
+		end := inst.End()
+		if end.Line != tt.line || end.Column != tt.column {
+			t.Errorf("%T at %s: expected end %d:%d, got %s", inst, inst.Pos(), tt.line, tt.column, end)
+		}
+	}
+}
+
+func TestParseValueQuoteStyle(t *testing.T) {
+	tests := []struct {
+		input string
+		value string
+		quote QuoteStyle
+	}{
+		{"ENV A=plain", "plain", Unquoted},
+		{`ENV A="double $HOME"`, "double $HOME", DoubleQuoted},
+		{"ENV A='single $HOME'", "single $HOME", SingleQuoted},
+		{`ENV A=mixed" part"`, "mixed part", Unquoted},
+		{"LABEL a='$5'", "$5", SingleQuoted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			df, errs := Parse("FROM alpine\n" + tt.input + "\n")
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			var kv KeyValue
+			switch v := df.Stages[0].Instructions[0].(type) {
+			case *EnvInstruction:
+				kv = v.Variables[0]
+			case *LabelInstruction:
+				kv = v.Labels[0]
+			}
+			if kv.Value != tt.value || kv.Quote != tt.quote {
+				t.Errorf("expected %q with quote %d, got %q with quote %d", tt.value, tt.quote, kv.Value, kv.Quote)
+			}
+		})
+	}
+
+	df, errs := Parse("ARG PATTERN='*.$EXT'\nFROM alpine\n")
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if df.Args[0].DefaultValue != "*.$EXT" || df.Args[0].DefaultQuote != SingleQuoted {
+		t.Errorf("unexpected ARG default %q with quote %d", df.Args[0].DefaultValue, df.Args[0].DefaultQuote)
+	}
+}
+
+func TestParseErrorCodes(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		code  ErrorCode
+		line  int
+	}{
+		{"instruction outside stage", "RUN echo hi\nFROM alpine\n", ErrInstructionOutsideStage, 1},
+		{"unterminated ENV value", "FROM alpine\nENV A=\"foo\n", ErrUnterminatedString, 2},
+		{"unterminated LABEL value", "FROM alpine\nLABEL a='foo\n", ErrUnterminatedString, 2},
+		{"escaped closing quote", "FROM alpine\nENV A=\"foo\\\"\n", ErrUnterminatedString, 2},
+		{"unterminated exec form argument", "FROM alpine\nCMD [\"echo\", \"hi]\n", ErrUnterminatedString, 2},
+		{"unterminated heredoc", "FROM alpine\nRUN <<EOF\necho hi\n", ErrMalformedHeredoc, 2},
+		{"shell form SHELL", "FROM alpine\nSHELL /bin/bash -c\n", ErrMalformedShell, 2},
+		{"empty SHELL", "FROM alpine\nSHELL []\n", ErrMalformedShell, 2},
+		{"FROM as ONBUILD trigger", "FROM alpine\nONBUILD FROM alpine\n", ErrInvalidOnbuild, 2},
+		{"ONBUILD without trigger", "FROM alpine\nONBUILD\n", ErrInvalidOnbuild, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errs := Parse(tt.input)
+			if len(errs) != 1 {
+				t.Fatalf("expected 1 error, got %v", errs)
+			}
+			if errs[0].Code != tt.code {
+				t.Errorf("expected code %s, got %s (%v)", tt.code, errs[0].Code, errs[0])
+			}
+			if errs[0].Pos.Line != tt.line {
+				t.Errorf("expected error on line %d, got %d", tt.line, errs[0].Pos.Line)
+			}
+			if !errors.Is(errs[0], tt.code) {
+				t.Errorf("expected errors.Is to match %s", tt.code)
+			}
+		})
+	}
+}
+
+func TestParseTerminatedStrings(t *testing.T) {
+	inputs := []string{
+		"FROM alpine\nENV A=\"foo\" B='bar'\n",
+		"FROM alpine\nENV A=\"foo\\\\\"\n",
+		"FROM alpine\nCMD [\"echo\", \"a \\\"b\\\"\"]\n",
+		"# escape=`\nFROM alpine\nENV A=\"C:\\\\\"\n",
+	}
+	for _, input := range inputs {
+		if _, errs := Parse(input); len(errs) > 0 {
+			t.Errorf("unexpected errors for %q: %v", input, errs)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/HueCodes/keel/internal/lexer"
+)
+
+// String renders the Dockerfile in canonical form: one instruction per
+// line, flags in a fixed order, exec-form arguments as JSON arrays, and a
+// blank line between stages. Comments are kept before the instruction
+// they are attached to, or that they preceded; comments without a
+// position go at the end of their stage. Parsing the result yields an
+// equivalent AST.
+func (d *Dockerfile) String() string {
+	var sb strings.Builder
+
+	if d.Escape != '\\' && d.Escape != 0 {
+		sb.WriteString("# escape=")
+		sb.WriteRune(d.Escape)
+		sb.WriteString("\n")
+	}
+
+	comments := d.Comments
+	for _, arg := range d.Args {
+		comments = writeCommentsBefore(&sb, comments, arg.Pos())
+		writeComments(&sb, arg.LeadingComments())
+		sb.WriteString(Render(arg))
+		sb.WriteString("\n")
+	}
+	writeCommentsBefore(&sb, comments, lexer.Position{})
+	if len(d.Args) > 0 && len(d.Stages) > 0 {
+		sb.WriteString("\n")
+	}
+
+	for i, stage := range d.Stages {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		if stage.From != nil {
+			sb.WriteString(Render(stage.From))
+			sb.WriteString("\n")
+		}
+		comments := stage.Comments
+		for _, inst := range stage.Instructions {
+			comments = writeCommentsBefore(&sb, comments, inst.Pos())
+			writeComments(&sb, inst.LeadingComments())
+			sb.WriteString(Render(inst))
+			sb.WriteString("\n")
+		}
+		writeCommentsBefore(&sb, comments, lexer.Position{})
+	}
+
+	return sb.String()
+}
+
+// writeComments writes comments one per line
+func writeComments(sb *strings.Builder, comments []*Comment) {
+	for _, c := range comments {
+		sb.WriteString(c.Text)
+		sb.WriteString("\n")
+	}
+}
+
+// writeCommentsBefore writes the comments on lines before pos and returns
+// the rest. The zero position writes them all.
+func writeCommentsBefore(sb *strings.Builder, comments []*Comment, pos lexer.Position) []*Comment {
+	for len(comments) > 0 && (pos.Line == 0 || comments[0].Pos().Line < pos.Line) {
+		sb.WriteString(comments[0].Text)
+		sb.WriteString("\n")
+		comments = comments[1:]
+	}
+	return comments
+}
+
+// Render returns the canonical text of a single instruction, without a
+// trailing newline
+func Render(inst Instruction) string {
+	var sb strings.Builder
+
+	switch v := inst.(type) {
+	case *FromInstruction:
+		sb.WriteString("FROM ")
+		writeFlag(&sb, "platform", v.Platform)
+		sb.WriteString(v.ImageRef())
+		if v.AsName != "" {
+			sb.WriteString(" AS ")
+			sb.WriteString(v.AsName)
+		}
+	case *RunInstruction:
+		sb.WriteString("RUN ")
+		for _, mount := range v.Mounts {
+			writeFlag(&sb, "mount", mount)
+		}
+		writeFlag(&sb, "network", v.Network)
+		writeFlag(&sb, "security", v.Security)
+		switch {
+		case v.Heredoc != nil:
+			sb.WriteString(v.Heredoc.Marker())
+			if v.Command != "" {
+				sb.WriteString(" ")
+				sb.WriteString(v.Command)
+			}
+			sb.WriteString("\n")
+			sb.WriteString(v.Heredoc.Content)
+			sb.WriteString(v.Heredoc.Delimiter)
+		case v.IsExec:
+			writeExecForm(&sb, v.Arguments)
+		default:
+			sb.WriteString(v.Command)
+		}
+	case *CmdInstruction:
+		sb.WriteString("CMD ")
+		writeCommand(&sb, v.IsExec, v.Arguments, v.Command)
+	case *EntrypointInstruction:
+		sb.WriteString("ENTRYPOINT ")
+		writeCommand(&sb, v.IsExec, v.Arguments, v.Command)
+	case *CopyInstruction:
+		sb.WriteString("COPY ")
+		writeFlag(&sb, "from", v.From)
+		writeFlag(&sb, "chown", v.Chown)
+		writeFlag(&sb, "chmod", v.Chmod)
+		if v.Link {
+			sb.WriteString("--link ")
+		}
+		writePaths(&sb, v.Sources, v.Destination)
+	case *AddInstruction:
+		sb.WriteString("ADD ")
+		writeFlag(&sb, "chown", v.Chown)
+		writeFlag(&sb, "chmod", v.Chmod)
+		writeFlag(&sb, "checksum", v.Checksum)
+		writePaths(&sb, v.Sources, v.Destination)
+	case *EnvInstruction:
+		sb.WriteString("ENV ")
+		if v.Legacy && len(v.Variables) == 1 {
+			// The legacy value runs to the end of the line
+			sb.WriteString(v.Variables[0].Key)
+			sb.WriteString(" ")
+			sb.WriteString(v.Variables[0].Value)
+			break
+		}
+		writeKeyValues(&sb, v.Variables)
+	case *ArgInstruction:
+		sb.WriteString("ARG ")
+		sb.WriteString(v.Name)
+		if v.HasDefault {
+			sb.WriteString("=")
+			sb.WriteString(QuoteValue(v.DefaultValue, v.DefaultQuote))
+		}
+	case *LabelInstruction:
+		sb.WriteString("LABEL ")
+		writeKeyValues(&sb, v.Labels)
+	case *ExposeInstruction:
+		sb.WriteString("EXPOSE ")
+		for i, port := range v.Ports {
+			if i > 0 {
+				sb.WriteString(" ")
+			}
+			sb.WriteString(port.String())
+		}
+	case *VolumeInstruction:
+		sb.WriteString("VOLUME ")
+		if strings.ContainsAny(strings.Join(v.Paths, ""), " \t") {
+			writeExecForm(&sb, v.Paths)
+		} else {
+			sb.WriteString(strings.Join(v.Paths, " "))
+		}
+	case *UserInstruction:
+		sb.WriteString("USER ")
+		sb.WriteString(v.User)
+		if v.Group != "" {
+			sb.WriteString(":")
+			sb.WriteString(v.Group)
+		}
+	case *WorkdirInstruction:
+		sb.WriteString("WORKDIR ")
+		sb.WriteString(v.Path)
+	case *ShellInstruction:
+		sb.WriteString("SHELL ")
+		writeExecForm(&sb, v.Shell)
+	case *HealthcheckInstruction:
+		sb.WriteString("HEALTHCHECK ")
+		if v.None {
+			sb.WriteString("NONE")
+			break
+		}
+		writeFlag(&sb, "interval", v.Interval)
+		writeFlag(&sb, "timeout", v.Timeout)
+		writeFlag(&sb, "start-period", v.StartPeriod)
+		writeFlag(&sb, "retries", v.Retries)
+		sb.WriteString("CMD ")
+		writeCommand(&sb, v.IsExec, v.Arguments, v.Command)
+	case *StopsignalInstruction:
+		sb.WriteString("STOPSIGNAL ")
+		sb.WriteString(v.Signal)
+	case *OnbuildInstruction:
+		sb.WriteString("ONBUILD")
+		if v.Instruction != nil {
+			sb.WriteString(" ")
+			sb.WriteString(Render(v.Instruction))
+		}
+	case *MaintainerInstruction:
+		sb.WriteString("MAINTAINER ")
+		sb.WriteString(v.Maintainer)
+	}
+
+	return strings.TrimRight(sb.String(), " ")
+}
+
+// writeFlag writes --name=value followed by a space, if value is set
+func writeFlag(sb *strings.Builder, name, value string) {
+	if value == "" {
+		return
+	}
+	sb.WriteString("--")
+	sb.WriteString(name)
+	sb.WriteString("=")
+	sb.WriteString(value)
+	sb.WriteString(" ")
+}
+
+func writeCommand(sb *strings.Builder, isExec bool, args []string, command string) {
+	if isExec {
+		writeExecForm(sb, args)
+	} else {
+		sb.WriteString(command)
+	}
+}
+
+// writeExecForm writes a JSON array. Arguments are kept as parsed, with
+// any escape sequences left in place.
+func writeExecForm(sb *strings.Builder, args []string) {
+	sb.WriteString("[")
+	for i, arg := range args {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("\"")
+		sb.WriteString(arg)
+		sb.WriteString("\"")
+	}
+	sb.WriteString("]")
+}
+
+func writePaths(sb *strings.Builder, sources []string, dest string) {
+	for _, src := range sources {
+		sb.WriteString(src)
+		sb.WriteString(" ")
+	}
+	sb.WriteString(dest)
+}
+
+func writeKeyValues(sb *strings.Builder, pairs []KeyValue) {
+	for i, kv := range pairs {
+		if i > 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(kv.Key)
+		sb.WriteString("=")
+		sb.WriteString(QuoteValue(kv.Value, kv.Quote))
+	}
+}
+
+// QuoteValue quotes an ENV, LABEL, or ARG value in the style it was
+// written in. Other values are quoted only if they contain whitespace,
+// quotes, escapes, or a # that would start a comment; variable
+// references such as $HOME are left bare.
+func QuoteValue(s string, style QuoteStyle) string {
+	switch {
+	case style == SingleQuoted && !strings.Contains(s, "'"):
+		return "'" + s + "'"
+	case style == DoubleQuoted:
+		return "\"" + s + "\""
+	case !strings.ContainsAny(s, " \t\n\"'\\#"):
+		return s
+	case strings.Contains(s, "\"") && !strings.ContainsAny(s, "'$"):
+		return "'" + s + "'"
+	}
+	return "\"" + s + "\""
+}
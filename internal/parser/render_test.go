@@ -0,0 +1,206 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/HueCodes/keel/internal/lexer"
+)
+
+// clearPositions zeroes every source position in the AST, so that ASTs
+// parsed from differently laid out sources can be compared
+func clearPositions(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			clearPositions(v.Elem())
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			clearPositions(v.Index(i))
+		}
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(lexer.Position{}) {
+			v.Set(reflect.Zero(v.Type()))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				clearPositions(v.Field(i))
+			}
+		}
+	}
+}
+
+func assertRoundTrip(t *testing.T, source string) {
+	t.Helper()
+
+	df, errs := Parse(source)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors parsing source: %v", errs)
+	}
+
+	rendered := df.String()
+	again, errs := Parse(rendered)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors parsing rendered output: %v\n%s", errs, rendered)
+	}
+
+	if again.String() != rendered {
+		t.Errorf("rendering is not stable:\nfirst:\n%s\nsecond:\n%s", rendered, again.String())
+	}
+
+	clearPositions(reflect.ValueOf(df))
+	clearPositions(reflect.ValueOf(again))
+	if !reflect.DeepEqual(df, again) {
+		t.Errorf("AST changed after rendering:\n%s", rendered)
+	}
+}
+
+func TestDockerfileString_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{
+			name:   "simple",
+			source: "FROM alpine:3.19\nRUN apk add --no-cache curl\nCMD [\"curl\", \"--version\"]\n",
+		},
+		{
+			name: "multi-stage",
+			source: `ARG GO_VERSION=1.22
+FROM --platform=$BUILDPLATFORM golang:${GO_VERSION} AS build
+WORKDIR /src
+COPY --chown=1000:1000 --chmod=644 go.mod go.sum ./
+RUN --mount=type=cache,target=/root/.cache/go-build --network=none go build -o /out/app
+
+FROM gcr.io/distroless/static@sha256:abc123
+COPY --from=build --link /out/app /app
+USER 1000:1000
+ENTRYPOINT ["/app"]
+`,
+		},
+		{
+			name: "metadata",
+			source: `FROM alpine:3.19
+ENV PATH=/usr/local/bin:$PATH APP_NAME="my app"
+ENV LEGACY some value with spaces
+LABEL org.opencontainers.image.title="Example image" version=1.0
+EXPOSE 80 443/tcp 53/udp
+VOLUME ["/data", "/var/log/my app"]
+ARG EMPTY=""
+ARG PATTERN='*.$EXT'
+LABEL description='costs $5 a month'
+STOPSIGNAL SIGTERM
+MAINTAINER Jane Doe <jane@example.com>
+`,
+		},
+		{
+			name: "healthcheck and shell",
+			source: `FROM alpine:3.19
+SHELL ["/bin/ash", "-eo", "pipefail", "-c"]
+HEALTHCHECK --interval=30s --timeout=5s --start-period=10s --retries=3 CMD wget -qO- http://localhost/ || exit 1
+ONBUILD COPY . /app
+`,
+		},
+		{
+			name:   "healthcheck none",
+			source: "FROM alpine:3.19\nHEALTHCHECK NONE\n",
+		},
+		{
+			name:   "heredoc",
+			source: "FROM alpine:3.19\nRUN <<EOF\nset -e\napk add curl\nEOF\nRUN <<-'SCRIPT' python3\n\tprint('hi')\n\tSCRIPT\nCMD [\"sh\"]\n",
+		},
+		{
+			name:   "comments",
+			source: "# syntax=docker/dockerfile:1\nARG BASE=alpine\n# base image\nFROM ${BASE}\n# install tools\nRUN apk add git\n\n# trailing comment\n",
+		},
+		{
+			name:   "escape directive",
+			source: "# escape=`\nFROM mcr.microsoft.com/windows/servercore:ltsc2022\nRUN dir\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertRoundTrip(t, tt.source)
+		})
+	}
+}
+
+func TestDockerfileString_Testdata(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "..", "testdata", "*", "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Skip("no testdata found")
+	}
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertRoundTrip(t, string(data))
+		})
+	}
+}
+
+func TestDockerfileString_Canonical(t *testing.T) {
+	df, errs := Parse("from   alpine:3.19   as   base\ncopy --link --from=x  a b   /dst/\n")
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	want := "FROM alpine:3.19 AS base\nCOPY --from=x --link a b /dst/\n"
+	if got := df.String(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestDockerfileString_Built(t *testing.T) {
+	df := &Dockerfile{
+		Stages: []*Stage{
+			{
+				From: &FromInstruction{Image: "alpine", Tag: "3.19"},
+				Instructions: []Instruction{
+					&RunInstruction{Command: "apk add curl"},
+					&CmdInstruction{IsExec: true, Arguments: []string{"curl", "--help"}},
+				},
+			},
+		},
+	}
+
+	want := "FROM alpine:3.19\nRUN apk add curl\nCMD [\"curl\", \"--help\"]\n"
+	if got := df.String(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestQuoteValue(t *testing.T) {
+	tests := []struct {
+		value    string
+		style    QuoteStyle
+		expected string
+	}{
+		{"app", Unquoted, "app"},
+		{"$HOME/data", Unquoted, "$HOME/data"},
+		{"a=b", Unquoted, "a=b"},
+		{"", Unquoted, ""},
+		{"hello world", Unquoted, `"hello world"`},
+		{`say "hi"`, Unquoted, `'say "hi"'`},
+		{"$USER@$HOST", SingleQuoted, "'$USER@$HOST'"},
+		{"it's", SingleQuoted, `"it's"`},
+		{"$HOME", DoubleQuoted, `"$HOME"`},
+	}
+
+	for _, tt := range tests {
+		if got := QuoteValue(tt.value, tt.style); got != tt.expected {
+			t.Errorf("QuoteValue(%q, %v) = %s, want %s", tt.value, tt.style, got, tt.expected)
+		}
+	}
+}
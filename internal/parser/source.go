@@ -0,0 +1,44 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// MaxInputSize is the largest Dockerfile ReadSource and ReadFile accept.
+// The lexer, parser, and rules all work on the whole source in memory, so
+// oversized input (typically a generated file gone wrong) is rejected up
+// front instead of being tokenized.
+const MaxInputSize = 32 << 20
+
+// ErrInputTooLarge is returned for input larger than MaxInputSize
+var ErrInputTooLarge = fmt.Errorf("input exceeds the maximum Dockerfile size of %d MiB", MaxInputSize>>20)
+
+// ReadSource reads a Dockerfile from r. It stops reading and returns
+// ErrInputTooLarge once more than MaxInputSize bytes have been read.
+func ReadSource(r io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxInputSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > MaxInputSize {
+		return "", ErrInputTooLarge
+	}
+	return string(data), nil
+}
+
+// ReadFile reads the Dockerfile at path, subject to MaxInputSize
+func ReadFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	// Regular files can be rejected without reading them
+	if info, err := f.Stat(); err == nil && info.Mode().IsRegular() && info.Size() > MaxInputSize {
+		return "", ErrInputTooLarge
+	}
+	return ReadSource(f)
+}
@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReadSource(t *testing.T) {
+	src, err := ReadSource(strings.NewReader("FROM alpine\n"))
+	if err != nil || src != "FROM alpine\n" {
+		t.Errorf("expected source to be read, got %q, %v", src, err)
+	}
+}
+
+// endlessReader produces an unbounded stream of comment lines
+type endlessReader struct{}
+
+func (endlessReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = "#\n"[i%2]
+	}
+	return len(p), nil
+}
+
+func TestReadSource_TooLarge(t *testing.T) {
+	_, err := ReadSource(endlessReader{})
+	if !errors.Is(err, ErrInputTooLarge) {
+		t.Errorf("expected ErrInputTooLarge, got %v", err)
+	}
+
+	// Exactly at the limit is accepted
+	src, err := ReadSource(io.LimitReader(endlessReader{}, MaxInputSize))
+	if err != nil || len(src) != MaxInputSize {
+		t.Errorf("expected %d bytes, got %d, %v", MaxInputSize, len(src), err)
+	}
+}
+
+func TestParse_LargeGeneratedInput(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("FROM alpine\n")
+	const n = 20000
+	for i := 0; i < n; i++ {
+		sb.WriteString("RUN echo step && touch /tmp/marker\n")
+	}
+
+	df, errs := Parse(sb.String())
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs[0])
+	}
+	if got := len(df.Stages[0].Instructions); got != n {
+		t.Errorf("expected %d instructions, got %d", n, got)
+	}
+}
@@ -0,0 +1,75 @@
+package reporter
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+// GroupBy selects how reporters that support grouping order diagnostics
+type GroupBy string
+
+const (
+	// GroupByPosition keeps diagnostics in source order, without headers
+	GroupByPosition GroupBy = "position"
+
+	// GroupByRule groups diagnostics by rule ID
+	GroupByRule GroupBy = "rule"
+
+	// GroupBySeverity groups diagnostics by severity, most severe first
+	GroupBySeverity GroupBy = "severity"
+)
+
+// ParseGroupBy parses a --group-by value
+func ParseGroupBy(s string) (GroupBy, error) {
+	switch g := GroupBy(s); g {
+	case GroupByPosition, GroupByRule, GroupBySeverity:
+		return g, nil
+	}
+	return "", fmt.Errorf("unknown grouping %q (expected position, rule, or severity)", s)
+}
+
+// Group is a run of diagnostics that share a rule or severity
+type Group struct {
+	// Key is the rule ID or severity the group shares, or empty when
+	// diagnostics aren't grouped
+	Key         string
+	Diagnostics []analyzer.Diagnostic
+}
+
+// GroupDiagnostics splits diags into groups, keeping source order within
+// each group. Rule groups are sorted by ID and severity groups from
+// error to hint. Position grouping returns a single group with no key.
+func GroupDiagnostics(diags []analyzer.Diagnostic, by GroupBy) []Group {
+	var key func(analyzer.Diagnostic) string
+	var less func(a, b analyzer.Diagnostic) bool
+	switch by {
+	case GroupByRule:
+		key = func(d analyzer.Diagnostic) string { return d.Rule }
+		less = func(a, b analyzer.Diagnostic) bool { return a.Rule < b.Rule }
+	case GroupBySeverity:
+		key = func(d analyzer.Diagnostic) string { return d.Severity.String() }
+		less = func(a, b analyzer.Diagnostic) bool { return a.Severity > b.Severity }
+	default:
+		if len(diags) == 0 {
+			return nil
+		}
+		return []Group{{Diagnostics: diags}}
+	}
+
+	sorted := make([]analyzer.Diagnostic, len(diags))
+	copy(sorted, diags)
+	sort.SliceStable(sorted, func(i, j int) bool { return less(sorted[i], sorted[j]) })
+
+	var groups []Group
+	for _, d := range sorted {
+		k := key(d)
+		if n := len(groups); n > 0 && groups[n-1].Key == k {
+			groups[n-1].Diagnostics = append(groups[n-1].Diagnostics, d)
+			continue
+		}
+		groups = append(groups, Group{Key: k, Diagnostics: []analyzer.Diagnostic{d}})
+	}
+	return groups
+}
@@ -0,0 +1,66 @@
+package reporter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/lexer"
+)
+
+func groupTestDiagnostics() []analyzer.Diagnostic {
+	return []analyzer.Diagnostic{
+		{Rule: "SEC002", Severity: analyzer.SeverityWarning, Pos: lexer.Position{Line: 2}},
+		{Rule: "BP005", Severity: analyzer.SeverityHint, Pos: lexer.Position{Line: 3}},
+		{Rule: "SEC002", Severity: analyzer.SeverityWarning, Pos: lexer.Position{Line: 5}},
+		{Rule: "SEC001", Severity: analyzer.SeverityError, Pos: lexer.Position{Line: 7}},
+	}
+}
+
+// summarize reduces groups to their keys and the lines of their diagnostics
+func summarize(groups []Group) map[string][]int {
+	got := make(map[string][]int)
+	for _, g := range groups {
+		for _, d := range g.Diagnostics {
+			got[g.Key] = append(got[g.Key], d.Pos.Line)
+		}
+	}
+	return got
+}
+
+func TestGroupDiagnostics(t *testing.T) {
+	tests := []struct {
+		by       GroupBy
+		keys     []string
+		expected map[string][]int
+	}{
+		{GroupByPosition, []string{""}, map[string][]int{"": {2, 3, 5, 7}}},
+		{GroupByRule, []string{"BP005", "SEC001", "SEC002"}, map[string][]int{"BP005": {3}, "SEC001": {7}, "SEC002": {2, 5}}},
+		{GroupBySeverity, []string{"error", "warning", "hint"}, map[string][]int{"error": {7}, "warning": {2, 5}, "hint": {3}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.by), func(t *testing.T) {
+			groups := GroupDiagnostics(groupTestDiagnostics(), tt.by)
+			var keys []string
+			for _, g := range groups {
+				keys = append(keys, g.Key)
+			}
+			if !reflect.DeepEqual(keys, tt.keys) {
+				t.Errorf("expected groups %v, got %v", tt.keys, keys)
+			}
+			if got := summarize(groups); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseGroupBy(t *testing.T) {
+	if g, err := ParseGroupBy("rule"); err != nil || g != GroupByRule {
+		t.Errorf("expected rule grouping, got %q, %v", g, err)
+	}
+	if _, err := ParseGroupBy("file"); err == nil {
+		t.Error("expected an error for an unknown grouping")
+	}
+}
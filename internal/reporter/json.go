@@ -45,6 +45,13 @@ type JSONSummary struct {
 
 // Report outputs the analysis results as JSON
 func (r *JSONReporter) Report(result *analyzer.Result, source string) error {
+	encoder := json.NewEncoder(r.cfg.Writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(NewJSONOutput(result))
+}
+
+// NewJSONOutput converts a result to the JSON output structure
+func NewJSONOutput(result *analyzer.Result) JSONOutput {
 	output := JSONOutput{
 		Filename:    result.Filename,
 		Diagnostics: make([]JSONDiagnostic, 0, len(result.Diagnostics)),
@@ -77,7 +84,5 @@ func (r *JSONReporter) Report(result *analyzer.Result, source string) error {
 		output.Diagnostics = append(output.Diagnostics, jd)
 	}
 
-	encoder := json.NewEncoder(r.cfg.Writer)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(output)
+	return output
 }
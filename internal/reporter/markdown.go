@@ -16,6 +16,9 @@ func (r *MarkdownReporter) Report(result *analyzer.Result, source string) error
 	w := r.cfg.Writer
 
 	if len(result.Diagnostics) == 0 {
+		if r.cfg.Quiet {
+			return nil
+		}
 		fmt.Fprintf(w, "## ✅ No issues found\n\nDockerfile `%s` passed all checks.\n", result.Filename)
 		return nil
 	}
@@ -41,19 +44,28 @@ func (r *MarkdownReporter) Report(result *analyzer.Result, source string) error
 	fmt.Fprintln(w)
 
 	// Details
-	fmt.Fprintf(w, "### Issues\n\n")
+	for _, group := range GroupDiagnostics(result.Diagnostics, r.cfg.GroupBy) {
+		switch {
+		case group.Key == "":
+			fmt.Fprintf(w, "### Issues\n\n")
+		case r.cfg.GroupBy == GroupBySeverity:
+			fmt.Fprintf(w, "### %s %s (%d)\n\n", severityEmoji(group.Diagnostics[0].Severity), group.Key, len(group.Diagnostics))
+		default:
+			fmt.Fprintf(w, "### `%s` (%d)\n\n", group.Key, len(group.Diagnostics))
+		}
 
-	for _, diag := range result.Diagnostics {
-		emoji := severityEmoji(diag.Severity)
-		fmt.Fprintf(w, "#### %s `%s` - Line %d\n\n", emoji, diag.Rule, diag.Pos.Line)
-		fmt.Fprintf(w, "%s\n\n", diag.Message)
+		for _, diag := range group.Diagnostics {
+			emoji := severityEmoji(diag.Severity)
+			fmt.Fprintf(w, "#### %s `%s` - Line %d\n\n", emoji, diag.Rule, diag.Pos.Line)
+			fmt.Fprintf(w, "%s\n\n", diag.Message)
 
-		if diag.Context != "" {
-			fmt.Fprintf(w, "```dockerfile\n%s\n```\n\n", diag.Context)
-		}
+			if diag.Context != "" {
+				fmt.Fprintf(w, "```dockerfile\n%s\n```\n\n", diag.Context)
+			}
 
-		if diag.Help != "" {
-			fmt.Fprintf(w, "> 💡 %s\n\n", diag.Help)
+			if diag.Help != "" {
+				fmt.Fprintf(w, "> 💡 %s\n\n", diag.Help)
+			}
 		}
 	}
 
@@ -0,0 +1,67 @@
+package reporter
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Factory creates a reporter that writes with the given configuration
+type Factory func(cfg *Config) Reporter
+
+// Formats added with Register, in registration order
+var (
+	registryMu sync.RWMutex
+	factories  = make(map[Format]Factory)
+	formats    []Format
+)
+
+func init() {
+	Register(FormatTerminal, func(cfg *Config) Reporter { return &TerminalReporter{cfg: cfg} })
+	Register(FormatJSON, func(cfg *Config) Reporter { return &JSONReporter{cfg: cfg} })
+	Register(FormatSARIF, func(cfg *Config) Reporter { return &SARIFReporter{cfg: cfg} })
+	Register(FormatMarkdown, func(cfg *Config) Reporter { return &MarkdownReporter{cfg: cfg} })
+	Register(FormatGitHub, func(cfg *Config) Reporter { return &GitHubReporter{cfg: cfg} })
+}
+
+// Register adds an output format that New can construct. Programs that
+// embed keel call it from an init function, before any reporter is
+// created:
+//
+//	func init() {
+//		reporter.Register("junit", func(cfg *reporter.Config) reporter.Reporter {
+//			return &JUnitReporter{w: cfg.Writer}
+//		})
+//	}
+//
+// Register panics if factory is nil or format is already registered.
+func Register(format Format, factory Factory) {
+	if factory == nil {
+		panic("reporter: Register called with a nil factory")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, dup := factories[format]; dup {
+		panic(fmt.Sprintf("reporter: format %s registered twice", format))
+	}
+	factories[format] = factory
+	formats = append(formats, format)
+}
+
+// Formats returns the registered output formats, built-in formats first
+func Formats() []Format {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return append([]Format(nil), formats...)
+}
+
+// lookup returns the factory for format, if it is registered
+func lookup(format Format) (Factory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	factory, ok := factories[format]
+	return factory, ok
+}
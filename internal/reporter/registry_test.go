@@ -0,0 +1,55 @@
+package reporter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+type countReporter struct {
+	w io.Writer
+}
+
+func (r *countReporter) Report(result *analyzer.Result, source string) error {
+	_, err := fmt.Fprintf(r.w, "%s: %d\n", result.Filename, len(result.Diagnostics))
+	return err
+}
+
+func TestRegister(t *testing.T) {
+	Register("count", func(cfg *Config) Reporter { return &countReporter{w: cfg.Writer} })
+
+	var buf bytes.Buffer
+	rep := New("count", &buf)
+	if _, ok := rep.(*countReporter); !ok {
+		t.Fatalf("expected the registered reporter, got %T", rep)
+	}
+	if err := rep.Report(testResult(), testSource); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != "Dockerfile: 1\n" {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+
+	formats := Formats()
+	if formats[0] != FormatTerminal || formats[len(formats)-1] != "count" {
+		t.Errorf("expected built-in formats first and count last, got %v", formats)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected registering a format twice to panic")
+		}
+	}()
+	Register("count", func(cfg *Config) Reporter { return &countReporter{w: cfg.Writer} })
+}
+
+func TestNew_UnknownFormat(t *testing.T) {
+	if rep := New("nope", io.Discard); rep == nil {
+		t.Fatal("expected a reporter")
+	} else if _, ok := rep.(*TerminalReporter); !ok {
+		t.Errorf("expected the terminal reporter for an unknown format, got %T", rep)
+	}
+}
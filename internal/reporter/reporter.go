@@ -11,6 +11,12 @@ type Reporter interface {
 	Report(result *analyzer.Result, source string) error
 }
 
+// Finisher is implemented by reporters that write output once all
+// results have been reported, such as a summary across files
+type Finisher interface {
+	Finish() error
+}
+
 // Format represents the output format
 type Format string
 
@@ -22,29 +28,25 @@ const (
 	FormatGitHub   Format = "github"
 )
 
-// New creates a reporter for the given format
+// New creates a reporter for the given format, falling back to the
+// terminal reporter for a format that isn't registered. Colors and line
+// wrapping default to on only when w is a terminal.
 func New(format Format, w io.Writer, opts ...Option) Reporter {
 	cfg := &Config{
 		Writer:    w,
-		UseColors: true,
+		UseColors: IsTerminal(w),
 		Verbose:   false,
+		Width:     TerminalWidth(w),
 	}
 	for _, opt := range opts {
 		opt(cfg)
 	}
 
-	switch format {
-	case FormatJSON:
-		return &JSONReporter{cfg: cfg}
-	case FormatSARIF:
-		return &SARIFReporter{cfg: cfg}
-	case FormatMarkdown:
-		return &MarkdownReporter{cfg: cfg}
-	case FormatGitHub:
-		return &GitHubReporter{cfg: cfg}
-	default:
-		return &TerminalReporter{cfg: cfg}
+	factory, ok := lookup(format)
+	if !ok {
+		factory, _ = lookup(FormatTerminal)
 	}
+	return factory(cfg)
 }
 
 // Config holds reporter configuration
@@ -52,6 +54,26 @@ type Config struct {
 	Writer    io.Writer
 	UseColors bool
 	Verbose   bool
+
+	// Width is the column at which terminal output is wrapped or
+	// truncated; 0 disables wrapping
+	Width int
+
+	// Quiet omits output for files without issues
+	Quiet bool
+
+	// Descriptions maps rule IDs to the description shown after each
+	// diagnostic's help; nil shows none
+	Descriptions map[string]string
+
+	// Summary collects results across files so that Finish can write a
+	// per-file summary table
+	Summary bool
+
+	// GroupBy orders diagnostics by position (the default), rule, or
+	// severity, with a header for each group, in terminal and Markdown
+	// output
+	GroupBy GroupBy
 }
 
 // Option is a function that configures a reporter
@@ -70,3 +92,40 @@ func WithVerbose(enabled bool) Option {
 		c.Verbose = enabled
 	}
 }
+
+// WithQuiet omits output for files without issues
+func WithQuiet(enabled bool) Option {
+	return func(c *Config) {
+		c.Quiet = enabled
+	}
+}
+
+// WithWidth sets the output width in columns, 0 for no limit
+func WithWidth(width int) Option {
+	return func(c *Config) {
+		c.Width = width
+	}
+}
+
+// WithDescriptions shows each rule's description after its diagnostics
+func WithDescriptions(descriptions map[string]string) Option {
+	return func(c *Config) {
+		c.Descriptions = descriptions
+	}
+}
+
+// WithSummary writes a per-file summary table when the reporter is
+// finished, for reporters that support one
+func WithSummary(enabled bool) Option {
+	return func(c *Config) {
+		c.Summary = enabled
+	}
+}
+
+// WithGroupBy groups diagnostics by rule or severity, for reporters that
+// support grouping
+func WithGroupBy(by GroupBy) Option {
+	return func(c *Config) {
+		c.GroupBy = by
+	}
+}
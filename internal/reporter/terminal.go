@@ -2,7 +2,9 @@ package reporter
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/HueCodes/keel/internal/analyzer"
 )
@@ -10,6 +12,15 @@ import (
 // TerminalReporter outputs results to the terminal with colors
 type TerminalReporter struct {
 	cfg *Config
+
+	// files holds the severity counts of each reported file, with WithSummary
+	files []fileCounts
+}
+
+// fileCounts are the diagnostics found in one file by severity
+type fileCounts struct {
+	name   string
+	counts map[analyzer.Severity]int
 }
 
 // ANSI color codes
@@ -50,45 +61,88 @@ func (r *TerminalReporter) Report(result *analyzer.Result, source string) error
 	w := r.cfg.Writer
 	lines := strings.Split(source, "\n")
 
-	for _, diag := range result.Diagnostics {
-		// Location and rule
-		loc := fmt.Sprintf("%s:%d:%d", result.Filename, diag.Pos.Line, diag.Pos.Column)
-		severity := r.color(r.severityColor(diag.Severity), diag.Severity.String())
-		rule := r.color(colorGray, "["+diag.Rule+"]")
-
-		fmt.Fprintf(w, "%s %s %s: %s\n", loc, rule, severity, diag.Message)
-
-		// Source context
-		if diag.Pos.Line > 0 && diag.Pos.Line <= len(lines) {
-			lineNum := diag.Pos.Line
-			line := lines[lineNum-1]
-
-			// Print line number gutter
-			gutter := fmt.Sprintf("%4d", lineNum)
-			fmt.Fprintf(w, "  %s │ %s\n", r.color(colorGray, gutter), line)
-
-			// Print underline
-			if diag.Pos.Column > 0 {
-				padding := strings.Repeat(" ", diag.Pos.Column-1)
-				underline := "^"
-				if diag.EndPos.Column > diag.Pos.Column {
-					underline = strings.Repeat("─", diag.EndPos.Column-diag.Pos.Column)
+	// The gutter grows with the line count so that line numbers stay aligned
+	gutterWidth := len(strconv.Itoa(len(lines)))
+	if gutterWidth < 4 {
+		gutterWidth = 4
+	}
+	margin := strings.Repeat(" ", gutterWidth+3)
+
+	for _, group := range GroupDiagnostics(result.Diagnostics, r.cfg.GroupBy) {
+		if group.Key != "" {
+			fmt.Fprintln(w, r.groupHeader(group))
+			fmt.Fprintln(w)
+		}
+
+		for _, diag := range group.Diagnostics {
+			// Location and rule
+			loc := fmt.Sprintf("%s:%d:%d", result.Filename, diag.Pos.Line, diag.Pos.Column)
+			severity := r.color(r.severityColor(diag.Severity), diag.Severity.String())
+			rule := r.color(colorGray, "["+diag.Rule+"]")
+
+			prefix := fmt.Sprintf("%s [%s] %s: ", loc, diag.Rule, diag.Severity)
+			message := r.wrap(diag.Message, utf8.RuneCountInString(prefix), len(margin))
+			fmt.Fprintf(w, "%s %s %s: %s\n", loc, rule, severity, strings.Join(message, "\n"+margin))
+
+			// Source context
+			if diag.Pos.Line > 0 && diag.Pos.Line <= len(lines) {
+				lineNum := diag.Pos.Line
+				line := r.truncate(lines[lineNum-1], len(margin)+2)
+
+				// Print line number gutter
+				gutter := fmt.Sprintf("%*d", gutterWidth, lineNum)
+				fmt.Fprintf(w, "  %s │ %s\n", r.color(colorGray, gutter), line)
+
+				// Print underline
+				if diag.Pos.Column > 0 {
+					padding := strings.Repeat(" ", diag.Pos.Column-1)
+					underline := "^"
+					endColumn := diag.EndPos.Column
+					if diag.EndPos.Line > diag.Pos.Line {
+						// Only the first line is shown, so underline to its end
+						endColumn = utf8.RuneCountInString(strings.TrimRight(lines[lineNum-1], " \t\r")) + 1
+					}
+					if endColumn > diag.Pos.Column {
+						underline = strings.Repeat("─", endColumn-diag.Pos.Column)
+					}
+					if avail := r.available(len(margin) + 2); avail > 0 && len(padding) < avail {
+						if len(padding)+utf8.RuneCountInString(underline) > avail {
+							underline = strings.Repeat("─", avail-len(padding))
+						}
+					}
+					fmt.Fprintf(w, "%s│ %s%s\n", margin, padding, r.color(r.severityColor(diag.Severity), underline))
 				}
-				fmt.Fprintf(w, "       │ %s%s\n", padding, r.color(r.severityColor(diag.Severity), underline))
 			}
-		}
 
-		// Help message
-		if diag.Help != "" {
-			fmt.Fprintf(w, "       │\n")
-			fmt.Fprintf(w, "       = %s: %s\n", r.color(colorCyan, "help"), diag.Help)
-		}
+			// Help message
+			if diag.Help != "" {
+				const label = "= help: "
+				help := r.wrap(diag.Help, len(margin)+len(label), len(margin)+len(label))
+				fmt.Fprintf(w, "%s│\n", margin)
+				fmt.Fprintf(w, "%s= %s: %s\n", margin, r.color(colorCyan, "help"),
+					strings.Join(help, "\n"+margin+strings.Repeat(" ", len(label))))
+			}
 
-		fmt.Fprintln(w)
+			// Rule description, with --explain
+			if desc := r.cfg.Descriptions[diag.Rule]; desc != "" {
+				const label = "= explain: "
+				text := r.wrap(desc, len(margin)+len(label), len(margin)+len(label))
+				if diag.Help == "" {
+					fmt.Fprintf(w, "%s│\n", margin)
+				}
+				fmt.Fprintf(w, "%s= %s: %s\n", margin, r.color(colorCyan, "explain"),
+					strings.Join(text, "\n"+margin+strings.Repeat(" ", len(label))))
+			}
+
+			fmt.Fprintln(w)
+		}
 	}
 
 	// Summary
 	counts := result.CountBySeverity()
+	if r.cfg.Summary {
+		r.files = append(r.files, fileCounts{name: result.Filename, counts: counts})
+	}
 	var parts []string
 	if c := counts[analyzer.SeverityError]; c > 0 {
 		parts = append(parts, r.color(colorRed, fmt.Sprintf("%d error(s)", c)))
@@ -105,9 +159,137 @@ func (r *TerminalReporter) Report(result *analyzer.Result, source string) error
 
 	if len(parts) > 0 {
 		fmt.Fprintf(w, "Found %s in %s\n", strings.Join(parts, ", "), result.Filename)
-	} else {
+	} else if !r.cfg.Quiet {
 		fmt.Fprintf(w, "%s No issues found in %s\n", r.color(colorGray, "✓"), result.Filename)
 	}
 
 	return nil
 }
+
+// groupHeader is the line written above a group of diagnostics, such as
+// "SEC002: 3 issue(s)", colored by severity when grouping by severity
+func (r *TerminalReporter) groupHeader(g Group) string {
+	header := fmt.Sprintf("%s: %d issue(s)", g.Key, len(g.Diagnostics))
+	if r.cfg.GroupBy == GroupBySeverity {
+		return r.color(colorBold+r.severityColor(g.Diagnostics[0].Severity), header)
+	}
+	return r.color(colorBold, header)
+}
+
+// summaryColumns are the severities in the summary table, in order
+var summaryColumns = []struct {
+	title    string
+	severity analyzer.Severity
+}{
+	{"Errors", analyzer.SeverityError},
+	{"Warnings", analyzer.SeverityWarning},
+	{"Info", analyzer.SeverityInfo},
+	{"Hints", analyzer.SeverityHint},
+}
+
+// Finish writes a table of the issues in each file and their totals,
+// when WithSummary is on and more than one file was reported. Quiet
+// output has no table.
+func (r *TerminalReporter) Finish() error {
+	if !r.cfg.Summary || r.cfg.Quiet || len(r.files) < 2 {
+		return nil
+	}
+	w := r.cfg.Writer
+
+	nameWidth := utf8.RuneCountInString("Total")
+	for _, f := range r.files {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(f.name))
+	}
+
+	// Pad before coloring so that escape codes don't upset the alignment
+	row := func(name string, counts map[analyzer.Severity]int, bold bool) {
+		cell := fmt.Sprintf("%-*s", nameWidth, name)
+		if bold {
+			cell = r.color(colorBold, cell)
+		}
+		var sb strings.Builder
+		sb.WriteString(cell)
+		for _, col := range summaryColumns {
+			n := counts[col.severity]
+			cell := fmt.Sprintf("%*d", len(col.title), n)
+			if n > 0 {
+				cell = r.color(r.severityColor(col.severity), cell)
+			}
+			sb.WriteString("  " + cell)
+		}
+		fmt.Fprintln(w, sb.String())
+	}
+
+	header := fmt.Sprintf("%-*s", nameWidth, "File")
+	for _, col := range summaryColumns {
+		header += "  " + col.title
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, r.color(colorBold, header))
+
+	total := make(map[analyzer.Severity]int)
+	for _, f := range r.files {
+		row(f.name, f.counts, false)
+		for s, n := range f.counts {
+			total[s] += n
+		}
+	}
+	row("Total", total, true)
+
+	return nil
+}
+
+// Narrowest column budget used for wrapping, so that text in a very
+// narrow terminal still gets a few words per line
+const minWrapWidth = 20
+
+// available returns the columns left after indent, or 0 when output
+// width is unlimited
+func (r *TerminalReporter) available(indent int) int {
+	if r.cfg.Width <= 0 {
+		return 0
+	}
+	if avail := r.cfg.Width - indent; avail > minWrapWidth {
+		return avail
+	}
+	return minWrapWidth
+}
+
+// wrap splits text into lines at word boundaries. The first line starts
+// at column first, and continuation lines at column rest.
+func (r *TerminalReporter) wrap(text string, first, rest int) []string {
+	avail := r.available(first)
+	if avail == 0 || utf8.RuneCountInString(text) <= avail {
+		return []string{text}
+	}
+
+	var lines []string
+	var cur strings.Builder
+	curLen := 0
+	for _, word := range strings.Fields(text) {
+		n := utf8.RuneCountInString(word)
+		if curLen > 0 && curLen+1+n > avail {
+			lines = append(lines, cur.String())
+			cur.Reset()
+			curLen = 0
+			avail = r.available(rest)
+		}
+		if curLen > 0 {
+			cur.WriteByte(' ')
+			curLen++
+		}
+		cur.WriteString(word)
+		curLen += n
+	}
+	return append(lines, cur.String())
+}
+
+// truncate shortens a source line to fit after indent, marking the cut with …
+func (r *TerminalReporter) truncate(line string, indent int) string {
+	avail := r.available(indent)
+	if avail == 0 || utf8.RuneCountInString(line) <= avail {
+		return line
+	}
+	runes := []rune(line)
+	return string(runes[:avail-1]) + "…"
+}
@@ -0,0 +1,260 @@
+package reporter
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/lexer"
+)
+
+func testResult() *analyzer.Result {
+	return &analyzer.Result{
+		Filename: "Dockerfile",
+		Diagnostics: []analyzer.Diagnostic{
+			{
+				Rule:     "SEC001",
+				Severity: analyzer.SeverityError,
+				Message:  "Container runs as root; add a USER instruction so that the process does not run with root privileges",
+				Pos:      lexer.Position{Line: 2, Column: 1},
+				EndPos:   lexer.Position{Line: 2, Column: 4},
+				Help:     "Create an unprivileged user with useradd or adduser and switch to it with USER before the CMD instruction",
+			},
+		},
+	}
+}
+
+const testSource = "FROM alpine:3.19\nRUN apk add --no-cache curl ca-certificates git openssh-client bash coreutils findutils\n"
+
+func TestTerminalReporter_NonTTYHasNoANSI(t *testing.T) {
+	var buf bytes.Buffer
+	if err := New(FormatTerminal, &buf).Report(testResult(), testSource); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(buf.String(), "\033[") {
+		t.Errorf("expected no ANSI codes when writing to a buffer, got %q", buf.String())
+	}
+
+	// A pipe is a file but not a terminal
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pr.Close()
+	if IsTerminal(pw) {
+		t.Error("expected a pipe not to be a terminal")
+	}
+	if err := New(FormatTerminal, pw).Report(testResult(), testSource); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pw.Close()
+
+	var out bytes.Buffer
+	if _, err := out.ReadFrom(pr); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "\033[") {
+		t.Errorf("expected no ANSI codes when writing to a pipe, got %q", out.String())
+	}
+}
+
+func TestTerminalReporter_ForcedColors(t *testing.T) {
+	var buf bytes.Buffer
+	if err := New(FormatTerminal, &buf, WithColors(true)).Report(testResult(), testSource); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), colorRed) {
+		t.Errorf("expected colors when forced on, got %q", buf.String())
+	}
+}
+
+func TestTerminalReporter_Width(t *testing.T) {
+	var buf bytes.Buffer
+	if err := New(FormatTerminal, &buf, WithWidth(60)).Report(testResult(), testSource); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	for _, line := range strings.Split(out, "\n") {
+		if n := len([]rune(line)); n > 60 {
+			t.Errorf("line exceeds width 60 (%d): %q", n, line)
+		}
+	}
+	if !strings.Contains(out, "…") {
+		t.Errorf("expected the long source line to be truncated, got:\n%s", out)
+	}
+	// Wrapping keeps every word of the message
+	if got := strings.Join(strings.Fields(out), " "); !strings.Contains(got, "so that the process does not run with root privileges") {
+		t.Errorf("expected the wrapped message to keep its words, got:\n%s", out)
+	}
+}
+
+func TestTerminalReporter_NoWidth(t *testing.T) {
+	var buf bytes.Buffer
+	if err := New(FormatTerminal, &buf).Report(testResult(), testSource); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), testResult().Diagnostics[0].Message) {
+		t.Errorf("expected the message unwrapped when writing to a buffer, got:\n%s", buf.String())
+	}
+}
+
+func TestTerminalReporter_GutterGrows(t *testing.T) {
+	source := strings.Repeat("# comment\n", 12000) + "FROM alpine\n"
+	result := &analyzer.Result{
+		Filename: "Dockerfile",
+		Diagnostics: []analyzer.Diagnostic{
+			{Rule: "SEC003", Severity: analyzer.SeverityWarning, Message: "unpinned", Pos: lexer.Position{Line: 12001, Column: 6}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := New(FormatTerminal, &buf).Report(result, source); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	if lines[1] != "  12001 │ FROM alpine" {
+		t.Errorf("unexpected source line %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "        │      ^") {
+		t.Errorf("expected the underline aligned with the wider gutter, got %q", lines[2])
+	}
+}
+
+func TestTerminalReporter_MultiLineUnderline(t *testing.T) {
+	source := "FROM alpine:3.19\nRUN apk update\nRUN apk add --no-cache curl ca-certificates\n"
+	result := &analyzer.Result{
+		Filename: "Dockerfile",
+		Diagnostics: []analyzer.Diagnostic{
+			{
+				Rule:     "PERF004",
+				Severity: analyzer.SeverityWarning,
+				Message:  "2 consecutive RUN instructions could be merged",
+				Pos:      lexer.Position{Line: 2, Column: 1},
+				EndPos:   lexer.Position{Line: 3, Column: 44},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := New(FormatTerminal, &buf).Report(result, source); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	if want := "       │ " + strings.Repeat("─", len("RUN apk update")); lines[2] != want {
+		t.Errorf("expected the underline to stop at the end of the first line, got %q", lines[2])
+	}
+}
+
+func TestTerminalReporter_Descriptions(t *testing.T) {
+	var buf bytes.Buffer
+	rep := New(FormatTerminal, &buf, WithColors(false), WithWidth(0),
+		WithDescriptions(map[string]string{"SEC001": "Running containers as root is a security risk."}))
+	if err := rep.Report(testResult(), testSource); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(buf.String(), "\n")
+	for i, l := range lines {
+		if strings.Contains(l, "= help: ") {
+			if want := "= explain: Running containers as root is a security risk."; strings.TrimSpace(lines[i+1]) != want {
+				t.Errorf("expected the description after the help line, got %q", lines[i+1])
+			}
+			return
+		}
+	}
+	t.Errorf("expected a help line, got:\n%s", buf.String())
+}
+
+func TestTerminalReporter_SummaryTable(t *testing.T) {
+	var buf bytes.Buffer
+	rep := New(FormatTerminal, &buf, WithSummary(true))
+
+	second := &analyzer.Result{
+		Filename: "services/api/Dockerfile",
+		Diagnostics: []analyzer.Diagnostic{
+			{Rule: "PERF003", Severity: analyzer.SeverityWarning, Message: "m", Pos: lexer.Position{Line: 1, Column: 1}},
+			{Rule: "PERF004", Severity: analyzer.SeverityWarning, Message: "m", Pos: lexer.Position{Line: 1, Column: 1}},
+			{Rule: "STY001", Severity: analyzer.SeverityHint, Message: "m", Pos: lexer.Position{Line: 1, Column: 1}},
+		},
+	}
+	for _, result := range []*analyzer.Result{testResult(), second} {
+		if err := rep.Report(result, testSource); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	buf.Reset()
+	if err := rep.(Finisher).Finish(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "\n" +
+		"File                     Errors  Warnings  Info  Hints\n" +
+		"Dockerfile                    1         0     0      0\n" +
+		"services/api/Dockerfile       0         2     0      1\n" +
+		"Total                         1         2     0      1\n"
+	if buf.String() != expected {
+		t.Errorf("expected table:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestTerminalReporter_SummaryTableSingleFile(t *testing.T) {
+	var buf bytes.Buffer
+	rep := New(FormatTerminal, &buf, WithSummary(true))
+	if err := rep.Report(testResult(), testSource); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buf.Reset()
+	if err := rep.(Finisher).Finish(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no table for a single file, got:\n%s", buf.String())
+	}
+}
+
+func TestTerminalReporter_GroupByRule(t *testing.T) {
+	var buf bytes.Buffer
+	rep := New(FormatTerminal, &buf, WithColors(false), WithGroupBy(GroupByRule))
+	result := &analyzer.Result{Filename: "Dockerfile", Diagnostics: groupTestDiagnostics()}
+	if err := rep.Report(result, testSource); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var headers []string
+	for _, l := range strings.Split(buf.String(), "\n") {
+		if strings.HasSuffix(l, "issue(s)") {
+			headers = append(headers, l)
+		}
+	}
+	expected := []string{"BP005: 1 issue(s)", "SEC001: 1 issue(s)", "SEC002: 2 issue(s)"}
+	if strings.Join(headers, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected headers %q, got %q in:\n%s", expected, headers, buf.String())
+	}
+
+	// Diagnostics follow their header, in source order
+	out := buf.String()
+	if i, j := strings.Index(out, "Dockerfile:2:"), strings.Index(out, "Dockerfile:5:"); i < strings.Index(out, "SEC002: 2") || j < i {
+		t.Errorf("expected SEC002's diagnostics under its header in order, got:\n%s", out)
+	}
+}
+
+func TestMarkdownReporter_GroupByRule(t *testing.T) {
+	var buf bytes.Buffer
+	rep := New(FormatMarkdown, &buf, WithGroupBy(GroupByRule))
+	result := &analyzer.Result{Filename: "Dockerfile", Diagnostics: groupTestDiagnostics()}
+	if err := rep.Report(result, testSource); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, header := range []string{"### `BP005` (1)\n", "### `SEC001` (1)\n", "### `SEC002` (2)\n"} {
+		if strings.Count(buf.String(), header) != 1 {
+			t.Errorf("expected one %q header, got:\n%s", header, buf.String())
+		}
+	}
+	if strings.Contains(buf.String(), "### Issues") {
+		t.Errorf("expected group headers in place of the Issues header, got:\n%s", buf.String())
+	}
+}
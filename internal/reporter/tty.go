@@ -0,0 +1,33 @@
+package reporter
+
+import (
+	"io"
+	"os"
+	"strconv"
+)
+
+// IsTerminal reports whether w writes to a terminal
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// TerminalWidth returns the width in columns of the terminal w writes to,
+// or 0 when w is not a terminal or its width is unknown. $COLUMNS takes
+// precedence over the size reported by the terminal.
+func TerminalWidth(w io.Writer) int {
+	if !IsTerminal(w) {
+		return 0
+	}
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return terminalSize(w.(*os.File))
+}
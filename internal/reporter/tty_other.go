@@ -0,0 +1,10 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package reporter
+
+import "os"
+
+// terminalSize is unsupported on this platform; callers fall back to $COLUMNS
+func terminalSize(f *os.File) int {
+	return 0
+}
@@ -16,7 +16,7 @@ func (r *BP001MissingLabels) Category() analyzer.Category { return analyzer.Cate
 func (r *BP001MissingLabels) Severity() analyzer.Severity { return analyzer.SeverityInfo }
 
 func (r *BP001MissingLabels) Description() string {
-	return "Images should have maintainer, version, and description labels for documentation."
+	return "Images should have maintainer, version, and description labels for documentation, preferably under the OCI org.opencontainers.image namespace."
 }
 
 var recommendedLabels = []string{
@@ -74,7 +74,7 @@ func (r *BP001MissingLabels) Check(df *parser.Dockerfile, ctx *analyzer.RuleCont
 			WithSeverity(r.Severity()).
 			WithMessagef("Missing recommended labels: %s", strings.Join(missing, ", ")).
 			WithPos(finalStage.From.Pos()).
-			WithHelp("Add LABEL instructions, e.g., LABEL maintainer=\"you@example.com\" version=\"1.0\" description=\"My app\"").
+			WithHelp("Add OCI labels, e.g., LABEL org.opencontainers.image.authors=\"you@example.com\" org.opencontainers.image.version=\"1.0\" org.opencontainers.image.description=\"My app\"").
 			Build()
 		diags = append(diags, diag)
 	}
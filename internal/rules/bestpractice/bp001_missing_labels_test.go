@@ -0,0 +1,57 @@
+package bestpractice
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBP001MissingLabels(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "no labels",
+			source:   "FROM alpine:3.19\n",
+			expected: 1,
+		},
+		{
+			name:     "OCI labels",
+			source:   "FROM alpine:3.19\nLABEL org.opencontainers.image.authors=me org.opencontainers.image.version=1.0 org.opencontainers.image.description=app\n",
+			expected: 0,
+		},
+		{
+			name:     "bare labels",
+			source:   "FROM alpine:3.19\nLABEL maintainer=me version=1.0 description=app\n",
+			expected: 0,
+		},
+		{
+			name:     "labels only in an earlier stage",
+			source:   "FROM golang:1.22 AS build\nLABEL maintainer=me version=1.0 description=app\n\nFROM alpine:3.19\n",
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP001MissingLabels{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
+
+func TestBP001MissingLabels_HelpUsesNamespacedKeys(t *testing.T) {
+	// Following the help must not trip STY007, which wants namespaced keys
+	diags := runRule(t, &BP001MissingLabels{}, "FROM alpine:3.19\n")
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d: %v", len(diags), diags)
+	}
+	for _, key := range []string{"authors", "version", "description"} {
+		if !strings.Contains(diags[0].Help, "org.opencontainers.image."+key+"=") {
+			t.Errorf("expected help to suggest org.opencontainers.image.%s, got %q", key, diags[0].Help)
+		}
+	}
+}
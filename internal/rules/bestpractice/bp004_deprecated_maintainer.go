@@ -11,7 +11,7 @@ type BP004DeprecatedMaintainer struct{}
 func (r *BP004DeprecatedMaintainer) ID() string          { return "BP004" }
 func (r *BP004DeprecatedMaintainer) Name() string        { return "deprecated-maintainer" }
 func (r *BP004DeprecatedMaintainer) Category() analyzer.Category { return analyzer.CategoryBestPractice }
-func (r *BP004DeprecatedMaintainer) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+func (r *BP004DeprecatedMaintainer) Severity() analyzer.Severity { return analyzer.SeverityInfo }
 
 func (r *BP004DeprecatedMaintainer) Description() string {
 	return "MAINTAINER is deprecated. Use LABEL maintainer=\"...\" instead."
@@ -30,7 +30,7 @@ func (r *BP004DeprecatedMaintainer) Check(df *parser.Dockerfile, ctx *analyzer.R
 			diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
 				WithSeverity(r.Severity()).
 				WithMessage("MAINTAINER instruction is deprecated").
-				WithPos(maint.Pos()).
+				WithRange(maint.Pos(), lineEnd(ctx, maint)).
 				WithContext(ctx.GetLine(maint.Pos().Line)).
 				WithHelp("Use LABEL instead: LABEL maintainer=\"" + maint.Maintainer + "\"").
 				WithFix("LABEL maintainer=\"" + maint.Maintainer + "\"").
@@ -0,0 +1,67 @@
+package bestpractice
+
+import (
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+func TestBP004DeprecatedMaintainer(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "maintainer present",
+			source:   "FROM alpine:3.19\nMAINTAINER John Doe\n",
+			expected: 1,
+		},
+		{
+			name:     "maintainer with email",
+			source:   "FROM alpine:3.19\nMAINTAINER John Doe <john@example.com>\n",
+			expected: 1,
+		},
+		{
+			name:     "maintainer in each stage",
+			source:   "FROM golang:1.22 AS build\nMAINTAINER John Doe\n\nFROM alpine:3.19\nMAINTAINER John Doe\n",
+			expected: 2,
+		},
+		{
+			name:     "maintainer absent",
+			source:   "FROM alpine:3.19\nLABEL maintainer=\"John Doe\"\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP004DeprecatedMaintainer{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+			for _, d := range diags {
+				if d.Severity != analyzer.SeverityInfo {
+					t.Errorf("expected info severity, got %v", d.Severity)
+				}
+			}
+		})
+	}
+}
+
+func TestBP004DeprecatedMaintainerFix(t *testing.T) {
+	source := "FROM alpine:3.19\nMAINTAINER John Doe <john@example.com>\n"
+	diags := runRule(t, &BP004DeprecatedMaintainer{}, source)
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(diags))
+	}
+
+	fixed, err := analyzer.ApplyFix(source, diags[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "FROM alpine:3.19\nLABEL maintainer=\"John Doe <john@example.com>\"\n"
+	if fixed != want {
+		t.Errorf("expected %q, got %q", want, fixed)
+	}
+}
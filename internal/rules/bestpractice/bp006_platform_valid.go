@@ -0,0 +1,195 @@
+package bestpractice
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// BP006PlatformValid checks that FROM --platform values are valid platforms
+type BP006PlatformValid struct{}
+
+func (r *BP006PlatformValid) ID() string          { return "BP006" }
+func (r *BP006PlatformValid) Name() string        { return "platform-valid" }
+func (r *BP006PlatformValid) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP006PlatformValid) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+
+func (r *BP006PlatformValid) Description() string {
+	return "FROM --platform should be a known os/arch[/variant] or a BuildKit platform argument."
+}
+
+// BuildKit automatic platform arguments
+var platformAutoArgs = []string{"BUILDPLATFORM", "TARGETPLATFORM"}
+
+var knownOS = []string{"linux", "windows", "darwin", "freebsd", "netbsd", "openbsd", "solaris", "illumos", "aix", "wasip1", "js"}
+
+var knownArch = []string{"amd64", "arm64", "arm", "386", "ppc64le", "ppc64", "s390x", "riscv64", "mips64le", "mips64", "mipsle", "mips", "loong64", "wasm"}
+
+// Valid variants per architecture
+var knownVariants = map[string][]string{
+	"amd64": {"v1", "v2", "v3", "v4"},
+	"arm64": {"v8", "v8.0", "v8.1", "v8.2", "v8.3", "v8.4", "v8.5", "v8.6", "v8.7", "v8.8", "v8.9", "v9", "v9.0", "v9.1", "v9.2", "v9.3", "v9.4", "v9.5"},
+	"arm":   {"v5", "v6", "v7", "v8"},
+}
+
+// Common architecture aliases
+var archAliases = map[string]string{
+	"x86_64":  "amd64",
+	"x86-64":  "amd64",
+	"aarch64": "arm64",
+	"armhf":   "arm",
+	"armel":   "arm",
+	"i386":    "386",
+	"x86":     "386",
+}
+
+func (r *BP006PlatformValid) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for _, stage := range df.Stages {
+		from := stage.From
+		if from == nil || from.Platform == "" {
+			continue
+		}
+
+		platform := from.Platform
+		var problem, suggestion string
+		if strings.Contains(platform, "$") {
+			problem, suggestion = checkPlatformArg(platform)
+		} else {
+			problem, suggestion = checkPlatform(platform)
+		}
+
+		if problem != "" {
+			builder := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithMessagef("Invalid --platform %q: %s", platform, problem).
+				WithPos(from.Pos()).
+				WithContext(ctx.GetLine(from.Pos().Line))
+			if suggestion != "" {
+				builder = builder.WithHelp("Did you mean --platform=" + suggestion + "?")
+			} else {
+				builder = builder.WithHelp("Use os/arch[/variant], e.g. linux/amd64 or linux/arm64/v8, or $BUILDPLATFORM/$TARGETPLATFORM")
+			}
+			diags = append(diags, builder.Build())
+		}
+	}
+
+	return diags
+}
+
+// checkPlatformArg flags likely misspellings of the BuildKit platform
+// arguments. Other variables are user-defined and can't be checked.
+func checkPlatformArg(platform string) (string, string) {
+	name := strings.TrimPrefix(platform, "$")
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "{"), "}")
+	if slices.Contains(platformAutoArgs, name) {
+		return "", ""
+	}
+	if guess := closestMatch(name, platformAutoArgs); guess != "" {
+		return "unknown build argument " + strconv.Quote(name), "$" + guess
+	}
+	return "", ""
+}
+
+// checkPlatform validates a platform string, returning a description of the
+// problem and a corrected platform if one can be guessed
+func checkPlatform(platform string) (string, string) {
+	if strings.Count(platform, "/") > 2 {
+		return "expected os/arch[/variant]", ""
+	}
+
+	spec := parser.ParsePlatform(strings.ToLower(platform))
+	fixed := spec
+	var problem string
+
+	if !slices.Contains(knownOS, spec.OS) {
+		problem = "unknown operating system " + strconv.Quote(spec.OS)
+		fixed.OS = closestMatch(spec.OS, knownOS)
+	}
+
+	if spec.Arch != "" && !slices.Contains(knownArch, spec.Arch) {
+		if problem == "" {
+			problem = "unknown architecture " + strconv.Quote(spec.Arch)
+		}
+		if alias, ok := archAliases[spec.Arch]; ok {
+			fixed.Arch = alias
+		} else {
+			fixed.Arch = closestMatch(spec.Arch, knownArch)
+		}
+	}
+
+	if spec.Variant != "" {
+		variants := knownVariants[fixed.Arch]
+		if !slices.Contains(variants, spec.Variant) {
+			if problem == "" {
+				problem = "unknown variant " + strconv.Quote(spec.Variant) + " for " + fixed.Arch
+			}
+			fixed.Variant = closestMatch(spec.Variant, variants)
+		}
+	}
+
+	if problem == "" {
+		return "", ""
+	}
+	if fixed.OS == "" || (spec.Arch != "" && fixed.Arch == "") || (spec.Variant != "" && fixed.Variant == "") {
+		return problem, ""
+	}
+
+	suggestion := fixed.OS
+	if fixed.Arch != "" {
+		suggestion += "/" + fixed.Arch
+	}
+	if fixed.Variant != "" {
+		suggestion += "/" + fixed.Variant
+	}
+	return problem, suggestion
+}
+
+// closestMatch returns the candidate that s is a prefix of, or else the
+// candidate within edit distance 2 of s, if any
+func closestMatch(s string, candidates []string) string {
+	if s != "" {
+		for _, c := range candidates {
+			if strings.HasPrefix(c, s) {
+				return c
+			}
+		}
+	}
+
+	best, bestDist := "", 3
+	for _, c := range candidates {
+		if d := editDistance(s, c); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// editDistance computes the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func init() {
+	Register(&BP006PlatformValid{})
+}
@@ -0,0 +1,55 @@
+package bestpractice
+
+import "testing"
+
+func TestBP006PlatformValid(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{"valid with variant", "FROM --platform=linux/arm64/v8 alpine:3.18\n", 0},
+		{"valid os/arch", "FROM --platform=linux/amd64 alpine:3.18\n", 0},
+		{"build platform auto-arg", "FROM --platform=$BUILDPLATFORM golang:1.22 AS build\n", 0},
+		{"target platform auto-arg braces", "FROM --platform=${TARGETPLATFORM} alpine:3.18\n", 0},
+		{"user variable", "FROM --platform=$MY_PLATFORM alpine:3.18\n", 0},
+		{"truncated arch", "FROM --platform=linux/amd alpine:3.18\n", 1},
+		{"transposed arch", "FROM --platform=linux/arm46 alpine:3.18\n", 1},
+		{"misspelled os", "FROM --platform=linx/amd64 alpine:3.18\n", 1},
+		{"invalid variant", "FROM --platform=linux/arm/v9 alpine:3.18\n", 1},
+		{"misspelled auto-arg", "FROM --platform=$BUILDPLATFROM alpine:3.18\n", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP006PlatformValid{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
+
+func TestBP006PlatformSuggestion(t *testing.T) {
+	tests := []struct {
+		platform string
+		expected string
+	}{
+		{"linux/amd", "linux/amd64"},
+		{"linux/arm46", "linux/arm64"},
+		{"linux/aarch64", "linux/arm64"},
+		{"linx/amd64", "linux/amd64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platform, func(t *testing.T) {
+			problem, suggestion := checkPlatform(tt.platform)
+			if problem == "" {
+				t.Fatal("expected a problem")
+			}
+			if suggestion != tt.expected {
+				t.Errorf("expected suggestion %q, got %q", tt.expected, suggestion)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package bestpractice
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/lexer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// BP007EntrypointShellForm checks for ENTRYPOINT in shell form
+type BP007EntrypointShellForm struct{}
+
+func (r *BP007EntrypointShellForm) ID() string          { return "BP007" }
+func (r *BP007EntrypointShellForm) Name() string        { return "entrypoint-shell-form" }
+func (r *BP007EntrypointShellForm) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP007EntrypointShellForm) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+
+func (r *BP007EntrypointShellForm) Description() string {
+	return "Shell-form ENTRYPOINT runs as a child of /bin/sh -c and does not receive signals such as SIGTERM."
+}
+
+// Init processes that forward signals to their child
+var initWrappers = []string{"tini", "dumb-init", "s6-svscan", "catatonit"}
+
+func (r *BP007EntrypointShellForm) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for _, stage := range df.Stages {
+		wrapped := false
+
+		for _, inst := range stage.Instructions {
+			switch v := inst.(type) {
+			case *parser.ShellInstruction:
+				// A SHELL wrapped in an init process forwards signals
+				wrapped = usesInitWrapper(strings.Join(v.Shell, " "))
+			case *parser.EntrypointInstruction:
+				if v.IsExec || wrapped {
+					continue
+				}
+
+				cmd := strings.TrimSpace(v.Command)
+				// exec replaces the shell, so the process receives signals directly
+				if strings.HasPrefix(cmd, "exec ") || usesInitWrapper(cmd) {
+					continue
+				}
+
+				builder := analyzer.NewDiagnostic(r.ID(), r.Category()).
+					WithSeverity(r.Severity()).
+					WithMessage("ENTRYPOINT uses shell form; the process will not receive SIGTERM").
+					WithRange(v.Pos(), lineEnd(ctx, v)).
+					WithContext(ctx.GetLine(v.Pos().Line)).
+					WithHelp("Use exec form, e.g. ENTRYPOINT [\"/app/server\"], or run the process under an init such as tini")
+				if fix := execForm("ENTRYPOINT", cmd); fix != "" {
+					builder = builder.WithFix(fix)
+				}
+				diags = append(diags, builder.Build())
+			}
+		}
+	}
+
+	return diags
+}
+
+// lineEnd returns the end of a single-line instruction's source line, so the
+// fix replaces the whole instruction. Continued lines keep the parser's end.
+func lineEnd(ctx *analyzer.RuleContext, inst parser.Instruction) lexer.Position {
+	line := strings.TrimRight(ctx.GetLine(inst.Pos().Line), "\r")
+	if strings.HasSuffix(strings.TrimRight(line, " \t"), "\\") {
+		return inst.End()
+	}
+	return lexer.Position{Line: inst.Pos().Line, Column: utf8.RuneCountInString(line) + 1}
+}
+
+// usesInitWrapper reports whether a command starts with an init process
+func usesInitWrapper(cmd string) bool {
+	fields := strings.Fields(cmd)
+	if len(fields) == 0 {
+		return false
+	}
+	name := fields[0]
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	for _, w := range initWrappers {
+		if name == w {
+			return true
+		}
+	}
+	return false
+}
+
+// execForm converts a simple shell-form command to an exec-form
+// instruction. Commands relying on shell features can't be converted
+// mechanically.
+func execForm(instruction, cmd string) string {
+	if cmd == "" || strings.ContainsAny(cmd, "$|&;<>*?`'\"\\(){}~") {
+		return ""
+	}
+
+	fields := strings.Fields(cmd)
+	quoted := make([]string, len(fields))
+	for i, f := range fields {
+		quoted[i] = strconv.Quote(f)
+	}
+	return instruction + " [" + strings.Join(quoted, ", ") + "]"
+}
+
+func init() {
+	Register(&BP007EntrypointShellForm{})
+}
@@ -0,0 +1,53 @@
+package bestpractice
+
+import (
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+func TestBP007EntrypointShellForm(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{"shell form", "FROM alpine:3.18\nENTRYPOINT /app/server --port 8080\n", 1},
+		{"exec form", "FROM alpine:3.18\nENTRYPOINT [\"/app/server\", \"--port\", \"8080\"]\n", 0},
+		{"shell form with exec", "FROM alpine:3.18\nENTRYPOINT exec /app/server\n", 0},
+		{"shell form under tini", "FROM alpine:3.18\nENTRYPOINT /sbin/tini -- /app/server\n", 0},
+		{"tini shell wrapper", "FROM alpine:3.18\nSHELL [\"/sbin/tini\", \"--\", \"/bin/sh\", \"-c\"]\nENTRYPOINT /app/server\n", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP007EntrypointShellForm{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
+
+func TestBP007EntrypointShellFormFix(t *testing.T) {
+	source := "FROM alpine:3.18\nENTRYPOINT /app/server --port 8080\n"
+	diags := runRule(t, &BP007EntrypointShellForm{}, source)
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(diags))
+	}
+
+	fixed, err := analyzer.ApplyFix(source, diags[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "FROM alpine:3.18\nENTRYPOINT [\"/app/server\", \"--port\", \"8080\"]\n"
+	if fixed != want {
+		t.Errorf("expected %q, got %q", want, fixed)
+	}
+
+	// Commands using shell features get no mechanical fix
+	diags = runRule(t, &BP007EntrypointShellForm{}, "FROM alpine:3.18\nENTRYPOINT /app/server --port $PORT\n")
+	if len(diags) != 1 || diags[0].Fixable {
+		t.Errorf("expected a non-fixable diagnostic, got %v", diags)
+	}
+}
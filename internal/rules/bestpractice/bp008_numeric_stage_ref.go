@@ -0,0 +1,74 @@
+package bestpractice
+
+import (
+	"strconv"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// BP008NumericStageRef checks for COPY --from referencing stages by index
+type BP008NumericStageRef struct{}
+
+func (r *BP008NumericStageRef) ID() string          { return "BP008" }
+func (r *BP008NumericStageRef) Name() string        { return "numeric-stage-ref" }
+func (r *BP008NumericStageRef) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP008NumericStageRef) Severity() analyzer.Severity { return analyzer.SeverityInfo }
+
+func (r *BP008NumericStageRef) Description() string {
+	return "COPY --from should reference named stages. Numeric indexes silently point at the wrong stage after stages are added or reordered."
+}
+
+func (r *BP008NumericStageRef) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for stageIdx, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			cp, ok := inst.(*parser.CopyInstruction)
+			if !ok || cp.From == "" {
+				continue
+			}
+
+			n, err := strconv.Atoi(cp.From)
+			if err != nil {
+				continue // a stage name or external image
+			}
+
+			builder := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithPos(cp.Pos()).
+				WithContext(ctx.GetLine(cp.Pos().Line))
+
+			switch {
+			case n < 0 || n >= len(df.Stages):
+				builder = builder.
+					WithSeverity(analyzer.SeverityError).
+					WithMessagef("COPY --from=%d refers to stage %d, but the Dockerfile has only %d stage(s)", n, n, len(df.Stages)).
+					WithHelp("Name the intended stage with FROM ... AS <name> and use --from=<name>")
+			case n >= stageIdx:
+				builder = builder.
+					WithSeverity(analyzer.SeverityError).
+					WithMessagef("COPY --from=%d refers to the current or a later stage", n).
+					WithHelp("COPY --from can only reference earlier stages")
+			case df.Stages[n].Name != "":
+				name := df.Stages[n].Name
+				builder = builder.
+					WithSeverity(r.Severity()).
+					WithMessagef("COPY --from=%d refers to a stage by index; it is named %q", n, name).
+					WithHelp("Use --from=" + name + " so the reference survives stage reordering")
+			default:
+				builder = builder.
+					WithSeverity(r.Severity()).
+					WithMessagef("COPY --from=%d refers to a stage by index", n).
+					WithHelp("Name the stage with FROM ... AS <name> and use --from=<name>")
+			}
+
+			diags = append(diags, builder.Build())
+		}
+	}
+
+	return diags
+}
+
+func init() {
+	Register(&BP008NumericStageRef{})
+}
@@ -0,0 +1,55 @@
+package bestpractice
+
+import (
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+func TestBP008NumericStageRef(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected []analyzer.Severity
+	}{
+		{
+			name:     "index with named stage",
+			source:   "FROM golang:1.22 AS build\nRUN go build\nFROM alpine:3.18\nCOPY --from=0 /app /app\n",
+			expected: []analyzer.Severity{analyzer.SeverityInfo},
+		},
+		{
+			name:     "index out of range",
+			source:   "FROM golang:1.22 AS build\nFROM alpine:3.18\nCOPY --from=99 /app /app\n",
+			expected: []analyzer.Severity{analyzer.SeverityError},
+		},
+		{
+			name:     "index of current stage",
+			source:   "FROM golang:1.22 AS build\nFROM alpine:3.18\nCOPY --from=1 /app /app\n",
+			expected: []analyzer.Severity{analyzer.SeverityError},
+		},
+		{
+			name:     "named reference",
+			source:   "FROM golang:1.22 AS build\nFROM alpine:3.18\nCOPY --from=build /app /app\n",
+			expected: nil,
+		},
+		{
+			name:     "external image",
+			source:   "FROM alpine:3.18\nCOPY --from=nginx:1.25 /etc/nginx /etc/nginx\n",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP008NumericStageRef{}, tt.source)
+			if len(diags) != len(tt.expected) {
+				t.Fatalf("expected %d diagnostics, got %d: %v", len(tt.expected), len(diags), diags)
+			}
+			for i, d := range diags {
+				if d.Severity != tt.expected[i] {
+					t.Errorf("expected severity %s, got %s", tt.expected[i], d.Severity)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package bestpractice
+
+import (
+	"fmt"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// BP009FromArgScope checks that variables used in FROM are global ARGs
+type BP009FromArgScope struct{}
+
+func (r *BP009FromArgScope) ID() string          { return "BP009" }
+func (r *BP009FromArgScope) Name() string        { return "from-arg-scope" }
+func (r *BP009FromArgScope) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP009FromArgScope) Severity() analyzer.Severity { return analyzer.SeverityError }
+
+func (r *BP009FromArgScope) Description() string {
+	return "FROM can only use ARGs declared before the first FROM; ARGs declared inside a stage expand to an empty string."
+}
+
+// Platform ARGs BuildKit predefines in the global scope
+var predefinedPlatformArgs = map[string]bool{
+	"BUILDPLATFORM": true, "BUILDOS": true, "BUILDARCH": true, "BUILDVARIANT": true,
+	"TARGETPLATFORM": true, "TARGETOS": true, "TARGETARCH": true, "TARGETVARIANT": true,
+}
+
+func (r *BP009FromArgScope) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	global := make(map[string]bool)
+	for _, arg := range df.Args {
+		global[arg.Name] = true
+	}
+
+	for _, stage := range df.Stages {
+		if stage.From == nil {
+			continue
+		}
+
+		for _, name := range fromVariables(stage.From) {
+			if global[name] || predefinedPlatformArgs[name] {
+				continue
+			}
+
+			builder := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithPos(stage.From.Pos()).
+				WithContext(ctx.GetLine(stage.From.Pos().Line)).
+				WithHelp(fmt.Sprintf("Declare ARG %s before the first FROM", name))
+			if stageDeclaresArg(stage, name) {
+				builder = builder.WithMessagef("ARG %q is declared inside the stage, so FROM cannot use it", name)
+			} else {
+				builder = builder.WithMessagef("FROM references %q, which is not declared as a global ARG", name)
+			}
+			diags = append(diags, builder.Build())
+		}
+	}
+
+	return diags
+}
+
+// fromVariables returns the names of the variables a FROM instruction
+// references, in order of first use
+func fromVariables(from *parser.FromInstruction) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, field := range []string{from.Platform, from.Image, from.Tag, from.Digest} {
+		for _, name := range shell.Vars(field) {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+func stageDeclaresArg(stage *parser.Stage, name string) bool {
+	for _, inst := range stage.Instructions {
+		if arg, ok := inst.(*parser.ArgInstruction); ok && arg.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	Register(&BP009FromArgScope{})
+}
@@ -0,0 +1,46 @@
+package bestpractice
+
+import "testing"
+
+func TestBP009FromArgScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "global arg",
+			source:   "ARG VERSION=20\nFROM node:${VERSION}-alpine\n",
+			expected: 0,
+		},
+		{
+			name:     "arg declared in stage",
+			source:   "FROM node:${VERSION}-alpine\nARG VERSION=20\n",
+			expected: 1,
+		},
+		{
+			name:     "undeclared variable",
+			source:   "FROM $BASE_IMAGE\n",
+			expected: 1,
+		},
+		{
+			name:     "predefined platform arg",
+			source:   "FROM --platform=$BUILDPLATFORM golang:1.22\n",
+			expected: 0,
+		},
+		{
+			name:     "no variables",
+			source:   "FROM alpine:3.18\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP009FromArgScope{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package bestpractice
+
+import (
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// BP010DebianFrontendEnv checks for DEBIAN_FRONTEND persisted with ENV in the final image
+type BP010DebianFrontendEnv struct{}
+
+func (r *BP010DebianFrontendEnv) ID() string          { return "BP010" }
+func (r *BP010DebianFrontendEnv) Name() string        { return "debian-frontend-env" }
+func (r *BP010DebianFrontendEnv) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP010DebianFrontendEnv) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+
+func (r *BP010DebianFrontendEnv) Description() string {
+	return "ENV DEBIAN_FRONTEND persists into the final image and changes how apt behaves for anyone using the container."
+}
+
+func (r *BP010DebianFrontendEnv) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	if len(df.Stages) == 0 {
+		return diags
+	}
+
+	// ENV is inherited by stages built FROM another stage, so walk from
+	// the final stage back through its parents
+	byName := make(map[string]*parser.Stage)
+	for _, stage := range df.Stages {
+		if stage.Name != "" {
+			byName[strings.ToLower(stage.Name)] = stage
+		}
+	}
+
+	seen := make(map[*parser.Stage]bool)
+	for stage := df.Stages[len(df.Stages)-1]; stage != nil && !seen[stage]; {
+		seen[stage] = true
+
+		for _, inst := range stage.Instructions {
+			env, ok := inst.(*parser.EnvInstruction)
+			if !ok {
+				continue
+			}
+			for _, kv := range env.Variables {
+				if kv.Key != "DEBIAN_FRONTEND" || kv.Value == "" {
+					continue
+				}
+				diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+					WithSeverity(r.Severity()).
+					WithMessagef("DEBIAN_FRONTEND=%s is set with ENV and persists in the final image", kv.Value).
+					WithPos(env.Pos()).
+					WithContext(ctx.GetLine(env.Pos().Line)).
+					WithHelp("Use ARG DEBIAN_FRONTEND=noninteractive, or set it inline: RUN DEBIAN_FRONTEND=noninteractive apt-get install ...").
+					Build()
+				diags = append(diags, diag)
+			}
+		}
+
+		if stage.From == nil {
+			break
+		}
+		stage = byName[strings.ToLower(stage.From.Image)]
+	}
+
+	return diags
+}
+
+func init() {
+	Register(&BP010DebianFrontendEnv{})
+}
@@ -0,0 +1,46 @@
+package bestpractice
+
+import "testing"
+
+func TestBP010DebianFrontendEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "persistent env",
+			source:   "FROM debian:12\nENV DEBIAN_FRONTEND=noninteractive\nRUN apt-get update\n",
+			expected: 1,
+		},
+		{
+			name:     "inline in run",
+			source:   "FROM debian:12\nRUN DEBIAN_FRONTEND=noninteractive apt-get update\n",
+			expected: 0,
+		},
+		{
+			name:     "arg",
+			source:   "FROM debian:12\nARG DEBIAN_FRONTEND=noninteractive\nRUN apt-get update\n",
+			expected: 0,
+		},
+		{
+			name:     "builder stage only",
+			source:   "FROM debian:12 AS build\nENV DEBIAN_FRONTEND=noninteractive\nFROM debian:12\nCOPY --from=build /app /app\n",
+			expected: 0,
+		},
+		{
+			name:     "inherited from parent stage",
+			source:   "FROM debian:12 AS base\nENV DEBIAN_FRONTEND=noninteractive\nFROM base\nRUN apt-get update\n",
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP010DebianFrontendEnv{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package bestpractice
+
+import (
+	"time"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// BP011HealthcheckTiming checks HEALTHCHECK durations for implausible values
+type BP011HealthcheckTiming struct{}
+
+func (r *BP011HealthcheckTiming) ID() string          { return "BP011" }
+func (r *BP011HealthcheckTiming) Name() string        { return "healthcheck-timing" }
+func (r *BP011HealthcheckTiming) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP011HealthcheckTiming) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+
+func (r *BP011HealthcheckTiming) Description() string {
+	return "HEALTHCHECK durations must be valid and positive, and the timeout should be shorter than the interval."
+}
+
+// Docker's defaults for unset HEALTHCHECK options
+const (
+	defaultHealthcheckInterval = 30 * time.Second
+	defaultHealthcheckTimeout  = 30 * time.Second
+)
+
+func (r *BP011HealthcheckTiming) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			hc, ok := inst.(*parser.HealthcheckInstruction)
+			if !ok || hc.None {
+				continue
+			}
+
+			report := func(format string, args ...interface{}) {
+				diags = append(diags, analyzer.NewDiagnostic(r.ID(), r.Category()).
+					WithSeverity(r.Severity()).
+					WithMessagef(format, args...).
+					WithPos(hc.Pos()).
+					WithContext(ctx.GetLine(hc.Pos().Line)).
+					WithHelp("Use durations such as 30s, 1m or 1m30s, with --timeout shorter than --interval").
+					Build())
+			}
+
+			// check returns an option's value and whether it is usable.
+			// Unparseable values are left to BP012.
+			check := func(flag, value string, def time.Duration) (time.Duration, bool) {
+				if value == "" {
+					return def, true
+				}
+				d, err := parser.ParseDuration(value)
+				if err != nil {
+					return 0, false
+				}
+				if d == 0 {
+					report("HEALTHCHECK --%s=%s must be greater than zero", flag, value)
+					return 0, false
+				}
+				return d, true
+			}
+
+			interval, intervalOK := check("interval", hc.Interval, defaultHealthcheckInterval)
+			timeout, timeoutOK := check("timeout", hc.Timeout, defaultHealthcheckTimeout)
+			check("start-period", hc.StartPeriod, 0)
+
+			explicit := hc.Interval != "" || hc.Timeout != ""
+			if explicit && intervalOK && timeoutOK && timeout >= interval {
+				report("HEALTHCHECK timeout (%s) is not shorter than the interval (%s)", timeout, interval)
+			}
+		}
+	}
+
+	return diags
+}
+
+func init() {
+	Register(&BP011HealthcheckTiming{})
+}
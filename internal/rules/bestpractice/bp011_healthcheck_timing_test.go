@@ -0,0 +1,56 @@
+package bestpractice
+
+import "testing"
+
+func TestBP011HealthcheckTiming(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "timeout longer than interval",
+			source:   "FROM alpine:3.18\nHEALTHCHECK --interval=30s --timeout=40s CMD wget -q localhost\n",
+			expected: 1,
+		},
+		{
+			name:     "plausible values",
+			source:   "FROM alpine:3.18\nHEALTHCHECK --interval=30s --timeout=3s CMD wget -q localhost\n",
+			expected: 0,
+		},
+		{
+			name:     "malformed interval left to BP012",
+			source:   "FROM alpine:3.18\nHEALTHCHECK --interval=abc CMD wget -q localhost\n",
+			expected: 0,
+		},
+		{
+			name:     "zero start period",
+			source:   "FROM alpine:3.18\nHEALTHCHECK --start-period=0s --timeout=5s CMD wget -q localhost\n",
+			expected: 1,
+		},
+		{
+			name:     "timeout against default interval",
+			source:   "FROM alpine:3.18\nHEALTHCHECK --timeout=1m CMD wget -q localhost\n",
+			expected: 1,
+		},
+		{
+			name:     "defaults only",
+			source:   "FROM alpine:3.18\nHEALTHCHECK CMD wget -q localhost\n",
+			expected: 0,
+		},
+		{
+			name:     "healthcheck none",
+			source:   "FROM alpine:3.18\nHEALTHCHECK NONE\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP011HealthcheckTiming{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
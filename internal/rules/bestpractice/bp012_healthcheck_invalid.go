@@ -0,0 +1,68 @@
+package bestpractice
+
+import (
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// BP012HealthcheckInvalid checks for HEALTHCHECK options Docker cannot parse
+type BP012HealthcheckInvalid struct{}
+
+func (r *BP012HealthcheckInvalid) ID() string          { return "BP012" }
+func (r *BP012HealthcheckInvalid) Name() string        { return "healthcheck-invalid" }
+func (r *BP012HealthcheckInvalid) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP012HealthcheckInvalid) Severity() analyzer.Severity { return analyzer.SeverityError }
+
+func (r *BP012HealthcheckInvalid) Description() string {
+	return "HEALTHCHECK durations must use Go duration syntax (e.g. 30s, 1m30s) and --retries must be a positive integer."
+}
+
+func (r *BP012HealthcheckInvalid) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			hc, ok := inst.(*parser.HealthcheckInstruction)
+			if !ok || hc.None {
+				continue
+			}
+
+			type invalidOption struct {
+				flag string
+				err  error
+			}
+			var invalid []invalidOption
+			if _, err := hc.IntervalDuration(); err != nil {
+				invalid = append(invalid, invalidOption{"interval", err})
+			}
+			if _, err := hc.TimeoutDuration(); err != nil {
+				invalid = append(invalid, invalidOption{"timeout", err})
+			}
+			if _, err := hc.StartPeriodDuration(); err != nil {
+				invalid = append(invalid, invalidOption{"start-period", err})
+			}
+			if hc.Retries != "" {
+				if _, err := parser.ParseRetries(hc.Retries); err != nil {
+					invalid = append(invalid, invalidOption{"retries", err})
+				}
+			}
+
+			for _, opt := range invalid {
+				diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+					WithSeverity(r.Severity()).
+					WithMessagef("HEALTHCHECK --%s: %s", opt.flag, opt.err).
+					WithPos(hc.Pos()).
+					WithContext(ctx.GetLine(hc.Pos().Line)).
+					WithHelp("Use durations such as 30s, 1m or 1m30s, and a whole number of retries such as --retries=3").
+					Build()
+				diags = append(diags, diag)
+			}
+		}
+	}
+
+	return diags
+}
+
+func init() {
+	Register(&BP012HealthcheckInvalid{})
+}
@@ -0,0 +1,51 @@
+package bestpractice
+
+import "testing"
+
+func TestBP012HealthcheckInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "valid options",
+			source:   "FROM alpine:3.18\nHEALTHCHECK --interval=1m30s --timeout=5s --start-period=10s --retries=3 CMD true\n",
+			expected: 0,
+		},
+		{
+			name:     "invalid interval",
+			source:   "FROM alpine:3.18\nHEALTHCHECK --interval=abc CMD true\n",
+			expected: 1,
+		},
+		{
+			name:     "missing unit",
+			source:   "FROM alpine:3.18\nHEALTHCHECK --timeout=30 CMD true\n",
+			expected: 1,
+		},
+		{
+			name:     "negative start period",
+			source:   "FROM alpine:3.18\nHEALTHCHECK --start-period=-5s CMD true\n",
+			expected: 1,
+		},
+		{
+			name:     "non-integer retries",
+			source:   "FROM alpine:3.18\nHEALTHCHECK --retries=three CMD true\n",
+			expected: 1,
+		},
+		{
+			name:     "zero retries",
+			source:   "FROM alpine:3.18\nHEALTHCHECK --retries=0 CMD true\n",
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP012HealthcheckInvalid{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package bestpractice
+
+import (
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// BP013UserNotCreated checks for USER naming a user that was never created
+type BP013UserNotCreated struct{}
+
+func (r *BP013UserNotCreated) ID() string          { return "BP013" }
+func (r *BP013UserNotCreated) Name() string        { return "user-not-created" }
+func (r *BP013UserNotCreated) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP013UserNotCreated) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+
+func (r *BP013UserNotCreated) Description() string {
+	return "USER refers to a user name that no RUN instruction creates; processes resolving the name will fail at runtime."
+}
+
+// Users that commonly exist in base images
+var wellKnownUsers = map[string]bool{
+	"root": true, "nobody": true, "daemon": true, "bin": true, "www-data": true,
+	"node": true, "nginx": true, "nonroot": true, "postgres": true, "mysql": true,
+	"redis": true, "mongodb": true, "rabbitmq": true, "elasticsearch": true,
+	"jenkins": true, "guest": true, "_apt": true,
+}
+
+// Commands that create users
+var userCreationCommands = map[string]bool{
+	"useradd": true, "adduser": true,
+}
+
+func (r *BP013UserNotCreated) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	byName := make(map[string]*parser.Stage)
+	for _, stage := range df.Stages {
+		if stage.Name != "" {
+			byName[strings.ToLower(stage.Name)] = stage
+		}
+	}
+
+	for _, stage := range df.Stages {
+		// Users created by stages this one is built FROM also exist
+		created, unknown := make(map[string]bool), false
+		seen := map[*parser.Stage]bool{stage: true}
+		for parent := parentStage(stage, byName); parent != nil && !seen[parent]; parent = parentStage(parent, byName) {
+			seen[parent] = true
+			unknown = collectUsers(parent.Instructions, created) || unknown
+		}
+
+		for _, inst := range stage.Instructions {
+			user, ok := inst.(*parser.UserInstruction)
+			if !ok {
+				unknown = collectUsers([]parser.Instruction{inst}, created) || unknown
+				continue
+			}
+			if unknown {
+				continue
+			}
+
+			name := user.User
+			if name == "" || isNumericID(name) || strings.Contains(name, "$") || wellKnownUsers[name] || created[name] {
+				continue
+			}
+
+			diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithMessagef("USER %q is never created", name).
+				WithPos(user.Pos()).
+				WithContext(ctx.GetLine(user.Pos().Line)).
+				WithHelp("Create the user first, e.g. RUN useradd -r " + name + ", or use a numeric UID").
+				Build()
+			diags = append(diags, diag)
+		}
+	}
+
+	return diags
+}
+
+// collectUsers adds the names of users created by insts to created. It
+// returns true when users are added in ways that can't be followed, such
+// as writing or copying /etc/passwd.
+func collectUsers(insts []parser.Instruction, created map[string]bool) bool {
+	unknown := false
+	for _, inst := range insts {
+		switch v := inst.(type) {
+		case *parser.RunInstruction:
+			cmd := v.Command
+			if v.IsExec {
+				cmd = strings.Join(v.Arguments, " ")
+			} else if v.Heredoc != nil {
+				cmd = v.Heredoc.Content
+			}
+			if strings.Contains(cmd, "/etc/passwd") {
+				unknown = true
+			}
+			for _, c := range shell.Split(cmd) {
+				args := c.Args()
+				if len(args) == 0 || !userCreationCommands[args[0].Value] {
+					continue
+				}
+				// Flag values are collected too; the extra names are harmless
+				for _, w := range args[1:] {
+					if !strings.HasPrefix(w.Value, "-") {
+						created[w.Value] = true
+					}
+				}
+			}
+		case *parser.CopyInstruction:
+			if v.Destination == "/etc/passwd" || v.Destination == "/etc/" || v.Destination == "/etc" {
+				unknown = true
+			}
+		}
+	}
+	return unknown
+}
+
+func parentStage(stage *parser.Stage, byName map[string]*parser.Stage) *parser.Stage {
+	if stage.From == nil {
+		return nil
+	}
+	return byName[strings.ToLower(stage.From.Image)]
+}
+
+func isNumericID(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func init() {
+	Register(&BP013UserNotCreated{})
+}
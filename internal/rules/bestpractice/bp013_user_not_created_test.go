@@ -0,0 +1,66 @@
+package bestpractice
+
+import "testing"
+
+func TestBP013UserNotCreated(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "user never created",
+			source:   "FROM alpine:3.18\nUSER appuser\n",
+			expected: 1,
+		},
+		{
+			name:     "created with adduser",
+			source:   "FROM alpine:3.18\nRUN addgroup -S app && adduser -S -G app appuser\nUSER appuser\n",
+			expected: 0,
+		},
+		{
+			name:     "created with useradd",
+			source:   "FROM debian:12\nRUN useradd -r -m -s /bin/false appuser\nUSER appuser:appuser\n",
+			expected: 0,
+		},
+		{
+			name:     "numeric uid",
+			source:   "FROM alpine:3.18\nUSER 1000\n",
+			expected: 0,
+		},
+		{
+			name:     "well-known user",
+			source:   "FROM node:20\nUSER node\n",
+			expected: 0,
+		},
+		{
+			name:     "created after user",
+			source:   "FROM alpine:3.18\nUSER appuser\nRUN adduser -D appuser\n",
+			expected: 1,
+		},
+		{
+			name:     "created in parent stage",
+			source:   "FROM alpine:3.18 AS base\nRUN adduser -D appuser\nFROM base\nUSER appuser\n",
+			expected: 0,
+		},
+		{
+			name:     "created in unrelated stage",
+			source:   "FROM alpine:3.18 AS build\nRUN adduser -D appuser\nFROM alpine:3.18\nUSER appuser\n",
+			expected: 1,
+		},
+		{
+			name:     "passwd copied from build stage",
+			source:   "FROM golang:1.22 AS build\nRUN useradd app\nFROM scratch\nCOPY --from=build /etc/passwd /etc/passwd\nUSER app\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP013UserNotCreated{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
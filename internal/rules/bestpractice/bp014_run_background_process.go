@@ -0,0 +1,91 @@
+package bestpractice
+
+import (
+	"path"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// BP014RunBackgroundProcess checks for RUN instructions that start long-running processes
+type BP014RunBackgroundProcess struct{}
+
+func (r *BP014RunBackgroundProcess) ID() string          { return "BP014" }
+func (r *BP014RunBackgroundProcess) Name() string        { return "run-background-process" }
+func (r *BP014RunBackgroundProcess) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP014RunBackgroundProcess) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+
+func (r *BP014RunBackgroundProcess) Description() string {
+	return "Processes started by RUN do not keep running in the container; RUN only changes the build-time filesystem."
+}
+
+func (r *BP014RunBackgroundProcess) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			run, ok := inst.(*parser.RunInstruction)
+			if !ok || run.IsExec {
+				continue
+			}
+
+			cmd := run.Command
+			if run.Heredoc != nil {
+				cmd = run.Heredoc.Content
+			}
+
+			// A process started and then used later in the same RUN, such
+			// as a database seeded during the build, is fine. Only flag
+			// one that is left running when the RUN ends.
+			cmds := shell.Split(cmd)
+			if len(cmds) == 0 {
+				continue
+			}
+			last := cmds[len(cmds)-1]
+
+			var what string
+			switch {
+			case last.Op == shell.OpBackground:
+				what = "runs " + last.Name() + " in the background"
+			case isServiceStart(last):
+				what = "starts a service"
+			default:
+				continue
+			}
+
+			diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithMessagef("RUN %s, but the process will not be running in the container", what).
+				WithPos(run.Pos()).
+				WithContext(ctx.GetLine(run.Pos().Line)).
+				WithHelp("Start long-running processes with CMD or ENTRYPOINT instead").
+				Build()
+			diags = append(diags, diag)
+		}
+	}
+
+	return diags
+}
+
+// isServiceStart reports whether a command starts a system service, e.g.
+// service nginx start, systemctl start nginx, or /etc/init.d/nginx start
+func isServiceStart(c shell.Command) bool {
+	args := c.Args()
+	if len(args) < 2 {
+		return false
+	}
+	name := path.Base(args[0].Value)
+	switch {
+	case name == "systemctl":
+		return args[1].Value == "start" || args[1].Value == "restart"
+	case name == "service" || name == "rc-service" || path.Dir(args[0].Value) == "/etc/init.d":
+		action := args[len(args)-1].Value
+		return action == "start" || action == "restart"
+	}
+	return false
+}
+
+func init() {
+	Register(&BP014RunBackgroundProcess{})
+}
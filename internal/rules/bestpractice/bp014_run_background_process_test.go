@@ -0,0 +1,61 @@
+package bestpractice
+
+import "testing"
+
+func TestBP014RunBackgroundProcess(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "trailing background",
+			source:   "FROM nginx:1.25\nRUN nginx &\n",
+			expected: 1,
+		},
+		{
+			name:     "plain command",
+			source:   "FROM alpine:3.18\nRUN make\n",
+			expected: 0,
+		},
+		{
+			name:     "service start",
+			source:   "FROM debian:12\nRUN apt-get install -y nginx && service nginx start\n",
+			expected: 1,
+		},
+		{
+			name:     "systemctl start",
+			source:   "FROM debian:12\nRUN systemctl start nginx\n",
+			expected: 1,
+		},
+		{
+			name:     "systemctl enable",
+			source:   "FROM debian:12\nRUN systemctl enable nginx\n",
+			expected: 0,
+		},
+		{
+			name:     "service used later in the same run",
+			source:   "FROM mysql:8\nRUN service mysql start && mysql < /init.sql\n",
+			expected: 0,
+		},
+		{
+			name:     "background process waited for",
+			source:   "FROM alpine:3.18\nRUN ./server & sleep 1 && ./smoke-test\n",
+			expected: 0,
+		},
+		{
+			name:     "ampersand in redirect",
+			source:   "FROM alpine:3.18\nRUN make >/dev/null 2>&1\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP014RunBackgroundProcess{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
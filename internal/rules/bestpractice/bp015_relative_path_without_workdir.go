@@ -0,0 +1,99 @@
+package bestpractice
+
+import (
+	"path"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// BP015RelativePathWithoutWorkdir checks for RUN invoking relative paths before any WORKDIR
+type BP015RelativePathWithoutWorkdir struct{}
+
+func (r *BP015RelativePathWithoutWorkdir) ID() string          { return "BP015" }
+func (r *BP015RelativePathWithoutWorkdir) Name() string        { return "relative-path-without-workdir" }
+func (r *BP015RelativePathWithoutWorkdir) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP015RelativePathWithoutWorkdir) Severity() analyzer.Severity { return analyzer.SeverityHint }
+
+func (r *BP015RelativePathWithoutWorkdir) Description() string {
+	return "RUN invokes a relative path before WORKDIR is set, so it depends on the base image's working directory."
+}
+
+// Interpreters whose first argument is a script path
+var scriptInterpreters = map[string]bool{
+	"sh": true, "bash": true, "ash": true, "zsh": true, "python": true, "python3": true,
+	"node": true, "ruby": true, "perl": true, "php": true, "source": true, ".": true,
+}
+
+func (r *BP015RelativePathWithoutWorkdir) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	workdirs := effectiveWorkdirs(df)
+
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			v, ok := inst.(*parser.RunInstruction)
+			if !ok || workdirs[v].Set || v.IsExec || v.Heredoc != nil {
+				continue
+			}
+			rel := relativeInvocation(v.Command)
+			if rel == "" {
+				continue
+			}
+			diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithMessagef("RUN invokes relative path %q before WORKDIR is set", rel).
+				WithPos(v.Pos()).
+				WithContext(ctx.GetLine(v.Pos().Line)).
+				WithHelp("Set WORKDIR before running scripts by relative path, or use an absolute path").
+				Build()
+			diags = append(diags, diag)
+		}
+	}
+
+	return diags
+}
+
+// relativeInvocation returns the first relative path a command runs, such
+// as ./build.sh or bash scripts/setup.sh, ignoring anything after a cd
+func relativeInvocation(cmd string) string {
+	for _, c := range shell.Split(cmd) {
+		args := c.Args()
+		if len(args) == 0 {
+			continue
+		}
+		name := args[0].Value
+		if name == "cd" || name == "pushd" {
+			return ""
+		}
+		if isRelativePath(name) {
+			return name
+		}
+		if scriptInterpreters[path.Base(name)] {
+			for _, a := range args[1:] {
+				if a.Value == "-c" || a.Value == "-m" || a.Value == "-e" {
+					// Inline code or a module, not a script path
+					break
+				}
+				if strings.HasPrefix(a.Value, "-") {
+					continue
+				}
+				if isRelativePath(a.Value) || (!strings.HasPrefix(a.Value, "/") && !strings.HasPrefix(a.Value, "$") && path.Ext(a.Value) != "") {
+					return a.Value
+				}
+				break
+			}
+		}
+	}
+	return ""
+}
+
+func isRelativePath(s string) bool {
+	return strings.HasPrefix(s, "./") || strings.HasPrefix(s, "../")
+}
+
+func init() {
+	Register(&BP015RelativePathWithoutWorkdir{})
+}
@@ -0,0 +1,61 @@
+package bestpractice
+
+import "testing"
+
+func TestBP015RelativePathWithoutWorkdir(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "relative script before workdir",
+			source:   "FROM alpine:3.18\nCOPY run.sh .\nRUN ./run.sh\n",
+			expected: 1,
+		},
+		{
+			name:     "relative script after workdir",
+			source:   "FROM alpine:3.18\nWORKDIR /app\nCOPY run.sh .\nRUN ./run.sh\n",
+			expected: 0,
+		},
+		{
+			name:     "interpreter with relative script",
+			source:   "FROM python:3.12\nRUN python setup.py install\n",
+			expected: 1,
+		},
+		{
+			name:     "inline interpreter code",
+			source:   "FROM python:3.12\nRUN python -c 'print(1)'\n",
+			expected: 0,
+		},
+		{
+			name:     "shell -c",
+			source:   "FROM alpine:3.18\nRUN sh -c 'echo install.sh'\n",
+			expected: 0,
+		},
+		{
+			name:     "cd first",
+			source:   "FROM alpine:3.18\nRUN cd /src && ./configure\n",
+			expected: 0,
+		},
+		{
+			name:     "absolute path",
+			source:   "FROM alpine:3.18\nRUN /usr/local/bin/setup.sh\n",
+			expected: 0,
+		},
+		{
+			name:     "workdir inherited from parent stage",
+			source:   "FROM alpine:3.18 AS base\nWORKDIR /app\nFROM base\nRUN ./run.sh\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP015RelativePathWithoutWorkdir{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package bestpractice
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// BP016AbsoluteCopySource checks for absolute source paths in COPY and ADD
+type BP016AbsoluteCopySource struct{}
+
+func (r *BP016AbsoluteCopySource) ID() string          { return "BP016" }
+func (r *BP016AbsoluteCopySource) Name() string        { return "absolute-copy-source" }
+func (r *BP016AbsoluteCopySource) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP016AbsoluteCopySource) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+
+func (r *BP016AbsoluteCopySource) Description() string {
+	return "COPY and ADD sources are relative to the build context; a leading slash does not refer to the host filesystem."
+}
+
+func (r *BP016AbsoluteCopySource) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			var sources []string
+			var name string
+
+			switch v := inst.(type) {
+			case *parser.CopyInstruction:
+				// Absolute paths are normal when copying from another stage or image
+				if v.From != "" {
+					continue
+				}
+				sources, name = v.Sources, "COPY"
+			case *parser.AddInstruction:
+				sources, name = v.Sources, "ADD"
+			default:
+				continue
+			}
+
+			for _, src := range sources {
+				if !strings.HasPrefix(src, "/") {
+					continue
+				}
+				diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+					WithSeverity(r.Severity()).
+					WithMessagef("%s source %q is an absolute path", name, src).
+					WithPos(inst.Pos()).
+					WithContext(ctx.GetLine(inst.Pos().Line)).
+					WithHelp(fmt.Sprintf("Sources are read from the build context; write %q instead", strings.TrimLeft(src, "/"))).
+					Build()
+				diags = append(diags, diag)
+			}
+		}
+	}
+
+	return diags
+}
+
+func init() {
+	Register(&BP016AbsoluteCopySource{})
+}
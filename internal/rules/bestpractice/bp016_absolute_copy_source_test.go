@@ -0,0 +1,41 @@
+package bestpractice
+
+import "testing"
+
+func TestBP016AbsoluteCopySource(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "absolute source",
+			source:   "FROM alpine:3.18\nCOPY /foo /bar\n",
+			expected: 1,
+		},
+		{
+			name:     "copy from stage",
+			source:   "FROM golang:1.22 AS build\nFROM alpine:3.18\nCOPY --from=build /foo /bar\n",
+			expected: 0,
+		},
+		{
+			name:     "relative source",
+			source:   "FROM alpine:3.18\nCOPY foo /bar\n",
+			expected: 0,
+		},
+		{
+			name:     "absolute add source",
+			source:   "FROM alpine:3.18\nADD /etc/app.conf /etc/app.conf\n",
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP016AbsoluteCopySource{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package bestpractice
+
+import (
+	"path"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// BP017MixedPackageManagers checks for more than one distro package manager in a stage
+type BP017MixedPackageManagers struct{}
+
+func (r *BP017MixedPackageManagers) ID() string          { return "BP017" }
+func (r *BP017MixedPackageManagers) Name() string        { return "mixed-package-managers" }
+func (r *BP017MixedPackageManagers) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP017MixedPackageManagers) Severity() analyzer.Severity { return analyzer.SeverityError }
+
+func (r *BP017MixedPackageManagers) Description() string {
+	return "A stage uses package managers from different distributions, which indicates a base image mismatch."
+}
+
+// Distro package managers, keyed by command, mapped to their family
+var distroPackageManagers = map[string]string{
+	"apt-get":  "apt",
+	"apt":      "apt",
+	"apk":      "apk",
+	"yum":      "dnf/yum",
+	"dnf":      "dnf/yum",
+	"microdnf": "dnf/yum",
+	"zypper":   "zypper",
+	"pacman":   "pacman",
+}
+
+func (r *BP017MixedPackageManagers) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for _, stage := range df.Stages {
+		first := ""
+
+		for _, inst := range stage.Instructions {
+			run, ok := inst.(*parser.RunInstruction)
+			if !ok {
+				continue
+			}
+
+			cmd := run.Command
+			if run.IsExec {
+				cmd = strings.Join(run.Arguments, " ")
+			} else if run.Heredoc != nil {
+				cmd = run.Heredoc.Content
+			}
+
+			other := ""
+			for _, c := range shell.Split(cmd) {
+				family, ok := distroPackageManagers[path.Base(c.Name())]
+				if !ok {
+					continue
+				}
+				if first == "" {
+					first = family
+				} else if family != first {
+					other = family
+					break
+				}
+			}
+			if other == "" {
+				continue
+			}
+
+			diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithMessagef("Stage uses both %s and %s package managers", first, other).
+				WithPos(run.Pos()).
+				WithContext(ctx.GetLine(run.Pos().Line)).
+				WithHelp("Use the package manager that matches the base image's distribution").
+				Build()
+			diags = append(diags, diag)
+			break
+		}
+	}
+
+	return diags
+}
+
+func init() {
+	Register(&BP017MixedPackageManagers{})
+}
@@ -0,0 +1,46 @@
+package bestpractice
+
+import "testing"
+
+func TestBP017MixedPackageManagers(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "apt-get and apk in one stage",
+			source:   "FROM alpine:3.18\nRUN apk add --no-cache curl\nRUN apt-get update && apt-get install -y git\n",
+			expected: 1,
+		},
+		{
+			name:     "only apk",
+			source:   "FROM alpine:3.18\nRUN apk add --no-cache curl\nRUN apk add --no-cache git\n",
+			expected: 0,
+		},
+		{
+			name:     "different stages",
+			source:   "FROM debian:12 AS build\nRUN apt-get update\nFROM alpine:3.18\nRUN apk add --no-cache curl\n",
+			expected: 0,
+		},
+		{
+			name:     "yum and dnf are the same family",
+			source:   "FROM fedora:40\nRUN dnf install -y git && yum clean all\n",
+			expected: 0,
+		},
+		{
+			name:     "mixed in one run",
+			source:   "FROM ubuntu:22.04\nRUN apt-get update && apk add curl\n",
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP017MixedPackageManagers{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
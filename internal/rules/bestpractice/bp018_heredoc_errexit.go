@@ -0,0 +1,121 @@
+package bestpractice
+
+import (
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// BP018HeredocErrexit checks for multi-command heredoc scripts that don't fail fast
+type BP018HeredocErrexit struct{}
+
+func (r *BP018HeredocErrexit) ID() string          { return "BP018" }
+func (r *BP018HeredocErrexit) Name() string        { return "heredoc-errexit" }
+func (r *BP018HeredocErrexit) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP018HeredocErrexit) Severity() analyzer.Severity { return analyzer.SeverityInfo }
+
+func (r *BP018HeredocErrexit) Description() string {
+	return "A heredoc RUN script keeps going after a failed command unless it uses set -e, so the build can succeed with a broken image."
+}
+
+func (r *BP018HeredocErrexit) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for _, stage := range df.Stages {
+		// Scripts without a shebang run with the SHELL
+		var defaultShell []string
+
+		for _, inst := range stage.Instructions {
+			if sh, ok := inst.(*parser.ShellInstruction); ok {
+				defaultShell = sh.Shell
+				continue
+			}
+
+			run, ok := inst.(*parser.RunInstruction)
+			if !ok || run.Heredoc == nil {
+				continue
+			}
+
+			// The interpreter is the command after the marker, if any,
+			// then the shebang, then the SHELL
+			body := run.Heredoc.Content
+			interp := defaultShell
+			if run.Command != "" {
+				interp = strings.Fields(run.Command)
+			} else if line, rest, _ := strings.Cut(body, "\n"); strings.HasPrefix(line, "#!") {
+				interp = strings.Fields(strings.TrimPrefix(line, "#!"))
+				if len(interp) > 1 && strings.HasSuffix(interp[0], "/env") {
+					interp = interp[1:]
+				}
+				body = rest
+			}
+			if !shell.IsPOSIX(interp) || hasErrexitFlag(interp) {
+				continue
+			}
+
+			// Count the lines of the script; a failure in "a && b" already
+			// fails the whole list
+			commands := 0
+			errexit := false
+			for _, c := range shell.Split(body) {
+				if c.Name() == "set" {
+					errexit = errexit || setsErrexit(c)
+					continue
+				}
+				if c.Op != shell.OpAnd && c.Op != shell.OpOr && c.Op != shell.OpPipe {
+					commands++
+				}
+			}
+			if errexit || commands < 2 {
+				continue
+			}
+
+			diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithMessage("Heredoc RUN script runs multiple commands without set -e").
+				WithPos(run.Pos()).
+				WithContext(ctx.GetLine(run.Pos().Line)).
+				WithHelp("Start the script with 'set -e' (or 'set -eux') so the build stops when a command fails").
+				Build()
+			diags = append(diags, diag)
+		}
+	}
+
+	return diags
+}
+
+// hasErrexitFlag reports whether an interpreter command line such as
+// "/bin/bash -ex" enables errexit
+func hasErrexitFlag(interp []string) bool {
+	for _, arg := range interp[min(1, len(interp)):] {
+		if isErrexitOption(arg) {
+			return true
+		}
+	}
+	return false
+}
+
+// setsErrexit reports whether a set command enables errexit, e.g. set -eux
+// or set -o errexit
+func setsErrexit(c shell.Command) bool {
+	args := c.Args()
+	for i, arg := range args {
+		if isErrexitOption(arg.Value) {
+			return true
+		}
+		if arg.Value == "-o" && i+1 < len(args) && args[i+1].Value == "errexit" {
+			return true
+		}
+	}
+	return false
+}
+
+func isErrexitOption(arg string) bool {
+	return strings.HasPrefix(arg, "-") && !strings.HasPrefix(arg, "--") && strings.Contains(arg[1:], "e")
+}
+
+func init() {
+	Register(&BP018HeredocErrexit{})
+}
@@ -0,0 +1,71 @@
+package bestpractice
+
+import "testing"
+
+func TestBP018HeredocErrexit(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "heredoc without set -e",
+			source:   "FROM debian:12\nRUN <<EOF\napt-get update\napt-get install -y curl\nEOF\n",
+			expected: 1,
+		},
+		{
+			name:     "bash shebang without set -e",
+			source:   "FROM debian:12\nRUN <<EOF\n#!/bin/bash\napt-get update\napt-get install -y curl\nEOF\n",
+			expected: 1,
+		},
+		{
+			name:     "with set -e",
+			source:   "FROM debian:12\nRUN <<EOF\nset -e\napt-get update\napt-get install -y curl\nEOF\n",
+			expected: 0,
+		},
+		{
+			name:     "with set -eux",
+			source:   "FROM debian:12\nRUN <<EOF\n#!/bin/sh\nset -eux\napt-get update\napt-get install -y curl\nEOF\n",
+			expected: 0,
+		},
+		{
+			name:     "with set -o errexit",
+			source:   "FROM debian:12\nRUN <<EOF\nset -o errexit\napt-get update\napt-get install -y curl\nEOF\n",
+			expected: 0,
+		},
+		{
+			name:     "errexit in shebang",
+			source:   "FROM debian:12\nRUN <<EOF\n#!/bin/bash -e\napt-get update\napt-get install -y curl\nEOF\n",
+			expected: 0,
+		},
+		{
+			name:     "single-line heredoc",
+			source:   "FROM debian:12\nRUN <<EOF\napt-get update\nEOF\n",
+			expected: 0,
+		},
+		{
+			name:     "commands joined with and",
+			source:   "FROM debian:12\nRUN <<EOF\napt-get update && apt-get install -y curl\nEOF\n",
+			expected: 0,
+		},
+		{
+			name:     "python shebang",
+			source:   "FROM python:3.12\nRUN <<EOF\n#!/usr/bin/env python3\nimport os\nprint(os.getcwd())\nEOF\n",
+			expected: 0,
+		},
+		{
+			name:     "python interpreter after marker",
+			source:   "FROM python:3.12\nRUN <<EOF python3\nimport os\nprint(os.getcwd())\nEOF\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP018HeredocErrexit{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
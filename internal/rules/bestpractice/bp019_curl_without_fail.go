@@ -0,0 +1,89 @@
+package bestpractice
+
+import (
+	"path"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// BP019CurlWithoutFail checks for curl downloads that succeed on HTTP errors
+type BP019CurlWithoutFail struct{}
+
+func (r *BP019CurlWithoutFail) ID() string          { return "BP019" }
+func (r *BP019CurlWithoutFail) Name() string        { return "curl-without-fail" }
+func (r *BP019CurlWithoutFail) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP019CurlWithoutFail) Severity() analyzer.Severity { return analyzer.SeverityInfo }
+
+func (r *BP019CurlWithoutFail) Description() string {
+	return "curl exits 0 on HTTP errors unless given --fail, so a 404 page can end up in the image instead of failing the build."
+}
+
+func (r *BP019CurlWithoutFail) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			run, ok := inst.(*parser.RunInstruction)
+			if !ok || run.IsExec {
+				continue
+			}
+
+			cmd := run.Command
+			if run.Heredoc != nil {
+				cmd = run.Heredoc.Content
+			}
+
+			// wget already exits non-zero on HTTP errors, so only curl is checked
+			for _, c := range shell.Split(cmd) {
+				if path.Base(c.Name()) != "curl" || !curlDownloads(c) || curlFails(c) {
+					continue
+				}
+
+				diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+					WithSeverity(r.Severity()).
+					WithMessage("curl without --fail does not fail on HTTP errors").
+					WithPos(run.Pos()).
+					WithContext(ctx.GetLine(run.Pos().Line)).
+					WithHelp("Use curl -fsSL so that an HTTP error fails the build").
+					Build()
+				diags = append(diags, diag)
+				break
+			}
+		}
+	}
+
+	return diags
+}
+
+// curlDownloads reports whether a curl command fetches a URL
+func curlDownloads(c shell.Command) bool {
+	for _, arg := range c.Args()[1:] {
+		if strings.Contains(arg.Value, "://") {
+			return true
+		}
+	}
+	return false
+}
+
+// curlFails reports whether a curl command has -f, --fail, or
+// --fail-with-body, including in combined short flags such as -fsSL
+func curlFails(c shell.Command) bool {
+	for _, arg := range c.Args()[1:] {
+		v := arg.Value
+		switch {
+		case v == "--fail" || v == "--fail-with-body":
+			return true
+		case strings.HasPrefix(v, "--"):
+		case strings.HasPrefix(v, "-") && strings.Contains(v, "f"):
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	Register(&BP019CurlWithoutFail{})
+}
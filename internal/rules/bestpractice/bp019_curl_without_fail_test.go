@@ -0,0 +1,56 @@
+package bestpractice
+
+import "testing"
+
+func TestBP019CurlWithoutFail(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "curl without fail",
+			source:   "FROM alpine\nRUN curl http://example.com/app.tar.gz -o app.tar.gz\n",
+			expected: 1,
+		},
+		{
+			name:     "curl -fsSL",
+			source:   "FROM alpine\nRUN curl -fsSL http://example.com/app.tar.gz -o app.tar.gz\n",
+			expected: 0,
+		},
+		{
+			name:     "combined flags with f later",
+			source:   "FROM alpine\nRUN curl -sSLf https://example.com/install.sh -o install.sh\n",
+			expected: 0,
+		},
+		{
+			name:     "long fail flag",
+			source:   "FROM alpine\nRUN curl --fail-with-body --location https://example.com/x -o x\n",
+			expected: 0,
+		},
+		{
+			name:     "second curl without fail",
+			source:   "FROM alpine\nRUN curl -f https://example.com/a -o a && curl -sL https://example.com/b -o b\n",
+			expected: 1,
+		},
+		{
+			name:     "no URL",
+			source:   "FROM alpine\nRUN curl --version\n",
+			expected: 0,
+		},
+		{
+			name:     "wget",
+			source:   "FROM alpine\nRUN wget https://example.com/app.tar.gz\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP019CurlWithoutFail{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package bestpractice
+
+import (
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// BP020ScratchShellForm checks for shell-form instructions in stages based on scratch
+type BP020ScratchShellForm struct{}
+
+func (r *BP020ScratchShellForm) ID() string          { return "BP020" }
+func (r *BP020ScratchShellForm) Name() string        { return "scratch-shell-form" }
+func (r *BP020ScratchShellForm) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP020ScratchShellForm) Severity() analyzer.Severity { return analyzer.SeverityError }
+
+func (r *BP020ScratchShellForm) Description() string {
+	return "A scratch image has no /bin/sh, so shell-form RUN, CMD, and ENTRYPOINT instructions fail."
+}
+
+func (r *BP020ScratchShellForm) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	// Stages without a shell, by lowercase name
+	noShell := make(map[string]bool)
+
+	for _, stage := range df.Stages {
+		from := stage.From
+		if from == nil {
+			continue
+		}
+
+		// A stage built on a shell-less stage has no shell either
+		scratch := strings.EqualFold(from.Image, "scratch") ||
+			(from.BaseStage != "" && noShell[strings.ToLower(from.BaseStage)])
+
+		for _, inst := range stage.Instructions {
+			if !scratch {
+				break
+			}
+
+			var name, help string
+			switch v := inst.(type) {
+			case *parser.ShellInstruction:
+				// An explicit SHELL means one was copied into the image
+				scratch = false
+				continue
+			case *parser.RunInstruction:
+				if v.IsExec {
+					continue
+				}
+				name, help = "RUN", "Run build steps in an earlier stage and COPY --from the results into the scratch stage"
+			case *parser.CmdInstruction:
+				if v.IsExec {
+					continue
+				}
+				name, help = "CMD", "Use exec form, e.g. CMD [\"/app\"]"
+			case *parser.EntrypointInstruction:
+				if v.IsExec {
+					continue
+				}
+				name, help = "ENTRYPOINT", "Use exec form, e.g. ENTRYPOINT [\"/app\"]"
+			default:
+				continue
+			}
+
+			diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithMessagef("Shell-form %s in a stage based on scratch, which has no shell", name).
+				WithPos(inst.Pos()).
+				WithContext(ctx.GetLine(inst.Pos().Line)).
+				WithHelp(help).
+				Build()
+			diags = append(diags, diag)
+		}
+
+		if scratch && stage.Name != "" {
+			noShell[strings.ToLower(stage.Name)] = true
+		}
+	}
+
+	return diags
+}
+
+func init() {
+	Register(&BP020ScratchShellForm{})
+}
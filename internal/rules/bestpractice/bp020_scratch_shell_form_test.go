@@ -0,0 +1,75 @@
+package bestpractice
+
+import (
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+func TestBP020ScratchShellForm(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "shell-form RUN",
+			source:   "FROM scratch\nRUN echo hello\n",
+			expected: 1,
+		},
+		{
+			name:     "exec-form CMD",
+			source:   "FROM scratch\nCOPY app /app\nCMD [\"/app\"]\n",
+			expected: 0,
+		},
+		{
+			name:     "shell-form CMD",
+			source:   "FROM scratch\nCMD echo hello\n",
+			expected: 1,
+		},
+		{
+			name:     "shell-form ENTRYPOINT",
+			source:   "FROM scratch\nENTRYPOINT /app --serve\n",
+			expected: 1,
+		},
+		{
+			name:     "exec-form RUN",
+			source:   "FROM scratch\nCOPY tool /tool\nRUN [\"/tool\", \"init\"]\n",
+			expected: 0,
+		},
+		{
+			name:     "non-scratch base",
+			source:   "FROM alpine:3.19\nRUN echo hello\nCMD echo hello\n",
+			expected: 0,
+		},
+		{
+			name:     "scratch final stage of a multi-stage build",
+			source:   "FROM golang:1.22 AS build\nRUN go build -o /app\n\nFROM scratch\nCOPY --from=build /app /app\nCMD /app\n",
+			expected: 1,
+		},
+		{
+			name:     "stage built on a scratch stage",
+			source:   "FROM scratch AS base\nCOPY app /app\n\nFROM base\nRUN echo hello\n",
+			expected: 1,
+		},
+		{
+			name:     "explicit SHELL",
+			source:   "FROM scratch\nCOPY --from=busybox:1.36 /bin/busybox /bin/busybox\nSHELL [\"/bin/busybox\", \"sh\", \"-c\"]\nRUN echo hello\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP020ScratchShellForm{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+			for _, d := range diags {
+				if d.Severity != analyzer.SeverityError {
+					t.Errorf("expected error severity, got %v", d.Severity)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package bestpractice
+
+import (
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/lexer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// BP021CopyToRoot checks for COPY or ADD into the root directory of the runtime stage
+type BP021CopyToRoot struct{}
+
+func (r *BP021CopyToRoot) ID() string          { return "BP021" }
+func (r *BP021CopyToRoot) Name() string        { return "copy-to-root" }
+func (r *BP021CopyToRoot) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP021CopyToRoot) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+
+func (r *BP021CopyToRoot) Description() string {
+	return "Copying files into / mixes application files with the root filesystem. Copy them into a dedicated directory such as /app."
+}
+
+func (r *BP021CopyToRoot) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	// Only the final stage ends up in the image unless configured otherwise
+	checkBuilders, _ := ctx.Config["check_builder_stages"].(bool)
+
+	for i, stage := range df.Stages {
+		if i < len(df.Stages)-1 && !checkBuilders {
+			continue
+		}
+		// In a scratch image the root filesystem is the application
+		if stage.From != nil && strings.EqualFold(stage.From.Image, "scratch") {
+			continue
+		}
+
+		for _, inst := range stage.Instructions {
+			var dest string
+			var pos lexer.Position
+			var name string
+
+			switch v := inst.(type) {
+			case *parser.CopyInstruction:
+				dest, pos, name = v.Destination, v.Pos(), "COPY"
+			case *parser.AddInstruction:
+				dest, pos, name = v.Destination, v.Pos(), "ADD"
+			default:
+				continue
+			}
+
+			if dest != "/" {
+				continue
+			}
+
+			diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithMessagef("%s copies files into the root directory", name).
+				WithPos(pos).
+				WithContext(ctx.GetLine(pos.Line)).
+				WithHelp("Copy into a dedicated directory, e.g. WORKDIR /app and " + name + " . .").
+				Build()
+			diags = append(diags, diag)
+		}
+	}
+
+	return diags
+}
+
+func init() {
+	Register(&BP021CopyToRoot{})
+}
@@ -0,0 +1,73 @@
+package bestpractice
+
+import (
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+func TestBP021CopyToRoot(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "copy to root",
+			source:   "FROM node:20\nCOPY . /\n",
+			expected: 1,
+		},
+		{
+			name:     "add to root",
+			source:   "FROM alpine:3.19\nADD app.tar.gz /\n",
+			expected: 1,
+		},
+		{
+			name:     "copy to app directory",
+			source:   "FROM node:20\nCOPY . /app\n",
+			expected: 0,
+		},
+		{
+			name:     "copy to root subdirectory",
+			source:   "FROM node:20\nCOPY config.json /etc/app/\n",
+			expected: 0,
+		},
+		{
+			name:     "builder stage skipped",
+			source:   "FROM golang:1.22 AS build\nCOPY . /\nRUN go build -o /out/app\n\nFROM alpine:3.19\nCOPY --from=build /out/app /usr/local/bin/app\n",
+			expected: 0,
+		},
+		{
+			name:     "final scratch stage",
+			source:   "FROM golang:1.22 AS build\nRUN go build -o /app\n\nFROM scratch\nCOPY --from=build /app /\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP021CopyToRoot{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
+
+func TestBP021CopyToRoot_CheckBuilderStages(t *testing.T) {
+	a := analyzer.New(
+		analyzer.WithRules(&BP021CopyToRoot{}),
+		analyzer.WithRuleConfig("BP021", map[string]interface{}{
+			"check_builder_stages": true,
+		}),
+	)
+
+	source := "FROM golang:1.22 AS build\nCOPY . /\nRUN go build -o /out/app\n\nFROM alpine:3.19\nCOPY --from=build /out/app /usr/local/bin/app\n"
+	result, _ := a.AnalyzeSource(source, "Dockerfile")
+	if len(result.Diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(result.Diagnostics))
+	}
+	if result.Diagnostics[0].Pos.Line != 2 {
+		t.Errorf("expected diagnostic on line 2, got %d", result.Diagnostics[0].Pos.Line)
+	}
+}
@@ -0,0 +1,118 @@
+package bestpractice
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// BP022HardcodedLocalhost checks for localhost addresses baked into runtime config
+type BP022HardcodedLocalhost struct{}
+
+func (r *BP022HardcodedLocalhost) ID() string          { return "BP022" }
+func (r *BP022HardcodedLocalhost) Name() string        { return "hardcoded-localhost" }
+func (r *BP022HardcodedLocalhost) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP022HardcodedLocalhost) Severity() analyzer.Severity { return analyzer.SeverityInfo }
+
+func (r *BP022HardcodedLocalhost) Description() string {
+	return "localhost inside a container is the container itself, so services addressed or bound there are rarely reachable at runtime."
+}
+
+var (
+	// A loopback host standing alone or as the host part of an address
+	loopbackPattern = regexp.MustCompile(`(?i)(?:^|[\s/@=,"'])(localhost|127\.0\.0\.1|\[::1\])(?:$|[\s:/,"'])`)
+	// 0.0.0.0 is the right bind address, but not something to connect to
+	anyAddrURLPattern = regexp.MustCompile(`://(0\.0\.0\.0)(?:$|[:/])`)
+)
+
+// Variables that are expected to list localhost
+var loopbackEnvKeys = map[string]bool{
+	"NO_PROXY": true,
+	"no_proxy": true,
+}
+
+func (r *BP022HardcodedLocalhost) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	if len(df.Stages) == 0 {
+		return diags
+	}
+
+	report := func(inst parser.Instruction, what, host string, severity analyzer.Severity) {
+		help := "Read the address from a variable set at runtime, e.g. docker run -e, or use the service's hostname"
+		if host == "0.0.0.0" {
+			help = "0.0.0.0 is only meaningful as a bind address; connect to the service's hostname instead"
+		}
+		diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+			WithSeverity(severity).
+			WithMessagef("%s refers to %s, which is the container itself at runtime", what, host).
+			WithPos(inst.Pos()).
+			WithContext(ctx.GetLine(inst.Pos().Line)).
+			WithHelp(help).
+			Build()
+		diags = append(diags, diag)
+	}
+
+	check := func(inst parser.Instruction, what, value string) {
+		if m := loopbackPattern.FindStringSubmatch(value); m != nil {
+			report(inst, what, m[1], r.Severity())
+		} else if m := anyAddrURLPattern.FindStringSubmatch(value); m != nil {
+			report(inst, what, m[1], analyzer.SeverityHint)
+		}
+	}
+
+	// Only the final image's config is used at runtime. ENV is inherited
+	// from the stages it is built FROM.
+	byName := make(map[string]*parser.Stage)
+	for _, stage := range df.Stages {
+		if stage.Name != "" {
+			byName[strings.ToLower(stage.Name)] = stage
+		}
+	}
+
+	final := df.Stages[len(df.Stages)-1]
+	seen := make(map[*parser.Stage]bool)
+	for stage := final; stage != nil && !seen[stage]; {
+		seen[stage] = true
+
+		for _, inst := range stage.Instructions {
+			switch v := inst.(type) {
+			case *parser.EnvInstruction:
+				for _, kv := range v.Variables {
+					if !loopbackEnvKeys[kv.Key] {
+						check(v, "ENV "+kv.Key, kv.Value)
+					}
+				}
+			case *parser.CmdInstruction:
+				// CMD and ENTRYPOINT are replaced, not inherited
+				if stage == final {
+					check(v, "CMD", commandText(v.IsExec, v.Arguments, v.Command))
+				}
+			case *parser.EntrypointInstruction:
+				if stage == final {
+					check(v, "ENTRYPOINT", commandText(v.IsExec, v.Arguments, v.Command))
+				}
+			}
+		}
+
+		if stage.From == nil {
+			break
+		}
+		stage = byName[strings.ToLower(stage.From.Image)]
+	}
+
+	return diags
+}
+
+func commandText(isExec bool, args []string, command string) string {
+	if isExec {
+		return strings.Join(args, " ")
+	}
+	return command
+}
+
+func init() {
+	Register(&BP022HardcodedLocalhost{})
+}
@@ -0,0 +1,88 @@
+package bestpractice
+
+import (
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+func TestBP022HardcodedLocalhost(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "env url with localhost",
+			source:   "FROM node:20\nENV API=http://localhost:8080\n",
+			expected: 1,
+		},
+		{
+			name:     "env with loopback address",
+			source:   "FROM node:20\nENV DB_HOST=127.0.0.1\n",
+			expected: 1,
+		},
+		{
+			name:     "cmd binding to localhost",
+			source:   "FROM python:3.12\nCMD [\"gunicorn\", \"--bind\", \"localhost:8000\", \"app:app\"]\n",
+			expected: 1,
+		},
+		{
+			name:     "shell form entrypoint",
+			source:   "FROM node:20\nENTRYPOINT node server.js --redis redis://localhost:6379\n",
+			expected: 1,
+		},
+		{
+			name:     "bind to all interfaces",
+			source:   "FROM python:3.12\nENV HOST=0.0.0.0\nCMD [\"uvicorn\", \"app:app\", \"--host\", \"0.0.0.0\"]\n",
+			expected: 0,
+		},
+		{
+			name:     "no_proxy list",
+			source:   "FROM node:20\nENV NO_PROXY=localhost,127.0.0.1\n",
+			expected: 0,
+		},
+		{
+			name:     "hostname containing localhost",
+			source:   "FROM node:20\nENV API=http://localhost-proxy.internal:8080\n",
+			expected: 0,
+		},
+		{
+			name:     "healthcheck is not checked",
+			source:   "FROM nginx:1.25\nHEALTHCHECK CMD curl -f http://localhost/ || exit 1\n",
+			expected: 0,
+		},
+		{
+			name:     "builder stage cmd",
+			source:   "FROM node:20 AS build\nCMD [\"node\", \"--inspect=localhost:9229\"]\n\nFROM node:20-slim\nCMD [\"node\", \"server.js\"]\n",
+			expected: 0,
+		},
+		{
+			name:     "env inherited from parent stage",
+			source:   "FROM node:20 AS base\nENV API=http://localhost:8080\n\nFROM base\nCMD [\"node\", \"server.js\"]\n",
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP022HardcodedLocalhost{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
+
+func TestBP022HardcodedLocalhost_Severity(t *testing.T) {
+	diags := runRule(t, &BP022HardcodedLocalhost{}, "FROM node:20\nENV API=http://localhost:8080\nENV UPSTREAM=http://0.0.0.0:9000\n")
+	if len(diags) != 2 {
+		t.Fatalf("expected 2 diagnostics, got %d: %v", len(diags), diags)
+	}
+	if diags[0].Severity != analyzer.SeverityInfo {
+		t.Errorf("expected localhost to be info, got %s", diags[0].Severity)
+	}
+	if diags[1].Severity != analyzer.SeverityHint {
+		t.Errorf("expected 0.0.0.0 url to be a hint, got %s", diags[1].Severity)
+	}
+}
@@ -0,0 +1,92 @@
+package bestpractice
+
+import (
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// BP023CmdEntrypointCombination checks for CMD and ENTRYPOINT forms that don't combine
+type BP023CmdEntrypointCombination struct{}
+
+func (r *BP023CmdEntrypointCombination) ID() string          { return "BP023" }
+func (r *BP023CmdEntrypointCombination) Name() string        { return "cmd-entrypoint-combination" }
+func (r *BP023CmdEntrypointCombination) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP023CmdEntrypointCombination) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+
+func (r *BP023CmdEntrypointCombination) Description() string {
+	return "With an ENTRYPOINT, CMD supplies its default arguments. That only works when both use exec form: a shell-form ENTRYPOINT ignores CMD, and a shell-form CMD is passed as /bin/sh -c arguments."
+}
+
+func (r *BP023CmdEntrypointCombination) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	// A stage built FROM another stage inherits its ENTRYPOINT and CMD
+	type config struct {
+		entrypoint *parser.EntrypointInstruction
+		cmd        *parser.CmdInstruction
+	}
+	byName := make(map[string]config)
+
+	for _, stage := range df.Stages {
+		var cfg config
+		if stage.From != nil {
+			cfg = byName[strings.ToLower(stage.From.Image)]
+		}
+
+		cmdSet := false
+		for _, inst := range stage.Instructions {
+			switch v := inst.(type) {
+			case *parser.EntrypointInstruction:
+				cfg.entrypoint = v
+				if !cmdSet {
+					// Setting ENTRYPOINT resets an inherited CMD
+					cfg.cmd = nil
+				}
+			case *parser.CmdInstruction:
+				cfg.cmd = v
+				cmdSet = true
+			}
+		}
+		if stage.Name != "" {
+			byName[strings.ToLower(stage.Name)] = cfg
+		}
+
+		// An inherited CMD only survives with an inherited ENTRYPOINT, so
+		// a combination is new only if this stage sets CMD
+		entrypoint, cmd := cfg.entrypoint, cfg.cmd
+		if entrypoint == nil || cmd == nil || !cmdSet {
+			continue
+		}
+
+		switch {
+		case !entrypoint.IsExec:
+			diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithMessage("CMD is ignored because ENTRYPOINT uses shell form").
+				WithPos(cmd.Pos()).
+				WithContext(ctx.GetLine(cmd.Pos().Line)).
+				WithHelp("Use exec form for ENTRYPOINT, e.g. ENTRYPOINT [\"/app/server\"], so CMD supplies its default arguments").
+				Build()
+			diags = append(diags, diag)
+		case !cmd.IsExec:
+			builder := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithMessage("Shell-form CMD is passed to the exec-form ENTRYPOINT as /bin/sh -c arguments").
+				WithRange(cmd.Pos(), lineEnd(ctx, cmd)).
+				WithContext(ctx.GetLine(cmd.Pos().Line)).
+				WithHelp("Use exec form for CMD so its words are passed as arguments, e.g. CMD [\"--port\", \"8080\"]")
+			if fix := execForm("CMD", strings.TrimSpace(cmd.Command)); fix != "" {
+				builder = builder.WithFix(fix)
+			}
+			diags = append(diags, builder.Build())
+		}
+	}
+
+	return diags
+}
+
+func init() {
+	Register(&BP023CmdEntrypointCombination{})
+}
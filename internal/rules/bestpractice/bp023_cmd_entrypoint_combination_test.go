@@ -0,0 +1,97 @@
+package bestpractice
+
+import (
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+func TestBP023CmdEntrypointCombination(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "exec entrypoint with exec cmd",
+			source:   "FROM alpine:3.19\nENTRYPOINT [\"/app/server\"]\nCMD [\"--port\", \"8080\"]\n",
+			expected: 0,
+		},
+		{
+			name:     "exec entrypoint with shell cmd",
+			source:   "FROM alpine:3.19\nENTRYPOINT [\"/app/server\"]\nCMD --port 8080\n",
+			expected: 1,
+		},
+		{
+			name:     "shell entrypoint with exec cmd",
+			source:   "FROM alpine:3.19\nENTRYPOINT /app/server\nCMD [\"--port\", \"8080\"]\n",
+			expected: 1,
+		},
+		{
+			name:     "shell entrypoint with shell cmd",
+			source:   "FROM alpine:3.19\nENTRYPOINT exec /app/server\nCMD --port 8080\n",
+			expected: 1,
+		},
+		{
+			name:     "shell cmd without entrypoint",
+			source:   "FROM alpine:3.19\nCMD /app/server --port 8080\n",
+			expected: 0,
+		},
+		{
+			name:     "shell entrypoint without cmd",
+			source:   "FROM alpine:3.19\nENTRYPOINT exec /app/server\n",
+			expected: 0,
+		},
+		{
+			name:     "only the last cmd counts",
+			source:   "FROM alpine:3.19\nENTRYPOINT [\"/app/server\"]\nCMD --port 8080\nCMD [\"--port\", \"9090\"]\n",
+			expected: 0,
+		},
+		{
+			name:     "entrypoint inherited from parent stage",
+			source:   "FROM alpine:3.19 AS base\nENTRYPOINT [\"/app/server\"]\n\nFROM base\nCMD --port 8080\n",
+			expected: 1,
+		},
+		{
+			name:     "entrypoint resets inherited cmd",
+			source:   "FROM alpine:3.19 AS base\nCMD /app/server --port 8080\n\nFROM base\nENTRYPOINT [\"/app/server\"]\n",
+			expected: 0,
+		},
+		{
+			name:     "cmd before entrypoint in the same stage",
+			source:   "FROM alpine:3.19\nCMD --port 8080\nENTRYPOINT [\"/app/server\"]\n",
+			expected: 1,
+		},
+		{
+			name:     "combination reported once",
+			source:   "FROM alpine:3.19 AS base\nENTRYPOINT [\"/app/server\"]\nCMD --port 8080\n\nFROM base\nUSER app\n",
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP023CmdEntrypointCombination{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
+
+func TestBP023CmdEntrypointCombination_Fix(t *testing.T) {
+	source := "FROM alpine:3.19\nENTRYPOINT [\"/app/server\"]\nCMD --port 8080\n"
+	diags := runRule(t, &BP023CmdEntrypointCombination{}, source)
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(diags))
+	}
+
+	fixed, err := analyzer.ApplyFix(source, diags[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "FROM alpine:3.19\nENTRYPOINT [\"/app/server\"]\nCMD [\"--port\", \"8080\"]\n"
+	if fixed != expected {
+		t.Errorf("expected %q, got %q", expected, fixed)
+	}
+}
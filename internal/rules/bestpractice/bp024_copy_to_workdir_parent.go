@@ -0,0 +1,80 @@
+package bestpractice
+
+import (
+	"path"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/lexer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// BP024CopyToWorkdirParent checks for COPY or ADD into a parent of the current WORKDIR
+type BP024CopyToWorkdirParent struct{}
+
+func (r *BP024CopyToWorkdirParent) ID() string          { return "BP024" }
+func (r *BP024CopyToWorkdirParent) Name() string        { return "copy-to-workdir-parent" }
+func (r *BP024CopyToWorkdirParent) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP024CopyToWorkdirParent) Severity() analyzer.Severity { return analyzer.SeverityHint }
+
+func (r *BP024CopyToWorkdirParent) Description() string {
+	return "Copying into a parent of the WORKDIR can overwrite or shadow files already in the working directory. Copy into the WORKDIR itself or a separate directory."
+}
+
+func (r *BP024CopyToWorkdirParent) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	workdirs := effectiveWorkdirs(df)
+
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			var dest string
+			var pos lexer.Position
+			var name string
+
+			switch v := inst.(type) {
+			case *parser.CopyInstruction:
+				dest, pos, name = v.Destination, v.Pos(), "COPY"
+			case *parser.AddInstruction:
+				dest, pos, name = v.Destination, v.Pos(), "ADD"
+			default:
+				continue
+			}
+
+			workdir := workdirs[inst].Path
+			if workdir == "" || dest == "" || strings.Contains(dest, "$") {
+				continue
+			}
+			target := path.Clean(dest)
+			if !path.IsAbs(target) {
+				target = path.Join(workdir, target)
+			}
+			if !isStrictParent(target, workdir) {
+				continue
+			}
+
+			diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithMessagef("%s into %s, a parent of WORKDIR %s", name, target, workdir).
+				WithPos(pos).
+				WithContext(ctx.GetLine(pos.Line)).
+				WithHelp("Copy into the WORKDIR, e.g. " + name + " . ., or into a directory outside it").
+				Build()
+			diags = append(diags, diag)
+		}
+	}
+
+	return diags
+}
+
+// isStrictParent reports whether dir contains child and is not child itself
+func isStrictParent(dir, child string) bool {
+	if dir == child {
+		return false
+	}
+	return dir == "/" || strings.HasPrefix(child, dir+"/")
+}
+
+func init() {
+	Register(&BP024CopyToWorkdirParent{})
+}
@@ -0,0 +1,76 @@
+package bestpractice
+
+import "testing"
+
+func TestBP024CopyToWorkdirParent(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "copy to parent of workdir",
+			source:   "FROM node:20\nWORKDIR /app/src\nCOPY . /app\n",
+			expected: 1,
+		},
+		{
+			name:     "copy to workdir",
+			source:   "FROM node:20\nWORKDIR /app/src\nCOPY . /app/src\n",
+			expected: 0,
+		},
+		{
+			name:     "copy to workdir with trailing slash",
+			source:   "FROM node:20\nWORKDIR /app/src\nCOPY . /app/src/\n",
+			expected: 0,
+		},
+		{
+			name:     "relative destination",
+			source:   "FROM node:20\nWORKDIR /app/src\nCOPY . .\n",
+			expected: 0,
+		},
+		{
+			name:     "relative parent destination",
+			source:   "FROM node:20\nWORKDIR /app/src\nADD vendor.tar.gz ..\n",
+			expected: 1,
+		},
+		{
+			name:     "relative workdir",
+			source:   "FROM node:20\nWORKDIR /app\nWORKDIR src\nCOPY . /app/\n",
+			expected: 1,
+		},
+		{
+			name:     "copy to root",
+			source:   "FROM node:20\nWORKDIR /app\nCOPY . /\n",
+			expected: 1,
+		},
+		{
+			name:     "sibling directory",
+			source:   "FROM node:20\nWORKDIR /app\nCOPY config.json /application/\n",
+			expected: 0,
+		},
+		{
+			name:     "copy before workdir",
+			source:   "FROM node:20\nCOPY . /app\nWORKDIR /app/src\n",
+			expected: 0,
+		},
+		{
+			name:     "variable workdir",
+			source:   "FROM node:20\nARG DIR=/app/src\nWORKDIR $DIR\nCOPY . /app\n",
+			expected: 0,
+		},
+		{
+			name:     "inherited workdir",
+			source:   "FROM node:20 AS base\nWORKDIR /app/src\n\nFROM base\nCOPY . /app\n",
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP024CopyToWorkdirParent{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
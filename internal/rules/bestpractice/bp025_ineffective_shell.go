@@ -0,0 +1,102 @@
+package bestpractice
+
+import (
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// BP025IneffectiveShell checks for malformed SHELL instructions and SHELL
+// instructions no shell-form instruction uses
+type BP025IneffectiveShell struct{}
+
+func (r *BP025IneffectiveShell) ID() string          { return "BP025" }
+func (r *BP025IneffectiveShell) Name() string        { return "ineffective-shell" }
+func (r *BP025IneffectiveShell) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP025IneffectiveShell) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+
+func (r *BP025IneffectiveShell) Description() string {
+	return "SHELL must be a JSON array such as SHELL [\"/bin/bash\", \"-c\"], and only affects later shell-form RUN, CMD, ENTRYPOINT, and HEALTHCHECK instructions."
+}
+
+func (r *BP025IneffectiveShell) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	// Stages other stages build on pass their SHELL down
+	isBase := make(map[string]bool)
+	for _, stage := range df.Stages {
+		if stage.From != nil && stage.From.BaseStage != "" {
+			isBase[strings.ToLower(stage.From.BaseStage)] = true
+		}
+	}
+
+	unused := func(sh *parser.ShellInstruction) {
+		diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+			WithSeverity(analyzer.SeverityInfo).
+			WithMessage("SHELL is not used by any shell-form instruction").
+			WithRange(sh.Pos(), sh.End()).
+			WithContext(ctx.GetLine(sh.Pos().Line)).
+			WithHelp("Remove the SHELL instruction, or move it before the shell-form instructions it should apply to").
+			Build()
+		diags = append(diags, diag)
+	}
+
+	for _, stage := range df.Stages {
+		var pending *parser.ShellInstruction
+
+		for _, inst := range stage.Instructions {
+			if sh, ok := inst.(*parser.ShellInstruction); ok {
+				if len(sh.Shell) == 0 {
+					diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+						WithSeverity(r.Severity()).
+						WithMessage("SHELL must be a non-empty JSON array").
+						WithRange(sh.Pos(), sh.End()).
+						WithContext(ctx.GetLine(sh.Pos().Line)).
+						WithHelp("Use exec form, e.g. SHELL [\"/bin/bash\", \"-o\", \"pipefail\", \"-c\"]").
+						Build()
+					diags = append(diags, diag)
+					continue
+				}
+				// A SHELL replaced before anything used it had no effect
+				if pending != nil {
+					unused(pending)
+				}
+				pending = sh
+				continue
+			}
+
+			if usesShell(inst) {
+				pending = nil
+			}
+		}
+
+		if pending != nil && !isBase[strings.ToLower(stage.Name)] {
+			unused(pending)
+		}
+	}
+
+	return diags
+}
+
+// usesShell reports whether inst runs through the SHELL
+func usesShell(inst parser.Instruction) bool {
+	switch v := inst.(type) {
+	case *parser.RunInstruction:
+		return !v.IsExec
+	case *parser.CmdInstruction:
+		return !v.IsExec
+	case *parser.EntrypointInstruction:
+		return !v.IsExec
+	case *parser.HealthcheckInstruction:
+		return !v.None && !v.IsExec
+	case *parser.OnbuildInstruction:
+		// The trigger runs in a downstream build
+		return true
+	}
+	return false
+}
+
+func init() {
+	Register(&BP025IneffectiveShell{})
+}
@@ -0,0 +1,74 @@
+package bestpractice
+
+import (
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+func TestBP025IneffectiveShell(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+		severity analyzer.Severity
+	}{
+		{
+			name:     "shell form SHELL",
+			source:   "FROM debian:12\nSHELL /bin/bash -c\nRUN echo hi\n",
+			expected: 1,
+			severity: analyzer.SeverityWarning,
+		},
+		{
+			name:     "empty SHELL",
+			source:   "FROM debian:12\nSHELL []\nRUN echo hi\n",
+			expected: 1,
+			severity: analyzer.SeverityWarning,
+		},
+		{
+			name:     "array SHELL",
+			source:   "FROM debian:12\nSHELL [\"/bin/bash\", \"-o\", \"pipefail\", \"-c\"]\nRUN curl -fsSL https://example.com | tar xz\n",
+			expected: 0,
+		},
+		{
+			name:     "used by shell-form CMD",
+			source:   "FROM debian:12\nSHELL [\"/bin/bash\", \"-c\"]\nCMD echo hi\n",
+			expected: 0,
+		},
+		{
+			name:     "never used",
+			source:   "FROM debian:12\nRUN echo hi\nSHELL [\"/bin/bash\", \"-c\"]\nCMD [\"/app\"]\n",
+			expected: 1,
+			severity: analyzer.SeverityInfo,
+		},
+		{
+			name:     "only exec form after",
+			source:   "FROM debian:12\nSHELL [\"/bin/bash\", \"-c\"]\nRUN [\"make\"]\n",
+			expected: 1,
+			severity: analyzer.SeverityInfo,
+		},
+		{
+			name:     "replaced before use",
+			source:   "FROM debian:12\nSHELL [\"/bin/bash\", \"-c\"]\nSHELL [\"/bin/sh\", \"-c\"]\nRUN echo hi\n",
+			expected: 1,
+			severity: analyzer.SeverityInfo,
+		},
+		{
+			name:     "inherited by a later stage",
+			source:   "FROM debian:12 AS base\nSHELL [\"/bin/bash\", \"-c\"]\n\nFROM base\nRUN echo hi\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP025IneffectiveShell{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Fatalf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+			if tt.expected > 0 && diags[0].Severity != tt.severity {
+				t.Errorf("expected severity %s, got %s", tt.severity, diags[0].Severity)
+			}
+		})
+	}
+}
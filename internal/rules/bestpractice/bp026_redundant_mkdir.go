@@ -0,0 +1,138 @@
+package bestpractice
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// BP026RedundantMkdir checks for RUN mkdir of a directory a later WORKDIR creates anyway
+type BP026RedundantMkdir struct{}
+
+func (r *BP026RedundantMkdir) ID() string          { return "BP026" }
+func (r *BP026RedundantMkdir) Name() string        { return "redundant-mkdir" }
+func (r *BP026RedundantMkdir) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP026RedundantMkdir) Severity() analyzer.Severity { return analyzer.SeverityHint }
+
+func (r *BP026RedundantMkdir) Description() string {
+	return "WORKDIR creates its directory if it doesn't exist, so a RUN mkdir of the same path beforehand is unnecessary."
+}
+
+func (r *BP026RedundantMkdir) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	workdirs := effectiveWorkdirs(df)
+
+	for _, stage := range df.Stages {
+		// Directories made by mkdir and not used since, and the RUN that made them
+		made := make(map[string]*parser.RunInstruction)
+		posix := true
+
+		for _, inst := range stage.Instructions {
+			switch v := inst.(type) {
+			case *parser.ShellInstruction:
+				posix = shell.IsPOSIX(v.Shell)
+			case *parser.WorkdirInstruction:
+				workdir := resolveWorkdir(workdirs[v].Path, v.Path)
+				run, ok := made[workdir]
+				if !ok {
+					continue
+				}
+				delete(made, workdir)
+
+				diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+					WithSeverity(r.Severity()).
+					WithMessagef("mkdir %s is redundant, WORKDIR on line %d creates it", workdir, v.Pos().Line).
+					WithPos(run.Pos()).
+					WithContext(ctx.GetLine(run.Pos().Line)).
+					WithHelp(fmt.Sprintf("Remove the mkdir and rely on WORKDIR %s to create the directory", v.Path)).
+					Build()
+				diags = append(diags, diag)
+			case *parser.RunInstruction:
+				text := v.Command
+				if v.IsExec {
+					text = strings.Join(v.Arguments, " ")
+				} else if v.Heredoc != nil {
+					text += "\n" + v.Heredoc.Content
+				}
+				// A directory another command uses has to exist before WORKDIR
+				for dir := range made {
+					if strings.Contains(text, dir) {
+						delete(made, dir)
+					}
+				}
+
+				if v.IsExec || v.Heredoc != nil || !posix {
+					continue
+				}
+				for _, dir := range mkdirOnly(v.Command, workdirs[v].Path) {
+					made[dir] = v
+				}
+			}
+		}
+	}
+
+	return diags
+}
+
+// mkdirOnly returns the directories cmd creates with a plain mkdir or
+// mkdir -p that no other command in cmd refers to. Directories created
+// with a mode are skipped, since WORKDIR can't set one.
+func mkdirOnly(cmd, workdir string) []string {
+	var dirs []string
+	var others []string
+
+	for _, c := range shell.Split(cmd) {
+		args := c.Args()
+		if c.Name() != "mkdir" {
+			for _, a := range args {
+				others = append(others, a.Value)
+			}
+			continue
+		}
+
+		var made []string
+		plain := true
+		for _, a := range args[1:] {
+			switch {
+			case a.Value == "-p" || a.Value == "--parents" || a.Value == "-v" || a.Value == "-pv" || a.Value == "-vp":
+			case strings.HasPrefix(a.Value, "-"):
+				plain = false
+			case strings.Contains(a.Value, "$"):
+				plain = false
+			case path.IsAbs(a.Value):
+				made = append(made, path.Clean(a.Value))
+			case workdir != "":
+				made = append(made, path.Join(workdir, a.Value))
+			default:
+				plain = false
+			}
+		}
+		if plain {
+			dirs = append(dirs, made...)
+		}
+	}
+
+	var unused []string
+	for _, dir := range dirs {
+		used := false
+		for _, w := range others {
+			if strings.Contains(w, dir) {
+				used = true
+				break
+			}
+		}
+		if !used {
+			unused = append(unused, dir)
+		}
+	}
+	return unused
+}
+
+func init() {
+	Register(&BP026RedundantMkdir{})
+}
@@ -0,0 +1,71 @@
+package bestpractice
+
+import "testing"
+
+func TestBP026RedundantMkdir(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "mkdir then workdir",
+			source:   "FROM alpine:3.19\nRUN mkdir -p /app\nWORKDIR /app\n",
+			expected: 1,
+		},
+		{
+			name:     "mkdir in a chain",
+			source:   "FROM alpine:3.19\nRUN apk add --no-cache curl && mkdir /app\nWORKDIR /app/\n",
+			expected: 1,
+		},
+		{
+			name:     "several directories",
+			source:   "FROM alpine:3.19\nRUN mkdir -p /app /data\nWORKDIR /app\n",
+			expected: 1,
+		},
+		{
+			name:     "relative workdir",
+			source:   "FROM alpine:3.19\nWORKDIR /srv\nRUN mkdir app\nWORKDIR app\n",
+			expected: 1,
+		},
+		{
+			name:     "no workdir",
+			source:   "FROM alpine:3.19\nRUN mkdir -p /app\n",
+			expected: 0,
+		},
+		{
+			name:     "different directory",
+			source:   "FROM alpine:3.19\nRUN mkdir -p /app/src\nWORKDIR /app\n",
+			expected: 0,
+		},
+		{
+			name:     "mkdir with mode",
+			source:   "FROM alpine:3.19\nRUN mkdir -m 700 /app\nWORKDIR /app\n",
+			expected: 0,
+		},
+		{
+			name:     "directory used in the same RUN",
+			source:   "FROM alpine:3.19\nRUN mkdir -p /app && chown app:app /app\nWORKDIR /app\n",
+			expected: 0,
+		},
+		{
+			name:     "directory used before workdir",
+			source:   "FROM alpine:3.19\nRUN mkdir -p /app\nRUN echo hi > /app/hello.txt\nWORKDIR /app\n",
+			expected: 0,
+		},
+		{
+			name:     "workdir before mkdir",
+			source:   "FROM alpine:3.19\nWORKDIR /app\nRUN mkdir -p /app\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP026RedundantMkdir{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
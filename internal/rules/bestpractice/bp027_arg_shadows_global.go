@@ -0,0 +1,58 @@
+package bestpractice
+
+import (
+	"fmt"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// BP027ArgShadowsGlobal checks for stage ARGs whose default overrides the default of a global ARG
+type BP027ArgShadowsGlobal struct{}
+
+func (r *BP027ArgShadowsGlobal) ID() string          { return "BP027" }
+func (r *BP027ArgShadowsGlobal) Name() string        { return "arg-shadows-global" }
+func (r *BP027ArgShadowsGlobal) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP027ArgShadowsGlobal) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+
+func (r *BP027ArgShadowsGlobal) Description() string {
+	return "A stage that redeclares a global ARG with its own default uses that default instead of the global one, so FROM and the stage can see different values. Redeclare it without a value to inherit the global default."
+}
+
+func (r *BP027ArgShadowsGlobal) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	global := make(map[string]*parser.ArgInstruction)
+	for _, arg := range df.Args {
+		global[arg.Name] = arg
+	}
+
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			arg, ok := inst.(*parser.ArgInstruction)
+			if !ok || !arg.HasDefault {
+				continue
+			}
+			// ARG NAME without a value inherits the global default
+			g, ok := global[arg.Name]
+			if !ok || !g.HasDefault || g.DefaultValue == arg.DefaultValue {
+				continue
+			}
+
+			diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithMessagef("ARG %s=%s overrides the global default %s=%s from line %d", arg.Name, arg.DefaultValue, g.Name, g.DefaultValue, g.Pos().Line).
+				WithPos(arg.Pos()).
+				WithContext(ctx.GetLine(arg.Pos().Line)).
+				WithHelp(fmt.Sprintf("Use ARG %s without a value to inherit the global default, or give the stage ARG a different name", arg.Name)).
+				Build()
+			diags = append(diags, diag)
+		}
+	}
+
+	return diags
+}
+
+func init() {
+	Register(&BP027ArgShadowsGlobal{})
+}
@@ -0,0 +1,51 @@
+package bestpractice
+
+import "testing"
+
+func TestBP027ArgShadowsGlobal(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "stage default overrides global default",
+			source:   "ARG FOO=1\nFROM alpine:3.19\nARG FOO=2\nRUN echo $FOO\n",
+			expected: 1,
+		},
+		{
+			name:     "redeclared without a value",
+			source:   "ARG FOO=1\nFROM alpine:3.19\nARG FOO\nRUN echo $FOO\n",
+			expected: 0,
+		},
+		{
+			name:     "same default",
+			source:   "ARG FOO=1\nFROM alpine:3.19\nARG FOO=1\n",
+			expected: 0,
+		},
+		{
+			name:     "global without a default",
+			source:   "ARG FOO\nFROM alpine:3.19\nARG FOO=2\n",
+			expected: 0,
+		},
+		{
+			name:     "stage only",
+			source:   "FROM alpine:3.19\nARG FOO=2\n",
+			expected: 0,
+		},
+		{
+			name:     "every stage checked",
+			source:   "ARG VERSION=1.0\nFROM alpine:3.19 AS build\nARG VERSION=2.0\n\nFROM alpine:3.19\nARG VERSION=3.0\n",
+			expected: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP027ArgShadowsGlobal{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package bestpractice
+
+import (
+	"path"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/lexer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// BP028CopyWithoutChown checks for COPY or ADD without --chown in a final stage that runs as a non-root user
+type BP028CopyWithoutChown struct{}
+
+func (r *BP028CopyWithoutChown) ID() string          { return "BP028" }
+func (r *BP028CopyWithoutChown) Name() string        { return "copy-without-chown" }
+func (r *BP028CopyWithoutChown) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP028CopyWithoutChown) Severity() analyzer.Severity { return analyzer.SeverityHint }
+
+func (r *BP028CopyWithoutChown) Description() string {
+	return "COPY and ADD create files owned by root whatever the USER is, so an application running as a non-root user may not be able to write to them. Use --chown to give them to that user."
+}
+
+// System directories whose files are expected to be owned by root
+var systemDirs = []string{"/bin", "/sbin", "/lib", "/lib64", "/usr", "/etc"}
+
+func (r *BP028CopyWithoutChown) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	if len(df.Stages) == 0 {
+		return diags
+	}
+
+	// The last USER of each stage by lowercase name, which later stages inherit
+	users := make(map[string]*parser.UserInstruction)
+	var user *parser.UserInstruction
+	for _, stage := range df.Stages {
+		user = nil
+		if stage.From != nil && stage.From.BaseStage != "" {
+			user = users[strings.ToLower(stage.From.BaseStage)]
+		}
+		for _, inst := range stage.Instructions {
+			if u, ok := inst.(*parser.UserInstruction); ok {
+				user = u
+			}
+		}
+		if stage.Name != "" {
+			users[strings.ToLower(stage.Name)] = user
+		}
+	}
+
+	// Only the final stage's user runs the application
+	if user == nil || isRootUser(user.User) || strings.Contains(user.User, "$") {
+		return diags
+	}
+	owner := user.User
+	if user.Group != "" {
+		owner += ":" + user.Group
+	}
+
+	workdirs := effectiveWorkdirs(df)
+	for _, inst := range df.Stages[len(df.Stages)-1].Instructions {
+		var chown, dest, name string
+		var pos lexer.Position
+
+		switch v := inst.(type) {
+		case *parser.CopyInstruction:
+			chown, dest, pos, name = v.Chown, v.Destination, v.Pos(), "COPY"
+		case *parser.AddInstruction:
+			chown, dest, pos, name = v.Chown, v.Destination, v.Pos(), "ADD"
+		default:
+			continue
+		}
+
+		if chown != "" || dest == "" || strings.Contains(dest, "$") {
+			continue
+		}
+		target := dest
+		if !path.IsAbs(target) {
+			workdir := workdirs[inst].Path
+			if workdir == "" {
+				continue
+			}
+			target = path.Join(workdir, target)
+		}
+		if isSystemDir(target) {
+			continue
+		}
+
+		diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+			WithSeverity(r.Severity()).
+			WithMessagef("%s without --chown creates root-owned files, but the image runs as %s", name, user.User).
+			WithPos(pos).
+			WithContext(ctx.GetLine(pos.Line)).
+			WithHelp("Give the files to the runtime user, e.g. " + name + " --chown=" + owner + " ..., if the application needs to write to them").
+			Build()
+		diags = append(diags, diag)
+	}
+
+	return diags
+}
+
+func isRootUser(user string) bool {
+	return user == "root" || user == "0"
+}
+
+// isSystemDir reports whether p is / or inside a directory of the base system
+func isSystemDir(p string) bool {
+	p = path.Clean(p)
+	if p == "/" {
+		return true
+	}
+	for _, dir := range systemDirs {
+		if p == dir || strings.HasPrefix(p, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	Register(&BP028CopyWithoutChown{})
+}
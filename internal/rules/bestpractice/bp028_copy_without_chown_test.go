@@ -0,0 +1,76 @@
+package bestpractice
+
+import "testing"
+
+func TestBP028CopyWithoutChown(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "non-root user without chown",
+			source:   "FROM node:20\nWORKDIR /app\nCOPY . .\nUSER node\n",
+			expected: 1,
+		},
+		{
+			name:     "add without chown",
+			source:   "FROM alpine:3.19\nADD app.tar.gz /srv/app/\nUSER 1000:1000\n",
+			expected: 1,
+		},
+		{
+			name:     "with chown",
+			source:   "FROM node:20\nWORKDIR /app\nCOPY --chown=node:node . .\nUSER node\n",
+			expected: 0,
+		},
+		{
+			name:     "root stage",
+			source:   "FROM node:20\nWORKDIR /app\nCOPY . .\n",
+			expected: 0,
+		},
+		{
+			name:     "explicit root user",
+			source:   "FROM node:20\nUSER node\nCOPY . /app\nUSER root\n",
+			expected: 0,
+		},
+		{
+			name:     "system directory",
+			source:   "FROM golang:1.22 AS build\nRUN go build -o /out/app\n\nFROM alpine:3.19\nCOPY --from=build /out/app /usr/local/bin/app\nUSER 1000\n",
+			expected: 0,
+		},
+		{
+			name:     "builder stage skipped",
+			source:   "FROM node:20 AS build\nUSER node\nCOPY . /app\n\nFROM nginx:1.25\nCOPY --from=build /app/dist /usr/share/nginx/html\n",
+			expected: 0,
+		},
+		{
+			name:     "user inherited from base stage",
+			source:   "FROM node:20 AS base\nUSER node\n\nFROM base\nCOPY . /home/node/app\n",
+			expected: 1,
+		},
+		{
+			name:     "workdir inherited from base stage",
+			source:   "FROM node:20 AS base\nWORKDIR /app\nUSER node\n\nFROM base AS app\nCOPY . .\n",
+			expected: 1,
+		},
+		{
+			name:     "inherited system workdir",
+			source:   "FROM alpine:3.19 AS base\nWORKDIR /usr/local/bin\n\nFROM base AS app\nCOPY app .\nUSER 1000\n",
+			expected: 0,
+		},
+		{
+			name:     "variable user",
+			source:   "FROM node:20\nARG APP_USER=node\nCOPY . /app\nUSER $APP_USER\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP028CopyWithoutChown{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package bestpractice
+
+import (
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// BP029WorkdirChain checks for relative WORKDIRs that climb out of an absolute one
+type BP029WorkdirChain struct{}
+
+func (r *BP029WorkdirChain) ID() string          { return "BP029" }
+func (r *BP029WorkdirChain) Name() string        { return "workdir-chain" }
+func (r *BP029WorkdirChain) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP029WorkdirChain) Severity() analyzer.Severity { return analyzer.SeverityHint }
+
+func (r *BP029WorkdirChain) Description() string {
+	return "A relative WORKDIR with .. resolves against the previous WORKDIR, so the resulting directory is easy to misread. Use the absolute path instead."
+}
+
+func (r *BP029WorkdirChain) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	workdirs := effectiveWorkdirs(df)
+
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			wd, ok := inst.(*parser.WorkdirInstruction)
+			if !ok {
+				continue
+			}
+
+			previous := workdirs[wd].Path
+			workdir := resolveWorkdir(previous, wd.Path)
+			if previous == "" || workdir == "" || !hasParentSegment(wd.Path) {
+				continue
+			}
+
+			diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithMessagef("WORKDIR %s resolves to %s from %s", wd.Path, workdir, previous).
+				WithPos(wd.Pos()).
+				WithContext(ctx.GetLine(wd.Pos().Line)).
+				WithHelp("Use the absolute path: WORKDIR " + workdir).
+				WithFix("WORKDIR " + workdir).
+				Build()
+			diags = append(diags, diag)
+		}
+	}
+
+	return diags
+}
+
+// hasParentSegment reports whether a relative path contains a .. element
+func hasParentSegment(p string) bool {
+	if strings.HasPrefix(p, "/") {
+		return false
+	}
+	for _, part := range strings.Split(p, "/") {
+		if part == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	Register(&BP029WorkdirChain{})
+}
@@ -0,0 +1,77 @@
+package bestpractice
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBP029WorkdirChain(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "relative parent after absolute",
+			source:   "FROM node:20\nWORKDIR /app\nWORKDIR ../lib\n",
+			expected: 1,
+		},
+		{
+			name:     "parent in the middle of the path",
+			source:   "FROM node:20\nWORKDIR /app/src\nWORKDIR build/../../lib\n",
+			expected: 1,
+		},
+		{
+			name:     "relative child",
+			source:   "FROM node:20\nWORKDIR /app\nWORKDIR src\n",
+			expected: 0,
+		},
+		{
+			name:     "absolute workdirs",
+			source:   "FROM node:20\nWORKDIR /app\nWORKDIR /lib\n",
+			expected: 0,
+		},
+		{
+			name:     "no previous workdir",
+			source:   "FROM node:20\nWORKDIR ../lib\n",
+			expected: 0,
+		},
+		{
+			name:     "variable in path",
+			source:   "FROM node:20\nWORKDIR /app\nWORKDIR ../$LIB\n",
+			expected: 0,
+		},
+		{
+			name:     "inherited from base stage",
+			source:   "FROM node:20 AS base\nWORKDIR /app\n\nFROM base\nWORKDIR ../lib\n",
+			expected: 1,
+		},
+		{
+			name:     "not inherited from unrelated stage",
+			source:   "FROM node:20 AS base\nWORKDIR /app\n\nFROM node:20\nWORKDIR ../lib\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP029WorkdirChain{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d", tt.expected, len(diags))
+			}
+		})
+	}
+}
+
+func TestBP029WorkdirChain_ResolvedPath(t *testing.T) {
+	diags := runRule(t, &BP029WorkdirChain{}, "FROM node:20\nWORKDIR /app\nWORKDIR ../lib\n")
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(diags))
+	}
+	if !strings.Contains(diags[0].Message, "resolves to /lib") {
+		t.Errorf("expected the resolved path in the message, got %q", diags[0].Message)
+	}
+	if diags[0].FixSuggestion != "WORKDIR /lib" {
+		t.Errorf("expected the fix WORKDIR /lib, got %q", diags[0].FixSuggestion)
+	}
+}
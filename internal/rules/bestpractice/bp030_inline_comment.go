@@ -0,0 +1,66 @@
+package bestpractice
+
+import (
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// BP030InlineComment checks for ENV and ARG lines with a # after the value
+type BP030InlineComment struct{}
+
+func (r *BP030InlineComment) ID() string          { return "BP030" }
+func (r *BP030InlineComment) Name() string        { return "inline-comment" }
+func (r *BP030InlineComment) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP030InlineComment) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+
+func (r *BP030InlineComment) Description() string {
+	return "Docker only treats # as a comment at the start of a line. After an ENV or ARG value it becomes part of the value, or an invalid extra argument. Move the comment to its own line."
+}
+
+func (r *BP030InlineComment) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	check := func(inst parser.Instruction, name, trailing string) {
+		if trailing == "" {
+			return
+		}
+		diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+			WithSeverity(r.Severity()).
+			WithMessagef("%s is not a comment here; Docker reads it as part of the %s instruction", trailing, name).
+			WithPos(inst.Pos()).
+			WithContext(ctx.GetLine(inst.Pos().Line)).
+			WithHelp("Move the comment to its own line above the " + name + " instruction").
+			Build()
+		diags = append(diags, diag)
+	}
+
+	for _, arg := range df.Args {
+		check(arg, "ARG", arg.Trailing)
+	}
+
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			switch v := inst.(type) {
+			case *parser.EnvInstruction:
+				trailing := v.Trailing
+				if v.Legacy && len(v.Variables) == 1 {
+					// The legacy form takes the rest of the line as the value
+					if i := strings.Index(v.Variables[0].Value, " #"); i >= 0 {
+						trailing = v.Variables[0].Value[i+1:]
+					}
+				}
+				check(v, "ENV", trailing)
+			case *parser.ArgInstruction:
+				check(v, "ARG", v.Trailing)
+			}
+		}
+	}
+
+	return diags
+}
+
+func init() {
+	Register(&BP030InlineComment{})
+}
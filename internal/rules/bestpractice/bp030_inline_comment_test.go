@@ -0,0 +1,61 @@
+package bestpractice
+
+import "testing"
+
+func TestBP030InlineComment(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "env with trailing comment",
+			source:   "FROM alpine\nENV FOO=bar # x\n",
+			expected: 1,
+		},
+		{
+			name:     "comment on the next line",
+			source:   "FROM alpine\nENV FOO=bar\n# x\n",
+			expected: 0,
+		},
+		{
+			name:     "legacy env with trailing comment",
+			source:   "FROM alpine\nENV FOO bar # x\n",
+			expected: 1,
+		},
+		{
+			name:     "stage arg with trailing comment",
+			source:   "FROM alpine\nARG VERSION=1.0 # pinned\n",
+			expected: 1,
+		},
+		{
+			name:     "global arg with trailing comment",
+			source:   "ARG VERSION=1.0 # pinned\nFROM alpine:${VERSION}\n",
+			expected: 1,
+		},
+		{
+			name:     "hash inside a quoted value",
+			source:   "FROM alpine\nENV COLOR=\"#ff0000\"\n",
+			expected: 0,
+		},
+		{
+			name:     "hash inside a word",
+			source:   "FROM alpine\nENV CHANNEL=dev#2\n",
+			expected: 0,
+		},
+		{
+			name:     "run with trailing comment",
+			source:   "FROM alpine\nRUN echo hi # x\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP030InlineComment{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d", tt.expected, len(diags))
+			}
+		})
+	}
+}
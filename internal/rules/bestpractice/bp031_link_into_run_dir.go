@@ -0,0 +1,119 @@
+package bestpractice
+
+import (
+	"path"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// BP031LinkIntoRunDir checks for COPY --link into a directory created by an earlier RUN
+type BP031LinkIntoRunDir struct{}
+
+func (r *BP031LinkIntoRunDir) ID() string          { return "BP031" }
+func (r *BP031LinkIntoRunDir) Name() string        { return "link-into-run-dir" }
+func (r *BP031LinkIntoRunDir) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP031LinkIntoRunDir) Severity() analyzer.Severity { return analyzer.SeverityInfo }
+
+func (r *BP031LinkIntoRunDir) Description() string {
+	return "COPY --link writes its files to a layer of their own, without looking at the layers before it. Directories on the destination path are created afresh in that layer, replacing the owner and mode an earlier RUN gave them, and symlinks made by earlier layers are not followed."
+}
+
+func (r *BP031LinkIntoRunDir) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	workdirs := effectiveWorkdirs(df)
+
+	for _, stage := range df.Stages {
+		// Directories made by mkdir, and the line of the RUN that made them
+		made := make(map[string]int)
+		posix := true
+
+		for _, inst := range stage.Instructions {
+			switch v := inst.(type) {
+			case *parser.ShellInstruction:
+				posix = shell.IsPOSIX(v.Shell)
+			case *parser.RunInstruction:
+				if v.IsExec || v.Heredoc != nil || !posix {
+					continue
+				}
+				for _, dir := range mkdirTargets(v.Command, workdirs[v].Path) {
+					made[dir] = v.Pos().Line
+				}
+			case *parser.CopyInstruction:
+				if !v.Link || v.Destination == "" || strings.Contains(v.Destination, "$") {
+					continue
+				}
+				dest := v.Destination
+				if !path.IsAbs(dest) {
+					workdir := workdirs[v].Path
+					if workdir == "" {
+						continue
+					}
+					dest = path.Join(workdir, dest)
+				}
+				// A file destination lands in its parent directory
+				dir := path.Clean(dest)
+				if !strings.HasSuffix(v.Destination, "/") && len(v.Sources) == 1 {
+					dir = path.Dir(dir)
+				}
+
+				// The innermost created directory on the path
+				created := ""
+				for d := range made {
+					if (d == dir || isStrictParent(d, dir)) && len(d) > len(created) {
+						created = d
+					}
+				}
+				if created == "" {
+					continue
+				}
+
+				diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+					WithSeverity(r.Severity()).
+					WithMessagef("COPY --link into %s, which the RUN on line %d created; --link doesn't see earlier layers", created, made[created]).
+					WithPos(v.Pos()).
+					WithContext(ctx.GetLine(v.Pos().Line)).
+					WithHelp("Drop --link, or set the owner and mode in the COPY itself with --chown and --chmod").
+					Build()
+				diags = append(diags, diag)
+			}
+		}
+	}
+
+	return diags
+}
+
+// mkdirTargets returns every directory cmd creates with mkdir, resolved
+// against workdir. Paths with variables, and relative paths when the
+// WORKDIR is unknown, are skipped.
+func mkdirTargets(cmd, workdir string) []string {
+	var dirs []string
+
+	for _, c := range shell.Split(cmd) {
+		if c.Name() != "mkdir" {
+			continue
+		}
+		args := c.Args()
+		for i := 1; i < len(args); i++ {
+			a := args[i].Value
+			switch {
+			case a == "-m" || a == "--mode":
+				i++
+			case strings.HasPrefix(a, "-"), strings.Contains(a, "$"):
+			case path.IsAbs(a):
+				dirs = append(dirs, path.Clean(a))
+			case workdir != "":
+				dirs = append(dirs, path.Join(workdir, a))
+			}
+		}
+	}
+
+	return dirs
+}
+
+func init() {
+	Register(&BP031LinkIntoRunDir{})
+}
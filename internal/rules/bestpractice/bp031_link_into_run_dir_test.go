@@ -0,0 +1,61 @@
+package bestpractice
+
+import "testing"
+
+func TestBP031LinkIntoRunDir(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "link into a RUN-created directory",
+			source:   "FROM alpine\nRUN mkdir -p /app/data && chown app /app/data\nCOPY --link config.yml /app/data/\n",
+			expected: 1,
+		},
+		{
+			name:     "link into a subdirectory of a RUN-created directory",
+			source:   "FROM alpine\nRUN mkdir -m 0700 /secrets\nCOPY --link keys/ /secrets/keys/\n",
+			expected: 1,
+		},
+		{
+			name:     "file destination in a RUN-created directory",
+			source:   "FROM alpine\nRUN mkdir /etc/app\nCOPY --link app.conf /etc/app/app.conf\n",
+			expected: 1,
+		},
+		{
+			name:     "relative destination resolved against WORKDIR",
+			source:   "FROM alpine\nWORKDIR /srv\nRUN mkdir static\nCOPY --link public/ static/\n",
+			expected: 1,
+		},
+		{
+			name:     "link into a fresh absolute path",
+			source:   "FROM alpine\nRUN mkdir -p /app/data\nCOPY --link config.yml /opt/app/\n",
+			expected: 0,
+		},
+		{
+			name:     "without link",
+			source:   "FROM alpine\nRUN mkdir -p /app/data\nCOPY config.yml /app/data/\n",
+			expected: 0,
+		},
+		{
+			name:     "link into a parent of a RUN-created directory",
+			source:   "FROM alpine\nRUN mkdir -p /app/data\nCOPY --link main /app/\n",
+			expected: 0,
+		},
+		{
+			name:     "directory created in another stage",
+			source:   "FROM alpine AS build\nRUN mkdir /out\n\nFROM alpine\nCOPY --link --from=build /out /out/\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP031LinkIntoRunDir{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
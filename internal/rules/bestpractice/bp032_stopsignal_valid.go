@@ -0,0 +1,106 @@
+package bestpractice
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// BP032StopsignalValid checks that STOPSIGNAL names a valid signal
+type BP032StopsignalValid struct{}
+
+func (r *BP032StopsignalValid) ID() string          { return "BP032" }
+func (r *BP032StopsignalValid) Name() string        { return "stopsignal-valid" }
+func (r *BP032StopsignalValid) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP032StopsignalValid) Severity() analyzer.Severity { return analyzer.SeverityError }
+
+func (r *BP032StopsignalValid) Description() string {
+	return "STOPSIGNAL must be a signal name such as SIGTERM or a signal number from 1 to 64. Docker rejects unknown names, and the container can't be stopped gracefully with an out-of-range number."
+}
+
+// maxSignal is SIGRTMAX on Linux
+const maxSignal = 64
+
+// signalNames are the Linux signal names Docker accepts, without the SIG
+// prefix
+var signalNames = append([]string{
+	"ABRT", "ALRM", "BUS", "CHLD", "CLD", "CONT", "FPE", "HUP", "ILL", "INT",
+	"IO", "IOT", "KILL", "PIPE", "POLL", "PROF", "PWR", "QUIT", "SEGV",
+	"STKFLT", "STOP", "SYS", "TERM", "TRAP", "TSTP", "TTIN", "TTOU", "URG",
+	"USR1", "USR2", "VTALRM", "WINCH", "XCPU", "XFSZ", "RTMIN", "RTMAX",
+}, realtimeSignals()...)
+
+// realtimeSignals returns the names of the real-time signals relative to
+// RTMIN and RTMAX
+func realtimeSignals() []string {
+	var names []string
+	for i := 1; i <= 15; i++ {
+		names = append(names, "RTMIN+"+strconv.Itoa(i))
+	}
+	for i := 14; i >= 1; i-- {
+		names = append(names, "RTMAX-"+strconv.Itoa(i))
+	}
+	return names
+}
+
+func (r *BP032StopsignalValid) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			stop, ok := inst.(*parser.StopsignalInstruction)
+			if !ok || stop.Signal == "" || strings.Contains(stop.Signal, "$") {
+				continue
+			}
+
+			problem, suggestion := checkSignal(stop.Signal)
+			if problem == "" {
+				continue
+			}
+
+			builder := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithMessagef("Invalid STOPSIGNAL %q: %s", stop.Signal, problem).
+				WithRange(stop.Pos(), stop.End()).
+				WithContext(ctx.GetLine(stop.Pos().Line))
+			if suggestion != "" {
+				builder = builder.WithHelp("Did you mean STOPSIGNAL " + suggestion + "?")
+			} else {
+				builder = builder.WithHelp("Use a signal name such as SIGTERM or SIGQUIT, or a number from 1 to 64")
+			}
+			diags = append(diags, builder.Build())
+		}
+	}
+
+	return diags
+}
+
+// checkSignal validates a signal name or number, returning a description
+// of the problem and the signal that was probably meant, if any
+func checkSignal(signal string) (string, string) {
+	if n, err := strconv.Atoi(signal); err == nil {
+		if n < 1 || n > maxSignal {
+			return "signal numbers range from 1 to " + strconv.Itoa(maxSignal), ""
+		}
+		return "", ""
+	}
+
+	name := strings.TrimPrefix(strings.ToUpper(signal), "SIG")
+	for _, known := range signalNames {
+		if name == known {
+			return "", ""
+		}
+	}
+	// Signal names are short, so only a prefix or a single typo is a
+	// confident guess
+	if guess := closestMatch(name, signalNames); guess != "" && (strings.HasPrefix(guess, name) || editDistance(name, guess) == 1) {
+		return "unknown signal", "SIG" + guess
+	}
+	return "unknown signal", ""
+}
+
+func init() {
+	Register(&BP032StopsignalValid{})
+}
@@ -0,0 +1,91 @@
+package bestpractice
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBP032StopsignalValid(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "signal name",
+			source:   "FROM alpine\nSTOPSIGNAL SIGKILL\n",
+			expected: 0,
+		},
+		{
+			name:     "signal number",
+			source:   "FROM alpine\nSTOPSIGNAL 9\n",
+			expected: 0,
+		},
+		{
+			name:     "name without SIG prefix",
+			source:   "FROM alpine\nSTOPSIGNAL term\n",
+			expected: 0,
+		},
+		{
+			name:     "real-time signal",
+			source:   "FROM alpine\nSTOPSIGNAL SIGRTMIN+3\n",
+			expected: 0,
+		},
+		{
+			name:     "unknown name",
+			source:   "FROM alpine\nSTOPSIGNAL SIGFOO\n",
+			expected: 1,
+		},
+		{
+			name:     "not a signal",
+			source:   "FROM alpine\nSTOPSIGNAL abc\n",
+			expected: 1,
+		},
+		{
+			name:     "number out of range",
+			source:   "FROM alpine\nSTOPSIGNAL 99999\n",
+			expected: 1,
+		},
+		{
+			name:     "zero",
+			source:   "FROM alpine\nSTOPSIGNAL 0\n",
+			expected: 1,
+		},
+		{
+			name:     "variable",
+			source:   "FROM alpine\nARG SIG=SIGTERM\nSTOPSIGNAL $SIG\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP032StopsignalValid{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
+
+func TestBP032StopsignalValid_Suggestion(t *testing.T) {
+	tests := []struct {
+		signal string
+		help   string
+	}{
+		{"SIGTERMM", "Did you mean STOPSIGNAL SIGTERM?"},
+		{"SIGQIUT", "Use a signal name"},
+		{"SIGFOO", "Use a signal name"},
+		{"SIGWINC", "Did you mean STOPSIGNAL SIGWINCH?"},
+	}
+
+	for _, tt := range tests {
+		diags := runRule(t, &BP032StopsignalValid{}, "FROM alpine\nSTOPSIGNAL "+tt.signal+"\n")
+		if len(diags) != 1 {
+			t.Fatalf("%s: expected 1 diagnostic, got %v", tt.signal, diags)
+		}
+		if !strings.HasPrefix(diags[0].Help, tt.help) {
+			t.Errorf("%s: expected help %q, got %q", tt.signal, tt.help, diags[0].Help)
+		}
+	}
+}
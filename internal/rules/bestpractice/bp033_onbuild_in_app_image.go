@@ -0,0 +1,46 @@
+package bestpractice
+
+import (
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// BP033OnbuildInAppImage checks for ONBUILD, which only belongs in base images
+type BP033OnbuildInAppImage struct{}
+
+func (r *BP033OnbuildInAppImage) ID() string          { return "BP033" }
+func (r *BP033OnbuildInAppImage) Name() string        { return "onbuild-in-app-image" }
+func (r *BP033OnbuildInAppImage) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP033OnbuildInAppImage) Severity() analyzer.Severity { return analyzer.SeverityInfo }
+
+func (r *BP033OnbuildInAppImage) Description() string {
+	return "ONBUILD triggers don't run in this build; they run when another Dockerfile uses the image as its base. In an application image that is usually a mistake. Disable this rule when authoring base images."
+}
+
+func (r *BP033OnbuildInAppImage) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			onbuild, ok := inst.(*parser.OnbuildInstruction)
+			if !ok {
+				continue
+			}
+
+			diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithMessage("ONBUILD only runs in downstream builds that use this image as a base").
+				WithRange(onbuild.Pos(), onbuild.End()).
+				WithContext(ctx.GetLine(onbuild.Pos().Line)).
+				WithHelp("Run the instruction directly if it's meant for this image, or disable BP033 in .keel.yaml if this is a base image").
+				Build()
+			diags = append(diags, diag)
+		}
+	}
+
+	return diags
+}
+
+func init() {
+	Register(&BP033OnbuildInAppImage{})
+}
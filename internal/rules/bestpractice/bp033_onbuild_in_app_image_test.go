@@ -0,0 +1,36 @@
+package bestpractice
+
+import "testing"
+
+func TestBP033OnbuildInAppImage(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "onbuild present",
+			source:   "FROM node:20\nONBUILD COPY . /app\nCMD [\"node\", \"server.js\"]\n",
+			expected: 1,
+		},
+		{
+			name:     "onbuild absent",
+			source:   "FROM node:20\nCOPY . /app\nCMD [\"node\", \"server.js\"]\n",
+			expected: 0,
+		},
+		{
+			name:     "each trigger",
+			source:   "FROM node:20\nONBUILD COPY package.json /app/\nONBUILD RUN npm ci\n",
+			expected: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP033OnbuildInAppImage{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
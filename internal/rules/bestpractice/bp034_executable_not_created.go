@@ -0,0 +1,193 @@
+package bestpractice
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// BP034ExecutableNotCreated checks for an ENTRYPOINT or CMD running a path nothing in the image creates
+type BP034ExecutableNotCreated struct{}
+
+func (r *BP034ExecutableNotCreated) ID() string          { return "BP034" }
+func (r *BP034ExecutableNotCreated) Name() string        { return "executable-not-created" }
+func (r *BP034ExecutableNotCreated) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP034ExecutableNotCreated) Severity() analyzer.Severity { return analyzer.SeverityHint }
+
+func (r *BP034ExecutableNotCreated) Description() string {
+	return "The exec-form ENTRYPOINT or CMD runs a path that no COPY, ADD, or RUN in the final stage writes, which usually means a typo or a missing COPY. The base image may provide it, so this is only a hint; paths under system directories are not checked."
+}
+
+func (r *BP034ExecutableNotCreated) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	if len(df.Stages) == 0 {
+		return diags
+	}
+
+	byName := make(map[string]*parser.Stage)
+	for _, stage := range df.Stages {
+		if stage.Name != "" {
+			byName[strings.ToLower(stage.Name)] = stage
+		}
+	}
+
+	// The final stage and the stages it is built FROM, base first
+	final := df.Stages[len(df.Stages)-1]
+	chain := []*parser.Stage{final}
+	seen := map[*parser.Stage]bool{final: true}
+	for parent := parentStage(final, byName); parent != nil && !seen[parent]; parent = parentStage(parent, byName) {
+		seen[parent] = true
+		chain = append(chain, parent)
+	}
+	slices.Reverse(chain)
+
+	// Paths written by COPY, ADD, and RUN; a directory covers everything under it
+	var written []string
+	workdirs := effectiveWorkdirs(df)
+	var entrypoint *parser.EntrypointInstruction
+	var cmd *parser.CmdInstruction
+	for _, stage := range chain {
+		for _, inst := range stage.Instructions {
+			workdir := workdirs[inst].Path
+			switch v := inst.(type) {
+			case *parser.CopyInstruction:
+				written = append(written, copyTargets(workdir, v.Sources, v.Destination)...)
+			case *parser.AddInstruction:
+				written = append(written, copyTargets(workdir, v.Sources, v.Destination)...)
+			case *parser.RunInstruction:
+				written = append(written, runPaths(v, workdir)...)
+			case *parser.EntrypointInstruction:
+				entrypoint = v
+			case *parser.CmdInstruction:
+				cmd = v
+			}
+		}
+	}
+
+	// With an ENTRYPOINT, CMD only supplies its arguments
+	var inst parser.Instruction
+	var name string
+	var args []string
+	switch {
+	case entrypoint != nil && entrypoint.IsExec:
+		inst, name, args = entrypoint, "ENTRYPOINT", entrypoint.Arguments
+	case entrypoint == nil && cmd != nil && cmd.IsExec:
+		inst, name, args = cmd, "CMD", cmd.Arguments
+	}
+	if len(args) == 0 {
+		return diags
+	}
+
+	// Bare names are looked up in PATH
+	exe := args[0]
+	if strings.Contains(exe, "$") || !strings.Contains(exe, "/") {
+		return diags
+	}
+	target := resolveWorkdir(finalWorkdir(chain, workdirs), exe)
+	if target == "" || isSystemDir(target) || isWritten(target, written) {
+		return diags
+	}
+
+	diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+		WithSeverity(r.Severity()).
+		WithMessagef("%s runs %s, which no COPY, ADD, or RUN creates", name, exe).
+		WithPos(inst.Pos()).
+		WithContext(ctx.GetLine(inst.Pos().Line)).
+		WithHelp("Check the path, or COPY the executable into the image; ignore this if the base image provides it").
+		Build()
+	diags = append(diags, diag)
+
+	return diags
+}
+
+// finalWorkdir returns the WORKDIR the container starts in, the one the
+// last instruction of the chain leaves in effect
+func finalWorkdir(chain []*parser.Stage, workdirs map[parser.Instruction]workdirState) string {
+	for i := len(chain) - 1; i >= 0; i-- {
+		insts := chain[i].Instructions
+		if len(insts) == 0 {
+			continue
+		}
+		last := insts[len(insts)-1]
+		if wd, ok := last.(*parser.WorkdirInstruction); ok {
+			return resolveWorkdir(workdirs[last].Path, wd.Path)
+		}
+		return workdirs[last].Path
+	}
+	return ""
+}
+
+// copyTargets returns the paths a COPY or ADD writes: the destination,
+// and for a directory destination the file each source lands at
+func copyTargets(workdir string, sources []string, dest string) []string {
+	if dest == "" || strings.Contains(dest, "$") {
+		return nil
+	}
+	base := resolveWorkdir(workdir, dest)
+	if base == "" {
+		return nil
+	}
+	targets := []string{base}
+	if strings.HasSuffix(dest, "/") || len(sources) > 1 {
+		for _, src := range sources {
+			targets = append(targets, path.Join(base, path.Base(src)))
+		}
+	}
+	return targets
+}
+
+// runPaths returns the paths named by a RUN's arguments, resolved
+// against the working directory, such as the output of go build -o
+func runPaths(run *parser.RunInstruction, workdir string) []string {
+	var words []string
+	if run.IsExec {
+		words = run.Arguments
+	} else {
+		cmd := run.Command
+		if run.Heredoc != nil {
+			cmd = run.Heredoc.Content
+		}
+		for _, c := range shell.Split(cmd) {
+			for _, w := range c.Args() {
+				words = append(words, w.Value)
+			}
+		}
+	}
+
+	var paths []string
+	for _, w := range words {
+		// Values of options such as --output=/app/server
+		if _, v, ok := strings.Cut(w, "="); ok && strings.HasPrefix(w, "-") {
+			w = v
+		}
+		if w == "" || strings.HasPrefix(w, "-") || strings.Contains(w, "$") {
+			continue
+		}
+		if p := resolveWorkdir(workdir, w); p != "" && p != "/" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// isWritten reports whether target, or a directory containing it, was written
+func isWritten(target string, written []string) bool {
+	for _, w := range written {
+		if w == target || isStrictParent(w, target) {
+			return true
+		}
+		if matched, _ := path.Match(w, target); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	Register(&BP034ExecutableNotCreated{})
+}
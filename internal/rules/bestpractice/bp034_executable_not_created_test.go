@@ -0,0 +1,91 @@
+package bestpractice
+
+import "testing"
+
+func TestBP034ExecutableNotCreated(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "CMD of a copied binary",
+			source:   "FROM golang:1.25 AS build\nRUN go build -o /out/server .\nFROM alpine:3.20\nCOPY --from=build /out/server /app/server\nCMD [\"/app/server\"]\n",
+			expected: 0,
+		},
+		{
+			name:     "CMD of a never-created path",
+			source:   "FROM alpine:3.20\nCOPY server /app/server\nCMD [\"/app/sever\"]\n",
+			expected: 1,
+		},
+		{
+			name:     "ENTRYPOINT of a never-created path",
+			source:   "FROM alpine:3.20\nENTRYPOINT [\"/app/server\"]\nCMD [\"--help\"]\n",
+			expected: 1,
+		},
+		{
+			name:     "copied into a directory",
+			source:   "FROM alpine:3.20\nCOPY server config.yaml /app/\nENTRYPOINT [\"/app/server\"]\n",
+			expected: 0,
+		},
+		{
+			name:     "directory copied whole",
+			source:   "FROM alpine:3.20\nWORKDIR /app\nCOPY . .\nCMD [\"./bin/server\"]\n",
+			expected: 0,
+		},
+		{
+			name:     "built by RUN in the working directory",
+			source:   "FROM golang:1.25\nWORKDIR /src\nCOPY go.mod main.go ./\nRUN go build -o server .\nCMD [\"/src/server\"]\n",
+			expected: 0,
+		},
+		{
+			name:     "extracted by RUN",
+			source:   "FROM alpine:3.20\nRUN mkdir /opt/tool && wget -qO- https://example.com/tool.tgz | tar -xz -C /opt/tool\nENTRYPOINT [\"/opt/tool/bin/tool\"]\n",
+			expected: 0,
+		},
+		{
+			name:     "inherited from a parent stage",
+			source:   "FROM alpine:3.20 AS base\nCOPY server /app/server\nFROM base\nCMD [\"/app/server\"]\n",
+			expected: 0,
+		},
+		{
+			name:     "relative to a WORKDIR inherited from a parent stage",
+			source:   "FROM alpine:3.20 AS base\nWORKDIR /app\nCOPY server .\nFROM base AS app\nCMD [\"./server\"]\n",
+			expected: 0,
+		},
+		{
+			name:     "WORKDIR inherited from a parent stage",
+			source:   "FROM alpine:3.20 AS base\nWORKDIR /app\nFROM base AS app\nCOPY server /srv/server\nCMD [\"./server\"]\n",
+			expected: 1,
+		},
+		{
+			name:     "name looked up in PATH",
+			source:   "FROM node:20\nCMD [\"node\", \"index.js\"]\n",
+			expected: 0,
+		},
+		{
+			name:     "system directory",
+			source:   "FROM python:3.12\nCMD [\"/usr/local/bin/python\", \"app.py\"]\n",
+			expected: 0,
+		},
+		{
+			name:     "shell form",
+			source:   "FROM alpine:3.20\nCMD /app/server\n",
+			expected: 0,
+		},
+		{
+			name:     "variable",
+			source:   "FROM alpine:3.20\nENV BIN=/app/server\nCMD [\"$BIN\"]\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP034ExecutableNotCreated{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package bestpractice
+
+import (
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// BP035UnusedVariable checks for ARGs that are never referenced and ENVs overwritten before use
+type BP035UnusedVariable struct{}
+
+func (r *BP035UnusedVariable) ID() string          { return "BP035" }
+func (r *BP035UnusedVariable) Name() string        { return "unused-variable" }
+func (r *BP035UnusedVariable) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP035UnusedVariable) Severity() analyzer.Severity { return analyzer.SeverityHint }
+
+func (r *BP035UnusedVariable) Description() string {
+	return "An ARG that nothing after it references, or an ENV set again before anything reads it, is dead code. ENVs are otherwise left alone, since programs read them from the environment without a $ reference."
+}
+
+// ARGs that tools read from the environment of RUN, so declaring one is
+// a use in itself
+var implicitArgs = map[string]bool{
+	"DEBIAN_FRONTEND": true, "SOURCE_DATE_EPOCH": true,
+	"HTTP_PROXY": true, "HTTPS_PROXY": true, "FTP_PROXY": true, "NO_PROXY": true, "ALL_PROXY": true,
+}
+
+func isImplicitArg(name string) bool {
+	upper := strings.ToUpper(name)
+	return implicitArgs[upper] || strings.HasPrefix(upper, "BUILDKIT_")
+}
+
+func (r *BP035UnusedVariable) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	// refs returns the variables referenced anywhere in an instruction's source
+	refs := func(inst parser.Instruction) map[string]bool {
+		found := make(map[string]bool)
+		for line := inst.Pos().Line; line <= inst.End().Line; line++ {
+			for _, v := range shell.Vars(ctx.GetLine(line)) {
+				found[v] = true
+			}
+		}
+		return found
+	}
+
+	// A global ARG is used by a FROM, a later global ARG, or a stage
+	// that redeclares it
+	globalUsed := make(map[string]bool)
+	for _, stage := range df.Stages {
+		if stage.From != nil {
+			for v := range refs(stage.From) {
+				globalUsed[v] = true
+			}
+		}
+		for _, inst := range stage.Instructions {
+			if arg, ok := inst.(*parser.ArgInstruction); ok {
+				globalUsed[arg.Name] = true
+			}
+		}
+	}
+	for i, arg := range df.Args {
+		used := globalUsed[arg.Name] || isImplicitArg(arg.Name)
+		for _, later := range df.Args[i+1:] {
+			used = used || refs(later)[arg.Name]
+		}
+		if used {
+			continue
+		}
+		diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+			WithSeverity(r.Severity()).
+			WithMessagef("Global ARG %s is not used by any FROM or stage", arg.Name).
+			WithPos(arg.Pos()).
+			WithContext(ctx.GetLine(arg.Pos().Line)).
+			WithHelp("Remove it, or redeclare it with ARG " + arg.Name + " in the stages that need it").
+			Build()
+		diags = append(diags, diag)
+	}
+
+	for _, stage := range df.Stages {
+		stageRefs := make([]map[string]bool, len(stage.Instructions))
+		for i, inst := range stage.Instructions {
+			stageRefs[i] = refs(inst)
+		}
+		// usedIn reports whether instructions from..to (inclusive) reference name
+		usedIn := func(name string, from, to int) bool {
+			for i := from; i <= to && i < len(stageRefs); i++ {
+				if stageRefs[i][name] {
+					return true
+				}
+			}
+			return false
+		}
+
+		for i, inst := range stage.Instructions {
+			switch v := inst.(type) {
+			case *parser.ArgInstruction:
+				if isImplicitArg(v.Name) || usedIn(v.Name, i+1, len(stageRefs)-1) {
+					continue
+				}
+				diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+					WithSeverity(r.Severity()).
+					WithMessagef("ARG %s is declared but never used", v.Name).
+					WithPos(v.Pos()).
+					WithContext(ctx.GetLine(v.Pos().Line)).
+					WithHelp("Remove the ARG, or reference it as $" + v.Name + " where it is needed").
+					Build()
+				diags = append(diags, diag)
+
+			case *parser.EnvInstruction:
+				for _, kv := range v.Variables {
+					next, line := nextEnv(stage.Instructions[i+1:], kv.Key)
+					if next < 0 || usedIn(kv.Key, i+1, i+1+next) {
+						continue
+					}
+					diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+						WithSeverity(r.Severity()).
+						WithMessagef("ENV %s is set again on line %d before it is used", kv.Key, line).
+						WithPos(v.Pos()).
+						WithContext(ctx.GetLine(v.Pos().Line)).
+						WithHelp("Remove the first ENV " + kv.Key + ", which has no effect").
+						Build()
+					diags = append(diags, diag)
+				}
+			}
+		}
+	}
+
+	return diags
+}
+
+// nextEnv returns the index in insts of the next ENV setting key, and
+// its line, or -1 if there is none
+func nextEnv(insts []parser.Instruction, key string) (int, int) {
+	for i, inst := range insts {
+		env, ok := inst.(*parser.EnvInstruction)
+		if !ok {
+			continue
+		}
+		for _, kv := range env.Variables {
+			if kv.Key == key {
+				return i, env.Pos().Line
+			}
+		}
+	}
+	return -1, 0
+}
+
+func init() {
+	Register(&BP035UnusedVariable{})
+}
@@ -0,0 +1,96 @@
+package bestpractice
+
+import "testing"
+
+func TestBP035UnusedVariable(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "unused ARG",
+			source:   "FROM alpine:3.20\nARG VERSION=1.0\nRUN echo hello\n",
+			expected: 1,
+		},
+		{
+			name:     "used ARG",
+			source:   "FROM alpine:3.20\nARG VERSION=1.0\nRUN echo ${VERSION}\n",
+			expected: 0,
+		},
+		{
+			name:     "ARG used in WORKDIR",
+			source:   "FROM alpine:3.20\nARG APP\nWORKDIR /srv/$APP\n",
+			expected: 0,
+		},
+		{
+			name:     "ARG used in a heredoc",
+			source:   "FROM alpine:3.20\nARG VERSION\nRUN <<EOF\necho \"$VERSION\"\nEOF\n",
+			expected: 0,
+		},
+		{
+			name:     "ARG used only before it is declared",
+			source:   "FROM alpine:3.20\nRUN echo $VERSION\nARG VERSION\n",
+			expected: 1,
+		},
+		{
+			name:     "ARG used in another stage",
+			source:   "FROM alpine:3.20 AS build\nARG VERSION\nRUN true\nFROM alpine:3.20\nARG VERSION\nRUN echo $VERSION\n",
+			expected: 1,
+		},
+		{
+			name:     "ARG read from the environment",
+			source:   "FROM debian:12\nARG DEBIAN_FRONTEND=noninteractive\nRUN apt-get update\n",
+			expected: 0,
+		},
+		{
+			name:     "global ARG used by FROM",
+			source:   "ARG BASE=alpine:3.20\nFROM ${BASE}\n",
+			expected: 0,
+		},
+		{
+			name:     "global ARG redeclared in a stage",
+			source:   "ARG VERSION=1.0\nFROM alpine:3.20\nARG VERSION\nRUN echo $VERSION\n",
+			expected: 0,
+		},
+		{
+			name:     "global ARG used by a later global ARG",
+			source:   "ARG TAG=3.20\nARG BASE=alpine:${TAG}\nFROM $BASE\n",
+			expected: 0,
+		},
+		{
+			name:     "unused global ARG",
+			source:   "ARG VERSION=1.0\nFROM alpine:3.20\n",
+			expected: 1,
+		},
+		{
+			name:     "ENV without references",
+			source:   "FROM python:3.12\nENV PYTHONUNBUFFERED=1\nCMD [\"python\", \"app.py\"]\n",
+			expected: 0,
+		},
+		{
+			name:     "ENV overwritten before use",
+			source:   "FROM node:20\nENV NODE_ENV=development\nENV NODE_ENV=production\n",
+			expected: 1,
+		},
+		{
+			name:     "ENV used by its redefinition",
+			source:   "FROM alpine:3.20\nENV PATH=/opt/a/bin:$PATH\nENV PATH=/opt/b/bin:$PATH\n",
+			expected: 0,
+		},
+		{
+			name:     "ENV used before being overwritten",
+			source:   "FROM node:20\nENV DIR=/a\nRUN mkdir $DIR\nENV DIR=/b\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP035UnusedVariable{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package bestpractice
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// BP036SourceOutsideContext checks for COPY and ADD sources that use ..
+// to reach outside the build context
+type BP036SourceOutsideContext struct{}
+
+func (r *BP036SourceOutsideContext) ID() string          { return "BP036" }
+func (r *BP036SourceOutsideContext) Name() string        { return "source-outside-context" }
+func (r *BP036SourceOutsideContext) Category() analyzer.Category { return analyzer.CategoryBestPractice }
+func (r *BP036SourceOutsideContext) Severity() analyzer.Severity { return analyzer.SeverityError }
+
+func (r *BP036SourceOutsideContext) Description() string {
+	return "COPY and ADD can only read files inside the build context. A source such as ../shared/config climbs out of it, so the build fails or copies a different file than intended."
+}
+
+func (r *BP036SourceOutsideContext) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			var sources []string
+			var name string
+
+			switch v := inst.(type) {
+			case *parser.CopyInstruction:
+				// Sources copied from another stage or image are paths in
+				// that filesystem, not in the build context
+				if v.From != "" {
+					continue
+				}
+				sources, name = v.Sources, "COPY"
+			case *parser.AddInstruction:
+				sources, name = v.Sources, "ADD"
+			default:
+				continue
+			}
+
+			for _, src := range sources {
+				if !escapesContext(src) {
+					continue
+				}
+				diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+					WithSeverity(r.Severity()).
+					WithMessagef("%s source %q is outside the build context", name, src).
+					WithPos(inst.Pos()).
+					WithContext(ctx.GetLine(inst.Pos().Line)).
+					WithHelp(fmt.Sprintf("Build from a parent directory so that %q is inside the context, or pass it as a named context (docker build --build-context) and COPY --from it", path.Clean(src))).
+					Build()
+				diags = append(diags, diag)
+			}
+		}
+	}
+
+	return diags
+}
+
+// escapesContext reports whether a context-relative source climbs above
+// the context root. Sources such as src/../lib that stay inside are
+// fine, as are URLs and variables.
+func escapesContext(src string) bool {
+	if strings.Contains(src, "://") || strings.HasPrefix(src, "git@") || strings.Contains(src, "$") {
+		return false
+	}
+	clean := path.Clean(src)
+	return clean == ".." || strings.HasPrefix(clean, "../")
+}
+
+func init() {
+	Register(&BP036SourceOutsideContext{})
+}
@@ -0,0 +1,71 @@
+package bestpractice
+
+import "testing"
+
+func TestBP036SourceOutsideContext(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "parent directory",
+			source:   "FROM alpine:3.20\nCOPY ../secret /x\n",
+			expected: 1,
+		},
+		{
+			name:     "relative source",
+			source:   "FROM alpine:3.20\nCOPY src /x\n",
+			expected: 0,
+		},
+		{
+			name:     "climbs back inside",
+			source:   "FROM alpine:3.20\nCOPY src/../lib /x\n",
+			expected: 0,
+		},
+		{
+			name:     "climbs out after a directory",
+			source:   "FROM alpine:3.20\nCOPY src/../../shared /x\n",
+			expected: 1,
+		},
+		{
+			name:     "bare parent",
+			source:   "FROM alpine:3.20\nCOPY .. /x\n",
+			expected: 1,
+		},
+		{
+			name:     "one of several sources",
+			source:   "FROM alpine:3.20\nCOPY go.mod ../go.sum /src/\n",
+			expected: 1,
+		},
+		{
+			name:     "add",
+			source:   "FROM alpine:3.20\nADD ../vendor.tar.gz /opt/\n",
+			expected: 1,
+		},
+		{
+			name:     "add url",
+			source:   "FROM alpine:3.20\nADD https://example.com/a/../b.tar.gz /opt/\n",
+			expected: 0,
+		},
+		{
+			name:     "copy from stage",
+			source:   "FROM golang:1.22 AS build\nFROM alpine:3.20\nCOPY --from=build ../app /app\n",
+			expected: 0,
+		},
+		{
+			name:     "file name with dots",
+			source:   "FROM alpine:3.20\nCOPY ..config /x\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &BP036SourceOutsideContext{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
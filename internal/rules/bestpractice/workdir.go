@@ -0,0 +1,62 @@
+package bestpractice
+
+import (
+	"path"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// workdirState is the WORKDIR in effect for an instruction
+type workdirState struct {
+	// Path is the working directory, or "" if it can't be known statically
+	Path string
+
+	// Set reports whether a WORKDIR instruction has run before, in the
+	// stage or in a stage it is built on
+	Set bool
+}
+
+// effectiveWorkdirs returns the WORKDIR in effect when each instruction
+// of df runs. For a WORKDIR instruction that is the directory it resolves
+// against, not the one it sets. A stage built on another stage inherits
+// the WORKDIR that stage ended with.
+func effectiveWorkdirs(df *parser.Dockerfile) map[parser.Instruction]workdirState {
+	states := make(map[parser.Instruction]workdirState)
+	ends := make(map[string]workdirState)
+
+	for _, stage := range df.Stages {
+		var state workdirState
+		if stage.From != nil && stage.From.BaseStage != "" {
+			state = ends[strings.ToLower(stage.From.BaseStage)]
+		}
+
+		for _, inst := range stage.Instructions {
+			states[inst] = state
+			if wd, ok := inst.(*parser.WorkdirInstruction); ok {
+				state = workdirState{Path: resolveWorkdir(state.Path, wd.Path), Set: true}
+			}
+		}
+
+		if stage.Name != "" {
+			ends[strings.ToLower(stage.Name)] = state
+		}
+	}
+
+	return states
+}
+
+// resolveWorkdir returns the WORKDIR after setting it to p, or "" if it
+// can't be known statically
+func resolveWorkdir(current, p string) string {
+	if strings.Contains(p, "$") {
+		return ""
+	}
+	if path.IsAbs(p) {
+		return path.Clean(p)
+	}
+	if current == "" {
+		return ""
+	}
+	return path.Join(current, p)
+}
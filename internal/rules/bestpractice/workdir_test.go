@@ -0,0 +1,51 @@
+package bestpractice
+
+import (
+	"testing"
+
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+func TestEffectiveWorkdirs(t *testing.T) {
+	src := `FROM alpine:3.19 AS base
+RUN ./setup.sh
+WORKDIR /app
+WORKDIR src
+RUN make
+
+FROM base AS build
+COPY . .
+WORKDIR $HOME
+
+FROM build
+RUN true
+
+FROM alpine:3.19
+RUN true
+`
+	df, errs := parser.Parse(src)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected parse errors: %v", errs)
+	}
+	workdirs := effectiveWorkdirs(df)
+
+	tests := []struct {
+		stage, inst int
+		expected    workdirState
+	}{
+		{0, 0, workdirState{}},
+		{0, 1, workdirState{}},
+		{0, 2, workdirState{Path: "/app", Set: true}},
+		{0, 3, workdirState{Path: "/app/src", Set: true}},
+		{1, 0, workdirState{Path: "/app/src", Set: true}},
+		{2, 0, workdirState{Set: true}},
+		{3, 0, workdirState{}},
+	}
+
+	for _, tt := range tests {
+		inst := df.Stages[tt.stage].Instructions[tt.inst]
+		if got := workdirs[inst]; got != tt.expected {
+			t.Errorf("stage %d instruction %d: expected %+v, got %+v", tt.stage, tt.inst, tt.expected, got)
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package performance
 
 import (
+	"strings"
+
 	"github.com/HueCodes/keel/internal/analyzer"
 	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
 )
 
 // PERF001CopyOrder checks for COPY/ADD before RUN that could invalidate cache
@@ -73,7 +76,7 @@ func isDependencyInstall(cmd string) bool {
 	}
 
 	for _, pattern := range installPatterns {
-		if containsSubstring(cmd, pattern) {
+		if shell.HasCommand(cmd, strings.Fields(pattern)...) {
 			return true
 		}
 	}
@@ -89,19 +92,6 @@ func isBroadCopy(sources []string) bool {
 	return false
 }
 
-func containsSubstring(s, substr string) bool {
-	return len(s) >= len(substr) && findSubstring(s, substr) >= 0
-}
-
-func findSubstring(s, substr string) int {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return i
-		}
-	}
-	return -1
-}
-
 func init() {
 	Register(&PERF001CopyOrder{})
 }
@@ -14,6 +14,7 @@ func (r *PERF003CacheCleanup) ID() string          { return "PERF003" }
 func (r *PERF003CacheCleanup) Name() string        { return "cache-not-cleaned" }
 func (r *PERF003CacheCleanup) Category() analyzer.Category { return analyzer.CategoryPerformance }
 func (r *PERF003CacheCleanup) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+func (r *PERF003CacheCleanup) Tags() []string { return []string{"layers"} }
 
 func (r *PERF003CacheCleanup) Description() string {
 	return "Package manager cache should be cleaned in the same RUN instruction to reduce layer size."
@@ -12,6 +12,7 @@ func (r *PERF004ConsecutiveRun) ID() string          { return "PERF004" }
 func (r *PERF004ConsecutiveRun) Name() string        { return "consecutive-run" }
 func (r *PERF004ConsecutiveRun) Category() analyzer.Category { return analyzer.CategoryPerformance }
 func (r *PERF004ConsecutiveRun) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+func (r *PERF004ConsecutiveRun) Tags() []string { return []string{"layers"} }
 
 func (r *PERF004ConsecutiveRun) Description() string {
 	return "Consecutive RUN instructions create multiple layers. Merge them to reduce image size."
@@ -0,0 +1,101 @@
+package performance
+
+import (
+	"path"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/lexer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// PERF007CopyDependencyDirs checks for copying dependency or VCS directories
+type PERF007CopyDependencyDirs struct{}
+
+func (r *PERF007CopyDependencyDirs) ID() string          { return "PERF007" }
+func (r *PERF007CopyDependencyDirs) Name() string        { return "copy-dependency-dirs" }
+func (r *PERF007CopyDependencyDirs) Category() analyzer.Category { return analyzer.CategoryPerformance }
+func (r *PERF007CopyDependencyDirs) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+
+func (r *PERF007CopyDependencyDirs) Description() string {
+	return "Dependency directories and VCS metadata should not be copied into the image. Exclude them with .dockerignore."
+}
+
+// Directories flagged by default; override with the "directories" config key
+var defaultDependencyDirs = []string{"node_modules", "vendor", ".git", "__pycache__", "target"}
+
+func (r *PERF007CopyDependencyDirs) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	dirs := configuredList(ctx.Config["directories"], defaultDependencyDirs)
+
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			var sources []string
+			var pos lexer.Position
+			var name string
+
+			switch v := inst.(type) {
+			case *parser.CopyInstruction:
+				// Copies from other stages are deliberate
+				if v.From != "" {
+					continue
+				}
+				sources, pos, name = v.Sources, v.Pos(), "COPY"
+			case *parser.AddInstruction:
+				sources, pos, name = v.Sources, v.Pos(), "ADD"
+			default:
+				continue
+			}
+
+			for _, src := range sources {
+				dir := matchDependencyDir(src, dirs)
+				if dir == "" {
+					continue
+				}
+				diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+					WithSeverity(r.Severity()).
+					WithMessagef("%s copies %s into the image", name, dir).
+					WithPos(pos).
+					WithContext(ctx.GetLine(pos.Line)).
+					WithHelp("Add " + dir + " to .dockerignore and install or build dependencies inside the image instead").
+					Build()
+				diags = append(diags, diag)
+			}
+		}
+	}
+
+	return diags
+}
+
+// configuredList reads a list of strings from rule config, falling back to defaults
+func configuredList(v interface{}, defaults []string) []string {
+	switch list := v.(type) {
+	case []string:
+		return list
+	case []interface{}:
+		var result []string
+		for _, d := range list {
+			if s, ok := d.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return defaults
+}
+
+// matchDependencyDir returns the directory name src is or ends in, if any
+func matchDependencyDir(src string, dirs []string) string {
+	base := path.Base(strings.TrimRight(src, "/"))
+	for _, dir := range dirs {
+		if base == dir {
+			return dir
+		}
+	}
+	return ""
+}
+
+func init() {
+	Register(&PERF007CopyDependencyDirs{})
+}
@@ -0,0 +1,48 @@
+package performance
+
+import (
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+func TestPERF007CopyDependencyDirs(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{"node_modules", "FROM node:20\nCOPY node_modules /app/node_modules\n", 1},
+		{"nested vendor", "FROM golang:1.22\nCOPY ./app/vendor/ /src/vendor\n", 1},
+		{"git directory via ADD", "FROM alpine:3.18\nADD .git /src/.git\n", 1},
+		{"source directory", "FROM node:20\nCOPY src /app\n", 0},
+		{"copy from build stage", "FROM node:20\nCOPY --from=build /app/node_modules /app/node_modules\n", 0},
+		{"similar name", "FROM node:20\nCOPY node_modules_backup.txt /app\n", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &PERF007CopyDependencyDirs{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
+
+func TestPERF007CopyDependencyDirs_Configured(t *testing.T) {
+	a := analyzer.New(
+		analyzer.WithRules(&PERF007CopyDependencyDirs{}),
+		analyzer.WithRuleConfig("PERF007", map[string]interface{}{
+			"directories": []interface{}{"dist"},
+		}),
+	)
+
+	result, _ := a.AnalyzeSource("FROM node:20\nCOPY dist /app/dist\nCOPY node_modules /app\n", "Dockerfile")
+	if len(result.Diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(result.Diagnostics))
+	}
+	if result.Diagnostics[0].Pos.Line != 2 {
+		t.Errorf("expected diagnostic on line 2, got %d", result.Diagnostics[0].Pos.Line)
+	}
+}
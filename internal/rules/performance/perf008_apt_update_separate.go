@@ -0,0 +1,102 @@
+package performance
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// PERF008AptUpdateSeparate checks for apt-get update in a RUN without an install
+type PERF008AptUpdateSeparate struct{}
+
+func (r *PERF008AptUpdateSeparate) ID() string          { return "PERF008" }
+func (r *PERF008AptUpdateSeparate) Name() string        { return "apt-update-separate" }
+func (r *PERF008AptUpdateSeparate) Category() analyzer.Category { return analyzer.CategoryPerformance }
+func (r *PERF008AptUpdateSeparate) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+
+func (r *PERF008AptUpdateSeparate) Description() string {
+	return "apt-get update in its own RUN is cached separately from the install that needs it, so later builds install from a stale package index."
+}
+
+func (r *PERF008AptUpdateSeparate) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for _, stage := range df.Stages {
+		posix := true
+
+		for i, inst := range stage.Instructions {
+			if sh, ok := inst.(*parser.ShellInstruction); ok {
+				posix = shell.IsPOSIX(sh.Shell)
+			}
+
+			run, ok := inst.(*parser.RunInstruction)
+			if !ok || run.IsExec || !posix {
+				continue
+			}
+
+			cmd := run.Command
+			if run.Heredoc != nil {
+				cmd = run.Heredoc.Content
+			}
+			update, install := AptUpdateInstall(cmd)
+			if !update || install {
+				continue
+			}
+
+			builder := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithMessage("apt-get update runs in a separate RUN from apt-get install").
+				WithPos(run.Pos()).
+				WithContext(ctx.GetLine(run.Pos().Line)).
+				WithHelp("Chain the update with the install in one RUN: apt-get update && apt-get install -y ...")
+
+			// Offer the merged instruction when the install comes right after
+			if i+1 < len(stage.Instructions) {
+				if next, ok := stage.Instructions[i+1].(*parser.RunInstruction); ok && sameRunFlags(run, next) && run.Heredoc == nil && next.Heredoc == nil && !next.IsExec {
+					if _, install := AptUpdateInstall(next.Command); install {
+						merged := *run
+						merged.Command = shell.Group(strings.TrimSpace(run.Command)) + " && " + shell.Group(strings.TrimSpace(next.Command))
+						builder = builder.WithRange(run.Pos(), next.End()).WithFix(parser.Render(&merged))
+					}
+				}
+			}
+
+			diags = append(diags, builder.Build())
+		}
+	}
+
+	return diags
+}
+
+// AptUpdateInstall reports whether cmd runs apt-get update, and whether
+// it runs a command that uses the index, such as apt-get install
+func AptUpdateInstall(cmd string) (update, install bool) {
+	for _, c := range shell.Split(cmd) {
+		switch path.Base(c.Name()) {
+		case "apt-get", "apt":
+		default:
+			continue
+		}
+		switch c.Subcommand("-o", "-c", "-t") {
+		case "update":
+			update = true
+		case "install", "upgrade", "dist-upgrade", "full-upgrade", "build-dep":
+			install = true
+		}
+	}
+	return update, install
+}
+
+// sameRunFlags reports whether two RUNs have the same --mount, --network,
+// and --security flags
+func sameRunFlags(a, b *parser.RunInstruction) bool {
+	return slices.Equal(a.Mounts, b.Mounts) && a.Network == b.Network && a.Security == b.Security
+}
+
+func init() {
+	Register(&PERF008AptUpdateSeparate{})
+}
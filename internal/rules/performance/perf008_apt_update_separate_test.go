@@ -0,0 +1,84 @@
+package performance
+
+import (
+	"testing"
+)
+
+func TestPERF008AptUpdateSeparate(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "separate update and install",
+			source:   "FROM debian:12\nRUN apt-get update\nRUN apt-get install -y curl\n",
+			expected: 1,
+		},
+		{
+			name:     "combined update and install",
+			source:   "FROM debian:12\nRUN apt-get update && apt-get install -y curl\n",
+			expected: 0,
+		},
+		{
+			name:     "update with flags",
+			source:   "FROM debian:12\nRUN apt-get -qq update\nRUN apt-get -qq install -y curl\n",
+			expected: 1,
+		},
+		{
+			name:     "apt update alone",
+			source:   "FROM ubuntu:24.04\nRUN apt update\n",
+			expected: 1,
+		},
+		{
+			name:     "update with upgrade",
+			source:   "FROM debian:12\nRUN apt-get update && apt-get -y upgrade\n",
+			expected: 0,
+		},
+		{
+			name:     "heredoc with update and install",
+			source:   "FROM debian:12\nRUN <<EOF\napt-get update\napt-get install -y curl\nEOF\n",
+			expected: 0,
+		},
+		{
+			name:     "update mentioned in a string",
+			source:   "FROM debian:12\nRUN echo 'run apt-get update first'\n",
+			expected: 0,
+		},
+		{
+			name:     "non-POSIX shell",
+			source:   "FROM mcr.microsoft.com/windows/servercore:ltsc2022\nSHELL [\"powershell\", \"-Command\"]\nRUN apt-get update\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &PERF008AptUpdateSeparate{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
+
+func TestPERF008AptUpdateSeparateFix(t *testing.T) {
+	source := "FROM debian:12\nRUN apt-get update\nRUN apt-get install -y curl\nCMD [\"curl\"]\n"
+	diags := runRule(t, &PERF008AptUpdateSeparate{}, source)
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(diags))
+	}
+
+	// The merged RUN spans two lines, so it is a suggestion for the
+	// merge-apt-update transform rather than an in-place edit
+	want := "RUN apt-get update && apt-get install -y curl"
+	if !diags[0].Fixable || diags[0].FixSuggestion != want {
+		t.Errorf("expected fix %q, got %q", want, diags[0].FixSuggestion)
+	}
+
+	// Without an adjacent install there is nothing to merge with
+	diags = runRule(t, &PERF008AptUpdateSeparate{}, "FROM debian:12\nRUN apt-get update\nWORKDIR /app\nRUN apt-get install -y curl\n")
+	if len(diags) != 1 || diags[0].Fixable {
+		t.Errorf("expected an unfixable diagnostic, got %v", diags)
+	}
+}
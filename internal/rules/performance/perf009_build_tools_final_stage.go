@@ -0,0 +1,173 @@
+package performance
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// PERF009BuildToolsFinalStage checks for build tools installed in the final stage of a multi-stage build
+type PERF009BuildToolsFinalStage struct{}
+
+func (r *PERF009BuildToolsFinalStage) ID() string          { return "PERF009" }
+func (r *PERF009BuildToolsFinalStage) Name() string        { return "build-tools-in-final-stage" }
+func (r *PERF009BuildToolsFinalStage) Category() analyzer.Category { return analyzer.CategoryPerformance }
+func (r *PERF009BuildToolsFinalStage) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+
+func (r *PERF009BuildToolsFinalStage) Description() string {
+	return "Compilers and build tools in the runtime image make it larger and give an attacker more to work with. Install them in a builder stage and copy only the build output into the final stage."
+}
+
+// Packages flagged by default; override with the "packages" config key
+var defaultBuildTools = []string{
+	"gcc", "g++", "gcc-c++", "clang", "make", "cmake", "autoconf", "automake",
+	"libtool", "build-essential", "build-base",
+}
+
+// Install and remove subcommands, and the options that take a separate
+// value, keyed by package manager
+var packageManagerCommands = map[string]struct {
+	install, remove []string
+	valueFlags      []string
+}{
+	"apt-get":  {[]string{"install"}, []string{"remove", "purge"}, []string{"-o", "-c", "-t"}},
+	"apt":      {[]string{"install"}, []string{"remove", "purge"}, []string{"-o", "-c", "-t"}},
+	"apk":      {[]string{"add"}, []string{"del"}, []string{"-t", "--virtual", "-X", "--repository"}},
+	"yum":      {[]string{"install"}, []string{"remove", "erase"}, nil},
+	"dnf":      {[]string{"install"}, []string{"remove", "erase"}, nil},
+	"microdnf": {[]string{"install"}, []string{"remove"}, nil},
+	"zypper":   {[]string{"install", "in"}, []string{"remove", "rm"}, nil},
+}
+
+func (r *PERF009BuildToolsFinalStage) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	// A single-stage build has nowhere else to put its build tools
+	if len(df.Stages) < 2 {
+		return diags
+	}
+
+	tools := make(map[string]bool)
+	for _, p := range configuredList(ctx.Config["packages"], defaultBuildTools) {
+		tools[p] = true
+	}
+
+	final := df.Stages[len(df.Stages)-1]
+	for _, inst := range final.Instructions {
+		run, ok := inst.(*parser.RunInstruction)
+		if !ok {
+			continue
+		}
+
+		cmd := run.Command
+		if run.IsExec {
+			cmd = strings.Join(run.Arguments, " ")
+		} else if run.Heredoc != nil {
+			cmd = run.Heredoc.Content
+		}
+
+		var found []string
+		for _, pkg := range installedPackages(cmd) {
+			if tools[pkg] {
+				found = append(found, pkg)
+			}
+		}
+		if len(found) == 0 {
+			continue
+		}
+
+		noun := "build tool"
+		if len(found) > 1 {
+			noun = "build tools"
+		}
+		diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+			WithSeverity(r.Severity()).
+			WithMessagef("Final stage installs %s %s", noun, strings.Join(found, ", ")).
+			WithPos(run.Pos()).
+			WithContext(ctx.GetLine(run.Pos().Line)).
+			WithHelp("Install build tools in a builder stage and COPY --from it only what the final image needs").
+			Build()
+		diags = append(diags, diag)
+	}
+
+	return diags
+}
+
+// installedPackages returns the packages a RUN command installs and
+// leaves installed. Packages removed later in the same command, directly
+// or through an apk --virtual group, are left out.
+func installedPackages(cmd string) []string {
+	var installed []string
+	removed := make(map[string]bool)
+	virtual := make(map[string][]string)
+
+	for _, c := range shell.Split(cmd) {
+		manager := path.Base(c.Name())
+		pm, ok := packageManagerCommands[manager]
+		if !ok {
+			continue
+		}
+		sub := c.Subcommand(pm.valueFlags...)
+
+		var names []string
+		group := ""
+		args := c.Args()
+		for i := 1; i < len(args); i++ {
+			w := args[i].Value
+			if manager == "apk" && (w == "-t" || w == "--virtual") {
+				if i+1 < len(args) {
+					group = args[i+1].Value
+				}
+			}
+			if strings.HasPrefix(w, "-") {
+				for _, f := range pm.valueFlags {
+					if w == f {
+						i++
+						break
+					}
+				}
+				continue
+			}
+			if w == sub && names == nil {
+				names = []string{}
+				continue
+			}
+			// Drop version pins such as gcc=4:12.2 or gcc~12
+			if j := strings.IndexAny(w, "=<>~"); j > 0 {
+				w = w[:j]
+			}
+			names = append(names, w)
+		}
+
+		switch {
+		case slices.Contains(pm.install, sub):
+			installed = append(installed, names...)
+			if group != "" {
+				virtual[group] = append(virtual[group], names...)
+			}
+		case slices.Contains(pm.remove, sub):
+			for _, name := range names {
+				removed[name] = true
+				for _, p := range virtual[name] {
+					removed[p] = true
+				}
+			}
+		}
+	}
+
+	var kept []string
+	for _, p := range installed {
+		if !removed[p] {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+func init() {
+	Register(&PERF009BuildToolsFinalStage{})
+}
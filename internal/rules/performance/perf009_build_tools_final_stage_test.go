@@ -0,0 +1,83 @@
+package performance
+
+import (
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+func TestPERF009BuildToolsFinalStage(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "final stage installs gcc",
+			source:   "FROM golang:1.25 AS build\nRUN go build -o /app\n\nFROM debian:12\nRUN apt-get update && apt-get install -y --no-install-recommends gcc ca-certificates\n",
+			expected: 1,
+		},
+		{
+			name:     "builder stage installs gcc",
+			source:   "FROM debian:12 AS build\nRUN apt-get update && apt-get install -y gcc make\n\nFROM debian:12\nCOPY --from=build /app /app\n",
+			expected: 0,
+		},
+		{
+			name:     "single stage",
+			source:   "FROM debian:12\nRUN apt-get install -y gcc\n",
+			expected: 0,
+		},
+		{
+			name:     "pinned version",
+			source:   "FROM alpine:3.19 AS build\n\nFROM alpine:3.19\nRUN apk add --no-cache build-base=0.5-r3\n",
+			expected: 1,
+		},
+		{
+			name:     "removed in the same RUN",
+			source:   "FROM alpine:3.19 AS build\n\nFROM alpine:3.19\nRUN apk add gcc musl-dev && make && apk del gcc\n",
+			expected: 0,
+		},
+		{
+			name:     "virtual group removed in the same RUN",
+			source:   "FROM alpine:3.19 AS build\n\nFROM alpine:3.19\nRUN apk add --no-cache --virtual .build-deps gcc make && make && apk del .build-deps\n",
+			expected: 0,
+		},
+		{
+			name:     "runtime packages",
+			source:   "FROM golang:1.25 AS build\n\nFROM debian:12\nRUN apt-get install -y curl ca-certificates\n",
+			expected: 0,
+		},
+		{
+			name:     "package named like a subcommand",
+			source:   "FROM golang:1.25 AS build\n\nFROM fedora:40\nRUN dnf -y install make\n",
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &PERF009BuildToolsFinalStage{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
+
+func TestPERF009BuildToolsFinalStage_Configured(t *testing.T) {
+	a := analyzer.New(
+		analyzer.WithRules(&PERF009BuildToolsFinalStage{}),
+		analyzer.WithRuleConfig("PERF009", map[string]interface{}{
+			"packages": []interface{}{"golang"},
+		}),
+	)
+
+	source := "FROM alpine:3.19 AS build\n\nFROM alpine:3.19\nRUN apk add gcc golang\n"
+	result, _ := a.AnalyzeSource(source, "Dockerfile")
+	if len(result.Diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(result.Diagnostics))
+	}
+	if got := result.Diagnostics[0].Message; got != "Final stage installs build tool golang" {
+		t.Errorf("unexpected message %q", got)
+	}
+}
@@ -0,0 +1,236 @@
+package performance
+
+import (
+	"path"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// PERF010ArchiveNotRemoved checks for downloaded archives left in the layer after extraction
+type PERF010ArchiveNotRemoved struct{}
+
+func (r *PERF010ArchiveNotRemoved) ID() string          { return "PERF010" }
+func (r *PERF010ArchiveNotRemoved) Name() string        { return "archive-not-removed" }
+func (r *PERF010ArchiveNotRemoved) Category() analyzer.Category { return analyzer.CategoryPerformance }
+func (r *PERF010ArchiveNotRemoved) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+
+func (r *PERF010ArchiveNotRemoved) Description() string {
+	return "An archive downloaded and extracted in a RUN stays in the image unless the same RUN removes it; deleting it in a later RUN doesn't shrink the earlier layer."
+}
+
+func (r *PERF010ArchiveNotRemoved) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for _, stage := range df.Stages {
+		posix := true
+
+		for _, inst := range stage.Instructions {
+			if sh, ok := inst.(*parser.ShellInstruction); ok {
+				posix = shell.IsPOSIX(sh.Shell)
+			}
+
+			run, ok := inst.(*parser.RunInstruction)
+			if !ok || run.IsExec || !posix {
+				continue
+			}
+
+			cmd := run.Command
+			if run.Heredoc != nil {
+				cmd = run.Heredoc.Content
+			}
+
+			for _, archive := range leftoverArchives(shell.Split(cmd)) {
+				diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+					WithSeverity(r.Severity()).
+					WithMessagef("Archive %s is extracted but not removed in the same RUN", archive).
+					WithPos(run.Pos()).
+					WithContext(ctx.GetLine(run.Pos().Line)).
+					WithHelp("Remove the archive in the same RUN after extracting it: ... && rm " + archive).
+					Build()
+				diags = append(diags, diag)
+			}
+		}
+	}
+
+	return diags
+}
+
+// leftoverArchives returns the files downloaded by curl or wget and
+// extracted by tar or unzip that no rm in cmds removes
+func leftoverArchives(cmds []shell.Command) []string {
+	var downloaded, removed []string
+	extracted := make(map[string]bool)
+
+	for _, c := range cmds {
+		args := c.Args()
+		if len(args) == 0 {
+			continue
+		}
+		switch path.Base(args[0].Value) {
+		case "curl":
+			if f := curlOutput(args[1:]); f != "" {
+				downloaded = append(downloaded, f)
+			}
+		case "wget":
+			if f := wgetOutput(args[1:]); f != "" {
+				downloaded = append(downloaded, f)
+			}
+		case "tar":
+			if tarExtracts(args[1:]) {
+				for _, a := range args[1:] {
+					extracted[path.Base(a.Value)] = true
+				}
+			}
+		case "unzip":
+			for _, a := range args[1:] {
+				extracted[path.Base(a.Value)] = true
+			}
+		case "rm":
+			for _, a := range args[1:] {
+				if !strings.HasPrefix(a.Value, "-") {
+					removed = append(removed, a.Value)
+				}
+			}
+		}
+	}
+
+	var leftover []string
+	for _, f := range downloaded {
+		if extracted[path.Base(f)] && !isRemoved(f, removed) {
+			leftover = append(leftover, f)
+		}
+	}
+	return leftover
+}
+
+// curlOutput returns the file curl writes to, from -o/--output or, with
+// -O/--remote-name, the last segment of the URL
+func curlOutput(args []shell.Word) string {
+	remoteName := false
+	url := ""
+	for i := 0; i < len(args); i++ {
+		w := args[i].Value
+		switch {
+		case w == "-o" || w == "--output":
+			if i+1 < len(args) {
+				return outputFile(args[i+1].Value)
+			}
+		case strings.HasPrefix(w, "--output="):
+			return outputFile(strings.TrimPrefix(w, "--output="))
+		case w == "-O" || w == "--remote-name":
+			remoteName = true
+		case strings.HasPrefix(w, "--"):
+		case strings.HasPrefix(w, "-"):
+			// Bundled short options such as -fsSLo file or -fsSLO
+			if j := strings.IndexByte(w, 'o'); j > 0 {
+				if rest := w[j+1:]; rest != "" {
+					return outputFile(rest)
+				}
+				if i+1 < len(args) {
+					return outputFile(args[i+1].Value)
+				}
+				return ""
+			}
+			if strings.Contains(w, "O") {
+				remoteName = true
+			}
+		case strings.Contains(w, "://"):
+			url = w
+		}
+	}
+	if remoteName && url != "" {
+		return urlFile(url)
+	}
+	return ""
+}
+
+// wgetOutput returns the file wget writes to: -O/--output-document, or
+// the last segment of the URL
+func wgetOutput(args []shell.Word) string {
+	url := ""
+	for i := 0; i < len(args); i++ {
+		w := args[i].Value
+		switch {
+		case w == "-O" || w == "--output-document":
+			if i+1 < len(args) {
+				return outputFile(args[i+1].Value)
+			}
+		case strings.HasPrefix(w, "--output-document="):
+			return outputFile(strings.TrimPrefix(w, "--output-document="))
+		case strings.HasPrefix(w, "-O"):
+			return outputFile(strings.TrimPrefix(w, "-O"))
+		case strings.Contains(w, "://"):
+			url = w
+		}
+	}
+	if url != "" {
+		return urlFile(url)
+	}
+	return ""
+}
+
+// outputFile returns f, or "" for -, which writes to stdout
+func outputFile(f string) string {
+	if f == "-" {
+		return ""
+	}
+	return f
+}
+
+// urlFile returns the file name curl -O and wget give a download
+func urlFile(url string) string {
+	url, _, _ = strings.Cut(url, "?")
+	url, _, _ = strings.Cut(url, "#")
+	_, rest, _ := strings.Cut(url, "://")
+	if !strings.Contains(rest, "/") {
+		return ""
+	}
+	return path.Base(url)
+}
+
+// tarExtracts reports whether tar's arguments select extraction, either
+// as an option (-xzf, --extract) or an old-style first word (xzf)
+func tarExtracts(args []shell.Word) bool {
+	for i, a := range args {
+		w := a.Value
+		switch {
+		case w == "--extract" || w == "--get":
+			return true
+		case strings.HasPrefix(w, "--"):
+		case strings.HasPrefix(w, "-"):
+			if strings.Contains(w, "x") {
+				return true
+			}
+		case i == 0:
+			if strings.Contains(w, "x") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isRemoved reports whether one of the rm arguments removes file,
+// directly, by glob, or by removing a directory containing it
+func isRemoved(file string, removed []string) bool {
+	for _, r := range removed {
+		r = strings.TrimSuffix(r, "/")
+		if r == file || path.Base(r) == path.Base(file) || strings.HasPrefix(file, r+"/") {
+			return true
+		}
+		if matched, _ := path.Match(r, file); matched {
+			return true
+		}
+		if matched, _ := path.Match(path.Base(r), path.Base(file)); matched && !strings.Contains(r, "/") {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	Register(&PERF010ArchiveNotRemoved{})
+}
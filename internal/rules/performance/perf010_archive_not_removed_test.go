@@ -0,0 +1,76 @@
+package performance
+
+import "testing"
+
+func TestPERF010ArchiveNotRemoved(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "download and extract without rm",
+			source:   "FROM debian:12\nRUN curl -fsSL -o x.tar.gz https://example.com/x.tar.gz && tar xf x.tar.gz\n",
+			expected: 1,
+		},
+		{
+			name:     "download, extract and rm",
+			source:   "FROM debian:12\nRUN curl -fsSL -o x.tar.gz https://example.com/x.tar.gz && tar xf x.tar.gz && rm x.tar.gz\n",
+			expected: 0,
+		},
+		{
+			name:     "bundled output flag",
+			source:   "FROM debian:12\nRUN curl -fsSLo /tmp/node.tar.xz https://nodejs.org/dist/node.tar.xz && tar -xJf /tmp/node.tar.xz -C /usr/local\n",
+			expected: 1,
+		},
+		{
+			name:     "curl remote name",
+			source:   "FROM debian:12\nRUN curl -fsSLO https://example.com/releases/tool.zip && unzip tool.zip\n",
+			expected: 1,
+		},
+		{
+			name:     "wget default name removed by glob",
+			source:   "FROM debian:12\nRUN wget https://example.com/tool.tar.gz && tar -xzf tool.tar.gz && rm -f *.tar.gz\n",
+			expected: 0,
+		},
+		{
+			name:     "removed with its directory",
+			source:   "FROM debian:12\nRUN wget -O /tmp/dl/tool.tar.gz https://example.com/tool.tar.gz && tar -xzf /tmp/dl/tool.tar.gz && rm -rf /tmp/dl\n",
+			expected: 0,
+		},
+		{
+			name:     "removed in a later RUN",
+			source:   "FROM debian:12\nRUN curl -o x.tar.gz https://example.com/x.tar.gz && tar xf x.tar.gz\nRUN rm x.tar.gz\n",
+			expected: 1,
+		},
+		{
+			name:     "piped to tar",
+			source:   "FROM debian:12\nRUN curl -fsSL https://example.com/x.tar.gz | tar -xz\n",
+			expected: 0,
+		},
+		{
+			name:     "download without extract",
+			source:   "FROM debian:12\nRUN curl -o /usr/local/bin/tool https://example.com/tool && chmod +x /usr/local/bin/tool\n",
+			expected: 0,
+		},
+		{
+			name:     "rm inside quotes",
+			source:   "FROM debian:12\nRUN curl -o x.tar.gz https://example.com/x.tar.gz && tar xf x.tar.gz && echo 'rm x.tar.gz'\n",
+			expected: 1,
+		},
+		{
+			name:     "heredoc",
+			source:   "FROM debian:12\nRUN <<EOF\ncurl -o x.tar.gz https://example.com/x.tar.gz\ntar xf x.tar.gz\nEOF\n",
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &PERF010ArchiveNotRemoved{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
@@ -22,6 +22,10 @@ type Rule interface {
 	// Severity returns the default severity
 	Severity() analyzer.Severity
 
+	// Tags returns labels for filtering beyond the category, such as
+	// supply-chain or layers. Most rules have none.
+	Tags() []string
+
 	// Check analyzes the Dockerfile and returns diagnostics
 	Check(df *parser.Dockerfile, ctx *Context) []analyzer.Diagnostic
 }
@@ -96,6 +100,7 @@ type BaseRule struct {
 	RuleDescription string
 	RuleCategory    analyzer.Category
 	RuleSeverity    analyzer.Severity
+	RuleTags        []string
 }
 
 func (r *BaseRule) ID() string                  { return r.RuleID }
@@ -103,6 +108,7 @@ func (r *BaseRule) Name() string                { return r.RuleName }
 func (r *BaseRule) Description() string         { return r.RuleDescription }
 func (r *BaseRule) Category() analyzer.Category { return r.RuleCategory }
 func (r *BaseRule) Severity() analyzer.Severity { return r.RuleSeverity }
+func (r *BaseRule) Tags() []string              { return r.RuleTags }
 
 // NewDiagnostic creates a diagnostic for this rule
 func (r *BaseRule) NewDiagnostic() *analyzer.DiagnosticBuilder {
@@ -13,6 +13,7 @@ func (r *SEC001RootUser) ID() string          { return "SEC001" }
 func (r *SEC001RootUser) Name() string        { return "root-user" }
 func (r *SEC001RootUser) Category() analyzer.Category { return analyzer.CategorySecurity }
 func (r *SEC001RootUser) Severity() analyzer.Severity { return analyzer.SeverityError }
+func (r *SEC001RootUser) Tags() []string { return []string{"cis"} }
 
 func (r *SEC001RootUser) Description() string {
 	return "Container runs as root user. Running containers as root is a security risk."
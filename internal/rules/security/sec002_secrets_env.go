@@ -36,6 +36,11 @@ var secretPatterns = []struct {
 func (r *SEC002SecretsEnv) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
 	var diags []analyzer.Diagnostic
 
+	// Global ARGs before the first FROM
+	for _, arg := range df.Args {
+		diags = append(diags, r.checkArg(arg, ctx)...)
+	}
+
 	for _, stage := range df.Stages {
 		for _, inst := range stage.Instructions {
 			switch v := inst.(type) {
@@ -53,16 +58,7 @@ func (r *SEC002SecretsEnv) Check(df *parser.Dockerfile, ctx *analyzer.RuleContex
 					}
 				}
 			case *parser.ArgInstruction:
-				if secretType := isSecretKey(v.Name); secretType != "" {
-					diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
-						WithSeverity(r.Severity()).
-						WithMessagef("ARG %q appears to contain a %s", v.Name, secretType).
-						WithPos(v.Pos()).
-						WithContext(ctx.GetLine(v.Pos().Line)).
-						WithHelp("ARG values are visible in image history. Use BuildKit secrets (--mount=type=secret) instead").
-						Build()
-					diags = append(diags, diag)
-				}
+				diags = append(diags, r.checkArg(v, ctx)...)
 			}
 		}
 	}
@@ -70,6 +66,20 @@ func (r *SEC002SecretsEnv) Check(df *parser.Dockerfile, ctx *analyzer.RuleContex
 	return diags
 }
 
+func (r *SEC002SecretsEnv) checkArg(arg *parser.ArgInstruction, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	secretType := isSecretKey(arg.Name)
+	if secretType == "" {
+		return nil
+	}
+	return []analyzer.Diagnostic{analyzer.NewDiagnostic(r.ID(), r.Category()).
+		WithSeverity(r.Severity()).
+		WithMessagef("ARG %q appears to contain a %s", arg.Name, secretType).
+		WithPos(arg.Pos()).
+		WithContext(ctx.GetLine(arg.Pos().Line)).
+		WithHelp("ARG values are visible in image history. Use BuildKit secrets (--mount=type=secret) instead").
+		Build()}
+}
+
 func isSecretKey(key string) string {
 	key = strings.ToLower(key)
 	for _, p := range secretPatterns {
@@ -14,6 +14,7 @@ func (r *SEC003UnpinnedTag) ID() string          { return "SEC003" }
 func (r *SEC003UnpinnedTag) Name() string        { return "unpinned-image-tag" }
 func (r *SEC003UnpinnedTag) Category() analyzer.Category { return analyzer.CategorySecurity }
 func (r *SEC003UnpinnedTag) Severity() analyzer.Severity { return analyzer.SeverityError }
+func (r *SEC003UnpinnedTag) Tags() []string { return []string{"supply-chain", "reproducibility"} }
 
 func (r *SEC003UnpinnedTag) Description() string {
 	return "Base image uses unpinned tag. Using 'latest' or no tag can lead to unpredictable builds."
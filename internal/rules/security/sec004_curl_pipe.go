@@ -15,6 +15,7 @@ func (r *SEC004CurlPipe) ID() string          { return "SEC004" }
 func (r *SEC004CurlPipe) Name() string        { return "curl-pipe-shell" }
 func (r *SEC004CurlPipe) Category() analyzer.Category { return analyzer.CategorySecurity }
 func (r *SEC004CurlPipe) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+func (r *SEC004CurlPipe) Tags() []string { return []string{"supply-chain"} }
 
 func (r *SEC004CurlPipe) Description() string {
 	return "curl/wget piped to shell is dangerous. Downloads should be verified before execution."
@@ -71,7 +71,7 @@ func (r *SEC006SensitiveFiles) Check(df *parser.Dockerfile, ctx *analyzer.RuleCo
 			}
 
 			for _, src := range sources {
-				if sensitive, desc := isSensitiveFile(src); sensitive {
+				if sensitive, desc := IsSensitiveFile(src); sensitive {
 					diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
 						WithSeverity(r.Severity()).
 						WithMessagef("Copying %s (%s) into image", src, desc).
@@ -88,7 +88,9 @@ func (r *SEC006SensitiveFiles) Check(df *parser.Dockerfile, ctx *analyzer.RuleCo
 	return diags
 }
 
-func isSensitiveFile(path string) (bool, string) {
+// IsSensitiveFile reports whether path names a file that should not be
+// copied into an image, with a description of what it is
+func IsSensitiveFile(path string) (bool, string) {
 	base := filepath.Base(path)
 
 	for _, p := range sensitivePatterns {
@@ -14,6 +14,7 @@ func (r *SEC007AddRemote) ID() string          { return "SEC007" }
 func (r *SEC007AddRemote) Name() string        { return "add-remote-url" }
 func (r *SEC007AddRemote) Category() analyzer.Category { return analyzer.CategorySecurity }
 func (r *SEC007AddRemote) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+func (r *SEC007AddRemote) Tags() []string { return []string{"supply-chain"} }
 
 func (r *SEC007AddRemote) Description() string {
 	return "ADD with remote URL downloads without verification. Use curl/wget with checksum verification instead."
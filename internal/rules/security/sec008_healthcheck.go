@@ -1,6 +1,8 @@
 package security
 
 import (
+	"strings"
+
 	"github.com/HueCodes/keel/internal/analyzer"
 	"github.com/HueCodes/keel/internal/parser"
 )
@@ -12,6 +14,7 @@ func (r *SEC008Healthcheck) ID() string          { return "SEC008" }
 func (r *SEC008Healthcheck) Name() string        { return "missing-healthcheck" }
 func (r *SEC008Healthcheck) Category() analyzer.Category { return analyzer.CategorySecurity }
 func (r *SEC008Healthcheck) Severity() analyzer.Severity { return analyzer.SeverityInfo }
+func (r *SEC008Healthcheck) Tags() []string { return []string{"cis"} }
 
 func (r *SEC008Healthcheck) Description() string {
 	return "HEALTHCHECK instruction is missing. Health checks enable container orchestrators to detect unhealthy containers."
@@ -27,6 +30,12 @@ func (r *SEC008Healthcheck) Check(df *parser.Dockerfile, ctx *analyzer.RuleConte
 
 	finalStage := df.Stages[len(df.Stages)-1]
 
+	// With services_only, only images that expose a port and run a
+	// command, and so look like long-running services, need a health check
+	if servicesOnly, _ := ctx.Config["services_only"].(bool); servicesOnly && !isService(df, finalStage) {
+		return diags
+	}
+
 	// Check if any stage has HEALTHCHECK (could be inherited)
 	hasHealthcheck := false
 	for _, stage := range df.Stages {
@@ -58,6 +67,36 @@ func (r *SEC008Healthcheck) Check(df *parser.Dockerfile, ctx *analyzer.RuleConte
 	return diags
 }
 
+// isService reports whether stage, or a stage it builds on, has both an
+// EXPOSE and a CMD or ENTRYPOINT
+func isService(df *parser.Dockerfile, stage *parser.Stage) bool {
+	stages := make(map[string]*parser.Stage)
+	for _, s := range df.Stages {
+		if s.Name != "" {
+			stages[strings.ToLower(s.Name)] = s
+		}
+	}
+
+	exposes, runs := false, false
+	seen := make(map[*parser.Stage]bool)
+	for s := stage; s != nil && !seen[s]; {
+		seen[s] = true
+		for _, inst := range s.Instructions {
+			switch inst.(type) {
+			case *parser.ExposeInstruction:
+				exposes = true
+			case *parser.CmdInstruction, *parser.EntrypointInstruction:
+				runs = true
+			}
+		}
+		if s.From == nil || s.From.BaseStage == "" {
+			break
+		}
+		s = stages[strings.ToLower(s.From.BaseStage)]
+	}
+	return exposes && runs
+}
+
 func init() {
 	Register(&SEC008Healthcheck{})
 }
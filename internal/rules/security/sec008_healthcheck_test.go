@@ -0,0 +1,58 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+func TestSEC008Healthcheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{"missing", "FROM alpine\nCMD [\"sh\"]\n", 1},
+		{"present", "FROM alpine\nHEALTHCHECK CMD true\nCMD [\"sh\"]\n", 0},
+		{"disabled", "FROM alpine\nHEALTHCHECK NONE\n", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &SEC008Healthcheck{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
+
+func TestSEC008Healthcheck_ServicesOnly(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{"expose and cmd", "FROM node:20\nEXPOSE 3000\nCMD [\"node\", \"server.js\"]\n", 1},
+		{"expose and entrypoint", "FROM nginx:1.27\nEXPOSE 80\nENTRYPOINT [\"nginx\", \"-g\", \"daemon off;\"]\n", 1},
+		{"with healthcheck", "FROM node:20\nEXPOSE 3000\nHEALTHCHECK CMD curl -f http://localhost:3000/ || exit 1\nCMD [\"node\", \"server.js\"]\n", 0},
+		{"no expose", "FROM alpine\nENTRYPOINT [\"jq\"]\n", 0},
+		{"expose without command", "FROM nginx:1.27\nEXPOSE 80\n", 0},
+		{"inherited from base stage", "FROM node:20 AS base\nEXPOSE 3000\n\nFROM base\nCMD [\"node\", \"server.js\"]\n", 1},
+		{"expose in unrelated stage", "FROM node:20 AS build\nEXPOSE 3000\n\nFROM alpine\nCMD [\"sh\"]\n", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := analyzer.New(
+				analyzer.WithRules(&SEC008Healthcheck{}),
+				analyzer.WithMinSeverity(analyzer.SeverityHint),
+				analyzer.WithRuleConfig("SEC008", map[string]interface{}{"services_only": true}),
+			)
+			result, _ := a.AnalyzeSource(tt.source, "Dockerfile")
+			if len(result.Diagnostics) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(result.Diagnostics), result.Diagnostics)
+			}
+		})
+	}
+}
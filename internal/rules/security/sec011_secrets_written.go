@@ -0,0 +1,129 @@
+package security
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// SEC011SecretsWritten checks for secrets written into files by RUN commands
+type SEC011SecretsWritten struct{}
+
+func (r *SEC011SecretsWritten) ID() string          { return "SEC011" }
+func (r *SEC011SecretsWritten) Name() string        { return "secrets-written-to-file" }
+func (r *SEC011SecretsWritten) Category() analyzer.Category { return analyzer.CategorySecurity }
+func (r *SEC011SecretsWritten) Severity() analyzer.Severity { return analyzer.SeverityError }
+
+func (r *SEC011SecretsWritten) Description() string {
+	return "Secrets written to files with echo, printf, or cat are baked into an image layer and remain in image history."
+}
+
+// echo/printf output, optionally redirected to a file
+var echoWritePattern = regexp.MustCompile(`^(?:echo|printf)\b([^>]*)(>)?`)
+
+// cat/tee fed by a heredoc, e.g. cat > /app/.env <<EOF
+var heredocWritePattern = regexp.MustCompile(`^(?:cat|tee)\b.*<<-?\s*["']?(\w+)["']?`)
+
+// KEY=value or key: value pairs
+var secretAssignPattern = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_.-]*)\s*[=:]\s*("[^"]*"|'[^']*'|[^\s"';&|>]+)`)
+
+func (r *SEC011SecretsWritten) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			run, ok := inst.(*parser.RunInstruction)
+			if !ok {
+				continue
+			}
+
+			cmd := run.Command
+			if run.Heredoc != nil {
+				cmd = run.Heredoc.Content
+			}
+
+			for _, key := range findWrittenSecrets(cmd) {
+				diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+					WithSeverity(r.Severity()).
+					WithMessagef("RUN writes %q (%s) into a file in the image", key, isSecretKey(key)).
+					WithPos(run.Pos()).
+					WithContext(ctx.GetLine(run.Pos().Line)).
+					WithHelp("Use BuildKit secrets (RUN --mount=type=secret,id=...) or provide the value at runtime instead").
+					Build()
+				diags = append(diags, diag)
+			}
+		}
+	}
+
+	return diags
+}
+
+// findWrittenSecrets returns the secret-shaped keys written to files by cmd
+func findWrittenSecrets(cmd string) []string {
+	var keys []string
+	seen := make(map[string]bool)
+
+	collect := func(payload string) {
+		for _, m := range secretAssignPattern.FindAllStringSubmatch(payload, -1) {
+			key, value := m[1], strings.Trim(m[2], `"'`)
+			// Values expanded from ARG/ENV are reported by SEC002
+			if value == "" || strings.HasPrefix(value, "$") {
+				continue
+			}
+			if isSecretKey(key) != "" && !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	delimiter := ""
+	for _, line := range strings.Split(cmd, "\n") {
+		// Inside a heredoc body that is written to a file
+		if delimiter != "" {
+			if strings.TrimSpace(line) == delimiter {
+				delimiter = ""
+				continue
+			}
+			collect(line)
+			continue
+		}
+
+		cmds := shell.Split(line)
+		for i, c := range cmds {
+			segment := strings.TrimPrefix(c.Text, "sudo ")
+
+			if m := echoWritePattern.FindStringSubmatch(segment); m != nil {
+				// Written when redirected or piped into tee
+				pipedToTee := c.Op == shell.OpPipe && i+1 < len(cmds) && pipeTarget(cmds[i+1]) == "tee"
+				if m[2] != "" || pipedToTee {
+					collect(m[1])
+				}
+				continue
+			}
+			if m := heredocWritePattern.FindStringSubmatch(segment); m != nil {
+				if strings.Contains(segment, ">") || strings.HasPrefix(segment, "tee") {
+					delimiter = m[1]
+				}
+			}
+		}
+	}
+
+	return keys
+}
+
+// pipeTarget returns the program receiving a pipe, looking past sudo
+func pipeTarget(c shell.Command) string {
+	args := c.Args()
+	if len(args) > 1 && args[0].Value == "sudo" {
+		return args[1].Value
+	}
+	return c.Name()
+}
+
+func init() {
+	Register(&SEC011SecretsWritten{})
+}
@@ -0,0 +1,56 @@
+package security
+
+import "testing"
+
+func TestSEC011SecretsWritten(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "echoed password into file",
+			source:   "FROM alpine:3.18\nRUN echo \"DB_PASSWORD=hunter2\" > /app/.env\n",
+			expected: 1,
+		},
+		{
+			name:     "printf appended to file",
+			source:   "FROM alpine:3.18\nRUN mkdir /app && printf 'API_KEY=abc123\\n' >> /app/config\n",
+			expected: 1,
+		},
+		{
+			name:     "echo piped into tee",
+			source:   "FROM alpine:3.18\nRUN echo \"GITHUB_TOKEN=ghp_xxx\" | tee /root/.env\n",
+			expected: 1,
+		},
+		{
+			name:     "cat heredoc into file",
+			source:   "FROM alpine:3.18\nRUN cat > /app/.env <<EOF\nPASSWORD=hunter2\nEOF\n",
+			expected: 1,
+		},
+		{
+			name:     "benign echo",
+			source:   "FROM alpine:3.18\nRUN echo \"PORT=8080\" > /app/.env\n",
+			expected: 0,
+		},
+		{
+			name:     "secret echoed to stdout only",
+			source:   "FROM alpine:3.18\nRUN echo \"PASSWORD=hunter2\"\n",
+			expected: 0,
+		},
+		{
+			name:     "value from variable",
+			source:   "FROM alpine:3.18\nRUN echo \"PASSWORD=$PASSWORD\" > /app/.env\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &SEC011SecretsWritten{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package security
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// SEC012DockerignoreSecrets checks that a broad COPY is backed by a
+// .dockerignore excluding sensitive files
+type SEC012DockerignoreSecrets struct {
+	// ReadFile reads the .dockerignore; defaults to os.ReadFile
+	ReadFile func(name string) ([]byte, error)
+}
+
+func (r *SEC012DockerignoreSecrets) ID() string          { return "SEC012" }
+func (r *SEC012DockerignoreSecrets) Name() string        { return "dockerignore-secrets" }
+func (r *SEC012DockerignoreSecrets) Category() analyzer.Category { return analyzer.CategorySecurity }
+func (r *SEC012DockerignoreSecrets) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+
+func (r *SEC012DockerignoreSecrets) Description() string {
+	return "When copying the whole build context, .dockerignore should exclude sensitive files such as .env, keys, and .git."
+}
+
+func (r *SEC012DockerignoreSecrets) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	broad := findBroadCopy(df)
+	if broad == nil {
+		return diags
+	}
+
+	patterns, found := r.readDockerignore(ctx.Filename)
+
+	var missing []string
+	for _, p := range sensitivePatterns {
+		if !isIgnored(p.pattern, patterns) {
+			missing = append(missing, p.pattern)
+		}
+	}
+	if len(missing) == 0 {
+		return diags
+	}
+
+	msg := "Broad COPY includes sensitive files not excluded by .dockerignore: " + strings.Join(missing, ", ")
+	if !found {
+		msg = "Broad COPY without a .dockerignore includes sensitive files such as " + strings.Join(missing[:min(3, len(missing))], ", ")
+	}
+
+	diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+		WithSeverity(r.Severity()).
+		WithMessage(msg).
+		WithPos(broad.Pos()).
+		WithContext(ctx.GetLine(broad.Pos().Line)).
+		WithHelp("Add these patterns to .dockerignore, or copy only the files the image needs").
+		Build()
+	diags = append(diags, diag)
+
+	return diags
+}
+
+// readDockerignore loads the ignore patterns next to the Dockerfile.
+// A <Dockerfile>.dockerignore takes precedence, as in BuildKit.
+func (r *SEC012DockerignoreSecrets) readDockerignore(dockerfile string) ([]string, bool) {
+	readFile := r.ReadFile
+	if readFile == nil {
+		readFile = os.ReadFile
+	}
+
+	dir := filepath.Dir(dockerfile)
+	candidates := []string{
+		dockerfile + ".dockerignore",
+		filepath.Join(dir, ".dockerignore"),
+	}
+
+	for _, name := range candidates {
+		data, err := readFile(name)
+		if err != nil {
+			continue
+		}
+
+		var patterns []string
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			patterns = append(patterns, line)
+		}
+		return patterns, true
+	}
+
+	return nil, false
+}
+
+// isIgnored reports whether the .dockerignore patterns exclude files
+// matching the sensitive pattern. Later patterns win, and ! re-includes.
+func isIgnored(sensitive string, patterns []string) bool {
+	// Ignoring a directory excludes its contents
+	targets := []string{sensitive}
+	if dir, ok := strings.CutSuffix(sensitive, "/*"); ok {
+		targets = append(targets, dir)
+	}
+
+	ignored := false
+	for _, p := range patterns {
+		negate := strings.HasPrefix(p, "!")
+		p = strings.TrimPrefix(p, "!")
+		p = strings.TrimPrefix(strings.TrimPrefix(p, "./"), "/")
+		p = strings.TrimPrefix(p, "**/")
+		p = strings.TrimSuffix(p, "/")
+
+		for _, target := range targets {
+			if p == target {
+				ignored = !negate
+				break
+			}
+			if matched, _ := filepath.Match(p, target); matched {
+				ignored = !negate
+				break
+			}
+		}
+	}
+	return ignored
+}
+
+// findBroadCopy returns the first COPY/ADD of the whole build context
+func findBroadCopy(df *parser.Dockerfile) parser.Instruction {
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			switch v := inst.(type) {
+			case *parser.CopyInstruction:
+				if v.From == "" && hasBroadSource(v.Sources) {
+					return v
+				}
+			case *parser.AddInstruction:
+				if hasBroadSource(v.Sources) {
+					return v
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func hasBroadSource(sources []string) bool {
+	for _, src := range sources {
+		if src == "." || src == "./" || src == "*" || src == "./*" {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	Register(&SEC012DockerignoreSecrets{})
+}
@@ -0,0 +1,94 @@
+package security
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// fakeFiles returns a reader serving files from a map
+func fakeFiles(files map[string]string) func(string) ([]byte, error) {
+	return func(name string) ([]byte, error) {
+		if content, ok := files[name]; ok {
+			return []byte(content), nil
+		}
+		return nil, os.ErrNotExist
+	}
+}
+
+// allSensitivePatterns is a .dockerignore covering every sensitive pattern
+func allSensitivePatterns() string {
+	var lines []string
+	for _, p := range sensitivePatterns {
+		lines = append(lines, p.pattern)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestSEC012DockerignoreSecrets(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		files    map[string]string
+		expected int
+		missing  string
+	}{
+		{
+			name:     "dockerignore missing .env",
+			source:   "FROM node:20\nCOPY . .\n",
+			files:    map[string]string{".dockerignore": strings.Replace(allSensitivePatterns(), ".env\n", "", 1)},
+			expected: 1,
+			missing:  ".env",
+		},
+		{
+			name:     "no dockerignore",
+			source:   "FROM node:20\nCOPY . .\n",
+			files:    map[string]string{},
+			expected: 1,
+		},
+		{
+			name:     "all patterns ignored",
+			source:   "FROM node:20\nCOPY . .\n",
+			files:    map[string]string{".dockerignore": allSensitivePatterns()},
+			expected: 0,
+		},
+		{
+			name:     "ignore everything",
+			source:   "FROM node:20\nCOPY . .\n",
+			files:    map[string]string{".dockerignore": "*\n!src\n"},
+			expected: 0,
+		},
+		{
+			name:     "re-included secret",
+			source:   "FROM node:20\nCOPY . .\n",
+			files:    map[string]string{".dockerignore": allSensitivePatterns() + "\n!.env\n"},
+			expected: 1,
+			missing:  ".env",
+		},
+		{
+			name:     "dockerfile-specific ignore file",
+			source:   "FROM node:20\nCOPY . .\n",
+			files:    map[string]string{"Dockerfile.dockerignore": allSensitivePatterns()},
+			expected: 0,
+		},
+		{
+			name:     "no broad copy",
+			source:   "FROM node:20\nCOPY src /app/src\n",
+			files:    map[string]string{},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &SEC012DockerignoreSecrets{ReadFile: fakeFiles(tt.files)}
+			diags := runRule(t, rule, tt.source)
+			if len(diags) != tt.expected {
+				t.Fatalf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+			if tt.missing != "" && !strings.Contains(diags[0].Message, tt.missing) {
+				t.Errorf("expected message to mention %s, got %q", tt.missing, diags[0].Message)
+			}
+		})
+	}
+}
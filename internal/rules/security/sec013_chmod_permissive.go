@@ -0,0 +1,103 @@
+package security
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/lexer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// SEC013ChmodPermissive checks for COPY/ADD --chmod modes that are invalid or world-writable
+type SEC013ChmodPermissive struct{}
+
+func (r *SEC013ChmodPermissive) ID() string          { return "SEC013" }
+func (r *SEC013ChmodPermissive) Name() string        { return "chmod-permissive" }
+func (r *SEC013ChmodPermissive) Category() analyzer.Category { return analyzer.CategorySecurity }
+func (r *SEC013ChmodPermissive) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+
+func (r *SEC013ChmodPermissive) Description() string {
+	return "COPY and ADD --chmod should be a valid mode that does not make files writable by every user."
+}
+
+var (
+	octalMode      = regexp.MustCompile(`^[0-7]{3,4}$`)
+	symbolicMode   = regexp.MustCompile(`^[ugoa]*([-+=][rwxXst]*)+$`)
+	symbolicAction = regexp.MustCompile(`[-+=][rwxXst]*`)
+)
+
+func (r *SEC013ChmodPermissive) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			var chmod, name string
+			var pos lexer.Position
+
+			switch v := inst.(type) {
+			case *parser.CopyInstruction:
+				chmod, name, pos = v.Chmod, "COPY", v.Pos()
+			case *parser.AddInstruction:
+				chmod, name, pos = v.Chmod, "ADD", v.Pos()
+			default:
+				continue
+			}
+
+			// Modes from build args can't be checked
+			if chmod == "" || strings.Contains(chmod, "$") {
+				continue
+			}
+
+			var msg, help string
+			switch valid, writable := checkMode(chmod); {
+			case !valid:
+				msg = "%s --chmod=%s is not a valid file mode"
+				help = "Use an octal mode such as 644 or 755"
+			case writable:
+				msg = "%s --chmod=%s makes files writable by every user"
+				help = "Remove write permission for others, e.g. 755 instead of 777 or 644 instead of 666"
+			default:
+				continue
+			}
+
+			diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithMessagef(msg, name, chmod).
+				WithPos(pos).
+				WithContext(ctx.GetLine(pos.Line)).
+				WithHelp(help).
+				Build()
+			diags = append(diags, diag)
+		}
+	}
+
+	return diags
+}
+
+// checkMode reports whether mode is a valid octal or symbolic mode, and
+// whether it grants write permission to others
+func checkMode(mode string) (valid, worldWritable bool) {
+	if octalMode.MatchString(mode) {
+		return true, (mode[len(mode)-1]-'0')&2 != 0
+	}
+
+	// Symbolic clauses such as u=rwx,go=rx
+	for _, clause := range strings.Split(mode, ",") {
+		if !symbolicMode.MatchString(clause) {
+			return false, false
+		}
+		who := clause[:strings.IndexAny(clause, "+-=")]
+		forOthers := who == "" || strings.ContainsAny(who, "oa")
+		for _, action := range symbolicAction.FindAllString(clause[len(who):], -1) {
+			if action[0] != '-' && strings.Contains(action, "w") && forOthers {
+				worldWritable = true
+			}
+		}
+	}
+	return true, worldWritable
+}
+
+func init() {
+	Register(&SEC013ChmodPermissive{})
+}
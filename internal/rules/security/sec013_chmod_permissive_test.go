@@ -0,0 +1,71 @@
+package security
+
+import "testing"
+
+func TestSEC013ChmodPermissive(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "world-writable",
+			source:   "FROM alpine\nCOPY --chmod=777 app /app\n",
+			expected: 1,
+		},
+		{
+			name:     "world-writable with leading zero",
+			source:   "FROM alpine\nADD --chmod=0666 app.tar /app\n",
+			expected: 1,
+		},
+		{
+			name:     "safe octal",
+			source:   "FROM alpine\nCOPY --chmod=0644 app /app\n",
+			expected: 0,
+		},
+		{
+			name:     "executable",
+			source:   "FROM alpine\nCOPY --chmod=755 entrypoint.sh /\n",
+			expected: 0,
+		},
+		{
+			name:     "non-octal garbage",
+			source:   "FROM alpine\nCOPY --chmod=abc app /app\n",
+			expected: 1,
+		},
+		{
+			name:     "octal with invalid digit",
+			source:   "FROM alpine\nCOPY --chmod=648 app /app\n",
+			expected: 1,
+		},
+		{
+			name:     "symbolic",
+			source:   "FROM alpine\nCOPY --chmod=u=rwx,go=rx app /app\n",
+			expected: 0,
+		},
+		{
+			name:     "symbolic world-writable",
+			source:   "FROM alpine\nCOPY --chmod=a+w app /app\n",
+			expected: 1,
+		},
+		{
+			name:     "build arg",
+			source:   "FROM alpine\nARG MODE=644\nCOPY --chmod=$MODE app /app\n",
+			expected: 0,
+		},
+		{
+			name:     "no chmod",
+			source:   "FROM alpine\nCOPY app /app\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &SEC013ChmodPermissive{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
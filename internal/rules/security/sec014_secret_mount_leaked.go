@@ -0,0 +1,269 @@
+package security
+
+import (
+	"path"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// SEC014SecretMountLeaked checks for RUN commands that copy a mounted secret
+// into the image or print it to the build output
+type SEC014SecretMountLeaked struct{}
+
+func (r *SEC014SecretMountLeaked) ID() string          { return "SEC014" }
+func (r *SEC014SecretMountLeaked) Name() string        { return "secret-mount-leaked" }
+func (r *SEC014SecretMountLeaked) Category() analyzer.Category { return analyzer.CategorySecurity }
+func (r *SEC014SecretMountLeaked) Severity() analyzer.Severity { return analyzer.SeverityError }
+
+func (r *SEC014SecretMountLeaked) Description() string {
+	return "Secrets mounted with RUN --mount=type=secret stay out of the image only if they are not written to files or printed to the build output."
+}
+
+// Commands whose output carries the secret they are given, as opposed to
+// commands like curl that only use it
+var secretPrinters = map[string]bool{
+	"cat": true, "echo": true, "printf": true, "head": true, "tail": true,
+	"base64": true, "sed": true, "envsubst": true, "tr": true,
+}
+
+func (r *SEC014SecretMountLeaked) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			run, ok := inst.(*parser.RunInstruction)
+			if !ok || run.IsExec {
+				continue
+			}
+
+			cmd := run.Command
+			if run.Heredoc != nil {
+				cmd += "\n" + run.Heredoc.Content
+			}
+
+			for _, leak := range findSecretLeaks(cmd, run.MountSpecs()) {
+				msg := "RUN prints secret " + leak.secret + " to the build output"
+				if leak.dest != "" {
+					msg = "RUN writes secret " + leak.secret + " to " + leak.dest + ", which persists in the image layer"
+				}
+				diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+					WithSeverity(r.Severity()).
+					WithMessage(msg).
+					WithPos(run.Pos()).
+					WithContext(ctx.GetLine(run.Pos().Line)).
+					WithHelp("Read the secret only where it is used, e.g. TOKEN=$(cat /run/secrets/token) cmd, or mount it where the tool expects it with target=").
+					Build()
+				diags = append(diags, diag)
+			}
+		}
+	}
+
+	return diags
+}
+
+// secretLeak is a secret written to dest, or printed when dest is empty
+type secretLeak struct {
+	secret string
+	dest   string
+}
+
+// findSecretLeaks returns the places cmd copies secrets into files or
+// prints them. Secrets are the files and variables of the secret mounts,
+// plus anything under /run/secrets.
+func findSecretLeaks(cmd string, mounts []parser.MountSpec) []secretLeak {
+	var refs, transient []string
+	for _, m := range mounts {
+		switch m.Type {
+		case parser.MountSecret:
+			if m.Target != "" {
+				refs = append(refs, m.Target)
+			}
+			if m.Env != "" {
+				refs = append(refs, "${"+m.Env+"}", "$"+m.Env)
+			}
+		case parser.MountTmpfs, parser.MountCache:
+			// Files written here don't end up in the layer
+			if m.Target != "" {
+				transient = append(transient, strings.TrimRight(m.Target, "/")+"/")
+			}
+		}
+	}
+	refs = append(refs, parser.DefaultSecretDir)
+
+	secretIn := func(words []shell.Word) string {
+		for _, w := range words {
+			for _, ref := range refs {
+				if strings.Contains(w.Value, ref) {
+					if strings.HasSuffix(ref, "/") {
+						return secretPath(w.Value, ref)
+					}
+					return ref
+				}
+			}
+		}
+		return ""
+	}
+
+	cmds := shell.Split(cmd)
+
+	// A file written and removed in the same RUN never reaches the layer
+	persisted := func(dest string, from int) bool {
+		if strings.HasPrefix(dest, "/dev/") || strings.HasPrefix(dest, "&") {
+			return false
+		}
+		for _, t := range transient {
+			if strings.HasPrefix(dest, t) {
+				return false
+			}
+		}
+		for _, c := range cmds[from:] {
+			args := commandArgs(c)
+			if len(args) == 0 || path.Base(args[0].Value) != "rm" {
+				continue
+			}
+			for _, a := range args[1:] {
+				if a.Value == dest {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	var leaks []secretLeak
+	for i := 0; i < len(cmds); i++ {
+		args, outputs := splitRedirects(commandArgs(cmds[i]))
+		if len(args) == 0 {
+			continue
+		}
+
+		name := path.Base(args[0].Value)
+		if name == "cp" || name == "install" {
+			operands := nonFlags(args[1:])
+			if len(operands) < 2 {
+				continue
+			}
+			dest := operands[len(operands)-1].Value
+			if secret := secretIn(operands[:len(operands)-1]); secret != "" && persisted(dest, i+1) {
+				leaks = append(leaks, secretLeak{secret, dest})
+			}
+			continue
+		}
+
+		secret := secretIn(args[1:])
+		if secret == "" || !secretPrinters[name] {
+			continue
+		}
+
+		if len(outputs) > 0 {
+			for _, dest := range outputs {
+				if persisted(dest, i+1) {
+					leaks = append(leaks, secretLeak{secret, dest})
+				}
+			}
+			continue
+		}
+
+		if cmds[i].Op != shell.OpPipe {
+			leaks = append(leaks, secretLeak{secret: secret})
+			continue
+		}
+
+		// Follow the pipeline to the command that consumes the output
+		for j := i + 1; j < len(cmds); j++ {
+			next, nextOutputs := splitRedirects(commandArgs(cmds[j]))
+			if len(next) > 0 && path.Base(next[0].Value) == "tee" {
+				for _, w := range nonFlags(next[1:]) {
+					nextOutputs = append(nextOutputs, w.Value)
+				}
+			}
+			for _, dest := range nextOutputs {
+				if persisted(dest, j+1) {
+					leaks = append(leaks, secretLeak{secret, dest})
+				}
+			}
+			if cmds[j].Op != shell.OpPipe {
+				i = j
+				break
+			}
+		}
+	}
+
+	return leaks
+}
+
+// secretPath returns the secret file path under dir mentioned in word
+func secretPath(word, dir string) string {
+	p := word[strings.Index(word, dir):]
+	if end := strings.IndexAny(p, " \"')`;"); end >= 0 {
+		p = p[:end]
+	}
+	return p
+}
+
+// commandArgs returns a command's arguments, looking past sudo
+func commandArgs(c shell.Command) []shell.Word {
+	args := c.Args()
+	if len(args) > 1 && args[0].Value == "sudo" {
+		return args[1:]
+	}
+	return args
+}
+
+// splitRedirects separates stdout redirections from a command's
+// arguments, returning the remaining arguments and the files written
+func splitRedirects(words []shell.Word) ([]shell.Word, []string) {
+	var args []shell.Word
+	var outputs []string
+
+	for i := 0; i < len(words); i++ {
+		w := words[i].Value
+		op := ""
+		for _, prefix := range []string{"&>>", "1>>", ">>", "&>", "1>", ">|", ">"} {
+			if strings.HasPrefix(w, prefix) {
+				op = prefix
+				break
+			}
+		}
+		if op == "" {
+			if strings.HasPrefix(w, "2>") || strings.HasPrefix(w, "<") {
+				// Redirections of stderr or stdin, possibly with a separate target
+				if w == "2>" || w == "2>>" || w == "<" {
+					i++
+				}
+				continue
+			}
+			args = append(args, words[i])
+			continue
+		}
+
+		target := strings.TrimPrefix(w, op)
+		if target == "" && i+1 < len(words) {
+			i++
+			target = words[i].Value
+		}
+		if target != "" {
+			outputs = append(outputs, target)
+		}
+	}
+
+	return args, outputs
+}
+
+// nonFlags returns the words that aren't options
+func nonFlags(words []shell.Word) []shell.Word {
+	var operands []shell.Word
+	for _, w := range words {
+		if !strings.HasPrefix(w.Value, "-") {
+			operands = append(operands, w)
+		}
+	}
+	return operands
+}
+
+func init() {
+	Register(&SEC014SecretMountLeaked{})
+}
@@ -0,0 +1,91 @@
+package security
+
+import "testing"
+
+func TestSEC014SecretMountLeaked(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "secret piped into a persisted file",
+			source:   "FROM node:20\nRUN --mount=type=secret,id=npmrc cat /run/secrets/npmrc | tee /root/.npmrc && npm ci\n",
+			expected: 1,
+		},
+		{
+			name:     "secret redirected into a file",
+			source:   "FROM node:20\nRUN --mount=type=secret,id=npmrc cat /run/secrets/npmrc > /root/.npmrc && npm ci\n",
+			expected: 1,
+		},
+		{
+			name:     "secret at a custom target copied",
+			source:   "FROM alpine:3.19\nRUN --mount=type=secret,id=netrc,target=/tmp/netrc cp /tmp/netrc /root/.netrc\n",
+			expected: 1,
+		},
+		{
+			name:     "env secret echoed into a file",
+			source:   "FROM alpine:3.19\nRUN --mount=type=secret,id=token,env=GH_TOKEN echo \"$GH_TOKEN\" >> /etc/gh-token\n",
+			expected: 1,
+		},
+		{
+			name:     "secret printed to the build log",
+			source:   "FROM alpine:3.19\nRUN --mount=type=secret,id=token cat /run/secrets/token\n",
+			expected: 1,
+		},
+		{
+			name:     "secret read without a mount",
+			source:   "FROM alpine:3.19\nRUN cat /run/secrets/token > /app/token\n",
+			expected: 1,
+		},
+		{
+			name:     "secret in heredoc script",
+			source:   "FROM alpine:3.19\nRUN --mount=type=secret,id=aws <<EOF\ncp /run/secrets/aws /root/.aws/credentials\naws s3 cp s3://bucket/file /data/file\nEOF\n",
+			expected: 1,
+		},
+		{
+			name:     "transient use via command substitution",
+			source:   "FROM alpine:3.19\nRUN --mount=type=secret,id=token TOKEN=$(cat /run/secrets/token) ./fetch.sh > /app/data.json\n",
+			expected: 0,
+		},
+		{
+			name:     "secret mounted where the tool expects it",
+			source:   "FROM node:20\nRUN --mount=type=secret,id=npmrc,target=/root/.npmrc npm ci\n",
+			expected: 0,
+		},
+		{
+			name:     "secret piped into a consumer",
+			source:   "FROM alpine:3.19\nRUN --mount=type=secret,id=pw cat /run/secrets/pw | docker login --password-stdin registry.example.com\n",
+			expected: 0,
+		},
+		{
+			name:     "env secret used by curl",
+			source:   "FROM alpine:3.19\nRUN --mount=type=secret,id=token,env=TOKEN curl -fsSL -H \"Authorization: Bearer $TOKEN\" https://example.com/app.tgz > /tmp/app.tgz\n",
+			expected: 0,
+		},
+		{
+			name:     "file removed in the same RUN",
+			source:   "FROM node:20\nRUN --mount=type=secret,id=npmrc cp /run/secrets/npmrc /root/.npmrc && npm ci && rm /root/.npmrc\n",
+			expected: 0,
+		},
+		{
+			name:     "written to a tmpfs mount",
+			source:   "FROM node:20\nRUN --mount=type=tmpfs,target=/scratch --mount=type=secret,id=npmrc cat /run/secrets/npmrc > /scratch/.npmrc && npm ci --userconfig /scratch/.npmrc\n",
+			expected: 0,
+		},
+		{
+			name:     "written to /dev/null",
+			source:   "FROM alpine:3.19\nRUN --mount=type=secret,id=token cat /run/secrets/token > /dev/null\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &SEC014SecretMountLeaked{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package security
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// SEC015UnquotedVariable checks for unquoted variables in destructive commands
+type SEC015UnquotedVariable struct{}
+
+func (r *SEC015UnquotedVariable) ID() string          { return "SEC015" }
+func (r *SEC015UnquotedVariable) Name() string        { return "unquoted-variable" }
+func (r *SEC015UnquotedVariable) Category() analyzer.Category { return analyzer.CategorySecurity }
+func (r *SEC015UnquotedVariable) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+
+func (r *SEC015UnquotedVariable) Description() string {
+	return "An unquoted variable in rm -r, chmod, or chown is split on whitespace and expanded as a glob, and an empty value can turn the command onto the wrong files."
+}
+
+// Commands that change or remove whatever paths they are given
+var riskyCommands = map[string]bool{
+	"rm": true, "chmod": true, "chown": true, "chgrp": true,
+}
+
+func (r *SEC015UnquotedVariable) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for _, stage := range df.Stages {
+		posix := true
+
+		for _, inst := range stage.Instructions {
+			if sh, ok := inst.(*parser.ShellInstruction); ok {
+				posix = shell.IsPOSIX(sh.Shell)
+			}
+
+			// Exec form has no shell, so nothing is expanded
+			run, ok := inst.(*parser.RunInstruction)
+			if !ok || run.IsExec || !posix {
+				continue
+			}
+
+			cmd := run.Command
+			if run.Heredoc != nil {
+				cmd += "\n" + run.Heredoc.Content
+			}
+
+			for _, c := range shell.Split(cmd) {
+				name := path.Base(c.Name())
+				if !riskyCommands[name] || name == "rm" && !recursiveRm(c) {
+					continue
+				}
+
+				for _, w := range c.Args()[1:] {
+					for _, v := range shell.UnquotedVars(cmd[w.Start:w.End]) {
+						diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+							WithSeverity(r.Severity()).
+							WithMessagef("$%s is unquoted in %s; an empty or multi-word value changes what it acts on", v, name).
+							WithPos(run.Pos()).
+							WithContext(ctx.GetLine(run.Pos().Line)).
+							WithHelp(fmt.Sprintf("Quote the variable as \"$%s\", or use \"${%s:?}\" to fail the build when it is empty", v, v)).
+							Build()
+						diags = append(diags, diag)
+					}
+				}
+			}
+		}
+	}
+
+	return diags
+}
+
+// recursiveRm reports whether an rm command removes directories
+func recursiveRm(c shell.Command) bool {
+	for _, w := range c.Args()[1:] {
+		if w.Value == "--recursive" {
+			return true
+		}
+		if strings.HasPrefix(w.Value, "-") && !strings.HasPrefix(w.Value, "--") && strings.ContainsAny(w.Value, "rR") {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	Register(&SEC015UnquotedVariable{})
+}
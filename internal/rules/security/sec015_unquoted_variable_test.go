@@ -0,0 +1,76 @@
+package security
+
+import "testing"
+
+func TestSEC015UnquotedVariable(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "unquoted rm -rf",
+			source:   "FROM alpine:3.19\nARG DIR\nRUN rm -rf $DIR\n",
+			expected: 1,
+		},
+		{
+			name:     "quoted rm -rf",
+			source:   "FROM alpine:3.19\nARG DIR\nRUN rm -rf \"$DIR\"\n",
+			expected: 0,
+		},
+		{
+			name:     "braced variable in a path",
+			source:   "FROM alpine:3.19\nRUN rm -rf ${APP_HOME}/cache\n",
+			expected: 1,
+		},
+		{
+			name:     "chown in a chain",
+			source:   "FROM alpine:3.19\nRUN adduser -D app && chown -R app:app $APP_DIR\n",
+			expected: 1,
+		},
+		{
+			name:     "chmod with quoted path",
+			source:   "FROM alpine:3.19\nRUN chmod 755 \"${APP_DIR}/bin\"\n",
+			expected: 0,
+		},
+		{
+			name:     "rm without recursion",
+			source:   "FROM alpine:3.19\nRUN rm -f $TMPFILE\n",
+			expected: 0,
+		},
+		{
+			name:     "other commands",
+			source:   "FROM alpine:3.19\nRUN mkdir -p $DIR && echo $DIR\n",
+			expected: 0,
+		},
+		{
+			name:     "single quotes",
+			source:   "FROM alpine:3.19\nRUN sh -c 'rm -rf $DIR'\n",
+			expected: 0,
+		},
+		{
+			name:     "heredoc",
+			source:   "FROM alpine:3.19\nRUN <<EOF\nset -e\nrm -rf $BUILD_DIR\nEOF\n",
+			expected: 1,
+		},
+		{
+			name:     "exec form",
+			source:   "FROM alpine:3.19\nRUN [\"rm\", \"-rf\", \"$DIR\"]\n",
+			expected: 0,
+		},
+		{
+			name:     "non-POSIX shell",
+			source:   "FROM mcr.microsoft.com/windows/servercore:ltsc2022\nSHELL [\"powershell\", \"-Command\"]\nRUN rm -r $env:TEMP\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &SEC015UnquotedVariable{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
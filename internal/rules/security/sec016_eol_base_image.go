@@ -0,0 +1,196 @@
+package security
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// SEC016EOLBaseImage checks for base images that no longer receive updates
+type SEC016EOLBaseImage struct{}
+
+func (r *SEC016EOLBaseImage) ID() string          { return "SEC016" }
+func (r *SEC016EOLBaseImage) Name() string        { return "eol-base-image" }
+func (r *SEC016EOLBaseImage) Category() analyzer.Category { return analyzer.CategorySecurity }
+func (r *SEC016EOLBaseImage) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+func (r *SEC016EOLBaseImage) Tags() []string { return []string{"supply-chain"} }
+
+func (r *SEC016EOLBaseImage) Description() string {
+	return "Base image is end-of-life and no longer receives security updates. Upgrade to a supported version."
+}
+
+// End-of-life images and the supported version to suggest for each. An
+// entry without a tag matches every tag of the image. Replace the list
+// with the "images" config key, or add to it with "extra_images"; both
+// take a mapping of image to suggestion, or a list of images.
+var defaultEOLImages = map[string]string{
+	"python:2.7":     "python:3.13",
+	"python:3.6":     "python:3.13",
+	"python:3.7":     "python:3.13",
+	"python:3.8":     "python:3.13",
+	"python:3.9":     "python:3.13",
+	"node:8":         "node:24",
+	"node:10":        "node:24",
+	"node:12":        "node:24",
+	"node:14":        "node:24",
+	"node:16":        "node:24",
+	"node:18":        "node:24",
+	"ubuntu:14.04":   "ubuntu:24.04",
+	"ubuntu:16.04":   "ubuntu:24.04",
+	"ubuntu:18.04":   "ubuntu:24.04",
+	"ubuntu:20.04":   "ubuntu:24.04",
+	"ubuntu:xenial":  "ubuntu:noble",
+	"ubuntu:bionic":  "ubuntu:noble",
+	"ubuntu:focal":   "ubuntu:noble",
+	"debian:8":       "debian:12",
+	"debian:9":       "debian:12",
+	"debian:10":      "debian:12",
+	"debian:jessie":  "debian:bookworm",
+	"debian:stretch": "debian:bookworm",
+	"debian:buster":  "debian:bookworm",
+	"centos":         "rockylinux:9",
+}
+
+var argRefPattern = regexp.MustCompile(`\$(?:\{([A-Za-z_][A-Za-z0-9_]*)\}|([A-Za-z_][A-Za-z0-9_]*))`)
+
+func (r *SEC016EOLBaseImage) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	eol := defaultEOLImages
+	if v, ok := ctx.Config["images"]; ok {
+		eol = eolImages(v)
+	}
+	if v, ok := ctx.Config["extra_images"]; ok {
+		merged := make(map[string]string, len(eol))
+		for ref, suggestion := range eol {
+			merged[ref] = suggestion
+		}
+		for ref, suggestion := range eolImages(v) {
+			merged[ref] = suggestion
+		}
+		eol = merged
+	}
+
+	defaults := make(map[string]string)
+	for _, arg := range df.Args {
+		if arg.HasDefault {
+			defaults[arg.Name] = arg.DefaultValue
+		}
+	}
+
+	for _, stage := range df.Stages {
+		from := stage.From
+		if from == nil || from.BaseStage != "" {
+			continue
+		}
+
+		ref := from.Image
+		if from.Tag != "" {
+			ref += ":" + from.Tag
+		}
+		resolved, ok := expandArgs(ref, defaults)
+		if !ok {
+			continue
+		}
+
+		match, found := matchEOL(resolved, eol)
+		if !found {
+			continue
+		}
+
+		msg := "Base image " + resolved + " is end-of-life"
+		if resolved != ref {
+			msg += " (resolved from " + ref + ")"
+		}
+		help := "Upgrade to a supported version"
+		if suggestion := eol[match]; suggestion != "" {
+			help += ", e.g. " + suggestion
+		}
+
+		diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+			WithSeverity(r.Severity()).
+			WithMessage(msg).
+			WithPos(from.Pos()).
+			WithContext(ctx.GetLine(from.Pos().Line)).
+			WithHelp(help).
+			Build()
+		diags = append(diags, diag)
+	}
+
+	return diags
+}
+
+// eolImages reads an image list from rule config: a mapping of image to
+// suggested replacement, or a list of images without suggestions
+func eolImages(v interface{}) map[string]string {
+	images := make(map[string]string)
+	switch list := v.(type) {
+	case map[string]interface{}:
+		for ref, suggestion := range list {
+			s, _ := suggestion.(string)
+			images[ref] = s
+		}
+	case []interface{}:
+		for _, ref := range list {
+			if s, ok := ref.(string); ok {
+				images[s] = ""
+			}
+		}
+	case []string:
+		for _, ref := range list {
+			images[ref] = ""
+		}
+	}
+	return images
+}
+
+// expandArgs substitutes global ARG defaults into s. It reports false if
+// s references an ARG without a default.
+func expandArgs(s string, defaults map[string]string) (string, bool) {
+	ok := true
+	expanded := argRefPattern.ReplaceAllStringFunc(s, func(m string) string {
+		sub := argRefPattern.FindStringSubmatch(m)
+		name := sub[1] + sub[2]
+		v, found := defaults[name]
+		if !found {
+			ok = false
+		}
+		return v
+	})
+	return expanded, ok && !strings.Contains(expanded, "$")
+}
+
+// matchEOL returns the entry of eol that ref matches. A tag entry such as
+// node:10 matches the tags 10, 10.24.1, and 10-alpine, but not 100.
+func matchEOL(ref string, eol map[string]string) (string, bool) {
+	if i := strings.Index(ref, "@"); i >= 0 {
+		ref = ref[:i]
+	}
+	image, tag := ref, ""
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		image, tag = ref[:i], ref[i+1:]
+	}
+	image = strings.TrimPrefix(image, "docker.io/")
+	image = strings.TrimPrefix(image, "index.docker.io/")
+	image = strings.TrimPrefix(image, "library/")
+
+	if _, ok := eol[image]; ok {
+		return image, true
+	}
+	for entry := range eol {
+		entryImage, entryTag, hasTag := strings.Cut(entry, ":")
+		if !hasTag || entryImage != image {
+			continue
+		}
+		if tag == entryTag || strings.HasPrefix(tag, entryTag+".") || strings.HasPrefix(tag, entryTag+"-") {
+			return entry, true
+		}
+	}
+	return "", false
+}
+
+func init() {
+	Register(&SEC016EOLBaseImage{})
+}
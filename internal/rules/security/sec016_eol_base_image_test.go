@@ -0,0 +1,91 @@
+package security
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+func TestSEC016EOLBaseImage(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{"eol tag", "FROM python:3.6\n", 1},
+		{"eol tag variant", "FROM node:10-alpine\n", 1},
+		{"eol patch version", "FROM ubuntu:18.04.1\n", 1},
+		{"current tag", "FROM python:3.13-slim\n", 0},
+		{"similar tag", "FROM node:100\n", 0},
+		{"official image with registry", "FROM docker.io/library/ubuntu:18.04\n", 1},
+		{"other registry", "FROM registry.example.com/python:3.6\n", 0},
+		{"image without tag entry", "FROM centos:7\n", 1},
+		{"no tag", "FROM python\n", 0},
+		{"global arg tag", "ARG PY=3.6\nFROM python:${PY}-slim\n", 1},
+		{"global arg image", "ARG BASE=node:10\nFROM $BASE\n", 1},
+		{"arg without default", "ARG PY\nFROM python:${PY}\n", 0},
+		{"stage reference", "FROM node:22 AS build\nFROM build\n", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &SEC016EOLBaseImage{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
+
+func TestSEC016EOLBaseImage_Message(t *testing.T) {
+	diags := runRule(t, &SEC016EOLBaseImage{}, "ARG PY=3.6\nFROM python:${PY}\n")
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(diags))
+	}
+	if !strings.Contains(diags[0].Message, "python:3.6") || !strings.Contains(diags[0].Message, "python:${PY}") {
+		t.Errorf("expected the resolved and original image in the message, got %q", diags[0].Message)
+	}
+	if !strings.Contains(diags[0].Help, "python:3.13") {
+		t.Errorf("expected a suggested version in the help, got %q", diags[0].Help)
+	}
+}
+
+func TestSEC016EOLBaseImage_Configured(t *testing.T) {
+	source := "FROM python:3.6\nFROM golang:1.19\nFROM ruby:2.7\n"
+
+	tests := []struct {
+		name     string
+		config   map[string]interface{}
+		expected []string
+	}{
+		{
+			name:     "extra images",
+			config:   map[string]interface{}{"extra_images": map[string]interface{}{"golang:1.19": "golang:1.25"}},
+			expected: []string{"python:3.6", "golang:1.19"},
+		},
+		{
+			name:     "replaced images",
+			config:   map[string]interface{}{"images": []interface{}{"ruby:2.7"}},
+			expected: []string{"ruby:2.7"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := analyzer.New(
+				analyzer.WithRules(&SEC016EOLBaseImage{}),
+				analyzer.WithRuleConfig("SEC016", tt.config),
+			)
+			result, _ := a.AnalyzeSource(source, "Dockerfile")
+			if len(result.Diagnostics) != len(tt.expected) {
+				t.Fatalf("expected %d diagnostics, got %d: %v", len(tt.expected), len(result.Diagnostics), result.Diagnostics)
+			}
+			for i, image := range tt.expected {
+				if !strings.Contains(result.Diagnostics[i].Message, image) {
+					t.Errorf("expected diagnostic %d to mention %s, got %q", i, image, result.Diagnostics[i].Message)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package security
+
+import (
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// SEC017RegistryAllowlist checks that base images come from approved registries
+type SEC017RegistryAllowlist struct{}
+
+func (r *SEC017RegistryAllowlist) ID() string          { return "SEC017" }
+func (r *SEC017RegistryAllowlist) Name() string        { return "registry-allowlist" }
+func (r *SEC017RegistryAllowlist) Category() analyzer.Category { return analyzer.CategorySecurity }
+func (r *SEC017RegistryAllowlist) Severity() analyzer.Severity { return analyzer.SeverityError }
+func (r *SEC017RegistryAllowlist) Tags() []string { return []string{"supply-chain"} }
+
+func (r *SEC017RegistryAllowlist) Description() string {
+	return "Base images must come from a registry in the allowed_registries list. Without the list, every registry is allowed."
+}
+
+func (r *SEC017RegistryAllowlist) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	// Entries are registry hosts such as ghcr.io, optionally with a path
+	// prefix such as docker.io/library
+	var allowed []string
+	switch list := ctx.Config["allowed_registries"].(type) {
+	case []string:
+		allowed = list
+	case []interface{}:
+		for _, v := range list {
+			if s, ok := v.(string); ok {
+				allowed = append(allowed, s)
+			}
+		}
+	}
+	if len(allowed) == 0 {
+		return diags
+	}
+
+	defaults := make(map[string]string)
+	for _, arg := range df.Args {
+		if arg.HasDefault {
+			defaults[arg.Name] = arg.DefaultValue
+		}
+	}
+
+	for _, stage := range df.Stages {
+		if stage.From == nil || stage.From.BaseStage != "" || strings.EqualFold(stage.From.Image, "scratch") {
+			continue
+		}
+
+		// Resolve parameterized images through global ARG defaults
+		from := *stage.From
+		var ok bool
+		if from.Image, ok = expandArgs(from.Image, defaults); !ok {
+			continue
+		}
+		if from.Tag, ok = expandArgs(from.Tag, defaults); !ok {
+			continue
+		}
+		if i := strings.LastIndex(from.Image, ":"); from.Tag == "" && i > strings.LastIndex(from.Image, "/") {
+			from.Image, from.Tag = from.Image[:i], from.Image[i+1:]
+		}
+
+		ref := from.CanonicalRef()
+		if registryAllowed(ref, allowed) {
+			continue
+		}
+		registry, _, _ := strings.Cut(ref, "/")
+
+		diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+			WithSeverity(r.Severity()).
+			WithMessagef("Base image %s comes from %s, which is not an allowed registry", stage.From.ImageRef(), registry).
+			WithPos(stage.From.Pos()).
+			WithContext(ctx.GetLine(stage.From.Pos().Line)).
+			WithHelp("Use an image from one of: " + strings.Join(allowed, ", ")).
+			Build()
+		diags = append(diags, diag)
+	}
+
+	return diags
+}
+
+// registryAllowed reports whether the canonical ref is under one of the
+// allowed registries or registry path prefixes
+func registryAllowed(ref string, allowed []string) bool {
+	ref = strings.ToLower(ref)
+	for _, a := range allowed {
+		a = strings.ToLower(strings.TrimSuffix(a, "/"))
+		if a == "index.docker.io" {
+			a = parser.DefaultRegistry
+		}
+		if strings.HasPrefix(ref, a+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	Register(&SEC017RegistryAllowlist{})
+}
@@ -0,0 +1,40 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+func TestSEC017RegistryAllowlist(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		allowed  []interface{}
+		expected int
+	}{
+		{"docker hub not allowed", "FROM docker.io/library/alpine:3.19\n", []interface{}{"ghcr.io"}, 1},
+		{"implicit docker hub not allowed", "FROM alpine:3.19\n", []interface{}{"ghcr.io"}, 1},
+		{"allowed registry", "FROM ghcr.io/org/app:1.0\n", []interface{}{"ghcr.io"}, 0},
+		{"registry with port", "FROM registry.example.com:5000/base:1\n", []interface{}{"registry.example.com:5000"}, 0},
+		{"path prefix", "FROM node:20\nFROM bitnami/node:20\n", []interface{}{"docker.io/library"}, 1},
+		{"no allowlist", "FROM alpine:3.19\n", nil, 0},
+		{"scratch", "FROM scratch\n", []interface{}{"ghcr.io"}, 0},
+		{"stage reference", "FROM ghcr.io/org/base:1 AS base\nFROM base\n", []interface{}{"ghcr.io"}, 0},
+		{"resolved through global arg", "ARG BASE=quay.io/org/app:1\nFROM $BASE\n", []interface{}{"ghcr.io"}, 1},
+		{"arg without default", "ARG BASE\nFROM $BASE\n", []interface{}{"ghcr.io"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []analyzer.Option{analyzer.WithRules(&SEC017RegistryAllowlist{})}
+			if tt.allowed != nil {
+				opts = append(opts, analyzer.WithRuleConfig("SEC017", map[string]interface{}{"allowed_registries": tt.allowed}))
+			}
+			result, _ := analyzer.New(opts...).AnalyzeSource(tt.source, "Dockerfile")
+			if len(result.Diagnostics) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(result.Diagnostics), result.Diagnostics)
+			}
+		})
+	}
+}
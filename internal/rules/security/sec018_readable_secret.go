@@ -0,0 +1,185 @@
+package security
+
+import (
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// SEC018ReadableSecret checks for sensitive files given permissions that
+// let users other than the owner read them
+type SEC018ReadableSecret struct{}
+
+func (r *SEC018ReadableSecret) ID() string          { return "SEC018" }
+func (r *SEC018ReadableSecret) Name() string        { return "readable-secret" }
+func (r *SEC018ReadableSecret) Category() analyzer.Category { return analyzer.CategorySecurity }
+func (r *SEC018ReadableSecret) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+
+func (r *SEC018ReadableSecret) Description() string {
+	return "Keys and other sensitive files should only be readable by their owner. A COPY --chmod or a later RUN chmod that grants read permission to the group or other users exposes them to every process in the container."
+}
+
+func (r *SEC018ReadableSecret) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	report := func(inst parser.Instruction, format string, args ...interface{}) {
+		diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+			WithSeverity(r.Severity()).
+			WithMessagef(format, args...).
+			WithPos(inst.Pos()).
+			WithContext(ctx.GetLine(inst.Pos().Line)).
+			WithHelp("Restrict the file to its owner, e.g. --chmod=600 or chmod 600, or mount it with RUN --mount=type=secret instead of copying it").
+			Build()
+		diags = append(diags, diag)
+	}
+
+	for _, stage := range df.Stages {
+		workdir := "/"
+		// Where sensitive files were copied to, and their descriptions
+		copied := make(map[string]string)
+
+		for _, inst := range stage.Instructions {
+			var sources []string
+			var dest, chmod, name string
+
+			switch v := inst.(type) {
+			case *parser.WorkdirInstruction:
+				workdir = absPath(workdir, v.Path)
+				continue
+			case *parser.RunInstruction:
+				if v.IsExec {
+					continue
+				}
+				script := v.Command
+				if v.Heredoc != nil {
+					script = v.Heredoc.Content
+				}
+				for _, cmd := range shell.Split(script) {
+					mode, files, recursive := parseChmod(cmd)
+					if mode == "" || !readableByOthers(mode) {
+						continue
+					}
+					for _, f := range files {
+						if file, desc, ok := copiedUnder(copied, absPath(workdir, f), recursive); ok {
+							report(v, "chmod %s makes %s (%s) readable by other users", mode, file, desc)
+							break
+						}
+					}
+				}
+				continue
+			case *parser.CopyInstruction:
+				if v.From != "" {
+					continue
+				}
+				sources, dest, chmod, name = v.Sources, v.Destination, v.Chmod, "COPY"
+			case *parser.AddInstruction:
+				sources, dest, chmod, name = v.Sources, v.Destination, v.Chmod, "ADD"
+			default:
+				continue
+			}
+
+			target := absPath(workdir, dest)
+			for _, src := range sources {
+				sensitive, desc := IsSensitiveFile(src)
+				if !sensitive {
+					continue
+				}
+				if chmod != "" && !strings.Contains(chmod, "$") && readableByOthers(chmod) {
+					report(inst, "%s --chmod=%s makes %s (%s) readable by other users", name, chmod, src, desc)
+				}
+
+				file := target
+				if strings.HasSuffix(dest, "/") || len(sources) > 1 {
+					file = path.Join(target, path.Base(src))
+				}
+				copied[file] = desc
+			}
+		}
+	}
+
+	return diags
+}
+
+// parseChmod returns the mode and files of a chmod command, and whether it
+// is recursive. The mode is "" for other commands.
+func parseChmod(cmd shell.Command) (mode string, files []string, recursive bool) {
+	args := cmd.Args()
+	if len(args) == 0 || path.Base(args[0].Value) != "chmod" {
+		return "", nil, false
+	}
+
+	for _, w := range args[1:] {
+		switch {
+		case w.Value == "--recursive":
+			recursive = true
+		case strings.HasPrefix(w.Value, "--"):
+		case mode == "" && strings.HasPrefix(w.Value, "-") && !symbolicMode.MatchString(w.Value):
+			// Short options such as -R or -Rv
+			recursive = recursive || strings.Contains(w.Value, "R")
+		case mode == "":
+			mode = w.Value
+		default:
+			files = append(files, w.Value)
+		}
+	}
+	return mode, files, recursive
+}
+
+// readableByOthers reports whether an octal or symbolic mode grants read
+// permission to the group or other users
+func readableByOthers(mode string) bool {
+	if octalMode.MatchString(mode) {
+		group, other := mode[len(mode)-2]-'0', mode[len(mode)-1]-'0'
+		return group&4 != 0 || other&4 != 0
+	}
+
+	for _, clause := range strings.Split(mode, ",") {
+		if !symbolicMode.MatchString(clause) {
+			return false
+		}
+		who := clause[:strings.IndexAny(clause, "+-=")]
+		if who != "" && !strings.ContainsAny(who, "goa") {
+			continue
+		}
+		for _, action := range symbolicAction.FindAllString(clause[len(who):], -1) {
+			if action[0] != '-' && strings.Contains(action, "r") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// copiedUnder returns the copied sensitive file at p, or under p when the
+// chmod is recursive
+func copiedUnder(copied map[string]string, p string, recursive bool) (string, string, bool) {
+	if desc, ok := copied[p]; ok {
+		return p, desc, true
+	}
+	if !recursive {
+		return "", "", false
+	}
+	for _, file := range slices.Sorted(maps.Keys(copied)) {
+		if strings.HasPrefix(file, strings.TrimSuffix(p, "/")+"/") {
+			return file, copied[file], true
+		}
+	}
+	return "", "", false
+}
+
+// absPath resolves p against workdir
+func absPath(workdir, p string) string {
+	if path.IsAbs(p) {
+		return path.Clean(p)
+	}
+	return path.Join(workdir, p)
+}
+
+func init() {
+	Register(&SEC018ReadableSecret{})
+}
@@ -0,0 +1,86 @@
+package security
+
+import "testing"
+
+func TestSEC018ReadableSecret(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "key copied world-readable",
+			source:   "FROM alpine\nCOPY --chmod=644 id_rsa /\n",
+			expected: 1,
+		},
+		{
+			name:     "key copied owner-only",
+			source:   "FROM alpine\nCOPY --chmod=600 id_rsa /\n",
+			expected: 0,
+		},
+		{
+			name:     "benign file",
+			source:   "FROM alpine\nCOPY --chmod=644 app.conf /etc/app/\n",
+			expected: 0,
+		},
+		{
+			name:     "group-readable symbolic mode",
+			source:   "FROM alpine\nCOPY --chmod=u=rw,g=r server.key /etc/ssl/\n",
+			expected: 1,
+		},
+		{
+			name:     "mode from a build arg",
+			source:   "FROM alpine\nARG MODE=600\nCOPY --chmod=$MODE id_rsa /\n",
+			expected: 0,
+		},
+		{
+			name:     "chmod after copy",
+			source:   "FROM alpine\nCOPY id_rsa /root/.ssh/id_rsa\nRUN chmod 644 /root/.ssh/id_rsa\n",
+			expected: 1,
+		},
+		{
+			name:     "chmod owner-only after copy",
+			source:   "FROM alpine\nCOPY id_rsa /root/.ssh/id_rsa\nRUN chmod 600 /root/.ssh/id_rsa\n",
+			expected: 0,
+		},
+		{
+			name:     "chmod relative to workdir",
+			source:   "FROM alpine\nWORKDIR /root/.ssh\nCOPY id_rsa ./\nRUN chmod a+r id_rsa\n",
+			expected: 1,
+		},
+		{
+			name:     "recursive chmod of the directory",
+			source:   "FROM alpine\nCOPY id_ed25519 /root/.ssh/\nRUN chmod -R 755 /root/.ssh\n",
+			expected: 1,
+		},
+		{
+			name:     "chmod of another file",
+			source:   "FROM alpine\nCOPY id_rsa /root/.ssh/\nCOPY run.sh /usr/local/bin/\nRUN chmod 755 /usr/local/bin/run.sh\n",
+			expected: 0,
+		},
+		{
+			name:     "chmod removing read",
+			source:   "FROM alpine\nCOPY id_rsa /root/.ssh/\nRUN chmod go-r /root/.ssh/id_rsa\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &SEC018ReadableSecret{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
+
+func TestSEC018ReadableSecret_OwnerOnlyStillSensitive(t *testing.T) {
+	source := "FROM alpine\nCOPY --chmod=600 id_rsa /\n"
+	if diags := runRule(t, &SEC006SensitiveFiles{}, source); len(diags) != 1 {
+		t.Errorf("expected SEC006 to still flag the key, got %v", diags)
+	}
+	if diags := runRule(t, &SEC018ReadableSecret{}, source); len(diags) != 0 {
+		t.Errorf("expected no permission diagnostic, got %v", diags)
+	}
+}
@@ -0,0 +1,121 @@
+package security
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// SEC019InsecureDownload checks for RUN downloading over plain HTTP
+type SEC019InsecureDownload struct{}
+
+func (r *SEC019InsecureDownload) ID() string          { return "SEC019" }
+func (r *SEC019InsecureDownload) Name() string        { return "insecure-download" }
+func (r *SEC019InsecureDownload) Category() analyzer.Category { return analyzer.CategorySecurity }
+func (r *SEC019InsecureDownload) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+func (r *SEC019InsecureDownload) Tags() []string { return []string{"supply-chain"} }
+
+func (r *SEC019InsecureDownload) Description() string {
+	return "Files fetched over plain http:// can be replaced by anyone on the network path between the build and the server. Download build dependencies and package sources over https://."
+}
+
+// An apt source line, e.g. deb [arch=amd64] http://example.com/debian stable main
+var aptSourcePattern = regexp.MustCompile(`\bdeb(-src)?\s+(\[[^\]]*\]\s+)?(http://\S+)`)
+
+// Hosts that never leave the build machine
+var loopbackHosts = map[string]bool{
+	"localhost": true, "127.0.0.1": true, "::1": true,
+}
+
+func (r *SEC019InsecureDownload) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for _, stage := range df.Stages {
+		posix := true
+
+		for _, inst := range stage.Instructions {
+			if sh, ok := inst.(*parser.ShellInstruction); ok {
+				posix = shell.IsPOSIX(sh.Shell)
+			}
+
+			run, ok := inst.(*parser.RunInstruction)
+			if !ok || run.IsExec || !posix {
+				continue
+			}
+
+			cmd := run.Command
+			if run.Heredoc != nil {
+				cmd = run.Heredoc.Content
+			}
+
+			for _, u := range insecureURLs(cmd) {
+				diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+					WithSeverity(r.Severity()).
+					WithMessagef("Download over plain HTTP: %s", u).
+					WithPos(run.Pos()).
+					WithContext(ctx.GetLine(run.Pos().Line)).
+					WithHelp("Use https:// so the download can't be tampered with in transit").
+					Build()
+				diags = append(diags, diag)
+			}
+		}
+	}
+
+	return diags
+}
+
+// insecureURLs returns the http:// URLs that cmd downloads with curl or
+// wget or adds as apt sources, leaving out loopback hosts
+func insecureURLs(cmd string) []string {
+	var urls []string
+	for _, c := range shell.Split(cmd) {
+		args := c.Args()
+		if len(args) == 0 {
+			continue
+		}
+
+		switch path.Base(args[0].Value) {
+		case "curl", "wget":
+			for _, a := range args[1:] {
+				w := a.Value
+				// Options such as --url=http://...
+				if _, v, ok := strings.Cut(w, "="); ok && strings.HasPrefix(w, "-") {
+					w = v
+				}
+				if isInsecureURL(w) {
+					urls = append(urls, w)
+				}
+			}
+		default:
+			// echo "deb http://..." > /etc/apt/sources.list, add-apt-repository
+			for _, a := range args[1:] {
+				for _, m := range aptSourcePattern.FindAllStringSubmatch(a.Value, -1) {
+					if isInsecureURL(m[3]) {
+						urls = append(urls, m[3])
+					}
+				}
+			}
+		}
+	}
+	return urls
+}
+
+func isInsecureURL(s string) bool {
+	if !strings.HasPrefix(strings.ToLower(s), "http://") {
+		return false
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return true
+	}
+	return !loopbackHosts[strings.ToLower(u.Hostname())]
+}
+
+func init() {
+	Register(&SEC019InsecureDownload{})
+}
@@ -0,0 +1,71 @@
+package security
+
+import "testing"
+
+func TestSEC019InsecureDownload(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "curl over http",
+			source:   "FROM alpine:3.20\nRUN curl -fsSL http://example.com/x -o /tmp/x\n",
+			expected: 1,
+		},
+		{
+			name:     "curl over https",
+			source:   "FROM alpine:3.20\nRUN curl -fsSL https://example.com/x -o /tmp/x\n",
+			expected: 0,
+		},
+		{
+			name:     "localhost",
+			source:   "FROM alpine:3.20\nRUN curl http://localhost:8080/health\n",
+			expected: 0,
+		},
+		{
+			name:     "loopback address",
+			source:   "FROM alpine:3.20\nRUN wget -qO- http://127.0.0.1/x\n",
+			expected: 0,
+		},
+		{
+			name:     "wget over http",
+			source:   "FROM alpine:3.20\nRUN wget http://example.com/tool.tar.gz && tar xzf tool.tar.gz\n",
+			expected: 1,
+		},
+		{
+			name:     "url option",
+			source:   "FROM alpine:3.20\nRUN curl --url=http://example.com/x\n",
+			expected: 1,
+		},
+		{
+			name:     "apt source over http",
+			source:   "FROM debian:12\nRUN echo \"deb [arch=amd64] http://repo.example.com/debian stable main\" > /etc/apt/sources.list.d/example.list\n",
+			expected: 1,
+		},
+		{
+			name:     "apt source over https",
+			source:   "FROM debian:12\nRUN echo \"deb https://repo.example.com/debian stable main\" > /etc/apt/sources.list.d/example.list\n",
+			expected: 0,
+		},
+		{
+			name:     "http URL not downloaded",
+			source:   "FROM alpine:3.20\nRUN echo http://example.com\n",
+			expected: 0,
+		},
+		{
+			name:     "heredoc",
+			source:   "FROM alpine:3.20\nRUN <<EOF\ncurl -o /tmp/x http://example.com/x\nEOF\n",
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &SEC019InsecureDownload{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
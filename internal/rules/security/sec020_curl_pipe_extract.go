@@ -0,0 +1,155 @@
+package security
+
+import (
+	"path"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// SEC020CurlPipeExtract checks for curl/wget piped into tar
+type SEC020CurlPipeExtract struct{}
+
+func (r *SEC020CurlPipeExtract) ID() string          { return "SEC020" }
+func (r *SEC020CurlPipeExtract) Name() string        { return "curl-pipe-extract" }
+func (r *SEC020CurlPipeExtract) Category() analyzer.Category { return analyzer.CategorySecurity }
+func (r *SEC020CurlPipeExtract) Severity() analyzer.Severity { return analyzer.SeverityWarning }
+func (r *SEC020CurlPipeExtract) Tags() []string { return []string{"supply-chain"} }
+
+func (r *SEC020CurlPipeExtract) Description() string {
+	return "curl/wget piped into tar extracts whatever the server sends, with no chance to check it first. Download the archive to a file, verify its checksum, then extract it."
+}
+
+func (r *SEC020CurlPipeExtract) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for _, stage := range df.Stages {
+		posix := true
+
+		for _, inst := range stage.Instructions {
+			if sh, ok := inst.(*parser.ShellInstruction); ok {
+				posix = shell.IsPOSIX(sh.Shell)
+			}
+
+			run, ok := inst.(*parser.RunInstruction)
+			if !ok || run.IsExec || !posix {
+				continue
+			}
+
+			cmd := run.Command
+			if run.Heredoc != nil {
+				cmd = run.Heredoc.Content
+			}
+
+			for _, tool := range pipedExtracts(cmd) {
+				diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+					WithSeverity(r.Severity()).
+					WithMessagef("%s output extracted without verification", tool).
+					WithPos(run.Pos()).
+					WithContext(ctx.GetLine(run.Pos().Line)).
+					WithHelp("Download to a file and check it before extracting, e.g., curl -fsSLo app.tgz URL && echo \"$SHA256  app.tgz\" | sha256sum -c && tar xzf app.tgz").
+					Build()
+				diags = append(diags, diag)
+			}
+		}
+	}
+
+	return diags
+}
+
+// pipedExtracts returns the download tool of each pipeline in cmd that
+// feeds curl or wget output into tar, possibly through other commands
+// such as gunzip
+func pipedExtracts(cmd string) []string {
+	var tools []string
+	cmds := shell.Split(cmd)
+	for i, c := range cmds {
+		args := c.Args()
+		if len(args) == 0 || c.Op != shell.OpPipe {
+			continue
+		}
+
+		tool := path.Base(args[0].Value)
+		switch {
+		case tool == "curl" && curlToStdout(args[1:]):
+		case tool == "wget" && wgetToStdout(args[1:]):
+		default:
+			continue
+		}
+
+		for j := i + 1; j < len(cmds); j++ {
+			next := cmds[j].Args()
+			if len(next) > 0 && (path.Base(next[0].Value) == "tar" || path.Base(next[0].Value) == "bsdtar") && extractsArchive(next[1:]) {
+				tools = append(tools, tool)
+				break
+			}
+			if cmds[j].Op != shell.OpPipe {
+				break
+			}
+		}
+	}
+	return tools
+}
+
+// curlToStdout reports whether curl writes the download to stdout, which
+// it does unless given -o/--output or -O/--remote-name
+func curlToStdout(args []shell.Word) bool {
+	for _, a := range args {
+		w := a.Value
+		switch {
+		case w == "--output" || w == "--remote-name" || w == "--remote-name-all" || strings.HasPrefix(w, "--output="):
+			return false
+		case strings.HasPrefix(w, "--"):
+		case strings.HasPrefix(w, "-") && strings.ContainsAny(w, "oO"):
+			return false
+		}
+	}
+	return true
+}
+
+// wgetToStdout reports whether wget writes the download to stdout, with
+// -O - or its spellings such as -qO-
+func wgetToStdout(args []shell.Word) bool {
+	for i, a := range args {
+		w := a.Value
+		switch {
+		case w == "--output-document=-":
+			return true
+		case w == "--output-document":
+			return i+1 < len(args) && args[i+1].Value == "-"
+		case strings.HasPrefix(w, "--"):
+		case strings.HasPrefix(w, "-"):
+			if j := strings.IndexByte(w, 'O'); j > 0 {
+				if rest := w[j+1:]; rest != "" {
+					return rest == "-"
+				}
+				return i+1 < len(args) && args[i+1].Value == "-"
+			}
+		}
+	}
+	return false
+}
+
+// extractsArchive reports whether the tar arguments extract, with -x,
+// --extract, or an old-style bundle such as xzf
+func extractsArchive(args []shell.Word) bool {
+	for i, a := range args {
+		w := a.Value
+		switch {
+		case w == "--extract" || w == "--get":
+			return true
+		case strings.HasPrefix(w, "--"):
+		case strings.HasPrefix(w, "-") || i == 0:
+			if strings.Contains(w, "x") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func init() {
+	Register(&SEC020CurlPipeExtract{})
+}
@@ -0,0 +1,76 @@
+package security
+
+import "testing"
+
+func TestSEC020CurlPipeExtract(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "curl piped to tar",
+			source:   "FROM alpine:3.20\nRUN curl -fsSL https://example.com/app.tgz | tar xz -C /opt\n",
+			expected: 1,
+		},
+		{
+			name:     "wget to stdout piped to tar",
+			source:   "FROM alpine:3.20\nRUN wget -qO- https://example.com/app.tgz | tar -xzf - -C /opt\n",
+			expected: 1,
+		},
+		{
+			name:     "wget -O - piped to tar",
+			source:   "FROM alpine:3.20\nRUN wget -O - https://example.com/app.tgz | tar --extract --gzip\n",
+			expected: 1,
+		},
+		{
+			name:     "through gunzip",
+			source:   "FROM alpine:3.20\nRUN curl -sL https://example.com/app.tar.gz | gunzip | tar x\n",
+			expected: 1,
+		},
+		{
+			name:     "download, verify, then extract",
+			source:   "FROM alpine:3.20\nRUN curl -fsSLo /tmp/app.tgz https://example.com/app.tgz && echo \"abc123  /tmp/app.tgz\" | sha256sum -c && tar xzf /tmp/app.tgz -C /opt\n",
+			expected: 0,
+		},
+		{
+			name:     "curl saved to a file",
+			source:   "FROM alpine:3.20\nRUN curl -o /tmp/app.tgz https://example.com/app.tgz | tar tz\n",
+			expected: 0,
+		},
+		{
+			name:     "tar listing",
+			source:   "FROM alpine:3.20\nRUN curl -sL https://example.com/app.tgz | tar tz\n",
+			expected: 0,
+		},
+		{
+			name:     "piped to a shell",
+			source:   "FROM alpine:3.20\nRUN curl -sL https://example.com/install.sh | sh\n",
+			expected: 0,
+		},
+		{
+			name:     "extract in a later pipeline",
+			source:   "FROM alpine:3.20\nRUN curl -sL https://example.com/sums | grep app && tar xzf app.tgz\n",
+			expected: 0,
+		},
+		{
+			name:     "heredoc",
+			source:   "FROM alpine:3.20\nRUN <<EOF\ncurl -sL https://example.com/app.tgz | tar xz\nEOF\n",
+			expected: 1,
+		},
+		{
+			name:     "non-POSIX shell",
+			source:   "FROM mcr.microsoft.com/windows/servercore:ltsc2022\nSHELL [\"powershell\", \"-Command\"]\nRUN curl -sL https://example.com/app.tgz | tar xz\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &SEC020CurlPipeExtract{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package style
+
+import (
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+// runRule analyzes source with a single rule and returns its diagnostics
+func runRule(t *testing.T, rule Rule, source string) []analyzer.Diagnostic {
+	t.Helper()
+	a := analyzer.New(
+		analyzer.WithRules(rule),
+		analyzer.WithMinSeverity(analyzer.SeverityHint),
+		analyzer.WithDisabled(analyzer.ParseRuleID),
+	)
+	result, _ := a.AnalyzeSource(source, "Dockerfile")
+	return result.Diagnostics
+}
@@ -0,0 +1,84 @@
+package style
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/lexer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// STY002Whitespace checks for trailing whitespace and tab indentation
+type STY002Whitespace struct{}
+
+func (r *STY002Whitespace) ID() string          { return "STY002" }
+func (r *STY002Whitespace) Name() string        { return "whitespace" }
+func (r *STY002Whitespace) Category() analyzer.Category { return analyzer.CategoryStyle }
+func (r *STY002Whitespace) Severity() analyzer.Severity { return analyzer.SeverityHint }
+
+func (r *STY002Whitespace) Description() string {
+	return "Lines should not end with whitespace or be indented with tabs. `keel fmt` removes both."
+}
+
+func (r *STY002Whitespace) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	// Whitespace in heredoc bodies is content, and <<- strips leading tabs
+	heredocLines := make(map[int]bool)
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			if run, ok := inst.(*parser.RunInstruction); ok && run.Heredoc != nil {
+				for line := run.Pos().Line + 1; line <= run.End().Line; line++ {
+					heredocLines[line] = true
+				}
+			}
+		}
+	}
+
+	for i, line := range ctx.SourceLines {
+		lineNum := i + 1
+		line = strings.TrimSuffix(line, "\r")
+		if heredocLines[lineNum] {
+			continue
+		}
+
+		trimmed := strings.TrimRight(line, " \t")
+		if len(trimmed) < len(line) {
+			diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithMessage("Trailing whitespace").
+				WithRange(
+					lexer.Position{Line: lineNum, Column: utf8.RuneCountInString(trimmed) + 1},
+					lexer.Position{Line: lineNum, Column: utf8.RuneCountInString(line) + 1},
+				).
+				WithContext(line).
+				WithHelp("Remove the trailing whitespace, or run keel fmt").
+				WithFix("").
+				Build()
+			diags = append(diags, diag)
+		}
+
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if strings.Contains(indent, "\t") && trimmed != "" {
+			diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithMessage("Line is indented with tabs").
+				WithRange(
+					lexer.Position{Line: lineNum, Column: 1},
+					lexer.Position{Line: lineNum, Column: len(indent) + 1},
+				).
+				WithContext(line).
+				WithHelp("Indent with spaces, or run keel fmt").
+				WithFix(strings.ReplaceAll(indent, "\t", "    ")).
+				Build()
+			diags = append(diags, diag)
+		}
+	}
+
+	return diags
+}
+
+func init() {
+	Register(&STY002Whitespace{})
+}
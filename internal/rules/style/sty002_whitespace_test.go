@@ -0,0 +1,68 @@
+package style
+
+import (
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+func TestSTY002Whitespace(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "trailing spaces",
+			source:   "FROM alpine:3.18  \nRUN make\n",
+			expected: 1,
+		},
+		{
+			name:     "clean lines",
+			source:   "FROM alpine:3.18\nRUN make \\\n    && make install\n",
+			expected: 0,
+		},
+		{
+			name:     "tab indentation",
+			source:   "FROM alpine:3.18\nRUN make \\\n\t&& make install\n",
+			expected: 1,
+		},
+		{
+			name:     "heredoc body",
+			source:   "FROM alpine:3.18\nRUN <<EOF\n\tmake  \nEOF\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &STY002Whitespace{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
+
+func TestSTY002Whitespace_Fix(t *testing.T) {
+	source := "FROM alpine:3.18  \nRUN make \\\n\t&& make install\n"
+	diags := runRule(t, &STY002Whitespace{}, source)
+
+	var edits []analyzer.TextEdit
+	for _, d := range diags {
+		edit, ok := analyzer.FixEdit(d)
+		if !ok {
+			t.Fatalf("expected %v to be fixable", d)
+		}
+		edits = append(edits, edit)
+	}
+
+	got, err := analyzer.ApplyEdits(source, edits)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "FROM alpine:3.18\nRUN make \\\n    && make install\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
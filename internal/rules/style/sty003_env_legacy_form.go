@@ -0,0 +1,70 @@
+package style
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/lexer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// STY003EnvLegacyForm checks for ENV in the legacy "key value" form
+type STY003EnvLegacyForm struct{}
+
+func (r *STY003EnvLegacyForm) ID() string          { return "STY003" }
+func (r *STY003EnvLegacyForm) Name() string        { return "env-legacy-form" }
+func (r *STY003EnvLegacyForm) Category() analyzer.Category { return analyzer.CategoryStyle }
+func (r *STY003EnvLegacyForm) Severity() analyzer.Severity { return analyzer.SeverityInfo }
+
+func (r *STY003EnvLegacyForm) Description() string {
+	return "ENV should use the key=value form; the legacy \"ENV key value\" form is ambiguous and deprecated."
+}
+
+func (r *STY003EnvLegacyForm) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			env, ok := inst.(*parser.EnvInstruction)
+			if !ok || !env.Legacy || len(env.Variables) != 1 {
+				continue
+			}
+
+			kv := env.Variables[0]
+			replacement := "ENV " + kv.Key + "=" + quoteEnvValue(kv.Value)
+
+			builder := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithMessagef("ENV %s uses the legacy \"key value\" form", kv.Key).
+				WithPos(env.Pos()).
+				WithContext(ctx.GetLine(env.Pos().Line)).
+				WithHelp("Use " + replacement)
+
+			// Only single-line instructions can be replaced in place
+			line := strings.TrimRight(ctx.GetLine(env.Pos().Line), " \t\r")
+			if !strings.HasSuffix(line, "\\") {
+				builder = builder.
+					WithRange(env.Pos(), lexer.Position{Line: env.Pos().Line, Column: utf8.RuneCountInString(line) + 1}).
+					WithFix(replacement)
+			}
+			diags = append(diags, builder.Build())
+		}
+	}
+
+	return diags
+}
+
+// quoteEnvValue double-quotes an ENV value when it contains whitespace or
+// quotes, so that it reads as a single value in key=value form
+func quoteEnvValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\"'\\") {
+		return value
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + r.Replace(value) + `"`
+}
+
+func init() {
+	Register(&STY003EnvLegacyForm{})
+}
@@ -0,0 +1,57 @@
+package style
+
+import (
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+func TestSTY003EnvLegacyForm(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "legacy form",
+			source:   "FROM alpine:3.18\nENV APP_HOME /app\n",
+			expected: 1,
+		},
+		{
+			name:     "equals form",
+			source:   "FROM alpine:3.18\nENV APP_HOME=/app\n",
+			expected: 0,
+		},
+		{
+			name:     "multiple pairs",
+			source:   "FROM alpine:3.18\nENV A=1 B=\"two words\"\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &STY003EnvLegacyForm{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
+
+func TestSTY003EnvLegacyForm_Fix(t *testing.T) {
+	source := "FROM alpine:3.18\nENV GREETING hello world\n"
+	diags := runRule(t, &STY003EnvLegacyForm{}, source)
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(diags))
+	}
+
+	got, err := analyzer.ApplyFix(source, diags[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "FROM alpine:3.18\nENV GREETING=\"hello world\"\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,80 @@
+package style
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/lexer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// STY004ExposeDuplicatePort checks for EXPOSE listing the same port more than once
+type STY004ExposeDuplicatePort struct{}
+
+func (r *STY004ExposeDuplicatePort) ID() string          { return "STY004" }
+func (r *STY004ExposeDuplicatePort) Name() string        { return "expose-duplicate-port" }
+func (r *STY004ExposeDuplicatePort) Category() analyzer.Category { return analyzer.CategoryStyle }
+func (r *STY004ExposeDuplicatePort) Severity() analyzer.Severity { return analyzer.SeverityInfo }
+
+func (r *STY004ExposeDuplicatePort) Description() string {
+	return "EXPOSE lists the same port more than once, for example 80 and 80/tcp; tcp is the default protocol."
+}
+
+func (r *STY004ExposeDuplicatePort) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			expose, ok := inst.(*parser.ExposeInstruction)
+			if !ok {
+				continue
+			}
+
+			unique := expose.UniquePorts()
+			if len(unique) == len(expose.Ports) {
+				continue
+			}
+
+			// Name each duplicate alongside the port it repeats
+			first := make(map[string]parser.PortSpec)
+			var dups []string
+			for _, p := range expose.Ports {
+				key := p.Port + "/" + p.EffectiveProtocol()
+				if f, ok := first[key]; ok {
+					dups = append(dups, f.String()+" and "+p.String())
+				} else {
+					first[key] = p
+				}
+			}
+
+			var ports []string
+			for _, p := range unique {
+				ports = append(ports, p.String())
+			}
+			replacement := "EXPOSE " + strings.Join(ports, " ")
+
+			builder := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithMessagef("EXPOSE lists the same port twice: %s", strings.Join(dups, ", ")).
+				WithPos(expose.Pos()).
+				WithContext(ctx.GetLine(expose.Pos().Line)).
+				WithHelp("Use " + replacement)
+
+			// Only single-line instructions can be replaced in place
+			line := strings.TrimRight(ctx.GetLine(expose.Pos().Line), " \t\r")
+			if !strings.HasSuffix(line, "\\") {
+				builder = builder.
+					WithRange(expose.Pos(), lexer.Position{Line: expose.Pos().Line, Column: utf8.RuneCountInString(line) + 1}).
+					WithFix(replacement)
+			}
+			diags = append(diags, builder.Build())
+		}
+	}
+
+	return diags
+}
+
+func init() {
+	Register(&STY004ExposeDuplicatePort{})
+}
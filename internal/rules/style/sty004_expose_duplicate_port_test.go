@@ -0,0 +1,47 @@
+package style
+
+import "testing"
+
+func TestSTY004ExposeDuplicatePort(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+		fix      string
+	}{
+		{
+			name:     "implicit and explicit tcp",
+			source:   "FROM alpine\nEXPOSE 80 80/tcp\n",
+			expected: 1,
+			fix:      "EXPOSE 80",
+		},
+		{
+			name:     "repeated port",
+			source:   "FROM alpine\nEXPOSE 443/tcp 8080 443/TCP\n",
+			expected: 1,
+			fix:      "EXPOSE 443/tcp 8080",
+		},
+		{
+			name:     "tcp and udp are distinct",
+			source:   "FROM alpine\nEXPOSE 80 80/udp\n",
+			expected: 0,
+		},
+		{
+			name:     "different ports",
+			source:   "FROM alpine\nEXPOSE 80 443\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &STY004ExposeDuplicatePort{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Fatalf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+			if tt.fix != "" && diags[0].FixSuggestion != tt.fix {
+				t.Errorf("expected fix %q, got %q", tt.fix, diags[0].FixSuggestion)
+			}
+		})
+	}
+}
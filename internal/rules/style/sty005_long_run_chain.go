@@ -0,0 +1,77 @@
+package style
+
+import (
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+	"github.com/HueCodes/keel/internal/shell"
+)
+
+// STY005LongRunChain checks for RUN instructions chaining too many commands
+type STY005LongRunChain struct{}
+
+func (r *STY005LongRunChain) ID() string          { return "STY005" }
+func (r *STY005LongRunChain) Name() string        { return "long-run-chain" }
+func (r *STY005LongRunChain) Category() analyzer.Category { return analyzer.CategoryStyle }
+func (r *STY005LongRunChain) Severity() analyzer.Severity { return analyzer.SeverityHint }
+
+func (r *STY005LongRunChain) Description() string {
+	return "A RUN joining many commands with && or ; is hard to read and review. A heredoc script keeps them in one layer, one command per line."
+}
+
+func (r *STY005LongRunChain) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	// Get configurable threshold (default 20)
+	threshold := 20
+	if v, ok := ctx.Config["max_commands"].(int); ok {
+		threshold = v
+	}
+
+	for _, stage := range df.Stages {
+		posix := true
+
+		for _, inst := range stage.Instructions {
+			if sh, ok := inst.(*parser.ShellInstruction); ok {
+				posix = shell.IsPOSIX(sh.Shell)
+			}
+
+			run, ok := inst.(*parser.RunInstruction)
+			if !ok || run.IsExec || run.Heredoc != nil || !posix {
+				continue
+			}
+
+			cmds := shell.Split(run.Command)
+			if len(cmds) == 0 {
+				continue
+			}
+
+			// Pipelines and background jobs stay part of their segment,
+			// and a trailing ; doesn't start a new one
+			segments := 1
+			for _, c := range cmds[:len(cmds)-1] {
+				switch c.Op {
+				case shell.OpAnd, shell.OpSemicolon, shell.OpNewline:
+					segments++
+				}
+			}
+			if segments <= threshold {
+				continue
+			}
+
+			diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+				WithSeverity(r.Severity()).
+				WithMessagef("RUN chains %d commands (max %d)", segments, threshold).
+				WithRange(run.Pos(), run.End()).
+				WithContext(ctx.GetLine(run.Pos().Line)).
+				WithHelp("Move the commands into a heredoc, e.g. RUN <<EOF ... EOF with set -e, or into a script that is copied in and run").
+				Build()
+			diags = append(diags, diag)
+		}
+	}
+
+	return diags
+}
+
+func init() {
+	Register(&STY005LongRunChain{})
+}
@@ -0,0 +1,98 @@
+package style
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+func TestSTY005LongRunChain(t *testing.T) {
+	chain := func(n int, sep string) string {
+		cmds := make([]string, n)
+		for i := range cmds {
+			cmds[i] = "echo " + string(rune('a'+i))
+		}
+		return "FROM alpine:3.19\nRUN " + strings.Join(cmds, sep) + "\n"
+	}
+
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "at the threshold",
+			source:   chain(5, " && "),
+			expected: 0,
+		},
+		{
+			name:     "over the threshold",
+			source:   chain(6, " && "),
+			expected: 1,
+		},
+		{
+			name:     "semicolons",
+			source:   chain(6, "; "),
+			expected: 1,
+		},
+		{
+			name:     "continued lines",
+			source:   chain(6, " \\\n    && "),
+			expected: 1,
+		},
+		{
+			name:     "pipes are one segment",
+			source:   "FROM alpine:3.19\nRUN a | b | c && d | e && f || g && h\n",
+			expected: 0,
+		},
+		{
+			name:     "trailing semicolon",
+			source:   "FROM alpine:3.19\nRUN a; b; c; d; e;\n",
+			expected: 0,
+		},
+		{
+			name:     "operators in quotes",
+			source:   "FROM alpine:3.19\nRUN sh -c 'a && b && c && d && e && f && g'\n",
+			expected: 0,
+		},
+		{
+			name:     "heredoc",
+			source:   "FROM alpine:3.19\nRUN <<EOF\na && b && c && d && e && f && g\nEOF\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := analyzer.New(
+				analyzer.WithRules(&STY005LongRunChain{}),
+				analyzer.WithMinSeverity(analyzer.SeverityHint),
+				analyzer.WithRuleConfig("STY005", map[string]interface{}{"max_commands": 5}),
+			)
+			result, errs := a.AnalyzeSource(tt.source, "Dockerfile")
+			if len(errs) > 0 {
+				t.Fatalf("unexpected parse errors: %v", errs)
+			}
+			if len(result.Diagnostics) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(result.Diagnostics), result.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestSTY005LongRunChain_DefaultThreshold(t *testing.T) {
+	cmds := make([]string, 21)
+	for i := range cmds {
+		cmds[i] = "true"
+	}
+	source := "FROM alpine:3.19\nRUN " + strings.Join(cmds, " && ") + "\n"
+
+	diags := runRule(t, &STY005LongRunChain{}, source)
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d: %v", len(diags), diags)
+	}
+	if want := "RUN chains 21 commands (max 20)"; diags[0].Message != want {
+		t.Errorf("expected message %q, got %q", want, diags[0].Message)
+	}
+}
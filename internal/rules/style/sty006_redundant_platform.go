@@ -0,0 +1,56 @@
+package style
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/lexer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// STY006RedundantPlatform checks for FROM --platform set to the default target platform
+type STY006RedundantPlatform struct{}
+
+func (r *STY006RedundantPlatform) ID() string          { return "STY006" }
+func (r *STY006RedundantPlatform) Name() string        { return "redundant-platform" }
+func (r *STY006RedundantPlatform) Category() analyzer.Category { return analyzer.CategoryStyle }
+func (r *STY006RedundantPlatform) Severity() analyzer.Severity { return analyzer.SeverityInfo }
+
+func (r *STY006RedundantPlatform) Description() string {
+	return "FROM already pulls the image for the target platform, so --platform=$TARGETPLATFORM has no effect. --platform=$BUILDPLATFORM, which runs a stage natively when cross-compiling, is not redundant."
+}
+
+func (r *STY006RedundantPlatform) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	for _, stage := range df.Stages {
+		from := stage.From
+		if from == nil || !from.RedundantPlatform() {
+			continue
+		}
+
+		builder := analyzer.NewDiagnostic(r.ID(), r.Category()).
+			WithSeverity(r.Severity()).
+			WithMessagef("--platform=%s is the default and can be removed", from.Platform).
+			WithPos(from.Pos()).
+			WithContext(ctx.GetLine(from.Pos().Line)).
+			WithHelp("Remove the --platform flag; use --platform=$BUILDPLATFORM only for stages that should run natively")
+
+		// Only single-line instructions can be replaced in place
+		line := strings.TrimRight(ctx.GetLine(from.Pos().Line), " \t\r")
+		flag := "--platform=" + from.Platform + " "
+		if !strings.HasSuffix(line, "\\") && strings.Count(line, flag) == 1 {
+			builder = builder.
+				WithRange(from.Pos(), lexer.Position{Line: from.Pos().Line, Column: utf8.RuneCountInString(line) + 1}).
+				WithFix(strings.TrimLeft(strings.Replace(line, flag, "", 1), " \t"))
+		}
+		diags = append(diags, builder.Build())
+	}
+
+	return diags
+}
+
+func init() {
+	Register(&STY006RedundantPlatform{})
+}
@@ -0,0 +1,52 @@
+package style
+
+import "testing"
+
+func TestSTY006RedundantPlatform(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+		fix      string
+	}{
+		{
+			name:     "target platform",
+			source:   "FROM --platform=$TARGETPLATFORM golang:1.25 AS build\n",
+			expected: 1,
+			fix:      "FROM golang:1.25 AS build",
+		},
+		{
+			name:     "braced target platform",
+			source:   "FROM --platform=${TARGETPLATFORM} alpine:3.19\n",
+			expected: 1,
+			fix:      "FROM alpine:3.19",
+		},
+		{
+			name:     "build platform",
+			source:   "FROM --platform=$BUILDPLATFORM golang:1.25 AS build\n",
+			expected: 0,
+		},
+		{
+			name:     "explicit platform",
+			source:   "FROM --platform=linux/amd64 alpine:3.19\n",
+			expected: 0,
+		},
+		{
+			name:     "no platform",
+			source:   "FROM alpine:3.19\n",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &STY006RedundantPlatform{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Fatalf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+			if tt.fix != "" && diags[0].FixSuggestion != tt.fix {
+				t.Errorf("expected fix %q, got %q", tt.fix, diags[0].FixSuggestion)
+			}
+		})
+	}
+}
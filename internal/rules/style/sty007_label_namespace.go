@@ -0,0 +1,99 @@
+package style
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+	"github.com/HueCodes/keel/internal/parser"
+)
+
+// STY007LabelNamespace checks for LABEL keys without a reverse-DNS namespace
+type STY007LabelNamespace struct{}
+
+func (r *STY007LabelNamespace) ID() string          { return "STY007" }
+func (r *STY007LabelNamespace) Name() string        { return "label-namespace" }
+func (r *STY007LabelNamespace) Category() analyzer.Category { return analyzer.CategoryStyle }
+func (r *STY007LabelNamespace) Severity() analyzer.Severity { return analyzer.SeverityHint }
+
+func (r *STY007LabelNamespace) Description() string {
+	return "Label keys should be namespaced with a reverse-DNS prefix, such as org.opencontainers.image.version, so they don't collide with labels set by base images and tools."
+}
+
+// defaultAllowedLabels are bare keys accepted without a namespace
+var defaultAllowedLabels = []string{"maintainer"}
+
+// ociAnnotations are the pre-defined OCI image annotation names, which
+// get the org.opencontainers.image prefix as their suggestion
+var ociAnnotations = map[string]bool{
+	"created": true, "authors": true, "url": true, "documentation": true,
+	"source": true, "version": true, "revision": true, "vendor": true,
+	"licenses": true, "ref.name": true, "title": true, "description": true,
+	"base.digest": true, "base.name": true,
+}
+
+func (r *STY007LabelNamespace) Check(df *parser.Dockerfile, ctx *analyzer.RuleContext) []analyzer.Diagnostic {
+	var diags []analyzer.Diagnostic
+
+	allowed := make(map[string]bool)
+	for _, k := range allowedLabels(ctx.Config["allowed_keys"]) {
+		allowed[strings.ToLower(k)] = true
+	}
+
+	for _, stage := range df.Stages {
+		for _, inst := range stage.Instructions {
+			label, ok := inst.(*parser.LabelInstruction)
+			if !ok {
+				continue
+			}
+
+			for _, kv := range label.Labels {
+				key := kv.Key
+				if key == "" || strings.Contains(key, ".") || strings.Contains(key, "$") || allowed[strings.ToLower(key)] {
+					continue
+				}
+
+				diag := analyzer.NewDiagnostic(r.ID(), r.Category()).
+					WithSeverity(r.Severity()).
+					WithMessagef("Label key '%s' has no namespace", key).
+					WithPos(label.Pos()).
+					WithContext(ctx.GetLine(label.Pos().Line)).
+					WithHelp(fmt.Sprintf("Use a namespaced key such as %s", suggestLabelKey(key))).
+					Build()
+				diags = append(diags, diag)
+			}
+		}
+	}
+
+	return diags
+}
+
+// allowedLabels reads the allowed_keys option, falling back to the defaults
+func allowedLabels(v interface{}) []string {
+	switch list := v.(type) {
+	case []string:
+		return list
+	case []interface{}:
+		var keys []string
+		for _, k := range list {
+			if s, ok := k.(string); ok {
+				keys = append(keys, s)
+			}
+		}
+		return keys
+	}
+	return defaultAllowedLabels
+}
+
+// suggestLabelKey returns a namespaced replacement for a bare label key
+func suggestLabelKey(key string) string {
+	lower := strings.ToLower(key)
+	if ociAnnotations[lower] {
+		return "org.opencontainers.image." + lower
+	}
+	return "com.example." + lower
+}
+
+func init() {
+	Register(&STY007LabelNamespace{})
+}
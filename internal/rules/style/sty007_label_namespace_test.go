@@ -0,0 +1,71 @@
+package style
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/HueCodes/keel/internal/analyzer"
+)
+
+func TestSTY007LabelNamespace(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected int
+	}{
+		{
+			name:     "bare key",
+			source:   "FROM alpine:3.19\nLABEL version=1\n",
+			expected: 1,
+		},
+		{
+			name:     "OCI key",
+			source:   "FROM alpine:3.19\nLABEL org.opencontainers.image.version=1\n",
+			expected: 0,
+		},
+		{
+			name:     "maintainer",
+			source:   "FROM alpine:3.19\nLABEL maintainer=x\n",
+			expected: 0,
+		},
+		{
+			name:     "one diagnostic per bare key",
+			source:   "FROM alpine:3.19\nLABEL version=1 team=web com.example.tier=backend\n",
+			expected: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := runRule(t, &STY007LabelNamespace{}, tt.source)
+			if len(diags) != tt.expected {
+				t.Errorf("expected %d diagnostics, got %d: %v", tt.expected, len(diags), diags)
+			}
+		})
+	}
+}
+
+func TestSTY007LabelNamespace_Suggestion(t *testing.T) {
+	diags := runRule(t, &STY007LabelNamespace{}, "FROM alpine:3.19\nLABEL Version=1 team=web\n")
+	if len(diags) != 2 {
+		t.Fatalf("expected 2 diagnostics, got %d: %v", len(diags), diags)
+	}
+	if !strings.Contains(diags[0].Help, "org.opencontainers.image.version") {
+		t.Errorf("expected an OCI suggestion, got %q", diags[0].Help)
+	}
+	if !strings.Contains(diags[1].Help, "com.example.team") {
+		t.Errorf("expected a reverse-DNS suggestion, got %q", diags[1].Help)
+	}
+}
+
+func TestSTY007LabelNamespace_AllowedKeys(t *testing.T) {
+	a := analyzer.New(
+		analyzer.WithRules(&STY007LabelNamespace{}),
+		analyzer.WithMinSeverity(analyzer.SeverityHint),
+		analyzer.WithRuleConfig("STY007", map[string]interface{}{"allowed_keys": []interface{}{"version"}}),
+	)
+	result, _ := a.AnalyzeSource("FROM alpine:3.19\nLABEL version=1 maintainer=x\n", "Dockerfile")
+	if len(result.Diagnostics) != 1 || !strings.Contains(result.Diagnostics[0].Message, "maintainer") {
+		t.Errorf("expected only maintainer to be flagged, got %v", result.Diagnostics)
+	}
+}
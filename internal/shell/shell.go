@@ -0,0 +1,507 @@
+// Package shell provides a small tokenizer for shell-form commands.
+//
+// It is not a full shell parser: it understands quoting, escapes, line
+// continuations, command substitution, subshells, and the list/pipeline
+// operators, which is enough to split RUN commands into individual
+// commands without being fooled by operators inside quotes.
+package shell
+
+import (
+	"path"
+	"strings"
+)
+
+// Operators that separate commands
+const (
+	OpAnd        = "&&"
+	OpOr         = "||"
+	OpSemicolon  = ";"
+	OpPipe       = "|"
+	OpBackground = "&"
+	OpNewline    = "\n"
+)
+
+// Word is a single shell word with quotes and escapes removed
+type Word struct {
+	Value string
+	Start int // byte offset of the word in the original command
+	End   int // byte offset just past the word
+}
+
+// Command is a single simple command within a command list
+type Command struct {
+	Text  string // original text of the command, trimmed
+	Words []Word
+	Op    string // operator following the command, "" for the last one
+	Start int    // byte offset of the command in the original string
+	End   int    // byte offset just past the command
+}
+
+// Reserved words that may precede a command in compound statements
+var reservedWords = map[string]bool{
+	"!": true, "if": true, "then": true, "else": true, "elif": true,
+	"do": true, "while": true, "until": true, "{": true,
+}
+
+// Args returns the command's words, skipping leading reserved words
+// (then, do, ...) and variable assignments (FOO=bar)
+func (c Command) Args() []Word {
+	words := c.Words
+	for len(words) > 0 {
+		w := words[0].Value
+		if reservedWords[w] || isAssignment(w) {
+			words = words[1:]
+			continue
+		}
+		break
+	}
+	return words
+}
+
+// Name returns the program the command runs, or "" if there is none
+func (c Command) Name() string {
+	args := c.Args()
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0].Value
+}
+
+// HasWords reports whether the command's arguments contain seq as
+// consecutive words, e.g. HasWords("npm", "install")
+func (c Command) HasWords(seq ...string) bool {
+	args := c.Args()
+	for i := 0; i+len(seq) <= len(args); i++ {
+		match := true
+		for j, s := range seq {
+			if args[i+j].Value != s {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// Subcommand returns the first argument after the program that isn't an
+// option, e.g. "install" for apt-get -y install curl. valueFlags lists
+// the options that take their value as a separate word, such as -o.
+func (c Command) Subcommand(valueFlags ...string) string {
+	args := c.Args()
+	for i := 1; i < len(args); i++ {
+		w := args[i].Value
+		if !strings.HasPrefix(w, "-") {
+			return w
+		}
+		for _, f := range valueFlags {
+			if w == f {
+				i++
+				break
+			}
+		}
+	}
+	return ""
+}
+
+func isAssignment(w string) bool {
+	eq := strings.IndexByte(w, '=')
+	if eq <= 0 {
+		return false
+	}
+	for i, c := range w[:eq] {
+		if c != '_' && !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && !(i > 0 && c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// Split splits a shell command into simple commands separated by
+// &&, ||, ;, |, & and newlines. Operators inside quotes, command
+// substitutions, and subshells do not split.
+func Split(cmd string) []Command {
+	t := &tokenizer{src: cmd, cmdStart: -1}
+	t.run()
+	return t.cmds
+}
+
+// Commands returns the trimmed text of each command in cmd
+func Commands(cmd string) []string {
+	var texts []string
+	for _, c := range Split(cmd) {
+		texts = append(texts, c.Text)
+	}
+	return texts
+}
+
+// HasCommand reports whether any command in cmd contains words as
+// consecutive arguments, e.g. HasCommand(cmd, "npm", "install")
+func HasCommand(cmd string, words ...string) bool {
+	for _, c := range Split(cmd) {
+		if c.HasWords(words...) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasOperator reports whether cmd contains a top-level operator other
+// than &&. Such commands need grouping before being joined with &&.
+func HasOperator(cmd string) bool {
+	for _, c := range Split(cmd) {
+		if c.Op != "" && c.Op != OpAnd {
+			return true
+		}
+	}
+	return false
+}
+
+// Group wraps cmd in a brace group when it contains top-level operators
+// other than &&, so that it can be safely joined with other commands
+// using && without changing its meaning
+func Group(cmd string) string {
+	if !HasOperator(cmd) {
+		return cmd
+	}
+	if strings.HasSuffix(cmd, "&") && !strings.HasSuffix(cmd, "&&") {
+		return "{ " + cmd + " }"
+	}
+	return "{ " + cmd + "; }"
+}
+
+// UnquotedVars returns the names of the variables expanded outside quotes
+// in raw, the source text of a word. Such expansions are subject to word
+// splitting and globbing: $DIR/tmp yields DIR, "$DIR"/tmp yields nothing.
+func UnquotedVars(raw string) []string {
+	var vars []string
+	inSingle, inDouble := false, false
+
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case inSingle:
+			if c == '\'' {
+				inSingle = false
+			}
+		case c == '\\':
+			i++
+		case c == '"':
+			inDouble = !inDouble
+		case c == '\'' && !inDouble:
+			inSingle = true
+		case c == '$' && !inDouble:
+			rest := raw[i+1:]
+			var name string
+			if strings.HasPrefix(rest, "{") {
+				end := strings.IndexByte(rest, '}')
+				if end < 0 {
+					continue
+				}
+				name = rest[1:end]
+				i += end + 1
+			} else {
+				n := 0
+				for n < len(rest) && isNameByte(rest[n]) {
+					n++
+				}
+				name = rest[:n]
+				i += n
+			}
+			// ${VAR:-default} and similar still expand VAR
+			if n := leadingName(name); n != "" {
+				vars = append(vars, n)
+			}
+		}
+	}
+
+	return vars
+}
+
+// Vars returns the names of the variables referenced in s as $NAME or
+// ${NAME...}, quoted or not. Escaped dollar signs are skipped.
+func Vars(s string) []string {
+	var vars []string
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '$':
+			rest := s[i+1:]
+			var name string
+			if strings.HasPrefix(rest, "{") {
+				end := strings.IndexByte(rest, '}')
+				if end < 0 {
+					continue
+				}
+				name = rest[1:end]
+				i += end + 1
+			} else {
+				name = leadingName(rest)
+				i += len(name)
+			}
+			if n := leadingName(name); n != "" {
+				vars = append(vars, n)
+			}
+		}
+	}
+
+	return vars
+}
+
+func isNameByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// leadingName returns the variable name at the start of s, or "" if s
+// doesn't start with one
+func leadingName(s string) string {
+	if s == "" || s[0] >= '0' && s[0] <= '9' {
+		return ""
+	}
+	n := 0
+	for n < len(s) && isNameByte(s[n]) {
+		n++
+	}
+	return s[:n]
+}
+
+// IsPOSIX reports whether a SHELL instruction's argv runs a POSIX-style
+// shell. An empty shell means the default /bin/sh -c.
+func IsPOSIX(shell []string) bool {
+	if len(shell) == 0 {
+		return true
+	}
+	switch path.Base(shell[0]) {
+	case "sh", "bash", "ash", "dash", "zsh", "ksh", "mksh", "busybox":
+		return true
+	}
+	return false
+}
+
+type tokenizer struct {
+	src  string
+	pos  int
+	cmds []Command
+
+	words     []Word
+	word      strings.Builder
+	wordStart int
+	inWord    bool
+	cmdStart  int
+	depth     int // subshell nesting
+}
+
+func (t *tokenizer) run() {
+	for t.pos < len(t.src) {
+		c := t.src[t.pos]
+
+		switch {
+		case c == '\\':
+			if t.pos+1 < len(t.src) && t.src[t.pos+1] == '\n' {
+				// Line continuation
+				t.pos += 2
+				continue
+			}
+			t.beginWord()
+			if t.pos+1 < len(t.src) {
+				t.word.WriteByte(t.src[t.pos+1])
+				t.pos += 2
+			} else {
+				t.word.WriteByte(c)
+				t.pos++
+			}
+		case c == '\'':
+			t.beginWord()
+			end := strings.IndexByte(t.src[t.pos+1:], '\'')
+			if end < 0 {
+				t.word.WriteString(t.src[t.pos+1:])
+				t.pos = len(t.src)
+			} else {
+				t.word.WriteString(t.src[t.pos+1 : t.pos+1+end])
+				t.pos += end + 2
+			}
+		case c == '"':
+			t.beginWord()
+			t.readDoubleQuoted()
+		case c == '$' && t.peek(1) == '(':
+			t.beginWord()
+			end := t.matchParen(t.pos + 1)
+			t.word.WriteString(t.src[t.pos:end])
+			t.pos = end
+		case c == '`':
+			t.beginWord()
+			end := t.matchBacktick(t.pos)
+			t.word.WriteString(t.src[t.pos:end])
+			t.pos = end
+		case c == ' ' || c == '\t' || c == '\r':
+			t.endWord()
+			t.pos++
+		case c == '#' && !t.inWord:
+			// Comment runs to the end of the line
+			for t.pos < len(t.src) && t.src[t.pos] != '\n' {
+				t.pos++
+			}
+		case c == '(' && !t.inWord:
+			t.markCommand()
+			t.depth++
+			t.pos++
+		case c == ')' && t.depth > 0:
+			t.endWord()
+			t.depth--
+			t.pos++
+		case t.depth == 0 && (c == '\n' || c == ';' || c == '|' || c == '&'):
+			if t.isRedirect() {
+				t.beginWord()
+				t.word.WriteByte(c)
+				t.pos++
+				continue
+			}
+			op := string(c)
+			if (c == '&' || c == '|') && t.peek(1) == c {
+				op += string(c)
+			}
+			t.endCommand(t.pos, op)
+			t.pos += len(op)
+		default:
+			t.beginWord()
+			t.word.WriteByte(c)
+			t.pos++
+		}
+	}
+	t.endCommand(len(t.src), "")
+}
+
+// isRedirect reports whether an & at the current position belongs to a
+// redirection such as 2>&1 or &>file
+func (t *tokenizer) isRedirect() bool {
+	if t.src[t.pos] != '&' {
+		return false
+	}
+	if t.pos > 0 && (t.src[t.pos-1] == '>' || t.src[t.pos-1] == '<') {
+		return true
+	}
+	return t.peek(1) == '>'
+}
+
+func (t *tokenizer) peek(n int) byte {
+	if t.pos+n < len(t.src) {
+		return t.src[t.pos+n]
+	}
+	return 0
+}
+
+func (t *tokenizer) readDoubleQuoted() {
+	t.pos++ // opening quote
+	for t.pos < len(t.src) && t.src[t.pos] != '"' {
+		c := t.src[t.pos]
+		if c == '\\' && t.pos+1 < len(t.src) && strings.IndexByte("\"\\$`\n", t.src[t.pos+1]) >= 0 {
+			if t.src[t.pos+1] != '\n' {
+				t.word.WriteByte(t.src[t.pos+1])
+			}
+			t.pos += 2
+			continue
+		}
+		if c == '$' && t.peek(1) == '(' {
+			end := t.matchParen(t.pos + 1)
+			t.word.WriteString(t.src[t.pos:end])
+			t.pos = end
+			continue
+		}
+		t.word.WriteByte(c)
+		t.pos++
+	}
+	if t.pos < len(t.src) {
+		t.pos++ // closing quote
+	}
+}
+
+// matchParen returns the offset just past the parenthesis matching the
+// one at open, skipping quoted text
+func (t *tokenizer) matchParen(open int) int {
+	depth := 0
+	for i := open; i < len(t.src); i++ {
+		switch t.src[i] {
+		case '\\':
+			i++
+		case '\'':
+			if end := strings.IndexByte(t.src[i+1:], '\''); end >= 0 {
+				i += end + 1
+			}
+		case '"':
+			for i++; i < len(t.src) && t.src[i] != '"'; i++ {
+				if t.src[i] == '\\' {
+					i++
+				}
+			}
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(t.src)
+}
+
+// matchBacktick returns the offset just past the backtick closing the one at open
+func (t *tokenizer) matchBacktick(open int) int {
+	for i := open + 1; i < len(t.src); i++ {
+		switch t.src[i] {
+		case '\\':
+			i++
+		case '`':
+			return i + 1
+		}
+	}
+	return len(t.src)
+}
+
+func (t *tokenizer) markCommand() {
+	if t.cmdStart < 0 {
+		t.cmdStart = t.pos
+	}
+}
+
+func (t *tokenizer) beginWord() {
+	t.markCommand()
+	if !t.inWord {
+		t.inWord = true
+		t.wordStart = t.pos
+	}
+}
+
+func (t *tokenizer) endWord() {
+	if !t.inWord {
+		return
+	}
+	t.words = append(t.words, Word{Value: t.word.String(), Start: t.wordStart, End: t.pos})
+	t.word.Reset()
+	t.inWord = false
+}
+
+func (t *tokenizer) endCommand(end int, op string) {
+	t.endWord()
+	if t.cmdStart >= 0 {
+		// A trailing backslash can only be a line continuation here
+		text := strings.TrimSpace(t.src[t.cmdStart:end])
+		text = strings.TrimSpace(strings.TrimSuffix(text, "\\"))
+		t.cmds = append(t.cmds, Command{
+			Text:  text,
+			Words: t.words,
+			Op:    op,
+			Start: t.cmdStart,
+			End:   end,
+		})
+	} else if op != "" && op != OpNewline && len(t.cmds) > 0 && t.cmds[len(t.cmds)-1].Op == OpNewline {
+		// An operator at the start of a line continues the previous list
+		t.cmds[len(t.cmds)-1].Op = op
+	}
+	t.words = nil
+	t.cmdStart = -1
+}
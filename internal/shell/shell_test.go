@@ -0,0 +1,272 @@
+package shell
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "and list",
+			input:    "apt-get update && apt-get install -y curl",
+			expected: []string{"apt-get update", "apt-get install -y curl"},
+		},
+		{
+			name:     "and inside single quotes",
+			input:    "echo 'a && b' && make",
+			expected: []string{"echo 'a && b'", "make"},
+		},
+		{
+			name:     "and inside double quotes",
+			input:    `sh -c "cd /app && make" ; echo done`,
+			expected: []string{`sh -c "cd /app && make"`, "echo done"},
+		},
+		{
+			name:     "escaped operator",
+			input:    `echo a \&\& b`,
+			expected: []string{`echo a \&\& b`},
+		},
+		{
+			name:     "command substitution",
+			input:    "echo $(cd /tmp && pwd) || true",
+			expected: []string{"echo $(cd /tmp && pwd)", "true"},
+		},
+		{
+			name:     "subshell",
+			input:    "(cd src && make) && make install",
+			expected: []string{"(cd src && make)", "make install"},
+		},
+		{
+			name:     "pipes and background",
+			input:    "curl -fsSL url | tar xz & wait",
+			expected: []string{"curl -fsSL url", "tar xz", "wait"},
+		},
+		{
+			name:     "redirects are not operators",
+			input:    "make >/dev/null 2>&1 && echo ok",
+			expected: []string{"make >/dev/null 2>&1", "echo ok"},
+		},
+		{
+			name:     "line continuations and newlines",
+			input:    "apt-get update \\\n    && apt-get install -y curl\nrm -rf /tmp/*",
+			expected: []string{"apt-get update", "apt-get install -y curl", "rm -rf /tmp/*"},
+		},
+		{
+			name:     "comments",
+			input:    "make # build && install\nmake install",
+			expected: []string{"make # build && install", "make install"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Commands(tt.input)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSplit_SingleQuotedAndIsNotSeparator(t *testing.T) {
+	cmds := Split("echo 'foo && bar'")
+	if len(cmds) != 1 {
+		t.Fatalf("expected 1 command, got %d", len(cmds))
+	}
+	if cmds[0].Op != "" {
+		t.Errorf("expected no operator, got %q", cmds[0].Op)
+	}
+	if len(cmds[0].Words) != 2 || cmds[0].Words[1].Value != "foo && bar" {
+		t.Errorf("expected words [echo, foo && bar], got %+v", cmds[0].Words)
+	}
+}
+
+func TestSplit_Operators(t *testing.T) {
+	cmds := Split("a && b || c; d | e")
+	var ops []string
+	for _, c := range cmds {
+		ops = append(ops, c.Op)
+	}
+	expected := []string{OpAnd, OpOr, OpSemicolon, OpPipe, ""}
+	if !reflect.DeepEqual(ops, expected) {
+		t.Errorf("expected operators %q, got %q", expected, ops)
+	}
+}
+
+func TestSplit_WordOffsets(t *testing.T) {
+	input := `apt-get update && sudo -E apt-get install "curl"`
+	cmds := Split(input)
+	if len(cmds) != 2 {
+		t.Fatalf("expected 2 commands, got %d", len(cmds))
+	}
+	for _, w := range cmds[1].Words {
+		raw := input[w.Start:w.End]
+		if w.Value != "curl" && raw != w.Value {
+			t.Errorf("word %q has raw text %q", w.Value, raw)
+		}
+	}
+	last := cmds[1].Words[len(cmds[1].Words)-1]
+	if input[last.Start:last.End] != `"curl"` {
+		t.Errorf("expected quoted raw text, got %q", input[last.Start:last.End])
+	}
+}
+
+func TestCommand_Args(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"npm ci", "npm"},
+		{"CI=true NODE_ENV=production npm ci", "npm"},
+		{"if true; then make; fi", "true"},
+		{"then make", "make"},
+		{"FOO=bar", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := Split(tt.input)[0].Name(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCommand_HasWords(t *testing.T) {
+	tests := []struct {
+		input    string
+		seq      []string
+		expected bool
+	}{
+		{"npm install --production", []string{"npm", "install"}, true},
+		{"python -m pip install flask", []string{"pip", "install"}, true},
+		{"echo 'npm install'", []string{"npm", "install"}, false},
+		{"npm run build", []string{"npm", "install"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := Split(tt.input)[0].HasWords(tt.seq...); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCommand_Subcommand(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"apt-get install -y curl", "install"},
+		{"apt-get -y update", "update"},
+		{"DEBIAN_FRONTEND=noninteractive apt-get -qq install curl", "install"},
+		{"apt-get -o Acquire::Retries=3 update", "update"},
+		{"apt-get --quiet", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := Split(tt.input)[0].Subcommand("-o"); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestHasOperator(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"make && make install", false},
+		{"make || true", true},
+		{"make; make install", true},
+		{"echo 'a || b'", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := HasOperator(tt.input); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsPOSIX(t *testing.T) {
+	tests := []struct {
+		shell    []string
+		expected bool
+	}{
+		{nil, true},
+		{[]string{"/bin/bash", "-o", "pipefail", "-c"}, true},
+		{[]string{"/bin/ash", "-eo", "pipefail", "-c"}, true},
+		{[]string{"powershell", "-Command"}, false},
+		{[]string{"cmd", "/S", "/C"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsPOSIX(tt.shell); got != tt.expected {
+			t.Errorf("IsPOSIX(%v) = %v, want %v", tt.shell, got, tt.expected)
+		}
+	}
+}
+
+func TestUnquotedVars(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected []string
+	}{
+		{`$DIR`, []string{"DIR"}},
+		{`${DIR}/cache`, []string{"DIR"}},
+		{`${DIR:-/tmp}`, []string{"DIR"}},
+		{`$A$B`, []string{"A", "B"}},
+		{`"$DIR"`, nil},
+		{`"${DIR}"/cache`, nil},
+		{`'$DIR'`, nil},
+		{`\$DIR`, nil},
+		{`"$A"$B`, []string{"B"}},
+		{`$1`, nil},
+		{`$(pwd)`, nil},
+		{`/app`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got := UnquotedVars(tt.raw)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestVars(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected []string
+	}{
+		{`$DIR`, []string{"DIR"}},
+		{`${DIR:-/tmp}/cache`, []string{"DIR"}},
+		{`"$A" '$B'$C`, []string{"A", "B", "C"}},
+		{`\$DIR`, nil},
+		{`$1 $(pwd) $$`, nil},
+		{`${A}${B}`, []string{"A", "B"}},
+		{`/app`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got := Vars(tt.s)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}